@@ -0,0 +1,78 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type HistoryResult struct {
+	Items        []*Change
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n HistoryResult) GetItems() interface{} {
+	return n.Items
+}
+
+func (n HistoryResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n HistoryResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// collections maps the ID prefixes of the resources with a change history to
+// the collection they are in.
+var collections = map[string]string{
+	"r":    "roles",
+	"u":    "users",
+	"ttcp": "targets",
+	"hsst": "host-sets",
+}
+
+// Get returns the changes made to the role, user, target or host set with the
+// given ID, oldest first. The kind of resource is determined by the ID's
+// prefix.
+func (c *Client) Get(ctx context.Context, resourceId string) (*HistoryResult, error) {
+	if resourceId == "" {
+		return nil, fmt.Errorf("empty resourceId value passed into Get request")
+	}
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+	i := strings.Index(resourceId, "_")
+	if i < 0 {
+		return nil, fmt.Errorf("malformed resource id %q", resourceId)
+	}
+	collection, ok := collections[resourceId[:i]]
+	if !ok {
+		return nil, fmt.Errorf("resource %q does not have a change history", resourceId)
+	}
+
+	req, err := c.client.NewRequest(ctx, "GET", fmt.Sprintf("%s/%s:history", collection, resourceId), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Get request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Get call: %w", err)
+	}
+
+	target := new(HistoryResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Get response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
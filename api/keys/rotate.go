@@ -0,0 +1,53 @@
+package keys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Rotate creates a new version of the root key and of every data encryption
+// key in the scope. The status of every key version of the scope after the
+// rotation is returned.
+func (c *Client) Rotate(ctx context.Context, scopeId string, opt ...Option) (*KeyVersionListResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into Rotate request")
+	}
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+	opts.postMap["scope_id"] = scopeId
+
+	req, err := c.client.NewRequest(ctx, "POST", "keys:rotate", opts.postMap, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Rotate request: %w", err)
+	}
+
+	if len(opts.queryMap) > 0 {
+		q := url.Values{}
+		for k, v := range opts.queryMap {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Rotate call: %w", err)
+	}
+
+	target := new(KeyVersionListResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Rotate response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
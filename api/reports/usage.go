@@ -0,0 +1,96 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Groupings supported by Usage
+const (
+	GroupByUser   = "user"
+	GroupByTarget = "target"
+	GroupByHost   = "host"
+	GroupByDay    = "day"
+)
+
+type UsageReportResult struct {
+	Items        []*Usage
+	responseBody *bytes.Buffer
+	responseMap  map[string]interface{}
+}
+
+func (n UsageReportResult) GetItems() interface{} {
+	return n.Items
+}
+
+func (n UsageReportResult) GetResponseBody() *bytes.Buffer {
+	return n.responseBody
+}
+
+func (n UsageReportResult) GetResponseMap() map[string]interface{} {
+	return n.responseMap
+}
+
+// WithStartTime restricts a usage report to sessions created at or after t.
+func WithStartTime(t time.Time) Option {
+	return func(o *options) {
+		o.queryMap["start_time"] = t.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// WithEndTime restricts a usage report to sessions created before t.
+func WithEndTime(t time.Time) Option {
+	return func(o *options) {
+		o.queryMap["end_time"] = t.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// Usage returns session usage within the scope aggregated by groupBy, which
+// must be one of GroupByUser, GroupByTarget, GroupByHost or GroupByDay.
+func (c *Client) Usage(ctx context.Context, scopeId, groupBy string, opt ...Option) (*UsageReportResult, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("empty scopeId value passed into Usage request")
+	}
+	if groupBy == "" {
+		return nil, fmt.Errorf("empty groupBy value passed into Usage request")
+	}
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+	opts.queryMap["scope_id"] = scopeId
+	opts.queryMap["group_by"] = groupBy
+
+	req, err := c.client.NewRequest(ctx, "GET", "reports/usage", nil, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Usage request: %w", err)
+	}
+
+	q := url.Values{}
+	for k, v := range opts.queryMap {
+		q.Add(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during Usage call: %w", err)
+	}
+
+	target := new(UsageReportResult)
+	apiErr, err := resp.Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Usage response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
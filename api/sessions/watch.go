@@ -0,0 +1,145 @@
+package sessions
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+)
+
+// SessionEvent describes a state change of a session or one of its
+// connections, as streamed by Watch.
+type SessionEvent struct {
+	// Type is either "session" or "connection"
+	Type         string    `json:"type,omitempty"`
+	SessionId    string    `json:"session_id,omitempty"`
+	ConnectionId string    `json:"connection_id,omitempty"`
+	ScopeId      string    `json:"scope_id,omitempty"`
+	UserId       string    `json:"user_id,omitempty"`
+	TargetId     string    `json:"target_id,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Time         time.Time `json:"time,omitempty"`
+}
+
+// WithWatchUserId restricts a Watch to events for sessions of the given user.
+func WithWatchUserId(userId string) Option {
+	return func(o *options) {
+		o.queryMap["user_id"] = userId
+	}
+}
+
+// WithWatchTargetId restricts a Watch to events for sessions of the given
+// target.
+func WithWatchTargetId(targetId string) Option {
+	return func(o *options) {
+		o.queryMap["target_id"] = targetId
+	}
+}
+
+// Watch streams session and connection state changes within the given scope.
+// Events are delivered on the returned event channel until ctx is canceled or
+// the controller ends the stream, at which point the event channel is closed.
+// If the stream ends because of an error it is sent on the error channel
+// before the event channel is closed.
+func (c *Client) Watch(ctx context.Context, scopeId string, opt ...Option) (<-chan *SessionEvent, <-chan error, error) {
+	if scopeId == "" {
+		return nil, nil, fmt.Errorf("empty scopeId value passed into Watch request")
+	}
+	if c.client == nil {
+		return nil, nil, errors.New("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+	opts.queryMap["scope_id"] = scopeId
+
+	// The stream is long lived so it must not be bound by the client timeout
+	client := c.client.Clone()
+	client.SetClientTimeout(0)
+
+	req, err := client.NewRequest(ctx, "GET", "sessions:watch", nil, apiOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating Watch request: %w", err)
+	}
+	req.Header.Set("accept", "text/event-stream")
+
+	q := url.Values{}
+	for k, v := range opts.queryMap {
+		q.Add(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error performing client request during Watch call: %w", err)
+	}
+	if resp.HttpResponse().StatusCode >= 400 {
+		apiErr, err := resp.Decode(nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error decoding Watch response: %w", err)
+		}
+		return nil, nil, apiErr
+	}
+
+	events := make(chan *SessionEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(events)
+		defer resp.HttpResponse().Body.Close()
+		if err := readEvents(ctx, resp.HttpResponse().Body, events); err != nil && ctx.Err() == nil {
+			errs <- err
+		}
+	}()
+	return events, errs, nil
+}
+
+// readEvents parses the Server-Sent Events read from r and sends each message
+// to events.
+func readEvents(ctx context.Context, r io.Reader, events chan<- *SessionEvent) error {
+	scanner := bufio.NewScanner(r)
+	var eventType string
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			switch eventType {
+			case "error":
+				apiErr := new(api.Error)
+				if err := json.Unmarshal(data.Bytes(), apiErr); err != nil {
+					return fmt.Errorf("error decoding Watch error: %w", err)
+				}
+				return apiErr
+			default:
+				e := new(SessionEvent)
+				if err := json.Unmarshal(data.Bytes(), e); err != nil {
+					return fmt.Errorf("error decoding Watch event: %w", err)
+				}
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			eventType = ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// Comment, used by the controller as a keepalive
+		case strings.HasPrefix(line, "event:"):
+			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	return scanner.Err()
+}
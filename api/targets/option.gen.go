@@ -57,6 +57,30 @@ func DefaultAttributes() Option {
 	}
 }
 
+func WithConnectionDownstreamBytesPerSecond(inConnectionDownstreamBytesPerSecond uint32) Option {
+	return func(o *options) {
+		o.postMap["connection_downstream_bytes_per_second"] = inConnectionDownstreamBytesPerSecond
+	}
+}
+
+func DefaultConnectionDownstreamBytesPerSecond() Option {
+	return func(o *options) {
+		o.postMap["connection_downstream_bytes_per_second"] = nil
+	}
+}
+
+func WithConnectionUpstreamBytesPerSecond(inConnectionUpstreamBytesPerSecond uint32) Option {
+	return func(o *options) {
+		o.postMap["connection_upstream_bytes_per_second"] = inConnectionUpstreamBytesPerSecond
+	}
+}
+
+func DefaultConnectionUpstreamBytesPerSecond() Option {
+	return func(o *options) {
+		o.postMap["connection_upstream_bytes_per_second"] = nil
+	}
+}
+
 func WithTcpTargetDefaultPort(inDefaultPort uint32) Option {
 	return func(o *options) {
 		raw, ok := o.postMap["attributes"]
@@ -81,6 +105,30 @@ func DefaultTcpTargetDefaultPort() Option {
 	}
 }
 
+func WithUdpTargetDefaultPort(inDefaultPort uint32) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["default_port"] = inDefaultPort
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultUdpTargetDefaultPort() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["default_port"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
 func WithDescription(inDescription string) Option {
 	return func(o *options) {
 		o.postMap["description"] = inDescription
@@ -123,6 +171,18 @@ func DefaultSessionConnectionLimit() Option {
 	}
 }
 
+func WithSessionDownstreamBytesPerSecond(inSessionDownstreamBytesPerSecond uint32) Option {
+	return func(o *options) {
+		o.postMap["session_downstream_bytes_per_second"] = inSessionDownstreamBytesPerSecond
+	}
+}
+
+func DefaultSessionDownstreamBytesPerSecond() Option {
+	return func(o *options) {
+		o.postMap["session_downstream_bytes_per_second"] = nil
+	}
+}
+
 func WithSessionMaxSeconds(inSessionMaxSeconds uint32) Option {
 	return func(o *options) {
 		o.postMap["session_max_seconds"] = inSessionMaxSeconds
@@ -134,3 +194,15 @@ func DefaultSessionMaxSeconds() Option {
 		o.postMap["session_max_seconds"] = nil
 	}
 }
+
+func WithSessionUpstreamBytesPerSecond(inSessionUpstreamBytesPerSecond uint32) Option {
+	return func(o *options) {
+		o.postMap["session_upstream_bytes_per_second"] = inSessionUpstreamBytesPerSecond
+	}
+}
+
+func DefaultSessionUpstreamBytesPerSecond() Option {
+	return func(o *options) {
+		o.postMap["session_upstream_bytes_per_second"] = nil
+	}
+}
@@ -0,0 +1,74 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Operational states of a worker.
+const (
+	OperationalStateActive   = "active"
+	OperationalStateDraining = "draining"
+	OperationalStateDisabled = "disabled"
+)
+
+// WithDrainTimeout sets how long a worker set to draining waits for its
+// connections to finish before closing them. Without it, the worker waits for
+// as long as they take.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.postMap["drain_timeout_seconds"] = uint32(timeout / time.Second)
+	}
+}
+
+// SetOperationalState changes the operational state of the worker to one of
+// OperationalStateActive, OperationalStateDraining or
+// OperationalStateDisabled. Only active workers are given new sessions.
+func (c *Client) SetOperationalState(ctx context.Context, workerId string, state string, opt ...Option) (*WorkerUpdateResult, error) {
+	if workerId == "" {
+		return nil, fmt.Errorf("empty workerId value passed into SetOperationalState request")
+	}
+	if state == "" {
+		return nil, fmt.Errorf("empty state value passed into SetOperationalState request")
+	}
+	if c.client == nil {
+		return nil, errors.New("nil client")
+	}
+
+	opts, apiOpts := getOpts(opt...)
+	opts.postMap["operational_state"] = state
+
+	req, err := c.client.NewRequest(ctx, "POST", fmt.Sprintf("workers/%s:set-operational-state", url.PathEscape(workerId)), opts.postMap, apiOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating SetOperationalState request: %w", err)
+	}
+
+	if len(opts.queryMap) > 0 {
+		q := url.Values{}
+		for k, v := range opts.queryMap {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing client request during SetOperationalState call: %w", err)
+	}
+
+	target := new(WorkerUpdateResult)
+	target.Item = new(Worker)
+	apiErr, err := resp.Decode(target.Item)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding SetOperationalState response: %w", err)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target.responseBody = resp.Body
+	target.responseMap = resp.Map
+	return target, nil
+}
@@ -10,8 +10,11 @@ import "time"
 // only ever be set at startup, but simply available to reference from anywhere.
 
 const (
-	TcpProxyV1     = "boundary-tcp-proxy-v1"
-	ServiceTokenV1 = "s1"
+	TcpProxyV1          = "boundary-tcp-proxy-v1"
+	TcpProxyMuxV1       = "boundary-tcp-proxy-mux-v1"
+	TcpProxyResumableV1 = "boundary-tcp-proxy-resumable-v1"
+	UdpProxyV1          = "boundary-udp-proxy-v1"
+	ServiceTokenV1      = "s1"
 )
 
 type ContextMaxRequestSizeType int
@@ -8,14 +8,19 @@ import (
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/authmethods"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/authtokens"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/groups"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/history"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/hostcatalogs"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/hosts"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/hostsets"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/jobs"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/keys"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/reports"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/roles"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/sessions"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/users"
+	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/workers"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -33,6 +38,7 @@ type fieldInfo struct {
 	SubtypeName       string
 	Query             bool
 	SkipDefault       bool
+	JsonString        bool
 }
 
 type structInfo struct {
@@ -375,6 +381,11 @@ var inputStructs = []*structInfo{
 		outFile:     "targets/tcp_target_attributes.gen.go",
 		subtypeName: "TcpTarget",
 	},
+	{
+		inProto:     &targets.UdpTargetAttributes{},
+		outFile:     "targets/udp_target_attributes.gen.go",
+		subtypeName: "UdpTarget",
+	},
 	{
 		inProto: &sessions.Session{},
 		outFile: "sessions/session.gen.go",
@@ -395,6 +406,68 @@ var inputStructs = []*structInfo{
 		inProto: &sessions.WorkerInfo{},
 		outFile: "sessions/workers.gen.go",
 	},
+	{
+		inProto: &reports.Usage{},
+		outFile: "reports/usage.gen.go",
+		templates: []*template.Template{
+			clientTemplate,
+		},
+		outputOnly: true,
+		extraOptions: []fieldInfo{
+			{
+				Name:      "Limit",
+				ProtoName: "limit",
+				FieldType: "uint32",
+				Query:     true,
+			},
+		},
+	},
+	{
+		inProto: &keys.KeyVersion{},
+		outFile: "keys/key_version.gen.go",
+		templates: []*template.Template{
+			clientTemplate,
+			deleteTemplate,
+			listTemplate,
+		},
+		pathArgs:            []string{"key"},
+		createResponseTypes: true,
+	},
+	{
+		inProto: &history.Change{},
+		outFile: "history/change.gen.go",
+		templates: []*template.Template{
+			clientTemplate,
+		},
+		outputOnly: true,
+	},
+	{
+		inProto:    &jobs.JobRun{},
+		outFile:    "jobs/job_run.gen.go",
+		outputOnly: true,
+	},
+	{
+		inProto: &jobs.Job{},
+		outFile: "jobs/job.gen.go",
+		templates: []*template.Template{
+			clientTemplate,
+			readTemplate,
+			listTemplate,
+		},
+		pathArgs:            []string{"job"},
+		createResponseTypes: true,
+	},
+	{
+		inProto: &workers.Worker{},
+		outFile: "workers/worker.gen.go",
+		templates: []*template.Template{
+			clientTemplate,
+			readTemplate,
+			listTemplate,
+		},
+		pathArgs:            []string{"worker"},
+		createResponseTypes: true,
+	},
 	{
 		inProto:     &targets.SessionAuthorization{},
 		outFile:     "targets/session_authorization.gen.go",
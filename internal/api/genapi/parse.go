@@ -68,6 +68,17 @@ func parsePBs() {
 				fi.FieldType = sliceText + ptr + name
 			case protoreflect.BytesKind:
 				fi.FieldType = "[]byte"
+			case protoreflect.DoubleKind:
+				fi.FieldType = sliceText + "float64"
+			case protoreflect.FloatKind:
+				fi.FieldType = sliceText + "float32"
+			case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+				fi.FieldType = sliceText + "int64"
+				// The JSON mapping for 64 bit integers encodes them as strings
+				fi.JsonString = fd.Cardinality() != protoreflect.Repeated
+			case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+				fi.FieldType = sliceText + "uint64"
+				fi.JsonString = fd.Cardinality() != protoreflect.Repeated
 			default:
 				fi.FieldType = sliceText + k.String()
 			}
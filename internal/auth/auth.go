@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/scopes"
 	"github.com/hashicorp/boundary/internal/gen/controller/tokens"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/perms"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
@@ -88,7 +89,8 @@ type verifier struct {
 // NewVerifierContext creates a context that carries a verifier object from the
 // HTTP handlers to the gRPC service handlers. It should only be created in the
 // HTTP handler and should exist for every request that reaches the service
-// handlers.
+// handlers. Once the request is verified the context also carries its
+// principal, which is recorded with any oplog entries written by the request.
 func NewVerifierContext(ctx context.Context,
 	logger hclog.Logger,
 	iamRepoFn common.IamRepoFactory,
@@ -96,6 +98,7 @@ func NewVerifierContext(ctx context.Context,
 	serversRepoFn common.ServersRepoFactory,
 	kms *kms.Kms,
 	requestInfo RequestInfo) context.Context {
+	ctx = oplog.NewPrincipalContext(ctx)
 	return context.WithValue(ctx, verifierKey, &verifier{
 		logger:          logger,
 		iamRepoFn:       iamRepoFn,
@@ -142,6 +145,7 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 		}
 		ret.UserId = v.requestInfo.userIdOverride
 		ret.Error = nil
+		oplog.SetPrincipal(ctx, oplog.Principal{UserId: ret.UserId})
 		return
 	}
 
@@ -187,6 +191,7 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 	}
 
 	ret.Error = nil
+	oplog.SetPrincipal(ctx, oplog.Principal{UserId: ret.UserId, AuthTokenId: ret.AuthTokenId})
 	return
 }
 
@@ -493,13 +498,6 @@ func (v *verifier) decryptToken() {
 			return
 		}
 
-		tokenWrapper, err := v.kms.GetWrapper(v.ctx, at.GetScopeId(), kms.KeyPurposeTokens)
-		if err != nil {
-			v.logger.Warn("decrypt bearer token: unable to get wrapper for tokens; continuing as anonymous user", "error", err)
-			v.requestInfo.TokenFormat = AuthTokenTypeUnknown
-			return
-		}
-
 		version := v.requestInfo.EncryptedToken[0:len(globals.ServiceTokenV1)]
 		switch version {
 		case globals.ServiceTokenV1:
@@ -522,6 +520,15 @@ func (v *verifier) decryptToken() {
 			return
 		}
 
+		// The token may have been encrypted with a key version created by a
+		// rotation on another controller, so make sure it's loaded
+		tokenWrapper, err := v.kms.GetWrapper(v.ctx, at.GetScopeId(), kms.KeyPurposeTokens, kms.WithKeyId(blobInfo.GetKeyInfo().GetKeyID()))
+		if err != nil {
+			v.logger.Warn("decrypt bearer token: unable to get wrapper for tokens; continuing as anonymous user", "error", err)
+			v.requestInfo.TokenFormat = AuthTokenTypeUnknown
+			return
+		}
+
 		s1Bytes, err := tokenWrapper.Decrypt(v.ctx, blobInfo, []byte(v.requestInfo.PublicId))
 		if err != nil {
 			v.logger.Trace("decrypt bearer token: error decrypting encrypted token; continuing as anonymous user", "error", err)
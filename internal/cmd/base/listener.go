@@ -1,11 +1,14 @@
 package base
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
@@ -29,6 +32,79 @@ type ServerListener struct {
 	HTTPServer   *http.Server
 	GrpcServer   *grpc.Server
 	ALPNListener net.Listener
+
+	// tlsReloadFunc reloads the certificate of the TLS configuration, if the
+	// listener does not disable TLS.
+	tlsReloadFunc reloadutil.ReloadFunc
+}
+
+// tlsProtos are the protos registered with the TLS configuration of a listener
+// which does not disable TLS.
+var tlsProtos = []string{"", "http/1.1", "h2"}
+
+// tlsCipherSuites are the cipher suites of every listener.
+// TODO: Way to configure
+var tlsCipherSuites = []uint16{
+	// 1.3
+	tls.TLS_AES_128_GCM_SHA256,
+	tls.TLS_AES_256_GCM_SHA384,
+	tls.TLS_CHACHA20_POLY1305_SHA256,
+	// 1.2
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+}
+
+// listenerTLSConfig returns the TLS configuration of a listener which does
+// not disable TLS, and a func reloading its certificate.
+func listenerTLSConfig(l *configutil.Listener, props map[string]string, ui cli.Ui) (*tls.Config, reloadutil.ReloadFunc, error) {
+	// Don't request a client cert unless they've explicitly configured it to do
+	// so
+	if !l.TLSRequireAndVerifyClientCert {
+		l.TLSDisableClientCerts = true
+	}
+	return listenerutil.TLSConfig(l, props, ui)
+}
+
+// TLSConfigChanged returns true if the TLS settings of the listener configs
+// differ.
+func TLSConfigChanged(old, new *configutil.Listener) bool {
+	return old.TLSDisable != new.TLSDisable ||
+		old.TLSCertFile != new.TLSCertFile ||
+		old.TLSKeyFile != new.TLSKeyFile ||
+		old.TLSMinVersion != new.TLSMinVersion ||
+		old.TLSCipherSuitesRaw != new.TLSCipherSuitesRaw ||
+		old.TLSPreferServerCipherSuites != new.TLSPreferServerCipherSuites ||
+		old.TLSRequireAndVerifyClientCert != new.TLSRequireAndVerifyClientCert ||
+		old.TLSClientCAFile != new.TLSClientCAFile ||
+		!reflect.DeepEqual(old.TLSDisableClientCertsRaw, new.TLSDisableClientCertsRaw)
+}
+
+// ReloadTLS replaces the TLS configuration of a listener which does not
+// disable TLS with the one of l, e.g. after its certificate or minimum TLS
+// version changed. The protos of the listener are registered again with the
+// new configuration, closing their previous listeners, and the new listeners
+// are returned by proto; the server of the listener must serve them.
+func (ln *ServerListener) ReloadTLS(l *configutil.Listener, ui cli.Ui) (map[string]net.Listener, error) {
+	if ln.Config.TLSDisable || l.TLSDisable {
+		return nil, errors.New("enabling or disabling tls requires a restart")
+	}
+	l.TLSCipherSuites = tlsCipherSuites
+	tlsConfig, reloadFunc, err := listenerTLSConfig(l, map[string]string{}, ui)
+	if err != nil {
+		return nil, fmt.Errorf("error reloading tls configuration: %w", err)
+	}
+	listeners := make(map[string]net.Listener, len(tlsProtos))
+	for _, proto := range tlsProtos {
+		ln.Mux.UnregisterProto(proto)
+		l, err := ln.Mux.RegisterProto(proto, tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error registering proto %q with reloaded tls configuration: %w", proto, err)
+		}
+		listeners[proto] = l
+	}
+	ln.tlsReloadFunc = reloadFunc
+	return listeners, nil
 }
 
 type WorkerAuthInfo struct {
@@ -70,6 +146,8 @@ func tcpListenerFactory(l *configutil.Listener, logger hclog.Logger, ui cli.Ui)
 			l.Address = "127.0.0.1:9201"
 		case "proxy":
 			l.Address = "127.0.0.1:9202"
+		case "metrics":
+			l.Address = "127.0.0.1:9203"
 		default:
 			l.Address = "127.0.0.1:9200"
 		}
@@ -83,6 +161,8 @@ func tcpListenerFactory(l *configutil.Listener, logger hclog.Logger, ui cli.Ui)
 				port = "9201"
 			case "proxy":
 				port = "9202"
+			case "metrics":
+				port = "9203"
 			default:
 				port = "9200"
 			}
@@ -147,24 +227,15 @@ func tcpListenerFactory(l *configutil.Listener, logger hclog.Logger, ui cli.Ui)
 		return alpnMux, props, nil, nil
 	}
 
-	// Don't request a client cert unless they've explicitly configured it to do
-	// so
-	if !l.TLSRequireAndVerifyClientCert {
-		l.TLSDisableClientCerts = true
-	}
-	tlsConfig, reloadFunc, err := listenerutil.TLSConfig(l, props, ui)
+	tlsConfig, reloadFunc, err := listenerTLSConfig(l, props, ui)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	// Register no proto, "http/1.1", and "h2", with same TLS config
-	if _, err = alpnMux.RegisterProto("", tlsConfig); err != nil {
-		return nil, nil, nil, err
-	}
-	if _, err = alpnMux.RegisterProto("http/1.1", tlsConfig); err != nil {
-		return nil, nil, nil, err
-	}
-	if _, err = alpnMux.RegisterProto("h2", tlsConfig); err != nil {
-		return nil, nil, nil, err
+	for _, proto := range tlsProtos {
+		if _, err = alpnMux.RegisterProto(proto, tlsConfig); err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	return alpnMux, props, reloadFunc, nil
@@ -236,3 +307,61 @@ func (ln TCPKeepAliveListener) Accept() (net.Conn, error) {
 	}
 	return tc, nil
 }
+
+// ConfigureHTTPServer sets up an HTTP server serving handler on the listener,
+// with the HTTP timeouts of the listener's configuration. Requests are served
+// with cancelCtx as their base context. It returns the functions which start
+// serving, to be called once all listeners have been configured.
+func ConfigureHTTPServer(ln *ServerListener, handler http.Handler, cancelCtx context.Context, logger hclog.Logger) ([]func(), error) {
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		IdleTimeout:       5 * time.Minute,
+		ErrorLog:          logger.StandardLogger(nil),
+		BaseContext: func(net.Listener) context.Context {
+			return cancelCtx
+		},
+	}
+	ln.HTTPServer = server
+
+	if ln.Config.HTTPReadHeaderTimeout > 0 {
+		server.ReadHeaderTimeout = ln.Config.HTTPReadHeaderTimeout
+	}
+	if ln.Config.HTTPReadTimeout > 0 {
+		server.ReadTimeout = ln.Config.HTTPReadTimeout
+	}
+	if ln.Config.HTTPWriteTimeout > 0 {
+		server.WriteTimeout = ln.Config.HTTPWriteTimeout
+	}
+	if ln.Config.HTTPIdleTimeout > 0 {
+		server.IdleTimeout = ln.Config.HTTPIdleTimeout
+	}
+
+	var servers []func()
+	switch ln.Config.TLSDisable {
+	case true:
+		l, err := ln.Mux.RegisterProto(alpnmux.NoProto, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error getting non-tls listener: %w", err)
+		}
+		if l == nil {
+			return nil, errors.New("could not get non-tls listener")
+		}
+		servers = append(servers, func() {
+			go server.Serve(l)
+		})
+
+	default:
+		for _, v := range []string{"", "http/1.1", "h2"} {
+			l := ln.Mux.GetListener(v)
+			if l == nil {
+				return nil, fmt.Errorf("could not get tls proto %q listener", v)
+			}
+			servers = append(servers, func() {
+				go server.Serve(l)
+			})
+		}
+	}
+	return servers, nil
+}
@@ -0,0 +1,18 @@
+package base
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/boundary/internal/metrics"
+	"github.com/hashicorp/go-hclog"
+)
+
+// ConfigureMetricsListener sets up an HTTP server serving Prometheus metrics on
+// a listener with the "metrics" purpose. It returns the functions which start
+// serving, to be called once all listeners have been configured.
+func ConfigureMetricsListener(ln *ServerListener, cancelCtx context.Context, logger hclog.Logger) ([]func(), error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	return ConfigureHTTPServer(ln, mux, cancelCtx, logger)
+}
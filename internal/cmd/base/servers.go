@@ -3,7 +3,7 @@ package base
 import (
 	"context"
 	"crypto/rand"
-	"crypto/tls"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
@@ -82,9 +82,16 @@ type Server struct {
 	DevTargetSessionConnectionLimit int
 
 	DatabaseUrl            string
+	DatabaseReplicaUrls    []string
 	DevDatabaseCleanupFunc func() error
 
-	Database *gorm.DB
+	Database         *gorm.DB
+	DatabaseReplicas []*gorm.DB
+
+	// DatabaseConnector and DatabaseReplicaConnectors make the connections
+	// to the database and its read replicas; see ReloadDatabaseUrls.
+	DatabaseConnector         *db.Connector
+	DatabaseReplicaConnectors []*db.Connector
 }
 
 func NewServer(cmd *Command) *Server {
@@ -273,17 +280,7 @@ func (b *Server) SetupListeners(ui cli.Ui, config *configutil.SharedConfig, allo
 		}
 
 		// Override for now
-		// TODO: Way to configure
-		lnConfig.TLSCipherSuites = []uint16{
-			// 1.3
-			tls.TLS_AES_128_GCM_SHA256,
-			tls.TLS_AES_256_GCM_SHA384,
-			tls.TLS_CHACHA20_POLY1305_SHA256,
-			// 1.2
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
-		}
+		lnConfig.TLSCipherSuites = tlsCipherSuites
 
 		lnMux, props, reloadFunc, err := NewListener(lnConfig, b.Logger, ui)
 		if err != nil {
@@ -303,9 +300,18 @@ func (b *Server) SetupListeners(ui cli.Ui, config *configutil.SharedConfig, allo
 			}
 		}
 
+		ln := &ServerListener{
+			Mux:           lnMux,
+			Config:        lnConfig,
+			tlsReloadFunc: reloadFunc,
+		}
 		if reloadFunc != nil {
+			// The listener's func is looked up when reloading, as it is
+			// replaced when its TLS configuration is reloaded
 			relSlice := b.ReloadFuncs["listener|"+lnConfig.Type]
-			relSlice = append(relSlice, reloadFunc)
+			relSlice = append(relSlice, func() error {
+				return ln.tlsReloadFunc()
+			})
 			b.ReloadFuncs["listener|"+lnConfig.Type] = relSlice
 		}
 
@@ -320,10 +326,7 @@ func (b *Server) SetupListeners(ui cli.Ui, config *configutil.SharedConfig, allo
 		}
 		props["max_request_duration"] = lnConfig.MaxRequestDuration.String()
 
-		b.Listeners = append(b.Listeners, &ServerListener{
-			Mux:    lnMux,
-			Config: lnConfig,
-		})
+		b.Listeners = append(b.Listeners, ln)
 
 		props["purpose"] = strings.Join(lnConfig.Purpose, ",")
 
@@ -429,16 +432,71 @@ func (b *Server) RunShutdownFuncs() error {
 }
 
 func (b *Server) ConnectToDatabase(dialect string) error {
-	dbase, err := gorm.Open(dialect, b.DatabaseUrl)
+	dbase, connector, err := openDatabase(dialect, b.DatabaseUrl)
 	if err != nil {
 		return fmt.Errorf("unable to create db object with dialect %s: %w", dialect, err)
 	}
 
 	b.Database = dbase
+	b.DatabaseConnector = connector
+	for _, url := range b.DatabaseReplicaUrls {
+		replica, connector, err := openDatabase(dialect, url)
+		if err != nil {
+			return fmt.Errorf("unable to create read replica db object with dialect %s: %w", dialect, err)
+		}
+		b.DatabaseReplicas = append(b.DatabaseReplicas, replica)
+		b.DatabaseReplicaConnectors = append(b.DatabaseReplicaConnectors, connector)
+	}
 	if os.Getenv("BOUNDARY_DISABLE_GORM_FORMATTER") == "" {
 		gorm.LogFormatter = db.GetGormLogFormatter(b.Logger)
 		b.Database.SetLogger(db.GetGormLogger(b.Logger))
+		for _, replica := range b.DatabaseReplicas {
+			replica.SetLogger(db.GetGormLogger(b.Logger))
+		}
+	}
+	return nil
+}
+
+// openDatabase opens a database whose connections are made by a connector, so
+// that its URL can be changed, e.g. when the password in it is rotated.
+func openDatabase(dialect, url string) (*gorm.DB, *db.Connector, error) {
+	if dialect != "postgres" {
+		dbase, err := gorm.Open(dialect, url)
+		return dbase, nil, err
 	}
+	connector, err := db.NewConnector(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlDb := sql.OpenDB(connector)
+	dbase, err := gorm.Open(dialect, sqlDb)
+	if err != nil {
+		sqlDb.Close()
+		return nil, nil, err
+	}
+	return dbase, connector, nil
+}
+
+// ReloadDatabaseUrls changes the URLs new connections to the database and its
+// read replicas are made with, e.g. after the password in them is rotated.
+// Adding or removing read replicas is not supported.
+func (b *Server) ReloadDatabaseUrls(url string, replicaUrls []string) error {
+	if b.DatabaseConnector == nil {
+		return errors.New("database urls cannot be reloaded")
+	}
+	if len(replicaUrls) != len(b.DatabaseReplicaConnectors) {
+		return fmt.Errorf("changing the number of read replicas from %d to %d requires a restart", len(b.DatabaseReplicaConnectors), len(replicaUrls))
+	}
+	if err := b.DatabaseConnector.SetUrl(url); err != nil {
+		return fmt.Errorf("error reloading database url: %w", err)
+	}
+	for i, replicaUrl := range replicaUrls {
+		if err := b.DatabaseReplicaConnectors[i].SetUrl(replicaUrl); err != nil {
+			return fmt.Errorf("error reloading database read replica url: %w", err)
+		}
+	}
+	b.DatabaseUrl = url
+	b.DatabaseReplicaUrls = replicaUrls
 	return nil
 }
 
@@ -590,6 +648,9 @@ func (b *Server) DestroyDevDatabase() error {
 	if b.Database != nil {
 		b.Database.Close()
 	}
+	for _, replica := range b.DatabaseReplicas {
+		replica.Close()
+	}
 	if b.DevDatabaseCleanupFunc != nil {
 		return b.DevDatabaseCleanupFunc()
 	}
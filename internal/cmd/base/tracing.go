@@ -0,0 +1,130 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/propagators"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"google.golang.org/grpc/credentials"
+)
+
+const tracingShutdownTimeout = 5 * time.Second
+
+// SetupTracing configures the global OpenTelemetry tracer provider from the
+// tracing stanza of the config. If conf is nil tracing is left disabled.
+func (b *Server) SetupTracing(conf *config.Tracing) error {
+	if conf == nil {
+		return nil
+	}
+
+	exporter, closer, err := newSpanExporter(conf)
+	if err != nil {
+		return fmt.Errorf("Error initializing tracing: %w", err)
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if conf.SampleRatio != nil {
+		if *conf.SampleRatio < 0 || *conf.SampleRatio > 1 {
+			return fmt.Errorf("Error initializing tracing: sample_ratio must be between 0 and 1")
+		}
+		sampler = sdktrace.TraceIDRatioBased(*conf.SampleRatio)
+	}
+
+	serviceName := conf.ServiceName
+	if serviceName == "" {
+		serviceName = "boundary"
+	}
+	attrs := []label.KeyValue{semconv.ServiceNameKey.String(serviceName)}
+	if ver := version.Get().Version; ver != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(ver))
+	}
+
+	processor := sdktrace.NewBatchSpanProcessor(exporter)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.ParentBased(sampler)}),
+		sdktrace.WithResource(resource.New(attrs...)),
+		sdktrace.WithSpanProcessor(processor),
+	)
+	global.SetTracerProvider(provider)
+	global.SetTextMapPropagator(otel.NewCompositeTextMapPropagator(propagators.TraceContext{}, propagators.Baggage{}))
+
+	b.ShutdownFuncs = append(b.ShutdownFuncs, func() error {
+		// Flush any queued spans before the exporter goes away
+		processor.Shutdown()
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := exporter.Shutdown(ctx); err != nil {
+			return fmt.Errorf("error shutting down trace exporter: %w", err)
+		}
+		if closer != nil {
+			return closer.Close()
+		}
+		return nil
+	})
+
+	b.InfoKeys = append(b.InfoKeys, "tracing")
+	b.Info["tracing"] = conf.Exporter
+	return nil
+}
+
+// newSpanExporter returns the exporter named in the config. If the exporter
+// holds a resource which must be released on shutdown it is returned as the
+// io.Closer.
+func newSpanExporter(conf *config.Tracing) (export.SpanExporter, io.Closer, error) {
+	switch conf.Exporter {
+	case "otlp":
+		opts := []otlp.ExporterOption{}
+		if conf.OtlpAddress != "" {
+			opts = append(opts, otlp.WithAddress(conf.OtlpAddress))
+		}
+		if conf.OtlpInsecure {
+			opts = append(opts, otlp.WithInsecure())
+		} else {
+			opts = append(opts, otlp.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+		}
+		exp, err := otlp.NewExporter(opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating otlp exporter: %w", err)
+		}
+		return exp, nil, nil
+
+	case "stdout":
+		exp, err := stdout.NewExporter(stdout.WithWriter(os.Stdout), stdout.WithoutMetricExport())
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating stdout exporter: %w", err)
+		}
+		return exp, nil, nil
+
+	case "file":
+		if conf.FilePath == "" {
+			return nil, nil, fmt.Errorf("file_path must be set for the file exporter")
+		}
+		f, err := os.OpenFile(conf.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening trace file: %w", err)
+		}
+		exp, err := stdout.NewExporter(stdout.WithWriter(f), stdout.WithoutMetricExport())
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("error creating file exporter: %w", err)
+		}
+		return exp, f, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown exporter %q, must be one of \"otlp\", \"stdout\" or \"file\"", conf.Exporter)
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/commands/accounts"
+	"github.com/hashicorp/boundary/internal/cmd/commands/agent"
+	"github.com/hashicorp/boundary/internal/cmd/commands/apply"
 	"github.com/hashicorp/boundary/internal/cmd/commands/authenticate"
 	"github.com/hashicorp/boundary/internal/cmd/commands/authmethods"
 	"github.com/hashicorp/boundary/internal/cmd/commands/authtokens"
@@ -15,16 +17,22 @@ import (
 	"github.com/hashicorp/boundary/internal/cmd/commands/database"
 	"github.com/hashicorp/boundary/internal/cmd/commands/dev"
 	"github.com/hashicorp/boundary/internal/cmd/commands/groups"
+	"github.com/hashicorp/boundary/internal/cmd/commands/history"
 	"github.com/hashicorp/boundary/internal/cmd/commands/hostcatalogs"
 	"github.com/hashicorp/boundary/internal/cmd/commands/hosts"
 	"github.com/hashicorp/boundary/internal/cmd/commands/hostsets"
+	"github.com/hashicorp/boundary/internal/cmd/commands/jobs"
+	"github.com/hashicorp/boundary/internal/cmd/commands/keys"
+	"github.com/hashicorp/boundary/internal/cmd/commands/reports"
 	"github.com/hashicorp/boundary/internal/cmd/commands/roles"
 	"github.com/hashicorp/boundary/internal/cmd/commands/scopes"
 	"github.com/hashicorp/boundary/internal/cmd/commands/server"
 	"github.com/hashicorp/boundary/internal/cmd/commands/sessions"
 	"github.com/hashicorp/boundary/internal/cmd/commands/targets"
+	"github.com/hashicorp/boundary/internal/cmd/commands/tunnels"
 	"github.com/hashicorp/boundary/internal/cmd/commands/users"
 	"github.com/hashicorp/boundary/internal/cmd/commands/version"
+	"github.com/hashicorp/boundary/internal/cmd/commands/workers"
 
 	"github.com/mitchellh/cli"
 )
@@ -41,6 +49,7 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 					ShutdownCh: base.MakeShutdownCh(),
 				}),
 				SighupCh:  MakeSighupCh(),
+				SigUSR1Ch: MakeSigUSR1Ch(),
 				SigUSR2Ch: MakeSigUSR2Ch(),
 			}, nil
 		},
@@ -60,6 +69,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 			}, nil
 		},
 
+		"agent": func() (cli.Command, error) {
+			return &agent.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+
 		"authenticate": func() (cli.Command, error) {
 			return &authenticate.Command{
 				Command: base.NewCommand(ui),
@@ -131,6 +146,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 			}, nil
 		},
 
+		"apply": func() (cli.Command, error) {
+			return &apply.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+
 		"auth-methods": func() (cli.Command, error) {
 			return &authmethods.Command{
 				Command: base.NewCommand(ui),
@@ -226,6 +247,11 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "get-token",
 			}, nil
 		},
+		"config validate": func() (cli.Command, error) {
+			return &config.ValidateCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
 		"config autocomplete": func() (cli.Command, error) {
 			return &config.AutocompleteCommand{
 				Command: base.NewCommand(ui),
@@ -257,6 +283,11 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "http",
 			}, nil
 		},
+		"connect socks": func() (cli.Command, error) {
+			return &connect.SocksCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
 		"connect ssh": func() (cli.Command, error) {
 			return &connect.Command{
 				Command: base.NewCommand(ui),
@@ -281,11 +312,72 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Command: base.NewCommand(ui),
 			}, nil
 		},
+		"database export": func() (cli.Command, error) {
+			return &database.ExportCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"database import": func() (cli.Command, error) {
+			return &database.ImportCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
 		"database init": func() (cli.Command, error) {
 			return &database.InitCommand{
 				Command: base.NewCommand(ui),
 			}, nil
 		},
+		"database migrate": func() (cli.Command, error) {
+			return &database.MigrateCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"database oplog": func() (cli.Command, error) {
+			return &database.OplogCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"database oplog archives": func() (cli.Command, error) {
+			return &database.OplogArchivesCommand{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"database oplog archives list": func() (cli.Command, error) {
+			return &database.OplogArchivesCommand{
+				Command: base.NewCommand(ui),
+				Func:    "list",
+			}, nil
+		},
+		"database oplog archives restore": func() (cli.Command, error) {
+			return &database.OplogArchivesCommand{
+				Command: base.NewCommand(ui),
+				Func:    "restore",
+			}, nil
+		},
+		"database oplog archives release": func() (cli.Command, error) {
+			return &database.OplogArchivesCommand{
+				Command: base.NewCommand(ui),
+				Func:    "release",
+			}, nil
+		},
+		"database oplog list": func() (cli.Command, error) {
+			return &database.OplogCommand{
+				Command: base.NewCommand(ui),
+				Func:    "list",
+			}, nil
+		},
+		"database oplog show": func() (cli.Command, error) {
+			return &database.OplogCommand{
+				Command: base.NewCommand(ui),
+				Func:    "show",
+			}, nil
+		},
+		"database oplog verify": func() (cli.Command, error) {
+			return &database.OplogCommand{
+				Command: base.NewCommand(ui),
+				Func:    "verify",
+			}, nil
+		},
 
 		"groups": func() (cli.Command, error) {
 			return &groups.Command{
@@ -341,6 +433,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 			}, nil
 		},
 
+		"history": func() (cli.Command, error) {
+			return &history.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+
 		"host-catalogs": func() (cli.Command, error) {
 			return &hostcatalogs.Command{
 				Command: base.NewCommand(ui),
@@ -503,6 +601,60 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 			}, nil
 		},
 
+		"jobs": func() (cli.Command, error) {
+			return &jobs.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"jobs read": func() (cli.Command, error) {
+			return &jobs.Command{
+				Command: base.NewCommand(ui),
+				Func:    "read",
+			}, nil
+		},
+		"jobs list": func() (cli.Command, error) {
+			return &jobs.Command{
+				Command: base.NewCommand(ui),
+				Func:    "list",
+			}, nil
+		},
+
+		"keys": func() (cli.Command, error) {
+			return &keys.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"keys list": func() (cli.Command, error) {
+			return &keys.Command{
+				Command: base.NewCommand(ui),
+				Func:    "list",
+			}, nil
+		},
+		"keys rotate": func() (cli.Command, error) {
+			return &keys.Command{
+				Command: base.NewCommand(ui),
+				Func:    "rotate",
+			}, nil
+		},
+		"keys destroy": func() (cli.Command, error) {
+			return &keys.Command{
+				Command: base.NewCommand(ui),
+				Func:    "destroy",
+			}, nil
+		},
+
+		"reports": func() (cli.Command, error) {
+			return &reports.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"reports usage": func() (cli.Command, error) {
+			return &reports.Command{
+				Command: base.NewCommand(ui),
+				Func:    "usage",
+			}, nil
+		},
+
 		"roles": func() (cli.Command, error) {
 			return &roles.Command{
 				Command: base.NewCommand(ui),
@@ -676,6 +828,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "create",
 			}, nil
 		},
+		"targets create udp": func() (cli.Command, error) {
+			return &targets.UdpCommand{
+				Command: base.NewCommand(ui),
+				Func:    "create",
+			}, nil
+		},
 		"targets update": func() (cli.Command, error) {
 			return &targets.Command{
 				Command: base.NewCommand(ui),
@@ -688,6 +846,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "update",
 			}, nil
 		},
+		"targets update udp": func() (cli.Command, error) {
+			return &targets.UdpCommand{
+				Command: base.NewCommand(ui),
+				Func:    "update",
+			}, nil
+		},
 		"targets add-host-sets": func() (cli.Command, error) {
 			return &targets.Command{
 				Command: base.NewCommand(ui),
@@ -707,6 +871,30 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 			}, nil
 		},
 
+		"tunnels": func() (cli.Command, error) {
+			return &tunnels.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"tunnels list": func() (cli.Command, error) {
+			return &tunnels.Command{
+				Command: base.NewCommand(ui),
+				Func:    "list",
+			}, nil
+		},
+		"tunnels up": func() (cli.Command, error) {
+			return &tunnels.Command{
+				Command: base.NewCommand(ui),
+				Func:    "up",
+			}, nil
+		},
+		"tunnels down": func() (cli.Command, error) {
+			return &tunnels.Command{
+				Command: base.NewCommand(ui),
+				Func:    "down",
+			}, nil
+		},
+
 		"users": func() (cli.Command, error) {
 			return &users.Command{
 				Command: base.NewCommand(ui),
@@ -760,6 +948,30 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) {
 				Func:    "remove-accounts",
 			}, nil
 		},
+
+		"workers": func() (cli.Command, error) {
+			return &workers.Command{
+				Command: base.NewCommand(ui),
+			}, nil
+		},
+		"workers read": func() (cli.Command, error) {
+			return &workers.Command{
+				Command: base.NewCommand(ui),
+				Func:    "read",
+			}, nil
+		},
+		"workers list": func() (cli.Command, error) {
+			return &workers.Command{
+				Command: base.NewCommand(ui),
+				Func:    "list",
+			}, nil
+		},
+		"workers set-operational-state": func() (cli.Command, error) {
+			return &workers.Command{
+				Command: base.NewCommand(ui),
+				Func:    "set-operational-state",
+			}, nil
+		},
 	}
 }
 
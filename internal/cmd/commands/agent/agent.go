@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+
+	flagProfile  string
+	flagSocket   string
+	flagLogLevel string
+}
+
+func (c *Command) Synopsis() string {
+	return "Run a background agent managing tunnels to targets"
+}
+
+func (c *Command) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary agent [options]",
+		"",
+		"  Run an agent which manages the named tunnels to targets declared in a profile file. Example:",
+		"",
+		"      $ boundary agent -profile ~/.boundary/tunnels.hcl",
+		"",
+		"  A profile declares tunnels as follows:",
+		"",
+		`      tunnel "web" {`,
+		`        target_id   = "ttcp_1234567890"`,
+		`        listen_port = 8080`,
+		`        auto_start  = true`,
+		`      }`,
+		"",
+		`  Each tunnel listens on a local port, like "boundary connect", and proxies connections through a session against its target. The agent uses the auth token available when it starts to authorize sessions; a new session is authorized before the current one expires, and connections to the worker are retried when they fail.`,
+		"",
+		`  The tunnels are managed with the "boundary tunnels" commands, which talk to the agent over a Unix socket only the current user can access.`,
+		"",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient)
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "profile",
+		Target: &c.flagProfile,
+		EnvVar: "BOUNDARY_AGENT_PROFILE",
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the profile file declaring the tunnels.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "socket",
+		Target:     &c.flagSocket,
+		EnvVar:     EnvAgentSocket,
+		Completion: complete.PredictFiles("*"),
+		Usage:      "Path of the Unix socket the agent listens on for commands. Defaults to a per-user path in the temporary directory.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "log-level",
+		Target:     &c.flagLogLevel,
+		Default:    "info",
+		EnvVar:     "BOUNDARY_LOG_LEVEL",
+		Completion: complete.PredictSet("trace", "debug", "info", "warn", "err"),
+		Usage:      `Log verbosity level. Supported values (in order of more detail to less) are "trace", "debug", "info", "warn", and "err".`,
+	})
+
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.flagProfile == "" {
+		c.UI.Error("Missing required parameter -profile")
+		return 1
+	}
+	profile, err := LoadProfile(strings.TrimSpace(c.flagProfile))
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error loading profile: %w", err).Error())
+		return 1
+	}
+	if c.flagSocket == "" {
+		c.flagSocket = DefaultSocketPath()
+	}
+
+	level := hclog.LevelFromString(c.flagLogLevel)
+	if level == hclog.NoLevel {
+		c.UI.Error(fmt.Sprintf("Unknown log level %q", c.flagLogLevel))
+		return 1
+	}
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   "agent",
+		Level:  level,
+		Output: os.Stderr,
+	})
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+	if client.Token() == "" {
+		c.UI.Warn(`No auth token found; sessions can only be authorized against targets open to anonymous users. Run "boundary authenticate" first to use a token.`)
+	}
+	targetClient := targets.NewClient(client)
+	authorize := func(ctx context.Context, targetId, hostId string) (string, error) {
+		var opts []targets.Option
+		if hostId != "" {
+			opts = append(opts, targets.WithHostId(hostId))
+		}
+		sar, err := targetClient.AuthorizeSession(ctx, targetId, opts...)
+		if err != nil {
+			if apiErr := api.AsServerError(err); apiErr != nil {
+				return "", errors.New(base.PrintApiError(apiErr))
+			}
+			return "", err
+		}
+		return sar.GetItem().(*targets.SessionAuthorization).AuthorizationToken, nil
+	}
+
+	ln, err := ListenControl(c.flagSocket)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error listening on control socket: %w", err).Error())
+		return 1
+	}
+
+	agent := NewAgent(c.Context, profile, authorize, logger)
+	srv := &http.Server{
+		Handler: agent.ControlHandler(),
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			logger.Error("error serving control api", "error", err)
+		}
+	}()
+
+	c.UI.Output(fmt.Sprintf("==> Boundary agent listening on %s with %d tunnel(s)", c.flagSocket, len(profile.Tunnels)))
+
+	for _, t := range profile.Tunnels {
+		if !t.AutoStart {
+			continue
+		}
+		if _, err := agent.Up(t.Name); err != nil {
+			logger.Error("error bringing tunnel up", "tunnel", t.Name, "error", err)
+		}
+	}
+
+	<-c.Context.Done()
+
+	c.UI.Output("==> Boundary agent shutdown triggered")
+	if err := srv.Close(); err != nil {
+		c.UI.Error(fmt.Errorf("Error closing control socket: %w", err).Error())
+	}
+	agent.Shutdown()
+	return 0
+}
@@ -0,0 +1,238 @@
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	targetspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	"github.com/hashicorp/go-hclog"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    []*TunnelConfig
+		wantErr string
+	}{
+		{
+			name: "valid",
+			profile: `
+tunnel "web" {
+	target_id = "ttcp_1234567890"
+	listen_port = 8080
+	auto_start = true
+}
+
+tunnel "db" {
+	target_id = "ttcp_0987654321"
+	host_id = "hst_1234567890"
+	listen_addr = "::1"
+}
+`,
+			want: []*TunnelConfig{
+				{Name: "web", TargetId: "ttcp_1234567890", ListenAddr: "127.0.0.1", ListenPort: 8080, AutoStart: true},
+				{Name: "db", TargetId: "ttcp_0987654321", HostId: "hst_1234567890", ListenAddr: "::1"},
+			},
+		},
+		{
+			name:    "duplicate",
+			profile: `tunnel "web" { target_id = "ttcp_1" } tunnel "web" { target_id = "ttcp_2" }`,
+			wantErr: `tunnel "web" declared more than once`,
+		},
+		{
+			name:    "no target",
+			profile: `tunnel "web" { listen_port = 8080 }`,
+			wantErr: `tunnel "web" has no target_id`,
+		},
+		{
+			name:    "bad listen addr",
+			profile: `tunnel "web" { target_id = "ttcp_1" listen_addr = "localhost" }`,
+			wantErr: `tunnel "web" has an invalid listen_addr "localhost"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := ParseProfile(tt.profile)
+			if tt.wantErr != "" {
+				require.Error(err)
+				assert.Equal(tt.wantErr, err.Error())
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.want, got.Tunnels)
+		})
+	}
+}
+
+// testAuthorizer authorizes sessions with certificates valid for lifetime
+// and counts the authorizations.
+type testAuthorizer struct {
+	lifetime time.Duration
+
+	l     sync.Mutex
+	count int
+	err   error
+}
+
+func (a *testAuthorizer) authorize(ctx context.Context, targetId, hostId string) (string, error) {
+	a.l.Lock()
+	defer a.l.Unlock()
+	if a.err != nil {
+		return "", a.err
+	}
+	a.count++
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(a.count)),
+		Subject:      pkix.Name{CommonName: "s_test"},
+		DNSNames:     []string{"s_test"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(a.lifetime),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	if err != nil {
+		return "", err
+	}
+	data := &targetspb.SessionAuthorizationData{
+		SessionId:       fmt.Sprintf("s_%010d", a.count),
+		TargetId:        targetId,
+		ConnectionLimit: -1,
+		Certificate:     cert,
+		PrivateKey:      priv,
+		WorkerInfo:      []*targetspb.WorkerInfo{{Address: "127.0.0.1:1"}},
+	}
+	marshaled, err := proto.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return base58.FastBase58Encoding(marshaled), nil
+}
+
+func (a *testAuthorizer) authorized() int {
+	a.l.Lock()
+	defer a.l.Unlock()
+	return a.count
+}
+
+func TestAgent_Control(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	profile, err := ParseProfile(`
+tunnel "web" {
+	target_id = "ttcp_1234567890"
+}
+
+tunnel "db" {
+	target_id = "ttcp_0987654321"
+}
+`)
+	require.NoError(err)
+
+	dir, err := ioutil.TempDir("", "boundary-agent-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "agent.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	authz := &testAuthorizer{lifetime: time.Hour}
+	agent := NewAgent(ctx, profile, authz.authorize, hclog.NewNullLogger())
+	defer agent.Shutdown()
+
+	ln, err := ListenControl(socket)
+	require.NoError(err)
+	srv := &http.Server{Handler: agent.ControlHandler()}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	info, err := os.Stat(socket)
+	require.NoError(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// A second agent cannot use the socket
+	_, err = ListenControl(socket)
+	assert.Error(err)
+
+	client := NewControlClient(socket)
+	list, err := client.List(ctx)
+	require.NoError(err)
+	require.Len(list, 2)
+	assert.Equal("web", list[0].Name)
+	assert.Equal(StatusDown, list[0].Status)
+	assert.Nil(list[0].Session)
+
+	status, err := client.Up(ctx, "web")
+	require.NoError(err)
+	assert.Equal(StatusUp, status.Status)
+	require.NotNil(status.Session)
+	assert.Equal("s_0000000001", status.Session.SessionId)
+	assert.Equal("127.0.0.1", status.Session.Address)
+	assert.NotZero(status.Session.Port)
+	require.NotNil(status.Connections)
+	assert.Equal(int32(-1), status.Connections.ConnectionsLeft)
+
+	// Bringing a tunnel up again keeps its session
+	status, err = client.Up(ctx, "web")
+	require.NoError(err)
+	assert.Equal("s_0000000001", status.Session.SessionId)
+	assert.Equal(1, authz.authorized())
+
+	_, err = client.Up(ctx, "nope")
+	require.Error(err)
+	assert.Contains(err.Error(), "tunnel not found")
+
+	authz.l.Lock()
+	authz.err = errors.New("permission denied")
+	authz.l.Unlock()
+	_, err = client.Up(ctx, "db")
+	require.Error(err)
+	assert.Contains(err.Error(), "permission denied")
+	status, err = client.Status(ctx, "db")
+	require.NoError(err)
+	assert.Equal(StatusDown, status.Status)
+	assert.Contains(status.Error, "permission denied")
+
+	status, err = client.Down(ctx, "web")
+	require.NoError(err)
+	assert.Equal(StatusDown, status.Status)
+	assert.Nil(status.Session)
+}
+
+func TestTunnel_Renew(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	// Sessions this short are renewed halfway through
+	authz := &testAuthorizer{lifetime: 2 * time.Second}
+	tun := newTunnel(&TunnelConfig{Name: "web", TargetId: "ttcp_1234567890", ListenAddr: "127.0.0.1"}, authz.authorize, hclog.NewNullLogger())
+	require.NoError(tun.Up(context.Background()))
+	defer tun.Down()
+
+	first := tun.Status().Session.SessionId
+	require.Eventually(func() bool {
+		return tun.Status().Session.SessionId != first
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.GreaterOrEqual(authz.authorized(), 2)
+}
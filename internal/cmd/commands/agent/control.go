@@ -0,0 +1,278 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// EnvAgentSocket is the env var naming the agent's control socket.
+const EnvAgentSocket = "BOUNDARY_AGENT_SOCKET"
+
+// DefaultSocketPath returns the path of the agent's control socket: the value
+// of BOUNDARY_AGENT_SOCKET if set, otherwise a per-user path in the temp dir.
+func DefaultSocketPath() string {
+	if path := os.Getenv(EnvAgentSocket); path != "" {
+		return path
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("boundary-agent-%d.sock", os.Getuid()))
+}
+
+// ErrTunnelNotFound is returned for a tunnel not declared in the agent's
+// profile.
+var ErrTunnelNotFound = errors.New("tunnel not found")
+
+// Agent manages the tunnels declared in a profile.
+type Agent struct {
+	ctx     context.Context
+	names   []string
+	tunnels map[string]*tunnel
+}
+
+// NewAgent returns an agent for the tunnels of the profile, which authorizes
+// sessions with authorize. The tunnels are down until brought up; all are
+// brought down when ctx is done.
+func NewAgent(ctx context.Context, profile *Profile, authorize AuthorizeFunc, logger hclog.Logger) *Agent {
+	a := &Agent{
+		ctx:     ctx,
+		tunnels: make(map[string]*tunnel, len(profile.Tunnels)),
+	}
+	for _, conf := range profile.Tunnels {
+		a.names = append(a.names, conf.Name)
+		a.tunnels[conf.Name] = newTunnel(conf, authorize, logger)
+	}
+	return a
+}
+
+// List returns the status of the tunnels in the order of the profile.
+func (a *Agent) List() []*TunnelStatus {
+	ret := make([]*TunnelStatus, 0, len(a.names))
+	for _, name := range a.names {
+		ret = append(ret, a.tunnels[name].Status())
+	}
+	return ret
+}
+
+// Status returns the status of the named tunnel.
+func (a *Agent) Status(name string) (*TunnelStatus, error) {
+	t, ok := a.tunnels[name]
+	if !ok {
+		return nil, ErrTunnelNotFound
+	}
+	return t.Status(), nil
+}
+
+// Up brings the named tunnel up and returns its status.
+func (a *Agent) Up(name string) (*TunnelStatus, error) {
+	t, ok := a.tunnels[name]
+	if !ok {
+		return nil, ErrTunnelNotFound
+	}
+	if err := t.Up(a.ctx); err != nil {
+		return nil, err
+	}
+	return t.Status(), nil
+}
+
+// Down brings the named tunnel down and returns its status.
+func (a *Agent) Down(name string) (*TunnelStatus, error) {
+	t, ok := a.tunnels[name]
+	if !ok {
+		return nil, ErrTunnelNotFound
+	}
+	t.Down()
+	return t.Status(), nil
+}
+
+// Shutdown brings all tunnels down.
+func (a *Agent) Shutdown() {
+	for _, name := range a.names {
+		a.tunnels[name].Down()
+	}
+}
+
+// controlError is the body of an error response of the control API.
+type controlError struct {
+	Error string `json:"error"`
+}
+
+// tunnelList is the body of a list response of the control API.
+type tunnelList struct {
+	Items []*TunnelStatus `json:"items"`
+}
+
+// ControlHandler returns the handler of the agent's control API:
+//
+//	GET  /v1/tunnels             lists the tunnels
+//	GET  /v1/tunnels/<name>      returns the status of a tunnel
+//	POST /v1/tunnels/<name>:up   brings a tunnel up
+//	POST /v1/tunnels/<name>:down brings a tunnel down
+func (a *Agent) ControlHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/tunnels", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeControlError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		writeControlResponse(w, http.StatusOK, &tunnelList{Items: a.List()})
+	})
+	mux.HandleFunc("/v1/tunnels/", func(w http.ResponseWriter, req *http.Request) {
+		name := strings.TrimPrefix(req.URL.Path, "/v1/tunnels/")
+		var action string
+		if i := strings.LastIndex(name, ":"); i != -1 {
+			name, action = name[:i], name[i+1:]
+		}
+
+		var status *TunnelStatus
+		var err error
+		switch {
+		case req.Method == http.MethodGet && action == "":
+			status, err = a.Status(name)
+		case req.Method == http.MethodPost && action == "up":
+			status, err = a.Up(name)
+		case req.Method == http.MethodPost && action == "down":
+			status, err = a.Down(name)
+		default:
+			writeControlError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		switch {
+		case errors.Is(err, ErrTunnelNotFound):
+			writeControlError(w, http.StatusNotFound, fmt.Errorf("%w: %s", err, name))
+		case err != nil:
+			writeControlError(w, http.StatusInternalServerError, err)
+		default:
+			writeControlResponse(w, http.StatusOK, status)
+		}
+	})
+	return mux
+}
+
+func writeControlResponse(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeControlError(w http.ResponseWriter, code int, err error) {
+	writeControlResponse(w, code, &controlError{Error: err.Error()})
+}
+
+// ListenControl listens on the Unix socket at path, which only the current
+// user can connect to. A socket left behind by an agent which is no longer
+// running is removed.
+func ListenControl(path string) (net.Listener, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("an agent is already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("error removing stale socket %s: %w", path, err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("error setting permissions of socket %s: %w", path, err)
+	}
+	return ln, nil
+}
+
+// ControlClient is a client of the agent's control API.
+type ControlClient struct {
+	socketPath string
+	client     *http.Client
+}
+
+// NewControlClient returns a client of the agent listening on the Unix socket
+// at socketPath.
+func NewControlClient(socketPath string) *ControlClient {
+	return &ControlClient{
+		socketPath: socketPath,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// List returns the status of the agent's tunnels.
+func (c *ControlClient) List(ctx context.Context) ([]*TunnelStatus, error) {
+	out := new(tunnelList)
+	if err := c.do(ctx, http.MethodGet, "/v1/tunnels", out); err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+// Status returns the status of the named tunnel.
+func (c *ControlClient) Status(ctx context.Context, name string) (*TunnelStatus, error) {
+	out := new(TunnelStatus)
+	if err := c.do(ctx, http.MethodGet, "/v1/tunnels/"+name, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Up brings the named tunnel up.
+func (c *ControlClient) Up(ctx context.Context, name string) (*TunnelStatus, error) {
+	out := new(TunnelStatus)
+	if err := c.do(ctx, http.MethodPost, "/v1/tunnels/"+name+":up", out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Down brings the named tunnel down.
+func (c *ControlClient) Down(ctx context.Context, name string) (*TunnelStatus, error) {
+	out := new(TunnelStatus)
+	if err := c.do(ctx, http.MethodPost, "/v1/tunnels/"+name+":down", out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ControlClient) do(ctx context.Context, method, path string, out interface{}) error {
+	// The host is ignored as connections are made to the socket
+	req, err := http.NewRequestWithContext(ctx, method, "http://agent"+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error contacting the agent at %s; is it running? %w", c.socketPath, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading agent response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		cErr := new(controlError)
+		if err := json.Unmarshal(body, cErr); err != nil || cErr.Error == "" {
+			return fmt.Errorf("agent returned status %d", resp.StatusCode)
+		}
+		return errors.New(cErr.Error)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error decoding agent response: %w", err)
+	}
+	return nil
+}
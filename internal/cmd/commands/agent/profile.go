@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"github.com/hashicorp/hcl"
+)
+
+// Profile declares the tunnels managed by the agent.
+type Profile struct {
+	Tunnels []*TunnelConfig `hcl:"tunnel"`
+}
+
+// TunnelConfig declares a named tunnel to a target.
+type TunnelConfig struct {
+	Name string `hcl:",key"`
+
+	// TargetId is the target sessions are authorized against
+	TargetId string `hcl:"target_id"`
+
+	// HostId is the host of the target to connect to; if empty, one of the
+	// target's hosts is chosen at random
+	HostId string `hcl:"host_id"`
+
+	// ListenAddr is the IP address the tunnel listens on; defaults to
+	// 127.0.0.1
+	ListenAddr string `hcl:"listen_addr"`
+
+	// ListenPort is the port the tunnel listens on; if zero, a random port is
+	// chosen each time the tunnel is brought up
+	ListenPort int `hcl:"listen_port"`
+
+	// AutoStart brings the tunnel up when the agent starts
+	AutoStart bool `hcl:"auto_start"`
+}
+
+// LoadProfile loads the profile from the file at path.
+func LoadProfile(path string) (*Profile, error) {
+	d, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProfile(string(d))
+}
+
+// ParseProfile parses a profile and checks its tunnels.
+func ParseProfile(d string) (*Profile, error) {
+	obj, err := hcl.Parse(d)
+	if err != nil {
+		return nil, err
+	}
+	result := new(Profile)
+	if err := hcl.DecodeObject(result, obj); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(result.Tunnels))
+	for _, t := range result.Tunnels {
+		switch {
+		case t.Name == "":
+			return nil, errors.New("tunnel without a name found")
+		case names[t.Name]:
+			return nil, fmt.Errorf("tunnel %q declared more than once", t.Name)
+		case t.TargetId == "":
+			return nil, fmt.Errorf("tunnel %q has no target_id", t.Name)
+		case t.ListenPort < 0 || t.ListenPort > 65535:
+			return nil, fmt.Errorf("tunnel %q has an invalid listen_port %d", t.Name, t.ListenPort)
+		}
+		names[t.Name] = true
+
+		if t.ListenAddr == "" {
+			t.ListenAddr = "127.0.0.1"
+		}
+		if net.ParseIP(t.ListenAddr) == nil {
+			return nil, fmt.Errorf("tunnel %q has an invalid listen_addr %q", t.Name, t.ListenAddr)
+		}
+	}
+	return result, nil
+}
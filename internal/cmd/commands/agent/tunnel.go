@@ -0,0 +1,379 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/commands/connect"
+	targetspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+	"go.uber.org/atomic"
+)
+
+const (
+	// reauthorizeBefore is how long before a tunnel's session expires a new
+	// session is authorized for it.
+	reauthorizeBefore = time.Minute
+
+	// reauthorizeRetryInterval is how long to wait before trying again when
+	// authorizing a new session fails.
+	reauthorizeRetryInterval = 10 * time.Second
+
+	// dialAttempts is how many times connecting to the worker is attempted for
+	// a connection to a tunnel, waiting dialBackoff longer after each failed
+	// attempt.
+	dialAttempts = 3
+	dialBackoff  = time.Second
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// TunnelStatus is the status of a tunnel reported by the agent. Session and
+// Connections are set while the tunnel is up.
+type TunnelStatus struct {
+	Name        string                  `json:"name"`
+	TargetId    string                  `json:"target_id"`
+	HostId      string                  `json:"host_id,omitempty"`
+	Status      string                  `json:"status"`
+	Session     *connect.SessionInfo    `json:"session,omitempty"`
+	Connections *connect.ConnectionInfo `json:"connections,omitempty"`
+	Error       string                  `json:"error,omitempty"`
+}
+
+// AuthorizeFunc authorizes a session against a target and returns the
+// authorization token.
+type AuthorizeFunc func(ctx context.Context, targetId, hostId string) (string, error)
+
+// tunnelSession is a session a tunnel proxies connections through.
+type tunnelSession struct {
+	data            *targetspb.SessionAuthorizationData
+	workerAddr      string
+	tofuToken       string
+	transport       *http.Transport
+	expiration      time.Time
+	connectionsLeft atomic.Int32
+
+	// ctx is done when the session expires or the tunnel is brought down,
+	// which closes the connections made through the session
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newTunnelSession(ctx context.Context, authzToken string) (*tunnelSession, error) {
+	data, err := connect.DecodeAuthorization(authzToken)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.GetWorkerInfo()) == 0 {
+		return nil, errors.New("No workers found in authorization data")
+	}
+	transport, parsedCert, err := connect.SessionTransport(data)
+	if err != nil {
+		return nil, err
+	}
+	tofuToken, err := base62.Random(20)
+	if err != nil {
+		return nil, fmt.Errorf("Could not derive random bytes for tofu token: %w", err)
+	}
+	s := &tunnelSession{
+		data:       data,
+		workerAddr: data.GetWorkerInfo()[0].GetAddress(),
+		tofuToken:  tofuToken,
+		transport:  transport,
+		expiration: parsedCert.NotAfter,
+	}
+	s.connectionsLeft.Store(data.GetConnectionLimit())
+	s.ctx, s.cancel = context.WithDeadline(ctx, s.expiration)
+	return s, nil
+}
+
+// usable returns whether new connections can be made through the session.
+func (s *tunnelSession) usable() bool {
+	return s.connectionsLeft.Load() != 0 && s.ctx.Err() == nil
+}
+
+// tunnel listens on a local port and proxies the connections to it to its
+// target. A new session is authorized before the current one expires or when
+// it can no longer be used; connections made through the previous session
+// stay open until it expires.
+type tunnel struct {
+	conf      *TunnelConfig
+	authorize AuthorizeFunc
+	logger    hclog.Logger
+
+	// authzLock serializes authorizing new sessions
+	authzLock sync.Mutex
+
+	l        sync.Mutex
+	up       bool
+	listener net.Listener
+	session  *tunnelSession
+	lastErr  error
+	cancel   context.CancelFunc
+	wg       *sync.WaitGroup
+}
+
+func newTunnel(conf *TunnelConfig, authorize AuthorizeFunc, logger hclog.Logger) *tunnel {
+	return &tunnel{
+		conf:      conf,
+		authorize: authorize,
+		logger:    logger.Named(conf.Name),
+	}
+}
+
+// Up starts listening and authorizes the first session. It does nothing if
+// the tunnel is already up.
+func (t *tunnel) Up(ctx context.Context) error {
+	t.l.Lock()
+	defer t.l.Unlock()
+	if t.up {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(t.conf.ListenAddr, strconv.Itoa(t.conf.ListenPort)))
+	if err != nil {
+		t.lastErr = fmt.Errorf("Error starting listening port: %w", err)
+		return t.lastErr
+	}
+	tunnelCtx, cancel := context.WithCancel(ctx)
+	sess, err := t.newSession(tunnelCtx)
+	if err != nil {
+		cancel()
+		ln.Close()
+		t.lastErr = err
+		return err
+	}
+
+	t.up = true
+	t.listener = ln
+	t.session = sess
+	t.lastErr = nil
+	t.cancel = cancel
+	t.wg = new(sync.WaitGroup)
+	t.wg.Add(2)
+	go t.accept(tunnelCtx, ln, t.wg)
+	go t.renew(tunnelCtx, t.wg)
+
+	t.logger.Info("tunnel up", "address", ln.Addr().String(), "session_id", sess.data.GetSessionId())
+	return nil
+}
+
+// Down closes the listener and the connections of the tunnel. It does
+// nothing if the tunnel is already down.
+func (t *tunnel) Down() {
+	t.l.Lock()
+	if !t.up {
+		t.l.Unlock()
+		return
+	}
+	t.up = false
+	// Canceled under the lock so that a session authorized concurrently is
+	// not stored after the tunnel is down
+	t.cancel()
+	t.listener.Close()
+	t.session = nil
+	wg := t.wg
+	t.l.Unlock()
+
+	wg.Wait()
+	t.logger.Info("tunnel down")
+}
+
+// Status returns the status of the tunnel.
+func (t *tunnel) Status() *TunnelStatus {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	status := &TunnelStatus{
+		Name:     t.conf.Name,
+		TargetId: t.conf.TargetId,
+		HostId:   t.conf.HostId,
+		Status:   StatusDown,
+	}
+	if t.lastErr != nil {
+		status.Error = t.lastErr.Error()
+	}
+	if !t.up {
+		return status
+	}
+
+	status.Status = StatusUp
+	addr := t.listener.Addr().(*net.TCPAddr)
+	status.Session = &connect.SessionInfo{
+		Address:         addr.IP.String(),
+		Port:            addr.Port,
+		Protocol:        "tcp",
+		Expiration:      t.session.expiration,
+		ConnectionLimit: t.session.data.GetConnectionLimit(),
+		SessionId:       t.session.data.GetSessionId(),
+	}
+	status.Connections = &connect.ConnectionInfo{
+		ConnectionsLeft: t.session.connectionsLeft.Load(),
+	}
+	return status
+}
+
+func (t *tunnel) newSession(ctx context.Context) (*tunnelSession, error) {
+	authzToken, err := t.authorize(ctx, t.conf.TargetId, t.conf.HostId)
+	if err != nil {
+		return nil, fmt.Errorf("Error trying to authorize a session against target %s: %w", t.conf.TargetId, err)
+	}
+	return newTunnelSession(ctx, authzToken)
+}
+
+func (t *tunnel) current() *tunnelSession {
+	t.l.Lock()
+	defer t.l.Unlock()
+	return t.session
+}
+
+func (t *tunnel) setErr(err error) {
+	t.l.Lock()
+	defer t.l.Unlock()
+	t.lastErr = err
+}
+
+// reauthorize replaces the session old with a new one and returns it. If old
+// was already replaced by a usable session, that session is returned.
+func (t *tunnel) reauthorize(ctx context.Context, old *tunnelSession) (*tunnelSession, error) {
+	t.authzLock.Lock()
+	defer t.authzLock.Unlock()
+
+	if cur := t.current(); cur != nil && cur != old && cur.usable() {
+		return cur, nil
+	}
+
+	sess, err := t.newSession(ctx)
+	if err != nil {
+		t.setErr(err)
+		return nil, err
+	}
+
+	t.l.Lock()
+	defer t.l.Unlock()
+	if ctx.Err() != nil {
+		sess.cancel()
+		return nil, ctx.Err()
+	}
+	t.session = sess
+	t.lastErr = nil
+	t.logger.Info("authorized new session", "session_id", sess.data.GetSessionId(), "expiration", sess.expiration)
+	return sess, nil
+}
+
+// renew authorizes a new session before the current one expires.
+func (t *tunnel) renew(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	var failed bool
+	for {
+		sess := t.current()
+		if sess == nil {
+			return
+		}
+		wait := time.Until(sess.expiration) - reauthorizeBefore
+		if half := time.Until(sess.expiration) / 2; wait < half {
+			// Sessions shorter than twice reauthorizeBefore are renewed
+			// halfway through
+			wait = half
+		}
+		if failed {
+			wait = reauthorizeRetryInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Skip the renewal if the session was replaced in the meantime
+		if t.current() != sess {
+			failed = false
+			continue
+		}
+		_, err := t.reauthorize(ctx, sess)
+		failed = err != nil
+		if failed && ctx.Err() == nil {
+			t.logger.Error("error authorizing new session", "error", err)
+		}
+	}
+}
+
+func (t *tunnel) accept(ctx context.Context, ln net.Listener, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.logger.Error("error accepting connection", "error", err)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			workerConn, err := t.dial(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					t.logger.Error("error connecting to worker", "error", err)
+					t.setErr(err)
+				}
+				return
+			}
+			connect.ProxyConnection(conn, workerConn)
+		}()
+	}
+}
+
+// dial connects to the worker through the current session. Failed attempts
+// are retried; if the session can no longer be used, a new session is
+// authorized first.
+func (t *tunnel) dial(ctx context.Context) (net.Conn, error) {
+	sess := t.current()
+	var err error
+	for attempt := 0; attempt < dialAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(time.Duration(attempt) * dialBackoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if sess == nil || !sess.usable() {
+			if sess, err = t.reauthorize(ctx, sess); err != nil {
+				continue
+			}
+		}
+
+		var conn net.Conn
+		var connsLeft int32
+		conn, connsLeft, err = connect.DialWorker(sess.ctx, sess.workerAddr, sess.tofuToken, sess.transport)
+		switch {
+		case err == nil:
+			sess.connectionsLeft.Store(connsLeft)
+			return conn, nil
+		case errors.Is(err, connect.ErrConnectionUnauthorized), errors.Is(err, connect.ErrSessionInUse):
+			// No more connections can be made through the session
+			sess.connectionsLeft.Store(0)
+		}
+	}
+	return nil, err
+}
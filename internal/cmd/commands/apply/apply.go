@@ -0,0 +1,194 @@
+package apply
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+
+	flagPath   string
+	flagPrune  bool
+	flagDryRun bool
+}
+
+func (c *Command) Synopsis() string {
+	return "Create, update and delete resources to match declarative definitions"
+}
+
+func (c *Command) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary apply [options]",
+		"",
+		"  Read resource definitions from HCL or JSON files, compare them to the live",
+		"  resources, print the changes needed to make the live resources match and",
+		"  then make them. Example:",
+		"",
+		`    $ boundary apply -f resources/`,
+		"",
+		"  Scopes, users, groups, roles, static host catalogs, hosts, host sets and",
+		"  TCP targets can be declared, each with a block labeled with a name which",
+		"  other definitions use to refer to it. Resources are matched to live",
+		"  resources by name within their scope or host catalog, which defaults to",
+		"  the label. Wherever a resource is referenced, an ID such as \"global\" or",
+		"  \"u_1234567890\" may be used instead of a label. For example:",
+		"",
+		`    scope "eng" {`,
+		`      scope       = "global"`,
+		`      description = "Engineering"`,
+		`    }`,
+		"",
+		`    role "eng-readers" {`,
+		`      scope      = "eng"`,
+		`      grants     = ["id=*;actions=read"]`,
+		`      principals = ["u_1234567890"]`,
+		`    }`,
+		"",
+		"  Updates are made against the version of each resource read when planning,",
+		"  so they fail if the resource is changed in the meantime. With -prune,",
+		"  resources within declared scopes and host catalogs which are no longer",
+		"  declared are deleted, including the roles created with a scope unless",
+		"  they are declared too. Resources in scopes referenced only by ID, such as",
+		"  the global scope, are never deleted.",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:       "f",
+		Target:     &c.flagPath,
+		Completion: complete.PredictOr(complete.PredictDirs("*"), complete.PredictFiles("*.hcl"), complete.PredictFiles("*.json")),
+		Usage:      "A definitions file, or a directory whose .hcl and .json files are read.",
+	})
+	f.BoolVar(&base.BoolVar{
+		Name:   "prune",
+		Target: &c.flagPrune,
+		Usage:  "Delete resources within declared scopes and host catalogs which are not declared.",
+	})
+	f.BoolVar(&base.BoolVar{
+		Name:   "dry-run",
+		Target: &c.flagDryRun,
+		Usage:  "Print the plan without making any changes.",
+	})
+
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.flagPath == "" {
+		c.UI.Error("Definitions are required but not passed in via -f")
+		return 1
+	}
+
+	defs, err := loadDefinitions(c.flagPath)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error loading definitions: %s", err))
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	p, err := newPlan(c.Context, defs, newApiReader(client), c.flagPrune)
+	if err != nil {
+		return c.apiError("planning", err)
+	}
+
+	create, update, del := p.counts()
+	changes := create + update + del
+	if base.Format(c.UI) == "table" {
+		c.UI.Output(p.String())
+	}
+	if c.flagDryRun || changes == 0 {
+		return c.outputJson(p, false)
+	}
+
+	if base.Format(c.UI) == "table" {
+		c.UI.Output("")
+	}
+	e := &executor{client: client, p: p}
+	failed, err := e.apply(c.Context, func(ch *change, id string) {
+		if base.Format(c.UI) != "table" {
+			return
+		}
+		switch ch.Action {
+		case actionCreate:
+			c.UI.Output(fmt.Sprintf("Created %s %q (%s)", ch.Kind, ch.Label, id))
+		case actionUpdate:
+			c.UI.Output(fmt.Sprintf("Updated %s %q (%s)", ch.Kind, ch.Label, id))
+		case actionDelete:
+			c.UI.Output(fmt.Sprintf("Deleted %s %q (%s)", ch.Kind, ch.Name, id))
+		}
+	})
+	if err != nil {
+		return c.apiError(fmt.Sprintf("applying %s", failed.describe()), err)
+	}
+	if base.Format(c.UI) == "table" {
+		c.UI.Output(fmt.Sprintf("\nApplied: %d created, %d updated, %d deleted.", create, update, del))
+	}
+	return c.outputJson(p, true)
+}
+
+func (c *Command) apiError(op string, err error) int {
+	if apiErr := api.AsServerError(err); apiErr != nil {
+		c.UI.Error(fmt.Sprintf("Error from controller when %s: %s", op, base.PrintApiError(apiErr)))
+		return 1
+	}
+	c.UI.Error(fmt.Sprintf("Error %s: %s", op, err.Error()))
+	return 2
+}
+
+// outputJson prints the plan, and whether it was applied, if the output
+// format is JSON.
+func (c *Command) outputJson(p *plan, applied bool) int {
+	if base.Format(c.UI) != "json" {
+		return 0
+	}
+	out := struct {
+		Changes []*change `json:"changes"`
+		Applied bool      `json:"applied"`
+	}{
+		Changes: p.Changes,
+		Applied: applied,
+	}
+	if out.Changes == nil {
+		out.Changes = []*change{}
+	}
+	b, err := base.JsonFormatter{}.Format(out)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+		return 1
+	}
+	c.UI.Output(string(b))
+	return 0
+}
@@ -0,0 +1,443 @@
+package apply
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/hashicorp/hcl"
+)
+
+// kind is the kind of a resource managed by apply. Each kind is declared with
+// a block of the same name.
+type kind string
+
+const (
+	kindScope       kind = "scope"
+	kindUser        kind = "user"
+	kindGroup       kind = "group"
+	kindRole        kind = "role"
+	kindHostCatalog kind = "host_catalog"
+	kindHost        kind = "host"
+	kindHostSet     kind = "host_set"
+	kindTarget      kind = "target"
+)
+
+// kinds lists the kinds in the order they are created. Every kind only
+// references kinds before it, except that scopes reference other scopes.
+var kinds = []kind{
+	kindScope,
+	kindUser,
+	kindGroup,
+	kindHostCatalog,
+	kindHost,
+	kindHostSet,
+	kindTarget,
+	kindRole,
+}
+
+type scopeDef struct {
+	Label                   string `hcl:",key"`
+	Scope                   string `hcl:"scope"`
+	Name                    string `hcl:"name"`
+	Description             string `hcl:"description"`
+	SkipAdminRoleCreation   bool   `hcl:"skip_admin_role_creation"`
+	SkipDefaultRoleCreation bool   `hcl:"skip_default_role_creation"`
+}
+
+type userDef struct {
+	Label       string `hcl:",key"`
+	Scope       string `hcl:"scope"`
+	Name        string `hcl:"name"`
+	Description string `hcl:"description"`
+}
+
+type groupDef struct {
+	Label       string   `hcl:",key"`
+	Scope       string   `hcl:"scope"`
+	Name        string   `hcl:"name"`
+	Description string   `hcl:"description"`
+	Members     []string `hcl:"members"`
+}
+
+type roleDef struct {
+	Label       string   `hcl:",key"`
+	Scope       string   `hcl:"scope"`
+	Name        string   `hcl:"name"`
+	Description string   `hcl:"description"`
+	GrantScope  string   `hcl:"grant_scope"`
+	Grants      []string `hcl:"grants"`
+	Principals  []string `hcl:"principals"`
+}
+
+type hostCatalogDef struct {
+	Label       string `hcl:",key"`
+	Scope       string `hcl:"scope"`
+	Name        string `hcl:"name"`
+	Description string `hcl:"description"`
+}
+
+type hostDef struct {
+	Label       string `hcl:",key"`
+	HostCatalog string `hcl:"host_catalog"`
+	Name        string `hcl:"name"`
+	Description string `hcl:"description"`
+	Address     string `hcl:"address"`
+}
+
+type hostSetDef struct {
+	Label       string   `hcl:",key"`
+	HostCatalog string   `hcl:"host_catalog"`
+	Name        string   `hcl:"name"`
+	Description string   `hcl:"description"`
+	Hosts       []string `hcl:"hosts"`
+}
+
+type targetDef struct {
+	Label                  string   `hcl:",key"`
+	Scope                  string   `hcl:"scope"`
+	Name                   string   `hcl:"name"`
+	Description            string   `hcl:"description"`
+	DefaultPort            int      `hcl:"default_port"`
+	SessionMaxSeconds      int      `hcl:"session_max_seconds"`
+	SessionConnectionLimit int      `hcl:"session_connection_limit"`
+	HostSets               []string `hcl:"host_sets"`
+}
+
+// definitions are the resources declared in a set of files.
+type definitions struct {
+	Scopes       []*scopeDef       `hcl:"scope"`
+	Users        []*userDef        `hcl:"user"`
+	Groups       []*groupDef       `hcl:"group"`
+	Roles        []*roleDef        `hcl:"role"`
+	HostCatalogs []*hostCatalogDef `hcl:"host_catalog"`
+	Hosts        []*hostDef        `hcl:"host"`
+	HostSets     []*hostSetDef     `hcl:"host_set"`
+	Targets      []*targetDef      `hcl:"target"`
+
+	// labels holds the declared labels of each kind.
+	labels map[kind]map[string]bool
+}
+
+// loadDefinitions reads the definitions in path, which is either a file or a
+// directory whose .hcl and .json files are read in lexical order.
+func loadDefinitions(path string) (*definitions, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, e := range entries {
+			switch filepath.Ext(e.Name()) {
+			case ".hcl", ".json":
+				if !e.IsDir() {
+					files = append(files, filepath.Join(path, e.Name()))
+				}
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no .hcl or .json files found in %s", path)
+		}
+		sort.Strings(files)
+	}
+
+	defs := &definitions{}
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		d, err := parseDefinitions(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", f, err)
+		}
+		defs.merge(d)
+	}
+	if err := defs.validate(); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+// parseDefinitions parses definitions given in HCL or JSON.
+func parseDefinitions(in string) (*definitions, error) {
+	obj, err := hcl.Parse(in)
+	if err != nil {
+		return nil, err
+	}
+	d := &definitions{}
+	if err := hcl.DecodeObject(d, obj); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *definitions) merge(o *definitions) {
+	d.Scopes = append(d.Scopes, o.Scopes...)
+	d.Users = append(d.Users, o.Users...)
+	d.Groups = append(d.Groups, o.Groups...)
+	d.Roles = append(d.Roles, o.Roles...)
+	d.HostCatalogs = append(d.HostCatalogs, o.HostCatalogs...)
+	d.Hosts = append(d.Hosts, o.Hosts...)
+	d.HostSets = append(d.HostSets, o.HostSets...)
+	d.Targets = append(d.Targets, o.Targets...)
+}
+
+// validate checks that labels are unique, that every reference is either to a
+// declared resource of the right kind or a well formed ID, and defaults names
+// to labels.
+func (d *definitions) validate() error {
+	d.labels = map[kind]map[string]bool{}
+	names := map[string]bool{}
+	declare := func(k kind, label, parent, name string) error {
+		if label == "" {
+			return fmt.Errorf("%s block without a label", k)
+		}
+		if d.labels[k] == nil {
+			d.labels[k] = map[string]bool{}
+		}
+		if d.labels[k][label] {
+			return fmt.Errorf("%s %q is declared more than once", k, label)
+		}
+		d.labels[k][label] = true
+		// Resources are matched to live resources by name within their
+		// parent, so names must be unique there.
+		key := fmt.Sprintf("%s/%s/%s", k, parent, name)
+		if names[key] {
+			return fmt.Errorf("%s %q: another %s in %q is named %q", k, label, k, parent, name)
+		}
+		names[key] = true
+		return nil
+	}
+
+	for _, s := range d.Scopes {
+		if s.Name == "" {
+			s.Name = s.Label
+		}
+		if err := declare(kindScope, s.Label, s.Scope, s.Name); err != nil {
+			return err
+		}
+	}
+	for _, u := range d.Users {
+		if u.Name == "" {
+			u.Name = u.Label
+		}
+		if err := declare(kindUser, u.Label, u.Scope, u.Name); err != nil {
+			return err
+		}
+	}
+	for _, g := range d.Groups {
+		if g.Name == "" {
+			g.Name = g.Label
+		}
+		if err := declare(kindGroup, g.Label, g.Scope, g.Name); err != nil {
+			return err
+		}
+	}
+	for _, r := range d.Roles {
+		if r.Name == "" {
+			r.Name = r.Label
+		}
+		if err := declare(kindRole, r.Label, r.Scope, r.Name); err != nil {
+			return err
+		}
+	}
+	for _, c := range d.HostCatalogs {
+		if c.Name == "" {
+			c.Name = c.Label
+		}
+		if err := declare(kindHostCatalog, c.Label, c.Scope, c.Name); err != nil {
+			return err
+		}
+	}
+	for _, h := range d.Hosts {
+		if h.Name == "" {
+			h.Name = h.Label
+		}
+		if err := declare(kindHost, h.Label, h.HostCatalog, h.Name); err != nil {
+			return err
+		}
+	}
+	for _, s := range d.HostSets {
+		if s.Name == "" {
+			s.Name = s.Label
+		}
+		if err := declare(kindHostSet, s.Label, s.HostCatalog, s.Name); err != nil {
+			return err
+		}
+	}
+	for _, t := range d.Targets {
+		if t.Name == "" {
+			t.Name = t.Label
+		}
+		if err := declare(kindTarget, t.Label, t.Scope, t.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range d.Scopes {
+		if err := d.checkRef(kindScope, s.Label, "scope", s.Scope, kindScope); err != nil {
+			return err
+		}
+		if s.Scope == s.Label {
+			return fmt.Errorf("scope %q is its own parent", s.Label)
+		}
+	}
+	if err := d.checkScopeCycles(); err != nil {
+		return err
+	}
+	for _, u := range d.Users {
+		if err := d.checkRef(kindUser, u.Label, "scope", u.Scope, kindScope); err != nil {
+			return err
+		}
+	}
+	for _, g := range d.Groups {
+		if err := d.checkRef(kindGroup, g.Label, "scope", g.Scope, kindScope); err != nil {
+			return err
+		}
+		for _, m := range g.Members {
+			if err := d.checkRef(kindGroup, g.Label, "members", m, kindUser); err != nil {
+				return err
+			}
+		}
+	}
+	for _, r := range d.Roles {
+		if err := d.checkRef(kindRole, r.Label, "scope", r.Scope, kindScope); err != nil {
+			return err
+		}
+		if r.GrantScope != "" {
+			if err := d.checkRef(kindRole, r.Label, "grant_scope", r.GrantScope, kindScope); err != nil {
+				return err
+			}
+		}
+		for _, p := range r.Principals {
+			if err := d.checkRef(kindRole, r.Label, "principals", p, kindUser, kindGroup); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range d.HostCatalogs {
+		if err := d.checkRef(kindHostCatalog, c.Label, "scope", c.Scope, kindScope); err != nil {
+			return err
+		}
+	}
+	for _, h := range d.Hosts {
+		if err := d.checkRef(kindHost, h.Label, "host_catalog", h.HostCatalog, kindHostCatalog); err != nil {
+			return err
+		}
+		if h.Address == "" {
+			return fmt.Errorf("host %q: address is required", h.Label)
+		}
+	}
+	for _, s := range d.HostSets {
+		if err := d.checkRef(kindHostSet, s.Label, "host_catalog", s.HostCatalog, kindHostCatalog); err != nil {
+			return err
+		}
+		for _, h := range s.Hosts {
+			if err := d.checkRef(kindHostSet, s.Label, "hosts", h, kindHost); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range d.Targets {
+		if err := d.checkRef(kindTarget, t.Label, "scope", t.Scope, kindScope); err != nil {
+			return err
+		}
+		for _, s := range t.HostSets {
+			if err := d.checkRef(kindTarget, t.Label, "host_sets", s, kindHostSet); err != nil {
+				return err
+			}
+		}
+		if t.DefaultPort < 0 || t.DefaultPort > math.MaxUint16 {
+			return fmt.Errorf("target %q: default_port must be between 1 and %d", t.Label, math.MaxUint16)
+		}
+		if t.SessionMaxSeconds < 0 || t.SessionMaxSeconds > math.MaxInt32 {
+			return fmt.Errorf("target %q: session_max_seconds must be between 1 and %d", t.Label, math.MaxInt32)
+		}
+		if t.SessionConnectionLimit < -1 || t.SessionConnectionLimit > math.MaxInt32 {
+			return fmt.Errorf("target %q: session_connection_limit must be -1 (unlimited) or greater than zero", t.Label)
+		}
+	}
+	return nil
+}
+
+// idPattern matches public IDs, e.g. "o_1234567890".
+var idPattern = regexp.MustCompile(`^[a-z]+_[0-9a-zA-Z]{10}$`)
+
+// wellKnownIds are the IDs which don't match idPattern.
+var wellKnownIds = map[string]bool{
+	scope.Global.String(): true,
+	"u_anon":              true,
+	"u_auth":              true,
+	"u_recovery":          true,
+}
+
+// checkRef checks that ref, the value of the attribute of the resource k.label,
+// is the label of a declared resource of one of the kinds to or an ID.
+func (d *definitions) checkRef(k kind, label, attr, ref string, to ...kind) error {
+	if ref == "" {
+		return fmt.Errorf("%s %q: %s is required", k, label, attr)
+	}
+	for _, t := range to {
+		if d.labels[t][ref] {
+			return nil
+		}
+	}
+	if wellKnownIds[ref] || idPattern.MatchString(ref) {
+		return nil
+	}
+	var names []string
+	for _, t := range to {
+		names = append(names, string(t))
+	}
+	return fmt.Errorf("%s %q: %s %q is neither a declared %s nor an ID", k, label, attr, ref, strings.Join(names, " or "))
+}
+
+func (d *definitions) checkScopeCycles() error {
+	parents := map[string]string{}
+	for _, s := range d.Scopes {
+		parents[s.Label] = s.Scope
+	}
+	for _, s := range d.Scopes {
+		seen := map[string]bool{s.Label: true}
+		for p, ok := parents[s.Label]; ok; p, ok = parents[p] {
+			if seen[p] {
+				return fmt.Errorf("scope %q: scope parents form a cycle", s.Label)
+			}
+			seen[p] = true
+		}
+	}
+	return nil
+}
+
+// isRef reports whether ref refers to a declared resource of kind k rather than
+// being an ID.
+func (d *definitions) isRef(k kind, ref string) bool {
+	return d.labels[k][ref]
+}
+
+// scopeDepth returns the number of declared ancestors of the scope.
+func (d *definitions) scopeDepth(s *scopeDef) int {
+	depth := 0
+	for p := s.Scope; d.isRef(kindScope, p); depth++ {
+		for _, o := range d.Scopes {
+			if o.Label == p {
+				p = o.Scope
+				break
+			}
+		}
+	}
+	return depth
+}
@@ -0,0 +1,388 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/groups"
+	"github.com/hashicorp/boundary/api/hostcatalogs"
+	"github.com/hashicorp/boundary/api/hosts"
+	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/roles"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/api/users"
+)
+
+// executor applies a plan through the API.
+type executor struct {
+	client *api.Client
+	p      *plan
+}
+
+// apply applies the changes of the plan in order, calling done after each.
+// It stops at the first error, returning the change which failed. Updates are
+// made against the version of the resource the plan was made with, so they
+// fail if the resource was changed since.
+func (e *executor) apply(ctx context.Context, done func(*change, string)) (*change, error) {
+	for _, c := range e.p.Changes {
+		id, err := e.applyChange(ctx, c)
+		if err != nil {
+			return c, err
+		}
+		if c.Action == actionCreate {
+			e.p.ids[c.Kind][c.Label] = id
+		}
+		done(c, id)
+	}
+	return nil, nil
+}
+
+func (e *executor) applyChange(ctx context.Context, c *change) (string, error) {
+	if c.Action == actionDelete {
+		return c.Id, e.delete(ctx, c)
+	}
+	switch d := c.def.(type) {
+	case *scopeDef:
+		return e.applyScope(ctx, c, d)
+	case *userDef:
+		return e.applyUser(ctx, c, d)
+	case *groupDef:
+		return e.applyGroup(ctx, c, d)
+	case *hostCatalogDef:
+		return e.applyHostCatalog(ctx, c, d)
+	case *hostDef:
+		return e.applyHost(ctx, c, d)
+	case *hostSetDef:
+		return e.applyHostSet(ctx, c, d)
+	case *targetDef:
+		return e.applyTarget(ctx, c, d)
+	case *roleDef:
+		return e.applyRole(ctx, c, d)
+	}
+	return "", fmt.Errorf("unknown definition type %T", c.def)
+}
+
+// mustResolve resolves a reference which the order of the plan guarantees is
+// known.
+func (e *executor) mustResolve(k kind, ref string) (string, error) {
+	id, ok := e.p.resolve(k, ref)
+	if !ok {
+		return "", fmt.Errorf("%s %q has not been created", k, ref)
+	}
+	return id, nil
+}
+
+func (e *executor) mustResolveAll(refs []string, ks ...kind) ([]string, error) {
+	ids := make([]string, 0, len(refs))
+	for _, r := range refs {
+		id, ok := e.p.resolveAny(r, ks...)
+		if !ok {
+			return nil, fmt.Errorf("%q has not been created", r)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (e *executor) applyScope(ctx context.Context, c *change, d *scopeDef) (string, error) {
+	client := scopes.NewClient(e.client)
+	if c.Action == actionCreate {
+		parentId, err := e.mustResolve(kindScope, d.Scope)
+		if err != nil {
+			return "", err
+		}
+		opts := []scopes.Option{scopes.WithName(d.Name)}
+		if d.Description != "" {
+			opts = append(opts, scopes.WithDescription(d.Description))
+		}
+		if d.SkipAdminRoleCreation {
+			opts = append(opts, scopes.WithSkipAdminRoleCreation(true))
+		}
+		if d.SkipDefaultRoleCreation {
+			opts = append(opts, scopes.WithSkipDefaultRoleCreation(true))
+		}
+		result, err := client.Create(ctx, parentId, opts...)
+		if err != nil {
+			return "", err
+		}
+		return result.Item.Id, nil
+	}
+	_, err := client.Update(ctx, c.Id, c.Version, scopes.WithDescription(d.Description))
+	return c.Id, err
+}
+
+func (e *executor) applyUser(ctx context.Context, c *change, d *userDef) (string, error) {
+	client := users.NewClient(e.client)
+	if c.Action == actionCreate {
+		scopeId, err := e.mustResolve(kindScope, d.Scope)
+		if err != nil {
+			return "", err
+		}
+		opts := []users.Option{users.WithName(d.Name)}
+		if d.Description != "" {
+			opts = append(opts, users.WithDescription(d.Description))
+		}
+		result, err := client.Create(ctx, scopeId, opts...)
+		if err != nil {
+			return "", err
+		}
+		return result.Item.Id, nil
+	}
+	_, err := client.Update(ctx, c.Id, c.Version, users.WithDescription(d.Description))
+	return c.Id, err
+}
+
+func (e *executor) applyGroup(ctx context.Context, c *change, d *groupDef) (string, error) {
+	client := groups.NewClient(e.client)
+	id, version := c.Id, c.Version
+	if c.Action == actionCreate {
+		scopeId, err := e.mustResolve(kindScope, d.Scope)
+		if err != nil {
+			return "", err
+		}
+		opts := []groups.Option{groups.WithName(d.Name)}
+		if d.Description != "" {
+			opts = append(opts, groups.WithDescription(d.Description))
+		}
+		result, err := client.Create(ctx, scopeId, opts...)
+		if err != nil {
+			return "", err
+		}
+		id, version = result.Item.Id, result.Item.Version
+	} else if _, ok := c.field("description"); ok {
+		result, err := client.Update(ctx, id, version, groups.WithDescription(d.Description))
+		if err != nil {
+			return "", err
+		}
+		version = result.Item.Version
+	}
+	if _, ok := c.field("members"); ok {
+		members, err := e.mustResolveAll(d.Members, kindUser)
+		if err != nil {
+			return "", err
+		}
+		if _, err := client.SetMembers(ctx, id, version, members); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+func (e *executor) applyHostCatalog(ctx context.Context, c *change, d *hostCatalogDef) (string, error) {
+	client := hostcatalogs.NewClient(e.client)
+	if c.Action == actionCreate {
+		scopeId, err := e.mustResolve(kindScope, d.Scope)
+		if err != nil {
+			return "", err
+		}
+		opts := []hostcatalogs.Option{hostcatalogs.WithName(d.Name)}
+		if d.Description != "" {
+			opts = append(opts, hostcatalogs.WithDescription(d.Description))
+		}
+		result, err := client.Create(ctx, "static", scopeId, opts...)
+		if err != nil {
+			return "", err
+		}
+		return result.Item.Id, nil
+	}
+	_, err := client.Update(ctx, c.Id, c.Version, hostcatalogs.WithDescription(d.Description))
+	return c.Id, err
+}
+
+func (e *executor) applyHost(ctx context.Context, c *change, d *hostDef) (string, error) {
+	client := hosts.NewClient(e.client)
+	if c.Action == actionCreate {
+		catalogId, err := e.mustResolve(kindHostCatalog, d.HostCatalog)
+		if err != nil {
+			return "", err
+		}
+		opts := []hosts.Option{hosts.WithName(d.Name), hosts.WithStaticHostAddress(d.Address)}
+		if d.Description != "" {
+			opts = append(opts, hosts.WithDescription(d.Description))
+		}
+		result, err := client.Create(ctx, catalogId, opts...)
+		if err != nil {
+			return "", err
+		}
+		return result.Item.Id, nil
+	}
+	var opts []hosts.Option
+	if _, ok := c.field("description"); ok {
+		opts = append(opts, hosts.WithDescription(d.Description))
+	}
+	if _, ok := c.field("address"); ok {
+		opts = append(opts, hosts.WithStaticHostAddress(d.Address))
+	}
+	_, err := client.Update(ctx, c.Id, c.Version, opts...)
+	return c.Id, err
+}
+
+func (e *executor) applyHostSet(ctx context.Context, c *change, d *hostSetDef) (string, error) {
+	client := hostsets.NewClient(e.client)
+	id, version := c.Id, c.Version
+	if c.Action == actionCreate {
+		catalogId, err := e.mustResolve(kindHostCatalog, d.HostCatalog)
+		if err != nil {
+			return "", err
+		}
+		opts := []hostsets.Option{hostsets.WithName(d.Name)}
+		if d.Description != "" {
+			opts = append(opts, hostsets.WithDescription(d.Description))
+		}
+		result, err := client.Create(ctx, catalogId, opts...)
+		if err != nil {
+			return "", err
+		}
+		id, version = result.Item.Id, result.Item.Version
+	} else if _, ok := c.field("description"); ok {
+		result, err := client.Update(ctx, id, version, hostsets.WithDescription(d.Description))
+		if err != nil {
+			return "", err
+		}
+		version = result.Item.Version
+	}
+	if _, ok := c.field("hosts"); ok {
+		hostIds, err := e.mustResolveAll(d.Hosts, kindHost)
+		if err != nil {
+			return "", err
+		}
+		if _, err := client.SetHosts(ctx, id, version, hostIds); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+func (e *executor) applyTarget(ctx context.Context, c *change, d *targetDef) (string, error) {
+	client := targets.NewClient(e.client)
+	var opts []targets.Option
+	if _, ok := c.field("default_port"); ok {
+		opts = append(opts, targets.WithTcpTargetDefaultPort(uint32(d.DefaultPort)))
+	}
+	if _, ok := c.field("session_max_seconds"); ok {
+		opts = append(opts, targets.WithSessionMaxSeconds(uint32(d.SessionMaxSeconds)))
+	}
+	if _, ok := c.field("session_connection_limit"); ok {
+		opts = append(opts, targets.WithSessionConnectionLimit(int32(d.SessionConnectionLimit)))
+	}
+	id, version := c.Id, c.Version
+	if c.Action == actionCreate {
+		scopeId, err := e.mustResolve(kindScope, d.Scope)
+		if err != nil {
+			return "", err
+		}
+		opts = append(opts, targets.WithName(d.Name))
+		if d.Description != "" {
+			opts = append(opts, targets.WithDescription(d.Description))
+		}
+		result, err := client.Create(ctx, "tcp", scopeId, opts...)
+		if err != nil {
+			return "", err
+		}
+		id, version = result.Item.Id, result.Item.Version
+	} else {
+		if _, ok := c.field("description"); ok {
+			opts = append(opts, targets.WithDescription(d.Description))
+		}
+		if len(opts) > 0 {
+			result, err := client.Update(ctx, id, version, opts...)
+			if err != nil {
+				return "", err
+			}
+			version = result.Item.Version
+		}
+	}
+	if _, ok := c.field("host_sets"); ok {
+		hostSetIds, err := e.mustResolveAll(d.HostSets, kindHostSet)
+		if err != nil {
+			return "", err
+		}
+		if _, err := client.SetHostSets(ctx, id, version, hostSetIds); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+func (e *executor) applyRole(ctx context.Context, c *change, d *roleDef) (string, error) {
+	client := roles.NewClient(e.client)
+	var opts []roles.Option
+	if _, ok := c.field("grant_scope"); ok {
+		grantScopeId, err := e.mustResolve(kindScope, d.GrantScope)
+		if err != nil {
+			return "", err
+		}
+		opts = append(opts, roles.WithGrantScopeId(grantScopeId))
+	}
+	id, version := c.Id, c.Version
+	if c.Action == actionCreate {
+		scopeId, err := e.mustResolve(kindScope, d.Scope)
+		if err != nil {
+			return "", err
+		}
+		opts = append(opts, roles.WithName(d.Name))
+		if d.Description != "" {
+			opts = append(opts, roles.WithDescription(d.Description))
+		}
+		result, err := client.Create(ctx, scopeId, opts...)
+		if err != nil {
+			return "", err
+		}
+		id, version = result.Item.Id, result.Item.Version
+	} else {
+		if _, ok := c.field("description"); ok {
+			opts = append(opts, roles.WithDescription(d.Description))
+		}
+		if len(opts) > 0 {
+			result, err := client.Update(ctx, id, version, opts...)
+			if err != nil {
+				return "", err
+			}
+			version = result.Item.Version
+		}
+	}
+	if _, ok := c.field("grants"); ok {
+		result, err := client.SetGrants(ctx, id, version, d.Grants)
+		if err != nil {
+			return "", err
+		}
+		version = result.Item.Version
+	}
+	if _, ok := c.field("principals"); ok {
+		principalIds, err := e.mustResolveAll(d.Principals, kindUser, kindGroup)
+		if err != nil {
+			return "", err
+		}
+		if _, err := client.SetPrincipals(ctx, id, version, principalIds); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+func (e *executor) delete(ctx context.Context, c *change) error {
+	var err error
+	switch c.Kind {
+	case kindScope:
+		_, err = scopes.NewClient(e.client).Delete(ctx, c.Id)
+	case kindUser:
+		_, err = users.NewClient(e.client).Delete(ctx, c.Id)
+	case kindGroup:
+		_, err = groups.NewClient(e.client).Delete(ctx, c.Id)
+	case kindHostCatalog:
+		_, err = hostcatalogs.NewClient(e.client).Delete(ctx, c.Id)
+	case kindHost:
+		_, err = hosts.NewClient(e.client).Delete(ctx, c.Id)
+	case kindHostSet:
+		_, err = hostsets.NewClient(e.client).Delete(ctx, c.Id)
+	case kindTarget:
+		_, err = targets.NewClient(e.client).Delete(ctx, c.Id)
+	case kindRole:
+		_, err = roles.NewClient(e.client).Delete(ctx, c.Id)
+	default:
+		err = fmt.Errorf("unknown kind %q", c.Kind)
+	}
+	return err
+}
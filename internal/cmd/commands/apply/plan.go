@@ -0,0 +1,715 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/api/groups"
+	"github.com/hashicorp/boundary/api/hostcatalogs"
+	"github.com/hashicorp/boundary/api/hosts"
+	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/roles"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/api/users"
+)
+
+// reader reads the live state of resources. Lists need not include the
+// associations of resources, e.g. a role's grants; reads must.
+type reader interface {
+	listScopes(ctx context.Context, scopeId string) ([]*scopes.Scope, error)
+	listUsers(ctx context.Context, scopeId string) ([]*users.User, error)
+	listGroups(ctx context.Context, scopeId string) ([]*groups.Group, error)
+	readGroup(ctx context.Context, id string) (*groups.Group, error)
+	listRoles(ctx context.Context, scopeId string) ([]*roles.Role, error)
+	readRole(ctx context.Context, id string) (*roles.Role, error)
+	listHostCatalogs(ctx context.Context, scopeId string) ([]*hostcatalogs.HostCatalog, error)
+	listHosts(ctx context.Context, hostCatalogId string) ([]*hosts.Host, error)
+	listHostSets(ctx context.Context, hostCatalogId string) ([]*hostsets.HostSet, error)
+	readHostSet(ctx context.Context, id string) (*hostsets.HostSet, error)
+	listTargets(ctx context.Context, scopeId string) ([]*targets.Target, error)
+	readTarget(ctx context.Context, id string) (*targets.Target, error)
+}
+
+type changeAction string
+
+const (
+	actionCreate changeAction = "create"
+	actionUpdate changeAction = "update"
+	actionDelete changeAction = "delete"
+)
+
+// fieldChange is a change to a single field of a resource. For fields holding
+// a set of values, e.g. a role's grants, the values added and removed are
+// listed instead.
+type fieldChange struct {
+	Field   string   `json:"field"`
+	Old     string   `json:"old,omitempty"`
+	New     string   `json:"new,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+func (f fieldChange) isSet() bool {
+	return f.Added != nil || f.Removed != nil
+}
+
+// change is a single create, update or delete of a resource.
+type change struct {
+	Action changeAction `json:"action"`
+	Kind   kind         `json:"kind"`
+	// Label is the label the resource is declared with. It is empty for
+	// resources deleted because they are no longer declared.
+	Label string `json:"label,omitempty"`
+	// Id is the ID of the resource to update or delete.
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	// Parent is the scope or host catalog of the resource, as a label if it
+	// is declared and otherwise as an ID.
+	Parent string `json:"parent,omitempty"`
+	// Version is the version of the resource the update was planned against.
+	// The update fails if the resource has changed since.
+	Version uint32        `json:"version,omitempty"`
+	Fields  []fieldChange `json:"fields,omitempty"`
+
+	def interface{}
+}
+
+// field returns the change to the field, if it is changed.
+func (c *change) field(name string) (fieldChange, bool) {
+	for _, f := range c.Fields {
+		if f.Field == name {
+			return f, true
+		}
+	}
+	return fieldChange{}, false
+}
+
+// plan is the set of changes which make the live state match the
+// definitions, in the order they must be applied.
+type plan struct {
+	Changes []*change `json:"changes"`
+
+	defs *definitions
+	// ids are the IDs of declared resources, by kind and label. Resources to
+	// be created are added as they are.
+	ids map[kind]map[string]string
+}
+
+func (p *plan) counts() (create, update, delete int) {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case actionCreate:
+			create++
+		case actionUpdate:
+			update++
+		case actionDelete:
+			delete++
+		}
+	}
+	return
+}
+
+// resolve returns the ID ref refers to, and whether it is known. References
+// to declared resources which don't exist yet are unknown.
+func (p *plan) resolve(k kind, ref string) (string, bool) {
+	if !p.defs.isRef(k, ref) {
+		return ref, true
+	}
+	id, ok := p.ids[k][ref]
+	return id, ok
+}
+
+// resolveAny resolves a reference to a resource of any of the kinds.
+func (p *plan) resolveAny(ref string, ks ...kind) (string, bool) {
+	for _, k := range ks {
+		if p.defs.isRef(k, ref) {
+			return p.resolve(k, ref)
+		}
+	}
+	return ref, true
+}
+
+// displayRefs returns the references as IDs where known and otherwise as
+// labels.
+func (p *plan) displayRefs(refs []string, ks ...kind) []string {
+	out := make([]string, 0, len(refs))
+	for _, r := range refs {
+		if id, ok := p.resolveAny(r, ks...); ok {
+			out = append(out, id)
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// planner builds a plan by reading the live state of the parents of the
+// declared resources.
+type planner struct {
+	ctx   context.Context
+	r     reader
+	prune bool
+	p     *plan
+
+	// matched holds the IDs of live resources matched to a declaration.
+	matched map[string]bool
+	// existing holds the labels of declared scopes and host catalogs which
+	// exist, and so can have resources to prune.
+	existingScopes   []string
+	existingCatalogs []string
+}
+
+// newPlan compares the definitions to the live state read from r. If prune is
+// true, resources within declared scopes and host catalogs which are not
+// declared are deleted.
+func newPlan(ctx context.Context, defs *definitions, r reader, prune bool) (*plan, error) {
+	pl := &planner{
+		ctx:     ctx,
+		r:       r,
+		prune:   prune,
+		matched: map[string]bool{},
+		p: &plan{
+			defs: defs,
+			ids:  map[kind]map[string]string{},
+		},
+	}
+	for _, k := range kinds {
+		pl.p.ids[k] = map[string]string{}
+	}
+	steps := []func() error{
+		pl.planScopes,
+		pl.planUsers,
+		pl.planGroups,
+		pl.planHostCatalogs,
+		pl.planHosts,
+		pl.planHostSets,
+		pl.planTargets,
+		pl.planRoles,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	if prune {
+		if err := pl.planPrune(); err != nil {
+			return nil, err
+		}
+	}
+	return pl.p, nil
+}
+
+func (pl *planner) add(c *change) {
+	pl.p.Changes = append(pl.p.Changes, c)
+}
+
+// matchedId records that the declared resource exists with the ID.
+func (pl *planner) matchedId(k kind, label, id string) {
+	pl.p.ids[k][label] = id
+	pl.matched[id] = true
+}
+
+func (pl *planner) planScopes() error {
+	defs := append([]*scopeDef{}, pl.p.defs.Scopes...)
+	sort.SliceStable(defs, func(i, j int) bool {
+		return pl.p.defs.scopeDepth(defs[i]) < pl.p.defs.scopeDepth(defs[j])
+	})
+	for _, d := range defs {
+		c := &change{Kind: kindScope, Label: d.Label, Name: d.Name, Parent: d.Scope, def: d}
+		var live *scopes.Scope
+		if parentId, ok := pl.p.resolve(kindScope, d.Scope); ok {
+			items, err := pl.r.listScopes(pl.ctx, parentId)
+			if err != nil {
+				return fmt.Errorf("error listing scopes in %s: %w", parentId, err)
+			}
+			for _, s := range items {
+				if s.Name == d.Name {
+					live = s
+					break
+				}
+			}
+		}
+		if live == nil {
+			c.Action = actionCreate
+			pl.add(c)
+			continue
+		}
+		pl.matchedId(kindScope, d.Label, live.Id)
+		pl.existingScopes = append(pl.existingScopes, d.Label)
+		c.Id, c.Version = live.Id, live.Version
+		c.Fields = diffString(c.Fields, "description", live.Description, d.Description)
+		pl.addIfChanged(c)
+	}
+	return nil
+}
+
+func (pl *planner) planUsers() error {
+	for _, d := range pl.p.defs.Users {
+		c := &change{Kind: kindUser, Label: d.Label, Name: d.Name, Parent: d.Scope, def: d}
+		var live *users.User
+		if scopeId, ok := pl.p.resolve(kindScope, d.Scope); ok {
+			items, err := pl.r.listUsers(pl.ctx, scopeId)
+			if err != nil {
+				return fmt.Errorf("error listing users in %s: %w", scopeId, err)
+			}
+			for _, u := range items {
+				if u.Name == d.Name {
+					live = u
+					break
+				}
+			}
+		}
+		if live == nil {
+			c.Action = actionCreate
+			pl.add(c)
+			continue
+		}
+		pl.matchedId(kindUser, d.Label, live.Id)
+		c.Id, c.Version = live.Id, live.Version
+		c.Fields = diffString(c.Fields, "description", live.Description, d.Description)
+		pl.addIfChanged(c)
+	}
+	return nil
+}
+
+func (pl *planner) planGroups() error {
+	for _, d := range pl.p.defs.Groups {
+		c := &change{Kind: kindGroup, Label: d.Label, Name: d.Name, Parent: d.Scope, def: d}
+		var liveId string
+		if scopeId, ok := pl.p.resolve(kindScope, d.Scope); ok {
+			items, err := pl.r.listGroups(pl.ctx, scopeId)
+			if err != nil {
+				return fmt.Errorf("error listing groups in %s: %w", scopeId, err)
+			}
+			for _, g := range items {
+				if g.Name == d.Name {
+					liveId = g.Id
+					break
+				}
+			}
+		}
+		if liveId == "" {
+			c.Action = actionCreate
+			c.Fields = diffSet(c.Fields, "members", nil, pl.p.displayRefs(d.Members, kindUser))
+			pl.add(c)
+			continue
+		}
+		live, err := pl.r.readGroup(pl.ctx, liveId)
+		if err != nil {
+			return fmt.Errorf("error reading group %s: %w", liveId, err)
+		}
+		pl.matchedId(kindGroup, d.Label, live.Id)
+		c.Id, c.Version = live.Id, live.Version
+		c.Fields = diffString(c.Fields, "description", live.Description, d.Description)
+		c.Fields = diffSet(c.Fields, "members", live.MemberIds, pl.p.displayRefs(d.Members, kindUser))
+		pl.addIfChanged(c)
+	}
+	return nil
+}
+
+func (pl *planner) planHostCatalogs() error {
+	for _, d := range pl.p.defs.HostCatalogs {
+		c := &change{Kind: kindHostCatalog, Label: d.Label, Name: d.Name, Parent: d.Scope, def: d}
+		var live *hostcatalogs.HostCatalog
+		if scopeId, ok := pl.p.resolve(kindScope, d.Scope); ok {
+			items, err := pl.r.listHostCatalogs(pl.ctx, scopeId)
+			if err != nil {
+				return fmt.Errorf("error listing host catalogs in %s: %w", scopeId, err)
+			}
+			for _, hc := range items {
+				if hc.Name == d.Name {
+					live = hc
+					break
+				}
+			}
+		}
+		if live == nil {
+			c.Action = actionCreate
+			pl.add(c)
+			continue
+		}
+		pl.matchedId(kindHostCatalog, d.Label, live.Id)
+		pl.existingCatalogs = append(pl.existingCatalogs, d.Label)
+		c.Id, c.Version = live.Id, live.Version
+		c.Fields = diffString(c.Fields, "description", live.Description, d.Description)
+		pl.addIfChanged(c)
+	}
+	return nil
+}
+
+func (pl *planner) planHosts() error {
+	for _, d := range pl.p.defs.Hosts {
+		c := &change{Kind: kindHost, Label: d.Label, Name: d.Name, Parent: d.HostCatalog, def: d}
+		var live *hosts.Host
+		if catalogId, ok := pl.p.resolve(kindHostCatalog, d.HostCatalog); ok {
+			items, err := pl.r.listHosts(pl.ctx, catalogId)
+			if err != nil {
+				return fmt.Errorf("error listing hosts in %s: %w", catalogId, err)
+			}
+			for _, h := range items {
+				if h.Name == d.Name {
+					live = h
+					break
+				}
+			}
+		}
+		if live == nil {
+			c.Action = actionCreate
+			c.Fields = diffString(c.Fields, "address", "", d.Address)
+			pl.add(c)
+			continue
+		}
+		pl.matchedId(kindHost, d.Label, live.Id)
+		c.Id, c.Version = live.Id, live.Version
+		c.Fields = diffString(c.Fields, "description", live.Description, d.Description)
+		c.Fields = diffString(c.Fields, "address", attrString(live.Attributes, "address"), d.Address)
+		pl.addIfChanged(c)
+	}
+	return nil
+}
+
+func (pl *planner) planHostSets() error {
+	for _, d := range pl.p.defs.HostSets {
+		c := &change{Kind: kindHostSet, Label: d.Label, Name: d.Name, Parent: d.HostCatalog, def: d}
+		var liveId string
+		if catalogId, ok := pl.p.resolve(kindHostCatalog, d.HostCatalog); ok {
+			items, err := pl.r.listHostSets(pl.ctx, catalogId)
+			if err != nil {
+				return fmt.Errorf("error listing host sets in %s: %w", catalogId, err)
+			}
+			for _, s := range items {
+				if s.Name == d.Name {
+					liveId = s.Id
+					break
+				}
+			}
+		}
+		if liveId == "" {
+			c.Action = actionCreate
+			c.Fields = diffSet(c.Fields, "hosts", nil, pl.p.displayRefs(d.Hosts, kindHost))
+			pl.add(c)
+			continue
+		}
+		live, err := pl.r.readHostSet(pl.ctx, liveId)
+		if err != nil {
+			return fmt.Errorf("error reading host set %s: %w", liveId, err)
+		}
+		pl.matchedId(kindHostSet, d.Label, live.Id)
+		c.Id, c.Version = live.Id, live.Version
+		c.Fields = diffString(c.Fields, "description", live.Description, d.Description)
+		c.Fields = diffSet(c.Fields, "hosts", live.HostIds, pl.p.displayRefs(d.Hosts, kindHost))
+		pl.addIfChanged(c)
+	}
+	return nil
+}
+
+func (pl *planner) planTargets() error {
+	for _, d := range pl.p.defs.Targets {
+		c := &change{Kind: kindTarget, Label: d.Label, Name: d.Name, Parent: d.Scope, def: d}
+		var liveId string
+		if scopeId, ok := pl.p.resolve(kindScope, d.Scope); ok {
+			items, err := pl.r.listTargets(pl.ctx, scopeId)
+			if err != nil {
+				return fmt.Errorf("error listing targets in %s: %w", scopeId, err)
+			}
+			for _, t := range items {
+				if t.Name == d.Name {
+					liveId = t.Id
+					break
+				}
+			}
+		}
+		var live *targets.Target
+		if liveId != "" {
+			var err error
+			if live, err = pl.r.readTarget(pl.ctx, liveId); err != nil {
+				return fmt.Errorf("error reading target %s: %w", liveId, err)
+			}
+		} else {
+			c.Action = actionCreate
+			live = &targets.Target{}
+		}
+		// Settings which aren't declared are left at their current or
+		// default value.
+		if d.DefaultPort != 0 {
+			c.Fields = diffUint(c.Fields, "default_port", uint64(attrNumber(live.Attributes, "default_port")), uint64(d.DefaultPort))
+		}
+		if d.SessionMaxSeconds != 0 {
+			c.Fields = diffUint(c.Fields, "session_max_seconds", uint64(live.SessionMaxSeconds), uint64(d.SessionMaxSeconds))
+		}
+		if d.SessionConnectionLimit != 0 {
+			c.Fields = diffInt(c.Fields, "session_connection_limit", int64(live.SessionConnectionLimit), int64(d.SessionConnectionLimit))
+		}
+		c.Fields = diffSet(c.Fields, "host_sets", live.HostSetIds, pl.p.displayRefs(d.HostSets, kindHostSet))
+		if c.Action == actionCreate {
+			pl.add(c)
+			continue
+		}
+		pl.matchedId(kindTarget, d.Label, live.Id)
+		c.Id, c.Version = live.Id, live.Version
+		c.Fields = diffString(c.Fields, "description", live.Description, d.Description)
+		pl.addIfChanged(c)
+	}
+	return nil
+}
+
+func (pl *planner) planRoles() error {
+	for _, d := range pl.p.defs.Roles {
+		c := &change{Kind: kindRole, Label: d.Label, Name: d.Name, Parent: d.Scope, def: d}
+		var liveId string
+		if scopeId, ok := pl.p.resolve(kindScope, d.Scope); ok {
+			items, err := pl.r.listRoles(pl.ctx, scopeId)
+			if err != nil {
+				return fmt.Errorf("error listing roles in %s: %w", scopeId, err)
+			}
+			for _, r := range items {
+				if r.Name == d.Name {
+					liveId = r.Id
+					break
+				}
+			}
+		}
+		var live *roles.Role
+		if liveId != "" {
+			var err error
+			if live, err = pl.r.readRole(pl.ctx, liveId); err != nil {
+				return fmt.Errorf("error reading role %s: %w", liveId, err)
+			}
+		} else {
+			c.Action = actionCreate
+			live = &roles.Role{}
+		}
+		if d.GrantScope != "" {
+			want := pl.p.displayRefs([]string{d.GrantScope}, kindScope)[0]
+			c.Fields = diffString(c.Fields, "grant_scope", live.GrantScopeId, want)
+		}
+		c.Fields = diffSet(c.Fields, "grants", live.GrantStrings, d.Grants)
+		c.Fields = diffSet(c.Fields, "principals", live.PrincipalIds, pl.p.displayRefs(d.Principals, kindUser, kindGroup))
+		if c.Action == actionCreate {
+			pl.add(c)
+			continue
+		}
+		pl.matchedId(kindRole, d.Label, live.Id)
+		c.Id, c.Version = live.Id, live.Version
+		c.Fields = diffString(c.Fields, "description", live.Description, d.Description)
+		pl.addIfChanged(c)
+	}
+	return nil
+}
+
+// planPrune deletes the undeclared resources in declared scopes and host
+// catalogs, in the reverse of the order kinds are created in. Resources in
+// scopes which are only referenced by ID, e.g. the global scope, are never
+// deleted.
+func (pl *planner) planPrune() error {
+	var deletes []*change
+	del := func(k kind, id, name, parent string) {
+		if !pl.matched[id] {
+			deletes = append(deletes, &change{Action: actionDelete, Kind: k, Id: id, Name: name, Parent: parent})
+		}
+	}
+	for _, label := range pl.existingScopes {
+		scopeId := pl.p.ids[kindScope][label]
+		rs, err := pl.r.listRoles(pl.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("error listing roles in %s: %w", scopeId, err)
+		}
+		for _, r := range rs {
+			del(kindRole, r.Id, r.Name, label)
+		}
+		ts, err := pl.r.listTargets(pl.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("error listing targets in %s: %w", scopeId, err)
+		}
+		for _, t := range ts {
+			del(kindTarget, t.Id, t.Name, label)
+		}
+	}
+	for _, label := range pl.existingCatalogs {
+		catalogId := pl.p.ids[kindHostCatalog][label]
+		ss, err := pl.r.listHostSets(pl.ctx, catalogId)
+		if err != nil {
+			return fmt.Errorf("error listing host sets in %s: %w", catalogId, err)
+		}
+		for _, s := range ss {
+			del(kindHostSet, s.Id, s.Name, label)
+		}
+		hs, err := pl.r.listHosts(pl.ctx, catalogId)
+		if err != nil {
+			return fmt.Errorf("error listing hosts in %s: %w", catalogId, err)
+		}
+		for _, h := range hs {
+			del(kindHost, h.Id, h.Name, label)
+		}
+	}
+	for _, label := range pl.existingScopes {
+		scopeId := pl.p.ids[kindScope][label]
+		cs, err := pl.r.listHostCatalogs(pl.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("error listing host catalogs in %s: %w", scopeId, err)
+		}
+		for _, c := range cs {
+			del(kindHostCatalog, c.Id, c.Name, label)
+		}
+		gs, err := pl.r.listGroups(pl.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("error listing groups in %s: %w", scopeId, err)
+		}
+		for _, g := range gs {
+			del(kindGroup, g.Id, g.Name, label)
+		}
+		us, err := pl.r.listUsers(pl.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("error listing users in %s: %w", scopeId, err)
+		}
+		for _, u := range us {
+			del(kindUser, u.Id, u.Name, label)
+		}
+	}
+	// Deleting a scope deletes everything within it, so scopes go last.
+	for _, label := range pl.existingScopes {
+		scopeId := pl.p.ids[kindScope][label]
+		ss, err := pl.r.listScopes(pl.ctx, scopeId)
+		if err != nil {
+			return fmt.Errorf("error listing scopes in %s: %w", scopeId, err)
+		}
+		for _, s := range ss {
+			del(kindScope, s.Id, s.Name, label)
+		}
+	}
+	pl.p.Changes = append(pl.p.Changes, deletes...)
+	return nil
+}
+
+// addIfChanged adds the update if any of its fields changed.
+func (pl *planner) addIfChanged(c *change) {
+	if len(c.Fields) == 0 {
+		return
+	}
+	c.Action = actionUpdate
+	pl.add(c)
+}
+
+func diffString(fields []fieldChange, name, old, new string) []fieldChange {
+	if old == new {
+		return fields
+	}
+	return append(fields, fieldChange{Field: name, Old: old, New: new})
+}
+
+func diffUint(fields []fieldChange, name string, old, new uint64) []fieldChange {
+	if old == new {
+		return fields
+	}
+	f := fieldChange{Field: name, New: fmt.Sprint(new)}
+	if old != 0 {
+		f.Old = fmt.Sprint(old)
+	}
+	return append(fields, f)
+}
+
+func diffInt(fields []fieldChange, name string, old, new int64) []fieldChange {
+	if old == new {
+		return fields
+	}
+	f := fieldChange{Field: name, New: fmt.Sprint(new)}
+	if old != 0 {
+		f.Old = fmt.Sprint(old)
+	}
+	return append(fields, f)
+}
+
+func diffSet(fields []fieldChange, name string, old, new []string) []fieldChange {
+	oldSet := map[string]bool{}
+	for _, v := range old {
+		oldSet[v] = true
+	}
+	newSet := map[string]bool{}
+	for _, v := range new {
+		newSet[v] = true
+	}
+	f := fieldChange{Field: name}
+	for _, v := range new {
+		if !oldSet[v] {
+			f.Added = append(f.Added, v)
+		}
+	}
+	for _, v := range old {
+		if !newSet[v] {
+			f.Removed = append(f.Removed, v)
+		}
+	}
+	if f.Added == nil && f.Removed == nil {
+		return fields
+	}
+	sort.Strings(f.Added)
+	sort.Strings(f.Removed)
+	return append(fields, f)
+}
+
+func attrString(attrs map[string]interface{}, name string) string {
+	s, _ := attrs[name].(string)
+	return s
+}
+
+func attrNumber(attrs map[string]interface{}, name string) float64 {
+	n, _ := attrs[name].(float64)
+	return n
+}
+
+// String formats the plan for display.
+func (p *plan) String() string {
+	create, update, del := p.counts()
+	if create+update+del == 0 {
+		return "No changes. The live state matches the definitions."
+	}
+	out := []string{
+		fmt.Sprintf("Plan: %d to create, %d to update, %d to delete.", create, update, del),
+		"",
+	}
+	for _, c := range p.Changes {
+		out = append(out, c.lines()...)
+	}
+	return strings.Join(out, "\n")
+}
+
+// describe names the change in messages, e.g. `create of role "admins"`.
+func (c *change) describe() string {
+	if c.Action == actionDelete {
+		return fmt.Sprintf("%s of %s %q (%s)", c.Action, c.Kind, c.Name, c.Id)
+	}
+	return fmt.Sprintf("%s of %s %q", c.Action, c.Kind, c.Label)
+}
+
+func (c *change) lines() []string {
+	var head string
+	switch c.Action {
+	case actionCreate:
+		head = fmt.Sprintf("  + %s %q (name %q in %s)", c.Kind, c.Label, c.Name, c.Parent)
+	case actionUpdate:
+		head = fmt.Sprintf("  ~ %s %q (%s, version %d)", c.Kind, c.Label, c.Id, c.Version)
+	case actionDelete:
+		head = fmt.Sprintf("  - %s %q (%s in %s)", c.Kind, c.Name, c.Id, c.Parent)
+	}
+	out := []string{head}
+	for _, f := range c.Fields {
+		if f.isSet() {
+			for _, v := range f.Added {
+				out = append(out, fmt.Sprintf("      %s: + %q", f.Field, v))
+			}
+			for _, v := range f.Removed {
+				out = append(out, fmt.Sprintf("      %s: - %q", f.Field, v))
+			}
+			continue
+		}
+		if c.Action == actionCreate {
+			out = append(out, fmt.Sprintf("      %s: %q", f.Field, f.New))
+			continue
+		}
+		out = append(out, fmt.Sprintf("      %s: %q -> %q", f.Field, f.Old, f.New))
+	}
+	return out
+}
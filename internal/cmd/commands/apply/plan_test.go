@@ -0,0 +1,309 @@
+package apply
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/api/groups"
+	"github.com/hashicorp/boundary/api/hostcatalogs"
+	"github.com/hashicorp/boundary/api/hosts"
+	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/roles"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/api/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader serves the live state from memory, keyed by parent ID.
+type fakeReader struct {
+	scopes       map[string][]*scopes.Scope
+	users        map[string][]*users.User
+	groups       map[string][]*groups.Group
+	roles        map[string][]*roles.Role
+	hostCatalogs map[string][]*hostcatalogs.HostCatalog
+	hosts        map[string][]*hosts.Host
+	hostSets     map[string][]*hostsets.HostSet
+	targets      map[string][]*targets.Target
+}
+
+func (r *fakeReader) listScopes(_ context.Context, id string) ([]*scopes.Scope, error) {
+	return r.scopes[id], nil
+}
+
+func (r *fakeReader) listUsers(_ context.Context, id string) ([]*users.User, error) {
+	return r.users[id], nil
+}
+
+func (r *fakeReader) listGroups(_ context.Context, id string) ([]*groups.Group, error) {
+	return r.groups[id], nil
+}
+
+func (r *fakeReader) readGroup(_ context.Context, id string) (*groups.Group, error) {
+	for _, gs := range r.groups {
+		for _, g := range gs {
+			if g.Id == id {
+				return g, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeReader) listRoles(_ context.Context, id string) ([]*roles.Role, error) {
+	return r.roles[id], nil
+}
+
+func (r *fakeReader) readRole(_ context.Context, id string) (*roles.Role, error) {
+	for _, rs := range r.roles {
+		for _, role := range rs {
+			if role.Id == id {
+				return role, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeReader) listHostCatalogs(_ context.Context, id string) ([]*hostcatalogs.HostCatalog, error) {
+	return r.hostCatalogs[id], nil
+}
+
+func (r *fakeReader) listHosts(_ context.Context, id string) ([]*hosts.Host, error) {
+	return r.hosts[id], nil
+}
+
+func (r *fakeReader) listHostSets(_ context.Context, id string) ([]*hostsets.HostSet, error) {
+	return r.hostSets[id], nil
+}
+
+func (r *fakeReader) readHostSet(_ context.Context, id string) (*hostsets.HostSet, error) {
+	for _, ss := range r.hostSets {
+		for _, s := range ss {
+			if s.Id == id {
+				return s, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeReader) listTargets(_ context.Context, id string) ([]*targets.Target, error) {
+	return r.targets[id], nil
+}
+
+func (r *fakeReader) readTarget(_ context.Context, id string) (*targets.Target, error) {
+	for _, ts := range r.targets {
+		for _, t := range ts {
+			if t.Id == id {
+				return t, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+const testDefinitions = `
+scope "eng" {
+  scope       = "global"
+  description = "Engineering"
+}
+
+scope "prod" {
+  scope = "eng"
+}
+
+user "alice" {
+  scope = "eng"
+}
+
+role "readers" {
+  scope       = "eng"
+  grant_scope = "prod"
+  grants      = ["id=*;actions=read"]
+  principals  = ["alice", "u_auth"]
+}
+
+host_catalog "hosts" {
+  scope = "prod"
+}
+
+host "web1" {
+  host_catalog = "hosts"
+  address      = "10.0.0.1"
+}
+
+host_set "web" {
+  host_catalog = "hosts"
+  hosts        = ["web1"]
+}
+
+target "ssh" {
+  scope                    = "prod"
+  default_port             = 22
+  session_connection_limit = -1
+  host_sets                = ["web"]
+}
+`
+
+func TestParseDefinitions(t *testing.T) {
+	t.Run("hcl", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := parseDefinitions(testDefinitions)
+		require.NoError(err)
+		require.NoError(d.validate())
+		require.Len(d.Scopes, 2)
+		assert.Equal("eng", d.Scopes[0].Name)
+		assert.Equal("Engineering", d.Scopes[0].Description)
+		require.Len(d.Roles, 1)
+		assert.Equal([]string{"alice", "u_auth"}, d.Roles[0].Principals)
+		require.Len(d.Targets, 1)
+		assert.Equal(22, d.Targets[0].DefaultPort)
+		assert.Equal(-1, d.Targets[0].SessionConnectionLimit)
+		assert.Equal(1, d.scopeDepth(d.Scopes[1]))
+	})
+	t.Run("json", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		d, err := parseDefinitions(`{"scope": {"eng": {"scope": "global", "name": "engineering"}}}`)
+		require.NoError(err)
+		require.NoError(d.validate())
+		require.Len(d.Scopes, 1)
+		assert.Equal("eng", d.Scopes[0].Label)
+		assert.Equal("engineering", d.Scopes[0].Name)
+	})
+
+	errCases := []struct {
+		name string
+		in   string
+		err  string
+	}{
+		{
+			name: "unknown reference",
+			in:   `role "r" { scope = "missing" }`,
+			err:  `role "r": scope "missing" is neither a declared scope nor an ID`,
+		},
+		{
+			name: "duplicate label",
+			in:   `user "a" { scope = "global" } user "a" { scope = "global" }`,
+			err:  `user "a" is declared more than once`,
+		},
+		{
+			name: "duplicate name",
+			in:   `user "a" { scope = "global" } user "b" { scope = "global", name = "a" }`,
+			err:  `user "b": another user in "global" is named "a"`,
+		},
+		{
+			name: "scope cycle",
+			in:   `scope "a" { scope = "b" } scope "b" { scope = "a" }`,
+			err:  `scope "a": scope parents form a cycle`,
+		},
+		{
+			name: "host without address",
+			in:   `host "h" { host_catalog = "hcst_1234567890" }`,
+			err:  `host "h": address is required`,
+		},
+	}
+	for _, tc := range errCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := parseDefinitions(tc.in)
+			require.NoError(t, err)
+			assert.EqualError(t, d.validate(), tc.err)
+		})
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	ctx := context.Background()
+	d, err := parseDefinitions(testDefinitions)
+	require.NoError(t, err)
+	require.NoError(t, d.validate())
+
+	t.Run("create all", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		p, err := newPlan(ctx, d, &fakeReader{}, true)
+		require.NoError(err)
+		var got []string
+		for _, c := range p.Changes {
+			assert.Equal(actionCreate, c.Action)
+			got = append(got, string(c.Kind)+"."+c.Label)
+		}
+		assert.Equal([]string{
+			"scope.eng", "scope.prod", "user.alice", "host_catalog.hosts",
+			"host.web1", "host_set.web", "target.ssh", "role.readers",
+		}, got)
+		c := p.Changes[len(p.Changes)-1]
+		f, ok := c.field("principals")
+		require.True(ok)
+		assert.Equal([]string{"alice", "u_auth"}, f.Added)
+	})
+
+	t.Run("update and prune", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		r := &fakeReader{
+			scopes: map[string][]*scopes.Scope{
+				"global":       {{Id: "o_1234567890", Name: "eng", Description: "Engineering", Version: 1}},
+				"o_1234567890": {{Id: "p_1234567890", Name: "prod", Version: 1}, {Id: "p_0987654321", Name: "old", Version: 1}},
+			},
+			users: map[string][]*users.User{
+				"o_1234567890": {{Id: "u_1234567890", Name: "alice", Version: 1}},
+			},
+			roles: map[string][]*roles.Role{
+				"o_1234567890": {
+					{
+						Id: "r_1234567890", Name: "readers", Version: 3, GrantScopeId: "p_1234567890",
+						GrantStrings: []string{"id=*;actions=read,list"},
+						PrincipalIds: []string{"u_1234567890", "u_auth"},
+					},
+					{Id: "r_0987654321", Name: "Administration", Version: 2},
+				},
+			},
+			hostCatalogs: map[string][]*hostcatalogs.HostCatalog{
+				"p_1234567890": {{Id: "hcst_1234567890", Name: "hosts", Version: 1}},
+			},
+			hosts: map[string][]*hosts.Host{
+				"hcst_1234567890": {{Id: "hst_1234567890", Name: "web1", Version: 1, Attributes: map[string]interface{}{"address": "10.0.0.1"}}},
+			},
+			hostSets: map[string][]*hostsets.HostSet{
+				"hcst_1234567890": {{Id: "hsst_1234567890", Name: "web", Version: 2, HostIds: []string{"hst_1234567890"}}},
+			},
+			targets: map[string][]*targets.Target{
+				"p_1234567890": {{
+					Id: "ttcp_1234567890", Name: "ssh", Version: 4, SessionConnectionLimit: 1,
+					Attributes: map[string]interface{}{"default_port": float64(22)},
+					HostSetIds: []string{"hsst_1234567890"},
+				}},
+			},
+		}
+		p, err := newPlan(ctx, d, r, true)
+		require.NoError(err)
+		require.Len(p.Changes, 4)
+
+		c := p.Changes[0]
+		assert.Equal(actionUpdate, c.Action)
+		assert.Equal("ttcp_1234567890", c.Id)
+		assert.Equal(uint32(4), c.Version)
+		assert.Equal([]fieldChange{{Field: "session_connection_limit", Old: "1", New: "-1"}}, c.Fields)
+
+		c = p.Changes[1]
+		assert.Equal(actionUpdate, c.Action)
+		assert.Equal("r_1234567890", c.Id)
+		assert.Equal(uint32(3), c.Version)
+		assert.Equal([]fieldChange{{Field: "grants", Added: []string{"id=*;actions=read"}, Removed: []string{"id=*;actions=read,list"}}}, c.Fields)
+
+		c = p.Changes[2]
+		assert.Equal(actionDelete, c.Action)
+		assert.Equal(kindRole, c.Kind)
+		assert.Equal("r_0987654321", c.Id)
+
+		c = p.Changes[3]
+		assert.Equal(actionDelete, c.Action)
+		assert.Equal(kindScope, c.Kind)
+		assert.Equal("p_0987654321", c.Id)
+
+		// Without pruning only the updates remain.
+		p, err = newPlan(ctx, d, r, false)
+		require.NoError(err)
+		assert.Len(p.Changes, 2)
+	})
+}
@@ -0,0 +1,188 @@
+package apply
+
+import (
+	"context"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/groups"
+	"github.com/hashicorp/boundary/api/hostcatalogs"
+	"github.com/hashicorp/boundary/api/hosts"
+	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/roles"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/api/users"
+)
+
+// apiReader is a reader which reads through the API. Lists are cached, as the
+// planner lists the contents of a scope once for each declared resource in
+// it.
+type apiReader struct {
+	client *api.Client
+	cache  map[string]interface{}
+}
+
+var _ reader = (*apiReader)(nil)
+
+func newApiReader(client *api.Client) *apiReader {
+	return &apiReader{client: client, cache: map[string]interface{}{}}
+}
+
+// cached returns the cached result of the list of kind k in parentId, calling
+// list to fill the cache if needed.
+func (r *apiReader) cached(k kind, parentId string, list func() (interface{}, error)) (interface{}, error) {
+	key := string(k) + "/" + parentId
+	if v, ok := r.cache[key]; ok {
+		return v, nil
+	}
+	v, err := list()
+	if err != nil {
+		return nil, err
+	}
+	r.cache[key] = v
+	return v, nil
+}
+
+func (r *apiReader) listScopes(ctx context.Context, scopeId string) ([]*scopes.Scope, error) {
+	v, err := r.cached(kindScope, scopeId, func() (interface{}, error) {
+		result, err := scopes.NewClient(r.client).List(ctx, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		return result.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*scopes.Scope), nil
+}
+
+func (r *apiReader) listUsers(ctx context.Context, scopeId string) ([]*users.User, error) {
+	v, err := r.cached(kindUser, scopeId, func() (interface{}, error) {
+		result, err := users.NewClient(r.client).List(ctx, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		return result.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*users.User), nil
+}
+
+func (r *apiReader) listGroups(ctx context.Context, scopeId string) ([]*groups.Group, error) {
+	v, err := r.cached(kindGroup, scopeId, func() (interface{}, error) {
+		result, err := groups.NewClient(r.client).List(ctx, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		return result.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*groups.Group), nil
+}
+
+func (r *apiReader) readGroup(ctx context.Context, id string) (*groups.Group, error) {
+	result, err := groups.NewClient(r.client).Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.Item, nil
+}
+
+func (r *apiReader) listRoles(ctx context.Context, scopeId string) ([]*roles.Role, error) {
+	v, err := r.cached(kindRole, scopeId, func() (interface{}, error) {
+		result, err := roles.NewClient(r.client).List(ctx, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		return result.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*roles.Role), nil
+}
+
+func (r *apiReader) readRole(ctx context.Context, id string) (*roles.Role, error) {
+	result, err := roles.NewClient(r.client).Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.Item, nil
+}
+
+func (r *apiReader) listHostCatalogs(ctx context.Context, scopeId string) ([]*hostcatalogs.HostCatalog, error) {
+	v, err := r.cached(kindHostCatalog, scopeId, func() (interface{}, error) {
+		result, err := hostcatalogs.NewClient(r.client).List(ctx, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		return result.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*hostcatalogs.HostCatalog), nil
+}
+
+func (r *apiReader) listHosts(ctx context.Context, hostCatalogId string) ([]*hosts.Host, error) {
+	v, err := r.cached(kindHost, hostCatalogId, func() (interface{}, error) {
+		result, err := hosts.NewClient(r.client).List(ctx, hostCatalogId)
+		if err != nil {
+			return nil, err
+		}
+		return result.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*hosts.Host), nil
+}
+
+func (r *apiReader) listHostSets(ctx context.Context, hostCatalogId string) ([]*hostsets.HostSet, error) {
+	v, err := r.cached(kindHostSet, hostCatalogId, func() (interface{}, error) {
+		result, err := hostsets.NewClient(r.client).List(ctx, hostCatalogId)
+		if err != nil {
+			return nil, err
+		}
+		return result.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*hostsets.HostSet), nil
+}
+
+func (r *apiReader) readHostSet(ctx context.Context, id string) (*hostsets.HostSet, error) {
+	result, err := hostsets.NewClient(r.client).Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.Item, nil
+}
+
+func (r *apiReader) listTargets(ctx context.Context, scopeId string) ([]*targets.Target, error) {
+	v, err := r.cached(kindTarget, scopeId, func() (interface{}, error) {
+		result, err := targets.NewClient(r.client).List(ctx, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		return result.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*targets.Target), nil
+}
+
+func (r *apiReader) readTarget(ctx context.Context, id string) (*targets.Target, error) {
+	result, err := targets.NewClient(r.client).Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.Item, nil
+}
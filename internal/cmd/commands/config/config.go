@@ -29,6 +29,10 @@ func (c *Command) Help() string {
 		"",
 		"      $ boundary config decrypt config.hcl",
 		"",
+		"    Validate a server config file without starting the server:",
+		"",
+		"      $ boundary config validate -config=config.hcl",
+		"",
 		"    Read a stored token out:",
 		"",
 		"      $ boundary config get-token",
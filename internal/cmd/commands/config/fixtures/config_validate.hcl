@@ -0,0 +1,28 @@
+controller {
+  name = "example-controller"
+  database {
+    url = "env://BOUNDARY_TEST_VALIDATE_PG_URL"
+  }
+}
+
+listener "tcp" {
+  purpose = "api"
+  tls_disable = true
+}
+
+listener "tcp" {
+  purpose = "cluster"
+  tls_disable = true
+}
+
+kms "aead" {
+  purpose = "root"
+  aead_type = "aes-gcm"
+  key = "env://BOUNDARY_TEST_VALIDATE_ROOT_KEY"
+}
+
+kms "aead" {
+  purpose = "worker-auth"
+  aead_type = "aes-gcm"
+  key = "8fZBjCUfN0TzjEGLQldGY4+iE9AkOvCfjh7+p0GtRBQ="
+}
@@ -0,0 +1,16 @@
+controller {
+  database {
+    url = "postgresql://boundary@localhost/boundary"
+  }
+}
+
+listener "tcp" {
+  purpose = "api"
+  tls_disable = true
+}
+
+kms "aead" {
+  purpose = "worker-auth"
+  aead_type = "aes-gcm"
+  key = "not a key"
+}
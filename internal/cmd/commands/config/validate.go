@@ -0,0 +1,174 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ValidateCommand)(nil)
+var _ cli.CommandAutocomplete = (*ValidateCommand)(nil)
+
+type ValidateCommand struct {
+	*base.Command
+
+	flagConfig    string
+	flagConfigKms string
+}
+
+func (c *ValidateCommand) Synopsis() string {
+	return "Validate Boundary's server configuration file"
+}
+
+func (c *ValidateCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary config validate [options]",
+		"",
+		"  Check a configuration file for \"boundary server\" without starting any servers. Example:",
+		"",
+		"    $ boundary config validate -config=/etc/boundary/controller.hcl",
+		"",
+		"  The file is parsed, references to files (file://) and env vars (env://) in secret values such as database URLs and KMS keys are read, and the controller, worker, listener and KMS blocks are checked. The KMS blocks are configured to check their parameters, which for some KMS types contacts the KMS provider. All problems found are reported.",
+		"",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *ValidateCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:   "config",
+		Target: &c.flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the configuration file to validate.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "config-kms",
+		Target: &c.flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file.`,
+	})
+
+	return set
+}
+
+func (c *ValidateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ValidateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ValidateCommand) Run(args []string) int {
+	f := c.Flags()
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.flagConfig == "" {
+		c.UI.Error(`Missing required parameter -config`)
+		return 1
+	}
+	c.flagConfig = strings.TrimSpace(c.flagConfig)
+
+	wrapperPath := c.flagConfig
+	if c.flagConfigKms != "" {
+		wrapperPath = strings.TrimSpace(c.flagConfigKms)
+	}
+	wrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		return c.output([]error{err})
+	}
+	if wrapper != nil {
+		if err := wrapper.Init(c.Context); err != nil {
+			return c.output([]error{fmt.Errorf("Could not initialize kms: %w", err)})
+		}
+		defer func() {
+			if err := wrapper.Finalize(c.Context); err != nil {
+				c.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+			}
+		}()
+	}
+
+	conf, err := config.LoadFile(c.flagConfig, wrapper)
+	if err != nil {
+		return c.output([]error{fmt.Errorf("Error parsing config: %w", err)})
+	}
+
+	var problems []error
+	if err := conf.Validate(); err != nil {
+		var mErr *multierror.Error
+		if errors.As(err, &mErr) {
+			problems = append(problems, mErr.Errors...)
+		} else {
+			problems = append(problems, err)
+		}
+	}
+
+	srv := base.NewServer(c.Command)
+	srv.Logger = hclog.NewNullLogger()
+	if err := srv.SetupKMSes(c.UI, conf); err != nil {
+		problems = append(problems, err)
+	}
+	if err := srv.RunShutdownFuncs(); err != nil {
+		c.UI.Warn(err.Error())
+	}
+
+	return c.output(problems)
+}
+
+func (c *ValidateCommand) output(problems []error) int {
+	switch base.Format(c.UI) {
+	case "json":
+		out := struct {
+			Valid    bool     `json:"valid"`
+			Problems []string `json:"problems,omitempty"`
+		}{
+			Valid: len(problems) == 0,
+		}
+		for _, p := range problems {
+			out.Problems = append(out.Problems, p.Error())
+		}
+		b, err := base.JsonFormatter{}.Format(out)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		if len(problems) == 0 {
+			c.UI.Info("Configuration is valid.")
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Configuration is invalid; %d problem(s) found:", len(problems)))
+		for _, p := range problems {
+			c.UI.Error("  * " + p.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
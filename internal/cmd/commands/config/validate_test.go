@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	configValidatePath        = "./fixtures/config_validate.hcl"
+	configValidateInvalidPath = "./fixtures/config_validate_invalid.hcl"
+)
+
+func TestValidate(t *testing.T) {
+	os.Setenv("BOUNDARY_TEST_VALIDATE_PG_URL", "postgresql://boundary@localhost/boundary")
+	defer os.Unsetenv("BOUNDARY_TEST_VALIDATE_PG_URL")
+
+	cases := []struct {
+		name     string
+		config   string
+		rootKey  string
+		exitCode int
+		contains []string
+	}{
+		{
+			name:     "valid",
+			config:   configValidatePath,
+			rootKey:  "7xtkEoS5EXPbgynwd+dDLHopaCqK8cq0Rpep4eooaTs=",
+			contains: []string{"Configuration is valid."},
+		},
+		{
+			name:     "missing-env-var",
+			config:   configValidatePath,
+			exitCode: 1,
+			contains: []string{"env var BOUNDARY_TEST_VALIDATE_ROOT_KEY is not set"},
+		},
+		{
+			name:     "invalid",
+			config:   configValidateInvalidPath,
+			exitCode: 1,
+			contains: []string{
+				"Controller has no name set",
+				`no listener with "cluster" purpose found`,
+				`no KMS with "root" purpose found`,
+				"Error parsing KMS configuration",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.rootKey != "" {
+				os.Setenv("BOUNDARY_TEST_VALIDATE_ROOT_KEY", c.rootKey)
+				defer os.Unsetenv("BOUNDARY_TEST_VALIDATE_ROOT_KEY")
+			}
+
+			var b bytes.Buffer
+			ui := &cli.BasicUi{
+				Reader:      bufio.NewReader(os.Stdin),
+				Writer:      &b,
+				ErrorWriter: &b,
+			}
+			cmd := &ValidateCommand{
+				Command: base.NewCommand(ui),
+			}
+
+			assert.Equal(t, c.exitCode, cmd.Run([]string{"-config", c.config}))
+			for _, s := range c.contains {
+				assert.Contains(t, b.String(), s)
+			}
+		})
+	}
+}
@@ -2,11 +2,7 @@ package connect
 
 import (
 	"context"
-	"crypto/ed25519"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -22,19 +18,12 @@ import (
 
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/targets"
-	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	targetspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
-	"github.com/hashicorp/boundary/internal/proxy"
-	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"github.com/mitchellh/cli"
-	"github.com/mr-tron/base58"
 	"github.com/posener/complete"
 	"go.uber.org/atomic"
-	"google.golang.org/protobuf/proto"
-	"nhooyr.io/websocket"
-	"nhooyr.io/websocket/wspb"
 )
 
 type SessionInfo struct {
@@ -67,6 +56,8 @@ type Command struct {
 	flagHostId     string
 	flagExec       string
 	flagUsername   string
+	flagMultiplex  bool
+	flagResumable  bool
 
 	// HTTP
 	httpFlags
@@ -94,6 +85,8 @@ type Command struct {
 	execCmdReturnValue *atomic.Int32
 	proxyCtx           context.Context
 	proxyCancel        context.CancelFunc
+	workerMuxLock      sync.Mutex
+	workerMux          *WorkerMux
 }
 
 func (c *Command) Synopsis() string {
@@ -175,6 +168,20 @@ func (c *Command) Flags() *base.FlagSets {
 		Usage:      `If set, after connecting to the worker, the given binary will be executed. This should be a binary on your path, or an absolute path. If all command flags are followed by " -- " (space, two hyphens, space), then any arguments after that will be sent directly to the binary.`,
 	})
 
+	f.BoolVar(&base.BoolVar{
+		Name:   "multiplex",
+		Target: &c.flagMultiplex,
+		EnvVar: "BOUNDARY_CONNECT_MULTIPLEX",
+		Usage:  "If set, all connections of the session are multiplexed over a single connection to the worker instead of each making its own, which speeds up clients that open many connections, such as browsers. The worker must support it.",
+	})
+
+	f.BoolVar(&base.BoolVar{
+		Name:   "resumable",
+		Target: &c.flagResumable,
+		EnvVar: "BOUNDARY_CONNECT_RESUMABLE",
+		Usage:  "If set, connections survive the network to the worker being briefly interrupted, for instance when switching networks: they are resumed over a new connection to the worker while the worker keeps the connection to the endpoint open. Cannot be used with -multiplex. The worker must support it.",
+	})
+
 	switch c.Func {
 	case "connect":
 		f.StringVar(&base.StringVar{
@@ -240,6 +247,9 @@ func (c *Command) Run(args []string) (retCode int) {
 	case c.flagAuthzToken == "" && c.flagTargetId == "":
 		c.UI.Error(`One of -target-id and -authz-token must be set`)
 		return 1
+	case c.flagMultiplex && c.flagResumable:
+		c.UI.Error(`-multiplex and -resumable cannot both be specified`)
+		return 1
 	}
 
 	if c.flagExec == "" {
@@ -327,38 +337,23 @@ func (c *Command) Run(args []string) (retCode int) {
 		authzString = sar.GetItem().(*targets.SessionAuthorization).AuthorizationToken
 	}
 
-	marshaled, err := base58.FastBase58Decoding(authzString)
+	c.sessionAuthzData, err = DecodeAuthorization(authzString)
 	if err != nil {
-		c.UI.Error(fmt.Errorf("Unable to base58-decode authorization data: %w", err).Error())
-		return 1
-	}
-	if len(marshaled) == 0 {
-		c.UI.Error("Zero length authorization information after decoding")
-		return 1
-	}
-
-	c.sessionAuthzData = new(targetspb.SessionAuthorizationData)
-	if err := proto.Unmarshal(marshaled, c.sessionAuthzData); err != nil {
-		c.UI.Error(fmt.Errorf("Unable to proto-decode authorization data: %w", err).Error())
+		c.UI.Error(err.Error())
 		return 1
 	}
 
 	if len(c.sessionAuthzData.GetWorkerInfo()) == 0 {
-		c.UI.Error("No workers found in authorization string")
+		c.UI.Error("No workers found in authorization data")
 		return 1
 	}
 
 	c.connectionsLeft.Store(c.sessionAuthzData.ConnectionLimit)
 	workerAddr := c.sessionAuthzData.GetWorkerInfo()[0].GetAddress()
 
-	parsedCert, err := x509.ParseCertificate(c.sessionAuthzData.Certificate)
+	transport, parsedCert, err := SessionTransport(c.sessionAuthzData)
 	if err != nil {
-		c.UI.Error(fmt.Errorf("Unable to decode mTLS certificate: %w", err).Error())
-		return 1
-	}
-
-	if len(parsedCert.DNSNames) != 1 {
-		c.UI.Error(fmt.Errorf("mTLS certificate has invalid parameters: %w", err).Error())
+		c.UI.Error(err.Error())
 		return 1
 	}
 
@@ -370,28 +365,9 @@ func (c *Command) Run(args []string) (retCode int) {
 	c.proxyCtx, c.proxyCancel = context.WithDeadline(c.Context, c.expiration)
 	defer c.proxyCancel()
 
-	certPool := x509.NewCertPool()
-	certPool.AddCert(parsedCert)
-
-	tlsConf := &tls.Config{
-		Certificates: []tls.Certificate{
-			{
-				Certificate: [][]byte{c.sessionAuthzData.Certificate},
-				PrivateKey:  ed25519.PrivateKey(c.sessionAuthzData.PrivateKey),
-				Leaf:        parsedCert,
-			},
-		},
-		RootCAs:    certPool,
-		ServerName: parsedCert.DNSNames[0],
-		MinVersion: tls.VersionTLS13,
-	}
-
-	transport := cleanhttp.DefaultTransport()
-	transport.DisableKeepAlives = false
-	transport.TLSClientConfig = tlsConf
-	// This isn't/shouldn't used anyways really because the connection is
-	// hijacked, just setting for completeness
-	transport.IdleConnTimeout = 0
+	if c.sessionAuthzData.GetType() == "udp" {
+		return c.runUdp(listenAddr, workerAddr, tofuToken, transport, passthroughArgs)
+	}
 
 	c.listener, err = net.ListenTCP("tcp", &net.TCPAddr{
 		IP:   listenAddr,
@@ -511,6 +487,12 @@ func (c *Command) Run(args []string) (retCode int) {
 
 	c.connWg.Wait()
 
+	c.workerMuxLock.Lock()
+	if c.workerMux != nil {
+		c.workerMux.Close()
+	}
+	c.workerMuxLock.Unlock()
+
 	if c.execCmdReturnValue != nil {
 		retCode = int(c.execCmdReturnValue.Load())
 	}
@@ -557,88 +539,56 @@ func (c *Command) handleConnection(
 
 	defer c.connWg.Done()
 
-	conn, resp, err := websocket.Dial(
-		c.proxyCtx,
-		fmt.Sprintf("wss://%s/v1/proxy", workerAddr),
-		&websocket.DialOptions{
-			HTTPClient: &http.Client{
-				Transport: transport,
-			},
-			Subprotocols: []string{globals.TcpProxyV1},
-		},
-	)
-	if err != nil {
-		switch {
-		case strings.Contains(err.Error(), "tls: internal error"):
-			return errors.New("Session is unauthorized")
-		case strings.Contains(err.Error(), "connect: connection refused"):
-			return fmt.Errorf("Unable to connect to worker at %s", workerAddr)
-		default:
-			return fmt.Errorf("Error dialing the worker: %w", err)
-		}
-	}
-
-	if resp == nil {
-		return errors.New("Response from worker is nil")
-	}
-	if resp.Header == nil {
-		return errors.New("Response header is nil")
-	}
-	negProto := resp.Header.Get("Sec-WebSocket-Protocol")
-	if negProto != globals.TcpProxyV1 {
-		return fmt.Errorf("Unexpected negotiated protocol: %s", negProto)
-	}
-
-	handshake := proxy.ClientHandshake{TofuToken: tofuToken}
-	if err := wspb.Write(c.proxyCtx, conn, &handshake); err != nil {
-		return fmt.Errorf("error sending handshake to worker: %w", err)
+	var netConn io.ReadWriteCloser
+	var connsLeft int32
+	var err error
+	switch {
+	case c.flagMultiplex:
+		netConn, connsLeft, err = c.openMuxConnection(workerAddr, tofuToken, transport)
+	case c.flagResumable:
+		netConn, connsLeft, err = DialWorkerResumable(c.proxyCtx, workerAddr, tofuToken, transport)
+	default:
+		netConn, connsLeft, err = DialWorker(c.proxyCtx, workerAddr, tofuToken, transport)
 	}
-	var handshakeResult proxy.HandshakeResult
-	if err := wspb.Read(c.proxyCtx, conn, &handshakeResult); err != nil {
-		switch {
-		case strings.Contains(err.Error(), "unable to authorize connection"):
+	if err != nil {
+		switch err {
+		case ErrConnectionUnauthorized:
 			// There's no reason to think we'd be able to authorize any more
 			// connections after the first has failed
 			c.connsLeftCh <- 0
-			return errors.New("Unable to authorize connection")
-		}
-		switch {
-		case strings.Contains(err.Error(), "tofu token not allowed"):
+		case ErrSessionInUse:
 			// Nothing will be able to be done here, so cancel the context too
 			c.proxyCancel()
-			return errors.New("Session is already in use")
-		default:
-			return fmt.Errorf("error reading handshake result: %w", err)
 		}
+		return err
 	}
 
-	if handshakeResult.GetConnectionsLeft() != -1 {
-		c.connsLeftCh <- handshakeResult.GetConnectionsLeft()
+	if connsLeft != -1 {
+		c.connsLeftCh <- connsLeft
 	}
 
-	// Get a wrapped net.Conn so we can use io.Copy
-	netConn := websocket.NetConn(c.proxyCtx, conn, websocket.MessageBinary)
-
-	localWg := new(sync.WaitGroup)
-	localWg.Add(2)
-
-	go func() {
-		defer localWg.Done()
-		io.Copy(netConn, listeningConn)
-		netConn.Close()
-		listeningConn.Close()
-	}()
-	go func() {
-		defer localWg.Done()
-		io.Copy(listeningConn, netConn)
-		listeningConn.Close()
-		netConn.Close()
-	}()
-	localWg.Wait()
+	ProxyConnection(listeningConn, netConn)
 
 	return nil
 }
 
+// openMuxConnection opens a connection over the multiplexed connection to
+// the worker, which is dialed on first use and whenever it was closed.
+func (c *Command) openMuxConnection(workerAddr, tofuToken string, transport *http.Transport) (net.Conn, int32, error) {
+	c.workerMuxLock.Lock()
+	if c.workerMux == nil || c.workerMux.Closed() {
+		mux, err := DialWorkerMux(c.proxyCtx, workerAddr, tofuToken, transport)
+		if err != nil {
+			c.workerMuxLock.Unlock()
+			return nil, 0, err
+		}
+		c.workerMux = mux
+	}
+	mux := c.workerMux
+	c.workerMuxLock.Unlock()
+	return mux.Open(c.proxyCtx)
+}
+
 func (c *Command) updateConnsLeft(connsLeft int32) {
 	c.connectionsLeft.Store(connsLeft)
 
@@ -27,6 +27,25 @@ func generateSessionInfoTableOutput(in SessionInfo) string {
 	return base.WrapForHelpText(ret)
 }
 
+func generateSocksInfoTableOutput(in SocksInfo) string {
+	nonAttributeMap := map[string]interface{}{
+		"Address":  in.Address,
+		"Port":     in.Port,
+		"Scope ID": in.ScopeId,
+		"Targets":  in.Targets,
+	}
+
+	maxLength := base.MaxAttributesLength(nonAttributeMap, nil, nil)
+
+	ret := []string{
+		"",
+		"SOCKS5 proxy listening information:",
+		base.WrapMap(2, maxLength+2, nonAttributeMap),
+	}
+
+	return base.WrapForHelpText(ret)
+}
+
 func generateConnectionInfoTableOutput(in ConnectionInfo) string {
 	var ret []string
 
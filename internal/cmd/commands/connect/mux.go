@@ -0,0 +1,85 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/yamux"
+	"nhooyr.io/websocket"
+)
+
+// WorkerMux is a connection to the worker of a session over which proxied
+// connections are multiplexed, sparing each of them the TLS and websocket
+// handshakes DialWorker makes. The worker still authorizes and accounts for
+// each connection separately.
+type WorkerMux struct {
+	conn    *websocket.Conn
+	session *yamux.Session
+}
+
+// DialWorkerMux opens a multiplexed connection for the session through the
+// worker. It is closed when ctx is done.
+func DialWorkerMux(ctx context.Context, workerAddr, tofuToken string, transport *http.Transport) (*WorkerMux, error) {
+	conn, _, err := dialWorker(ctx, workerAddr, transport, globals.TcpProxyMuxV1, &proxy.ClientHandshake{TofuToken: tofuToken})
+	if err != nil {
+		return nil, err
+	}
+	session, err := yamux.Client(websocket.NetConn(ctx, conn, websocket.MessageBinary), proxy.MuxConfig())
+	if err != nil {
+		conn.Close(websocket.StatusInternalError, "unable to start multiplexed session")
+		return nil, fmt.Errorf("error starting multiplexed session: %w", err)
+	}
+	return &WorkerMux{
+		conn:    conn,
+		session: session,
+	}, nil
+}
+
+// Open opens a proxied connection over the multiplexed connection and
+// returns it with the number of connections left in the session, which is -1
+// if unlimited.
+func (m *WorkerMux) Open(ctx context.Context) (net.Conn, int32, error) {
+	stream, err := m.session.OpenStream()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error opening stream to the worker: %w", err)
+	}
+
+	// Reading the result is bounded by ctx rather than the stream's deadline
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			stream.Close()
+		case <-done:
+		}
+	}()
+
+	result, err := proxy.ReadStreamResult(stream)
+	if err != nil {
+		stream.Close()
+		if errors.Is(err, proxy.ErrStreamUnauthorized) {
+			return nil, 0, ErrConnectionUnauthorized
+		}
+		return nil, 0, err
+	}
+	return stream, result.GetConnectionsLeft(), nil
+}
+
+// Closed returns whether the multiplexed connection is closed, after which
+// no more connections can be opened over it.
+func (m *WorkerMux) Closed() bool {
+	return m.session.IsClosed()
+}
+
+// Close closes the multiplexed connection and all connections opened over it.
+func (m *WorkerMux) Close() error {
+	err := m.session.Close()
+	m.conn.Close(websocket.StatusNormalClosure, "done")
+	return err
+}
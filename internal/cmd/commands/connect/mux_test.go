@@ -0,0 +1,169 @@
+package connect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/yamux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wspb"
+)
+
+const testTofuToken = "0123456789abcdefghijklmnop"
+
+// testWorker starts a worker speaking the TCP proxy protocols that echoes
+// proxied connections and authorizes at most connectionLimit of them, or any
+// number if it is -1.
+func testWorker(t testing.TB, connectionLimit int32) (string, *http.Transport) {
+	t.Helper()
+	connsLeft := atomic.NewInt32(connectionLimit)
+	authorize := func() (int32, bool) {
+		if connectionLimit == -1 {
+			return -1, true
+		}
+		left := connsLeft.Dec()
+		return left, left >= 0
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+			Subprotocols: []string{globals.TcpProxyV1, globals.TcpProxyMuxV1},
+		})
+		if err != nil {
+			return
+		}
+		defer conn.Close(websocket.StatusNormalClosure, "done")
+		ctx := r.Context()
+
+		var handshake proxy.ClientHandshake
+		if err := wspb.Read(ctx, conn, &handshake); err != nil {
+			return
+		}
+		netConn := websocket.NetConn(ctx, conn, websocket.MessageBinary)
+
+		switch conn.Subprotocol() {
+		case globals.TcpProxyV1:
+			left, ok := authorize()
+			if !ok {
+				conn.Close(websocket.StatusInternalError, "unable to authorize connection")
+				return
+			}
+			if err := wspb.Write(ctx, conn, &proxy.HandshakeResult{ConnectionLimit: connectionLimit, ConnectionsLeft: left}); err != nil {
+				return
+			}
+			io.Copy(netConn, netConn)
+
+		case globals.TcpProxyMuxV1:
+			if err := wspb.Write(ctx, conn, &proxy.HandshakeResult{ConnectionLimit: connectionLimit}); err != nil {
+				return
+			}
+			session, err := yamux.Server(netConn, proxy.MuxConfig())
+			if err != nil {
+				return
+			}
+			defer session.Close()
+			for {
+				stream, err := session.AcceptStream()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer stream.Close()
+					left, ok := authorize()
+					if !ok {
+						return
+					}
+					if err := proxy.WriteStreamResult(stream, &proxy.HandshakeResult{ConnectionLimit: connectionLimit, ConnectionsLeft: left}); err != nil {
+						return
+					}
+					io.Copy(stream, stream)
+				}()
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "https://"), srv.Client().Transport.(*http.Transport)
+}
+
+func testEcho(t testing.TB, conn io.ReadWriter, msg string) {
+	t.Helper()
+	_, err := conn.Write([]byte(msg))
+	require.NoError(t, err)
+	buf := make([]byte, len(msg))
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, msg, string(buf))
+}
+
+func TestWorkerMux(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	workerAddr, transport := testWorker(t, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux, err := DialWorkerMux(ctx, workerAddr, testTofuToken, transport)
+	require.NoError(err)
+	defer mux.Close()
+
+	first, connsLeft, err := mux.Open(ctx)
+	require.NoError(err)
+	assert.Equal(int32(1), connsLeft)
+	second, connsLeft, err := mux.Open(ctx)
+	require.NoError(err)
+	assert.Equal(int32(0), connsLeft)
+
+	// Connections are independent of each other
+	testEcho(t, second, "second")
+	testEcho(t, first, "first")
+	require.NoError(first.Close())
+	testEcho(t, second, "still open")
+
+	// Connections the worker does not authorize are refused
+	_, _, err = mux.Open(ctx)
+	assert.Equal(ErrConnectionUnauthorized, err)
+	assert.False(mux.Closed())
+
+	require.NoError(mux.Close())
+	assert.True(mux.Closed())
+	_, _, err = mux.Open(ctx)
+	assert.Error(err)
+}
+
+// The benchmarks compare the latency of proxying a new connection, from
+// dialing to the first echoed byte, without and with multiplexing.
+
+func BenchmarkDialWorker(b *testing.B) {
+	workerAddr, transport := testWorker(b, -1)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn, _, err := DialWorker(ctx, workerAddr, testTofuToken, transport)
+		require.NoError(b, err)
+		testEcho(b, conn, "x")
+		conn.Close()
+	}
+}
+
+func BenchmarkWorkerMuxOpen(b *testing.B) {
+	workerAddr, transport := testWorker(b, -1)
+	ctx := context.Background()
+	mux, err := DialWorkerMux(ctx, workerAddr, testTofuToken, transport)
+	require.NoError(b, err)
+	defer mux.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn, _, err := mux.Open(ctx)
+		require.NoError(b, err)
+		testEcho(b, conn, "x")
+		conn.Close()
+	}
+}
@@ -0,0 +1,149 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/proxy"
+	"nhooyr.io/websocket"
+)
+
+const (
+	// resumeDialTimeout bounds each attempt to dial the worker when resuming.
+	resumeDialTimeout = 10 * time.Second
+
+	// resumeMaxBackoff is the longest wait between attempts to resume.
+	resumeMaxBackoff = 5 * time.Second
+
+	// resumableCloseTimeout is how long a closed resumable connection waits
+	// for the worker to finish it before it is aborted.
+	resumableCloseTimeout = 10 * time.Second
+)
+
+var errResumableConnClosed = errors.New("resumable connection closed")
+
+// resumableWorkerConn is a proxied connection to the worker that survives
+// the websocket to the worker failing, by resuming it over a new one.
+type resumableWorkerConn struct {
+	*proxy.ResumableStream
+	closeOnce sync.Once
+}
+
+// Close closes the connection for writing and lets the worker finish it,
+// discarding whatever the worker still sends.
+func (c *resumableWorkerConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.ResumableStream.Close()
+		go func() {
+			io.Copy(ioutil.Discard, c.ResumableStream)
+			select {
+			case <-c.Done():
+			case <-time.After(resumableCloseTimeout):
+				c.Abort(errResumableConnClosed)
+			}
+		}()
+	})
+	return nil
+}
+
+// DialWorkerResumable opens a resumable proxied connection for the session
+// through the worker and returns it with the number of connections left in
+// the session, which is -1 if unlimited. Whenever the websocket to the worker
+// fails, the connection is resumed over a new one, which must succeed within
+// proxy.ResumeGracePeriod; meanwhile the worker keeps the connection to the
+// endpoint open. The connection is aborted when ctx is done.
+func DialWorkerResumable(ctx context.Context, workerAddr, tofuToken string, transport *http.Transport) (io.ReadWriteCloser, int32, error) {
+	conn, result, err := dialWorker(ctx, workerAddr, transport, globals.TcpProxyResumableV1, &proxy.ClientHandshake{TofuToken: tofuToken})
+	if err != nil {
+		return nil, 0, err
+	}
+	resumeToken := result.GetResumeToken()
+	if resumeToken == "" {
+		conn.Close(websocket.StatusProtocolError, "missing resume token")
+		return nil, 0, errors.New("Worker did not return a resume token")
+	}
+
+	stream := proxy.NewResumableStream(0)
+	go runResumable(ctx, stream, conn, 0, func(ctx context.Context) (*websocket.Conn, uint64, error) {
+		conn, result, err := dialWorker(ctx, workerAddr, transport, globals.TcpProxyResumableV1, &proxy.ClientHandshake{
+			TofuToken:      tofuToken,
+			ResumeToken:    resumeToken,
+			ResumeReceived: stream.Received(),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		return conn, result.GetResumeReceived(), nil
+	})
+	return &resumableWorkerConn{ResumableStream: stream}, result.GetConnectionsLeft(), nil
+}
+
+// runResumable carries the stream over the websocket, and over the ones
+// returned by redial whenever the previous one fails, until the stream is
+// done or aborted. The stream is aborted if it cannot be resumed.
+func runResumable(ctx context.Context, stream *proxy.ResumableStream, conn *websocket.Conn, peerReceived uint64, redial func(context.Context) (*websocket.Conn, uint64, error)) {
+	for {
+		err := stream.Attach(ctx, peerReceived, func(ctx context.Context) ([]byte, error) {
+			_, frame, err := conn.Read(ctx)
+			return frame, err
+		}, func(ctx context.Context, frame []byte) error {
+			return conn.Write(ctx, websocket.MessageBinary, frame)
+		})
+		if err == nil {
+			conn.Close(websocket.StatusNormalClosure, "done")
+			return
+		}
+		conn.Close(websocket.StatusGoingAway, "resuming")
+		switch {
+		case stream.Err() != nil:
+			return
+		case ctx.Err() != nil:
+			stream.Abort(ctx.Err())
+			return
+		case errors.Is(err, proxy.ErrCannotResume), errors.Is(err, proxy.ErrInvalidResumableFrame):
+			stream.Abort(err)
+			return
+		}
+
+		conn, peerReceived, err = resume(ctx, redial)
+		if err != nil {
+			stream.Abort(err)
+			return
+		}
+	}
+}
+
+// resume calls redial with backoff until it succeeds, fails in a way
+// retrying cannot help with or proxy.ResumeGracePeriod has passed.
+func resume(ctx context.Context, redial func(context.Context) (*websocket.Conn, uint64, error)) (*websocket.Conn, uint64, error) {
+	deadline := time.Now().Add(proxy.ResumeGracePeriod)
+	backoff := 100 * time.Millisecond
+	for {
+		dialCtx, cancel := context.WithTimeout(ctx, resumeDialTimeout)
+		conn, peerReceived, err := redial(dialCtx)
+		cancel()
+		switch {
+		case err == nil:
+			return conn, peerReceived, nil
+		case errors.Is(err, proxy.ErrCannotResume), errors.Is(err, ErrSessionInUse), errors.Is(err, ErrConnectionUnauthorized):
+			return nil, 0, err
+		case time.Now().Add(backoff).After(deadline):
+			return nil, 0, fmt.Errorf("Unable to resume connection within %s: %w", proxy.ResumeGracePeriod, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > resumeMaxBackoff {
+			backoff = resumeMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/globals"
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wspb"
+)
+
+// testResumableWorker is a worker speaking the resumable TCP proxy protocol
+// that echoes proxied connections.
+type testResumableWorker struct {
+	l       sync.Mutex
+	streams map[string]*proxy.ResumableStream
+	current *websocket.Conn
+}
+
+// drop closes the current websocket, as if the network failed.
+func (tw *testResumableWorker) drop() {
+	tw.l.Lock()
+	defer tw.l.Unlock()
+	tw.current.Close(websocket.StatusGoingAway, "dropped")
+}
+
+// forget makes the worker forget all connections.
+func (tw *testResumableWorker) forget() {
+	tw.l.Lock()
+	defer tw.l.Unlock()
+	tw.streams = make(map[string]*proxy.ResumableStream)
+}
+
+func (tw *testResumableWorker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols: []string{globals.TcpProxyResumableV1},
+	})
+	if err != nil {
+		return
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "done")
+	ctx := r.Context()
+
+	var handshake proxy.ClientHandshake
+	if err := wspb.Read(ctx, conn, &handshake); err != nil {
+		return
+	}
+	token := handshake.GetResumeToken()
+	tw.l.Lock()
+	stream, ok := tw.streams[token]
+	if !ok && token != "" {
+		tw.l.Unlock()
+		conn.Close(websocket.StatusPolicyViolation, "unable to resume connection")
+		return
+	}
+	if !ok {
+		token = "token"
+		stream = proxy.NewResumableStream(0)
+		tw.streams[token] = stream
+		go func() {
+			io.Copy(stream, stream)
+			stream.Close()
+		}()
+	}
+	tw.current = conn
+	tw.l.Unlock()
+
+	if err := wspb.Write(ctx, conn, &proxy.HandshakeResult{
+		ConnectionsLeft: -1,
+		ResumeToken:     token,
+		ResumeReceived:  stream.Received(),
+	}); err != nil {
+		return
+	}
+	stream.Attach(ctx, handshake.GetResumeReceived(), func(ctx context.Context) ([]byte, error) {
+		_, frame, err := conn.Read(ctx)
+		return frame, err
+	}, func(ctx context.Context, frame []byte) error {
+		return conn.Write(ctx, websocket.MessageBinary, frame)
+	})
+}
+
+func TestDialWorkerResumable(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	tw := &testResumableWorker{streams: make(map[string]*proxy.ResumableStream)}
+	srv := httptest.NewTLSServer(tw)
+	defer srv.Close()
+	workerAddr := strings.TrimPrefix(srv.URL, "https://")
+	transport := srv.Client().Transport.(*http.Transport)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, connsLeft, err := DialWorkerResumable(ctx, workerAddr, testTofuToken, transport)
+	require.NoError(err)
+	assert.Equal(int32(-1), connsLeft)
+	testEcho(t, conn, "before")
+
+	// The connection is resumed after the websocket fails, without losing
+	// data written meanwhile
+	tw.drop()
+	testEcho(t, conn, "after the first drop")
+	tw.drop()
+	tw.drop()
+	testEcho(t, conn, "after more drops")
+
+	// Closing finishes the connection on both ends
+	require.NoError(conn.Close())
+	tw.l.Lock()
+	stream := tw.streams["token"]
+	tw.l.Unlock()
+	select {
+	case <-stream.Done():
+		assert.NoError(stream.Err())
+	case <-time.After(5 * time.Second):
+		require.FailNow("connection was not finished")
+	}
+
+	// A connection the worker does not know anymore is aborted
+	conn, _, err = DialWorkerResumable(ctx, workerAddr, testTofuToken, transport)
+	require.NoError(err)
+	testEcho(t, conn, "before")
+	tw.forget()
+	tw.drop()
+	_, err = conn.Read(make([]byte, 1))
+	assert.True(errors.Is(err, proxy.ErrCannotResume), "got %v", err)
+}
@@ -0,0 +1,196 @@
+package connect
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/boundary/globals"
+	targetspb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/mr-tron/base58"
+	"google.golang.org/protobuf/proto"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wspb"
+)
+
+var (
+	// ErrConnectionUnauthorized is returned by DialWorker when the worker
+	// does not authorize a connection for the session; no more connections
+	// can be made for the session.
+	ErrConnectionUnauthorized = errors.New("Unable to authorize connection")
+
+	// ErrSessionInUse is returned by DialWorker when the session is already
+	// in use by another client.
+	ErrSessionInUse = errors.New("Session is already in use")
+)
+
+// DecodeAuthorization decodes the authorization token returned by an
+// authorize-session call.
+func DecodeAuthorization(authzString string) (*targetspb.SessionAuthorizationData, error) {
+	marshaled, err := base58.FastBase58Decoding(authzString)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to base58-decode authorization data: %w", err)
+	}
+	if len(marshaled) == 0 {
+		return nil, errors.New("Zero length authorization information after decoding")
+	}
+
+	data := new(targetspb.SessionAuthorizationData)
+	if err := proto.Unmarshal(marshaled, data); err != nil {
+		return nil, fmt.Errorf("Unable to proto-decode authorization data: %w", err)
+	}
+
+	if len(data.GetWorkerInfo()) == 0 {
+		return nil, errors.New("No workers found in authorization string")
+	}
+	return data, nil
+}
+
+// SessionTransport returns the transport to connect to the workers for the
+// session with, using the session's mTLS certificate, and the parsed
+// certificate.
+func SessionTransport(data *targetspb.SessionAuthorizationData) (*http.Transport, *x509.Certificate, error) {
+	parsedCert, err := x509.ParseCertificate(data.Certificate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to decode mTLS certificate: %w", err)
+	}
+
+	if len(parsedCert.DNSNames) != 1 {
+		return nil, nil, errors.New("mTLS certificate has invalid parameters")
+	}
+
+	certPool := x509.NewCertPool()
+	certPool.AddCert(parsedCert)
+
+	tlsConf := &tls.Config{
+		Certificates: []tls.Certificate{
+			{
+				Certificate: [][]byte{data.Certificate},
+				PrivateKey:  ed25519.PrivateKey(data.PrivateKey),
+				Leaf:        parsedCert,
+			},
+		},
+		RootCAs:    certPool,
+		ServerName: parsedCert.DNSNames[0],
+		MinVersion: tls.VersionTLS13,
+	}
+
+	transport := cleanhttp.DefaultTransport()
+	transport.DisableKeepAlives = false
+	transport.TLSClientConfig = tlsConf
+	// This isn't/shouldn't used anyways really because the connection is
+	// hijacked, just setting for completeness
+	transport.IdleConnTimeout = 0
+
+	return transport, parsedCert, nil
+}
+
+// DialWorker opens a proxied connection for the session through the worker
+// and returns it with the number of connections left in the session, which is
+// -1 if unlimited. The connection is closed when ctx is done.
+func DialWorker(ctx context.Context, workerAddr, tofuToken string, transport *http.Transport) (net.Conn, int32, error) {
+	conn, result, err := dialWorker(ctx, workerAddr, transport, globals.TcpProxyV1, &proxy.ClientHandshake{TofuToken: tofuToken})
+	if err != nil {
+		return nil, 0, err
+	}
+	// Get a wrapped net.Conn so we can use io.Copy
+	return websocket.NetConn(ctx, conn, websocket.MessageBinary), result.GetConnectionsLeft(), nil
+}
+
+// DialWorkerUdp opens a proxied connection of the UDP proxy protocol for the
+// session through the worker, over which datagrams are exchanged as frames,
+// and returns it with the number of connections left in the session.
+func DialWorkerUdp(ctx context.Context, workerAddr, tofuToken string, transport *http.Transport) (*websocket.Conn, int32, error) {
+	conn, result, err := dialWorker(ctx, workerAddr, transport, globals.UdpProxyV1, &proxy.ClientHandshake{TofuToken: tofuToken})
+	if err != nil {
+		return nil, 0, err
+	}
+	conn.SetReadLimit(proxy.MaxUdpFrameSize)
+	return conn, result.GetConnectionsLeft(), nil
+}
+
+// dialWorker opens a websocket of the subprotocol to the worker and performs
+// the proxy handshake over it.
+func dialWorker(ctx context.Context, workerAddr string, transport *http.Transport, subprotocol string, handshake *proxy.ClientHandshake) (*websocket.Conn, *proxy.HandshakeResult, error) {
+	conn, resp, err := websocket.Dial(
+		ctx,
+		fmt.Sprintf("wss://%s/v1/proxy", workerAddr),
+		&websocket.DialOptions{
+			HTTPClient: &http.Client{
+				Transport: transport,
+			},
+			Subprotocols: []string{subprotocol},
+		},
+	)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "tls: internal error"):
+			return nil, nil, errors.New("Session is unauthorized")
+		case strings.Contains(err.Error(), "connect: connection refused"):
+			return nil, nil, fmt.Errorf("Unable to connect to worker at %s", workerAddr)
+		default:
+			return nil, nil, fmt.Errorf("Error dialing the worker: %w", err)
+		}
+	}
+
+	if resp == nil {
+		return nil, nil, errors.New("Response from worker is nil")
+	}
+	if resp.Header == nil {
+		return nil, nil, errors.New("Response header is nil")
+	}
+	negProto := resp.Header.Get("Sec-WebSocket-Protocol")
+	if negProto != subprotocol {
+		return nil, nil, fmt.Errorf("Unexpected negotiated protocol: %s", negProto)
+	}
+
+	if err := wspb.Write(ctx, conn, handshake); err != nil {
+		return nil, nil, fmt.Errorf("error sending handshake to worker: %w", err)
+	}
+	var handshakeResult proxy.HandshakeResult
+	if err := wspb.Read(ctx, conn, &handshakeResult); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "unable to authorize connection"):
+			return nil, nil, ErrConnectionUnauthorized
+		case strings.Contains(err.Error(), "tofu token not allowed"):
+			return nil, nil, ErrSessionInUse
+		case strings.Contains(err.Error(), "unable to resume connection"):
+			return nil, nil, proxy.ErrCannotResume
+		default:
+			return nil, nil, fmt.Errorf("error reading handshake result: %w", err)
+		}
+	}
+
+	return conn, &handshakeResult, nil
+}
+
+// ProxyConnection copies data between the local connection and the one to
+// the worker until either is closed.
+func ProxyConnection(localConn net.Conn, workerConn io.ReadWriteCloser) {
+	localWg := new(sync.WaitGroup)
+	localWg.Add(2)
+
+	go func() {
+		defer localWg.Done()
+		io.Copy(workerConn, localConn)
+		workerConn.Close()
+		localConn.Close()
+	}()
+	go func() {
+		defer localWg.Done()
+		io.Copy(localConn, workerConn)
+		localConn.Close()
+		workerConn.Close()
+	}()
+	localWg.Wait()
+}
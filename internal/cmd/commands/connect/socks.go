@@ -0,0 +1,547 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/hosts"
+	"github.com/hashicorp/boundary/api/hostsets"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"go.uber.org/atomic"
+)
+
+const (
+	// socksRefreshInterval is how often the targets the proxy resolves
+	// requests to are listed again.
+	socksRefreshInterval = 5 * time.Minute
+
+	// socksMinRefreshInterval is the least time between listing the targets
+	// again when a request resolves to no target.
+	socksMinRefreshInterval = 10 * time.Second
+
+	// socksSessionExpiryMargin is how long before its expiration a cached
+	// session is no longer used for new connections.
+	socksSessionExpiryMargin = 10 * time.Second
+)
+
+// SocksInfo is the information output when the SOCKS proxy is listening.
+type SocksInfo struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	ScopeId string `json:"scope_id"`
+	Targets int    `json:"targets"`
+}
+
+var _ cli.Command = (*SocksCommand)(nil)
+var _ cli.CommandAutocomplete = (*SocksCommand)(nil)
+
+// SocksCommand runs a local SOCKS5 proxy which proxies each connection
+// through a session against the target the requested host resolves to.
+type SocksCommand struct {
+	*base.Command
+
+	flagListen string
+
+	client   *api.Client
+	index    *socksIndex
+	sessions *socksSessions
+}
+
+func (c *SocksCommand) Synopsis() string {
+	return "Run a local SOCKS5 proxy to the targets you can access"
+}
+
+func (c *SocksCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary connect socks [options]",
+		"",
+		"  Run a local SOCKS5 proxy which proxies connections to targets through Boundary workers. Example:",
+		"",
+		"      $ boundary connect socks -listen 127.0.0.1:1080 -scope-id o_1234567890",
+		"",
+		`  The host of a requested connection is resolved to a target in the scope (or any of its child scopes) by the target's name or ID, or by the address of a host in the target's host sets. When resolving by host address, the target's default port must match the requested port. A session is authorized against the target when it is first needed and is reused for further connections until it expires or has no connections left.`,
+		"",
+		`  Clients should send host names to the proxy rather than resolving them themselves, e.g. by using "socks5h://127.0.0.1:1080" as the proxy URL.`,
+		"",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *SocksCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:       "listen",
+		Target:     &c.flagListen,
+		Default:    "127.0.0.1:1080",
+		EnvVar:     "BOUNDARY_CONNECT_SOCKS_LISTEN",
+		Completion: complete.PredictAnything,
+		Usage:      `The address and port the SOCKS5 proxy listens on.`,
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:       "scope-id",
+		Target:     &c.FlagScopeId,
+		Default:    "global",
+		EnvVar:     "BOUNDARY_SCOPE_ID",
+		Completion: complete.PredictAnything,
+		Usage:      "The scope whose targets, and the targets of its child scopes, connections are resolved to.",
+	})
+
+	return set
+}
+
+func (c *SocksCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SocksCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *SocksCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	var err error
+	c.client, err = c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	c.index = &socksIndex{}
+	if err := c.refreshIndex(); err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+	c.sessions = &socksSessions{
+		sessions:  make(map[string]*socksSession),
+		authorize: c.authorizeSession,
+	}
+
+	listener, err := net.Listen("tcp", c.flagListen)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error starting listening port: %w", err).Error())
+		return 1
+	}
+	listenerAddr := listener.Addr().(*net.TCPAddr)
+
+	info := SocksInfo{
+		Address: listenerAddr.IP.String(),
+		Port:    listenerAddr.Port,
+		ScopeId: c.FlagScopeId,
+		Targets: c.index.size(),
+	}
+	switch base.Format(c.UI) {
+	case "table":
+		c.UI.Output(generateSocksInfoTableOutput(info))
+	case "json":
+		out, err := json.Marshal(&info)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("error marshaling socks information: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(out))
+	}
+
+	go func() {
+		<-c.Context.Done()
+		listener.Close()
+	}()
+
+	connWg := new(sync.WaitGroup)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-c.Context.Done():
+				connWg.Wait()
+				return 0
+			default:
+				c.UI.Error(fmt.Errorf("Error accepting connection: %w", err).Error())
+				continue
+			}
+		}
+		connWg.Add(1)
+		go func() {
+			defer connWg.Done()
+			defer conn.Close()
+			if err := c.handleConnection(conn); err != nil {
+				c.UI.Error(err.Error())
+			}
+		}()
+	}
+}
+
+func (c *SocksCommand) handleConnection(conn net.Conn) error {
+	host, port, err := socks5Handshake(conn)
+	if err != nil {
+		return err
+	}
+	addr := socks5Addr(host, port)
+
+	if time.Since(c.index.refreshed()) > socksRefreshInterval {
+		if err := c.refreshIndex(); err != nil {
+			c.UI.Error(err.Error())
+		}
+	}
+	target, ok := c.index.resolve(host, port)
+	if !ok && time.Since(c.index.refreshed()) > socksMinRefreshInterval {
+		if err := c.refreshIndex(); err != nil {
+			c.UI.Error(err.Error())
+		}
+		target, ok = c.index.resolve(host, port)
+	}
+	if !ok {
+		writeSocks5Reply(conn, socks5NotAllowed)
+		return fmt.Errorf("No target found for %s", addr)
+	}
+
+	// A cached session may have expired or been canceled since it was last
+	// used; in that case try once more with a new session
+	var workerConn net.Conn
+	var sess *socksSession
+	for i := 0; i < 2 && workerConn == nil; i++ {
+		sess, err = c.sessions.get(c.Context, target)
+		if err != nil {
+			writeSocks5Reply(conn, socks5NotAllowed)
+			return fmt.Errorf("Error authorizing a session for %s against target %s: %w", addr, target.targetId, err)
+		}
+		var connsLeft int32
+		workerConn, connsLeft, err = DialWorker(sess.ctx, sess.workerAddr, sess.tofuToken, sess.transport)
+		switch {
+		case err == ErrConnectionUnauthorized || err == ErrSessionInUse:
+			c.sessions.remove(target, sess)
+		case err != nil:
+			writeSocks5Reply(conn, socks5HostUnreachable)
+			return fmt.Errorf("Error connecting to %s through target %s: %w", addr, target.targetId, err)
+		default:
+			sess.connectionsLeft.Store(connsLeft)
+		}
+	}
+	if workerConn == nil {
+		writeSocks5Reply(conn, socks5GeneralFailure)
+		return fmt.Errorf("Error connecting to %s through target %s: %w", addr, target.targetId, err)
+	}
+
+	if err := writeSocks5Reply(conn, socks5Succeeded); err != nil {
+		workerConn.Close()
+		return fmt.Errorf("error writing socks reply: %w", err)
+	}
+	ProxyConnection(conn, workerConn)
+	return nil
+}
+
+// authorizeSession authorizes a session against the target.
+func (c *SocksCommand) authorizeSession(ctx context.Context, target socksTarget) (*socksSession, error) {
+	var opts []targets.Option
+	if target.hostId != "" {
+		opts = append(opts, targets.WithHostId(target.hostId))
+	}
+	sar, err := targets.NewClient(c.client).AuthorizeSession(ctx, target.targetId, opts...)
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			return nil, errors.New(base.PrintApiError(apiErr))
+		}
+		return nil, err
+	}
+
+	data, err := DecodeAuthorization(sar.GetItem().(*targets.SessionAuthorization).AuthorizationToken)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.GetWorkerInfo()) == 0 {
+		return nil, errors.New("No workers found in authorization data")
+	}
+	transport, parsedCert, err := SessionTransport(data)
+	if err != nil {
+		return nil, err
+	}
+	tofuToken, err := base62.Random(20)
+	if err != nil {
+		return nil, fmt.Errorf("Could not derive random bytes for tofu token: %w", err)
+	}
+
+	sess := &socksSession{
+		sessionId:  data.GetSessionId(),
+		workerAddr: data.GetWorkerInfo()[0].GetAddress(),
+		tofuToken:  tofuToken,
+		transport:  transport,
+		expiration: parsedCert.NotAfter,
+	}
+	sess.connectionsLeft.Store(data.GetConnectionLimit())
+	// The session's connections are closed when the command is shut down or
+	// the session expires
+	sess.ctx, sess.cancel = context.WithDeadline(c.Context, sess.expiration)
+	return sess, nil
+}
+
+// refreshIndex lists the targets in the scope and its child scopes and the
+// addresses of their hosts again.
+func (c *SocksCommand) refreshIndex() error {
+	var entries []socksIndexEntry
+	scopeIds := []string{c.FlagScopeId}
+	for len(scopeIds) > 0 {
+		scopeId := scopeIds[0]
+		scopeIds = scopeIds[1:]
+
+		// Only projects contain targets, and only global and org scopes
+		// contain other scopes
+		if strings.HasPrefix(scopeId, "p_") {
+			projectEntries, err := c.listProjectTargets(scopeId)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, projectEntries...)
+			continue
+		}
+		result, err := scopes.NewClient(c.client).List(c.Context, scopeId)
+		if err != nil {
+			return fmt.Errorf("Error listing scopes in %s: %w", scopeId, err)
+		}
+		for _, s := range result.Items {
+			scopeIds = append(scopeIds, s.Id)
+		}
+	}
+	c.index.store(entries)
+	return nil
+}
+
+// listProjectTargets returns the targets of the project with the addresses of
+// the hosts in their host sets.
+func (c *SocksCommand) listProjectTargets(scopeId string) ([]socksIndexEntry, error) {
+	targetClient := targets.NewClient(c.client)
+	result, err := targetClient.List(c.Context, scopeId)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing targets in %s: %w", scopeId, err)
+	}
+
+	// Hosts are listed once per catalog
+	catalogHosts := make(map[string]map[string]string)
+	hostAddresses := func(catalogId string) (map[string]string, error) {
+		if addrs, ok := catalogHosts[catalogId]; ok {
+			return addrs, nil
+		}
+		result, err := hosts.NewClient(c.client).List(c.Context, catalogId)
+		if err != nil {
+			return nil, fmt.Errorf("Error listing hosts in %s: %w", catalogId, err)
+		}
+		addrs := make(map[string]string, len(result.Items))
+		for _, h := range result.Items {
+			if addr, ok := h.Attributes["address"].(string); ok {
+				addrs[h.Id] = addr
+			}
+		}
+		catalogHosts[catalogId] = addrs
+		return addrs, nil
+	}
+
+	entries := make([]socksIndexEntry, 0, len(result.Items))
+	for _, t := range result.Items {
+		// Host sets are not included when listing targets
+		read, err := targetClient.Read(c.Context, t.Id)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading target %s: %w", t.Id, err)
+		}
+		t = read.Item
+
+		entry := socksIndexEntry{
+			id:        t.Id,
+			name:      t.Name,
+			addresses: make(map[string]string),
+		}
+		if port, ok := t.Attributes["default_port"].(float64); ok {
+			entry.defaultPort = int(port)
+		}
+		for _, hs := range t.HostSets {
+			addrs, err := hostAddresses(hs.HostCatalogId)
+			if err != nil {
+				return nil, err
+			}
+			set, err := hostsets.NewClient(c.client).Read(c.Context, hs.Id)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading host set %s: %w", hs.Id, err)
+			}
+			for _, hostId := range set.Item.HostIds {
+				if addr, ok := addrs[hostId]; ok {
+					entry.addresses[strings.ToLower(addr)] = hostId
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// socksTarget is the target a SOCKS request resolves to and, if the request
+// was for the address of one of its hosts, the host.
+type socksTarget struct {
+	targetId string
+	hostId   string
+}
+
+// socksIndexEntry is a target the SOCKS proxy can resolve requests to.
+type socksIndexEntry struct {
+	id          string
+	name        string
+	defaultPort int
+	// addresses maps the addresses of the target's hosts to the host IDs
+	addresses map[string]string
+}
+
+// socksIndex resolves the hosts of SOCKS requests to targets.
+type socksIndex struct {
+	l           sync.RWMutex
+	entries     []socksIndexEntry
+	refreshedAt time.Time
+}
+
+func (i *socksIndex) store(entries []socksIndexEntry) {
+	sort.Slice(entries, func(a, b int) bool { return entries[a].id < entries[b].id })
+	i.l.Lock()
+	defer i.l.Unlock()
+	i.entries = entries
+	i.refreshedAt = time.Now()
+}
+
+func (i *socksIndex) refreshed() time.Time {
+	i.l.RLock()
+	defer i.l.RUnlock()
+	return i.refreshedAt
+}
+
+func (i *socksIndex) size() int {
+	i.l.RLock()
+	defer i.l.RUnlock()
+	return len(i.entries)
+}
+
+// resolve returns the target for the host and port of a request. A target
+// whose ID or name is the host is preferred, and of several targets with the
+// same name the one whose default port is the port. Otherwise the host is
+// looked up in the addresses of the targets' hosts, which requires the
+// target's default port to be the port if it has one.
+func (i *socksIndex) resolve(host string, port int) (socksTarget, bool) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	i.l.RLock()
+	defer i.l.RUnlock()
+
+	var byName []socksIndexEntry
+	for _, e := range i.entries {
+		if host == strings.ToLower(e.id) || (e.name != "" && host == strings.ToLower(e.name)) {
+			byName = append(byName, e)
+		}
+	}
+	switch len(byName) {
+	case 0:
+	case 1:
+		return socksTarget{targetId: byName[0].id}, true
+	default:
+		for _, e := range byName {
+			if e.defaultPort == port {
+				return socksTarget{targetId: e.id}, true
+			}
+		}
+		return socksTarget{targetId: byName[0].id}, true
+	}
+
+	for _, e := range i.entries {
+		hostId, ok := e.addresses[host]
+		if !ok {
+			continue
+		}
+		if e.defaultPort != 0 && e.defaultPort != port {
+			continue
+		}
+		return socksTarget{targetId: e.id, hostId: hostId}, true
+	}
+	return socksTarget{}, false
+}
+
+// socksSession is a session authorized for the SOCKS proxy, which is used for
+// connections to its target until it expires or has no connections left.
+type socksSession struct {
+	sessionId       string
+	workerAddr      string
+	tofuToken       string
+	transport       *http.Transport
+	expiration      time.Time
+	connectionsLeft atomic.Int32
+	ctx             context.Context
+	cancel          context.CancelFunc
+}
+
+// usable returns whether new connections can be made for the session.
+func (s *socksSession) usable() bool {
+	return time.Until(s.expiration) > socksSessionExpiryMargin &&
+		s.connectionsLeft.Load() != 0 &&
+		s.ctx.Err() == nil
+}
+
+// socksSessions caches the sessions of the SOCKS proxy by target.
+type socksSessions struct {
+	l         sync.Mutex
+	sessions  map[string]*socksSession
+	authorize func(context.Context, socksTarget) (*socksSession, error)
+}
+
+func (t socksTarget) key() string {
+	return t.targetId + "/" + t.hostId
+}
+
+// get returns a usable session for the target, authorizing a new one if the
+// cached session is not usable. Expired sessions are dropped from the cache.
+// The lock is held while authorizing so that concurrent connections to a
+// target share one new session.
+func (s *socksSessions) get(ctx context.Context, target socksTarget) (*socksSession, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if sess, ok := s.sessions[target.key()]; ok {
+		if sess.usable() {
+			return sess, nil
+		}
+		delete(s.sessions, target.key())
+		if time.Now().After(sess.expiration) {
+			sess.cancel()
+		}
+	}
+
+	sess, err := s.authorize(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	s.sessions[target.key()] = sess
+	return sess, nil
+}
+
+// remove drops the session from the cache if it is the one cached for the
+// target, e.g. after the worker refused a connection for it. Its open
+// connections are not closed.
+func (s *socksSessions) remove(target socksTarget, sess *socksSession) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.sessions[target.key()] == sess {
+		delete(s.sessions, target.key())
+	}
+}
@@ -0,0 +1,140 @@
+package connect
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+)
+
+// The parts of SOCKS version 5 (RFC 1928) used by "connect socks": no
+// authentication and the CONNECT command.
+const (
+	socks5Version = 0x05
+
+	socks5AuthNone         = 0x00
+	socks5AuthNoAcceptable = 0xff
+
+	socks5CmdConnect = 0x01
+
+	socks5AddrIPv4   = 0x01
+	socks5AddrDomain = 0x03
+	socks5AddrIPv6   = 0x04
+)
+
+// socks5Reply is the reply field of a SOCKS5 reply.
+type socks5Reply byte
+
+const (
+	socks5Succeeded            socks5Reply = 0x00
+	socks5GeneralFailure       socks5Reply = 0x01
+	socks5NotAllowed           socks5Reply = 0x02
+	socks5HostUnreachable      socks5Reply = 0x04
+	socks5CmdNotSupported      socks5Reply = 0x07
+	socks5AddrTypeNotSupported socks5Reply = 0x08
+)
+
+// socks5Error is an error in a SOCKS5 request, answered with its reply.
+type socks5Error struct {
+	reply socks5Reply
+	msg   string
+}
+
+func (e *socks5Error) Error() string {
+	return e.msg
+}
+
+// socks5Handshake reads the client's greeting and request from conn and
+// returns the requested host and port. Requests which are not supported are
+// answered with an error reply. The caller must answer a returned request
+// with writeSocks5Reply.
+func socks5Handshake(conn io.ReadWriter) (string, int, error) {
+	// Greeting: version, number of methods, methods
+	var header [2]byte
+	if _, err := io.ReadFull(conn, header[:]); err != nil {
+		return "", 0, fmt.Errorf("error reading socks greeting: %w", err)
+	}
+	if header[0] != socks5Version {
+		return "", 0, fmt.Errorf("unsupported socks version %d", header[0])
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", 0, fmt.Errorf("error reading socks authentication methods: %w", err)
+	}
+	var noAuth bool
+	for _, m := range methods {
+		if m == socks5AuthNone {
+			noAuth = true
+		}
+	}
+	if !noAuth {
+		conn.Write([]byte{socks5Version, socks5AuthNoAcceptable})
+		return "", 0, errors.New("socks client does not support connecting without authentication")
+	}
+	if _, err := conn.Write([]byte{socks5Version, socks5AuthNone}); err != nil {
+		return "", 0, fmt.Errorf("error writing socks authentication method: %w", err)
+	}
+
+	// Request: version, command, reserved, address type, address, port
+	var req [4]byte
+	if _, err := io.ReadFull(conn, req[:]); err != nil {
+		return "", 0, fmt.Errorf("error reading socks request: %w", err)
+	}
+	if req[0] != socks5Version {
+		return "", 0, fmt.Errorf("unsupported socks version %d", req[0])
+	}
+
+	var host string
+	switch req[3] {
+	case socks5AddrIPv4, socks5AddrIPv6:
+		ip := make(net.IP, net.IPv4len)
+		if req[3] == socks5AddrIPv6 {
+			ip = make(net.IP, net.IPv6len)
+		}
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", 0, fmt.Errorf("error reading socks request address: %w", err)
+		}
+		host = ip.String()
+	case socks5AddrDomain:
+		var l [1]byte
+		if _, err := io.ReadFull(conn, l[:]); err != nil {
+			return "", 0, fmt.Errorf("error reading socks request address: %w", err)
+		}
+		domain := make([]byte, l[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", 0, fmt.Errorf("error reading socks request address: %w", err)
+		}
+		host = string(domain)
+	default:
+		err := &socks5Error{reply: socks5AddrTypeNotSupported, msg: fmt.Sprintf("unsupported socks address type %d", req[3])}
+		writeSocks5Reply(conn, err.reply)
+		return "", 0, err
+	}
+
+	var port [2]byte
+	if _, err := io.ReadFull(conn, port[:]); err != nil {
+		return "", 0, fmt.Errorf("error reading socks request port: %w", err)
+	}
+
+	if req[1] != socks5CmdConnect {
+		err := &socks5Error{reply: socks5CmdNotSupported, msg: fmt.Sprintf("unsupported socks command %d", req[1])}
+		writeSocks5Reply(conn, err.reply)
+		return "", 0, err
+	}
+
+	return host, int(binary.BigEndian.Uint16(port[:])), nil
+}
+
+// writeSocks5Reply answers a SOCKS5 request. The bound address is not
+// reported, as connections are proxied through a worker.
+func writeSocks5Reply(conn io.Writer, reply socks5Reply) error {
+	_, err := conn.Write([]byte{socks5Version, byte(reply), 0x00, socks5AddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+// socks5Addr returns the address of a SOCKS5 request for display.
+func socks5Addr(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
@@ -0,0 +1,234 @@
+package connect
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocks5Handshake(t *testing.T) {
+	port := func(p uint16) []byte {
+		b := make([]byte, 2)
+		binary.BigEndian.PutUint16(b, p)
+		return b
+	}
+	concat := func(parts ...[]byte) []byte {
+		var out []byte
+		for _, p := range parts {
+			out = append(out, p...)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		request   []byte
+		wantHost  string
+		wantPort  int
+		wantReply []byte
+		wantErr   bool
+	}{
+		{
+			name:      "domain",
+			request:   concat([]byte{5, 1, 0, 5, 1, 0, 3, 5}, []byte("myapp"), port(443)),
+			wantHost:  "myapp",
+			wantPort:  443,
+			wantReply: []byte{5, 0},
+		},
+		{
+			name:      "ipv4",
+			request:   concat([]byte{5, 2, 2, 0, 5, 1, 0, 1, 10, 0, 0, 1}, port(22)),
+			wantHost:  "10.0.0.1",
+			wantPort:  22,
+			wantReply: []byte{5, 0},
+		},
+		{
+			name:      "ipv6",
+			request:   concat([]byte{5, 1, 0, 5, 1, 0, 4}, net.ParseIP("fd00::1"), port(5432)),
+			wantHost:  "fd00::1",
+			wantPort:  5432,
+			wantReply: []byte{5, 0},
+		},
+		{
+			name:      "no acceptable auth",
+			request:   []byte{5, 1, 2},
+			wantReply: []byte{5, 0xff},
+			wantErr:   true,
+		},
+		{
+			name:      "bind",
+			request:   concat([]byte{5, 1, 0, 5, 2, 0, 1, 10, 0, 0, 1}, port(22)),
+			wantReply: []byte{5, 0, 5, byte(socks5CmdNotSupported), 0, 1, 0, 0, 0, 0, 0, 0},
+			wantErr:   true,
+		},
+		{
+			name:    "socks4",
+			request: []byte{4, 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			client, server := net.Pipe()
+			defer client.Close()
+
+			type result struct {
+				host string
+				port int
+				err  error
+			}
+			resCh := make(chan result, 1)
+			go func() {
+				host, port, err := socks5Handshake(server)
+				server.Close()
+				resCh <- result{host, port, err}
+			}()
+
+			go client.Write(tt.request)
+			reply, err := ioutil.ReadAll(client)
+			require.NoError(err)
+			assert.Equal(tt.wantReply, nilIfEmpty(reply))
+
+			res := <-resCh
+			if tt.wantErr {
+				assert.Error(res.err)
+				return
+			}
+			require.NoError(res.err)
+			assert.Equal(tt.wantHost, res.host)
+			assert.Equal(tt.wantPort, res.port)
+		})
+	}
+}
+
+func nilIfEmpty(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+func TestSocksIndex_resolve(t *testing.T) {
+	index := &socksIndex{}
+	index.store([]socksIndexEntry{
+		{
+			id:          "ttcp_web",
+			name:        "web",
+			defaultPort: 443,
+			addresses:   map[string]string{"10.0.0.1": "hst_web1", "web1.internal": "hst_web1b"},
+		},
+		{
+			id:          "ttcp_ssh",
+			name:        "ssh",
+			defaultPort: 22,
+			addresses:   map[string]string{"10.0.0.1": "hst_ssh1"},
+		},
+		{
+			id:          "ttcp_dbprod",
+			name:        "db",
+			defaultPort: 5432,
+		},
+		{
+			id:          "ttcp_dbdev",
+			name:        "db",
+			defaultPort: 5433,
+		},
+		{
+			id:        "ttcp_any",
+			addresses: map[string]string{"10.0.0.2": "hst_any"},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		host   string
+		port   int
+		want   socksTarget
+		wantOk bool
+	}{
+		{name: "by name", host: "web", port: 80, want: socksTarget{targetId: "ttcp_web"}, wantOk: true},
+		{name: "by name case and dot", host: "WEB.", port: 443, want: socksTarget{targetId: "ttcp_web"}, wantOk: true},
+		{name: "by id", host: "ttcp_ssh", port: 22, want: socksTarget{targetId: "ttcp_ssh"}, wantOk: true},
+		{name: "duplicate name by port", host: "db", port: 5433, want: socksTarget{targetId: "ttcp_dbdev"}, wantOk: true},
+		{name: "duplicate name other port", host: "db", port: 1, want: socksTarget{targetId: "ttcp_dbdev"}, wantOk: true},
+		{name: "address by port", host: "10.0.0.1", port: 22, want: socksTarget{targetId: "ttcp_ssh", hostId: "hst_ssh1"}, wantOk: true},
+		{name: "address other target", host: "10.0.0.1", port: 443, want: socksTarget{targetId: "ttcp_web", hostId: "hst_web1"}, wantOk: true},
+		{name: "address wrong port", host: "10.0.0.1", port: 80},
+		{name: "hostname address", host: "Web1.Internal", port: 443, want: socksTarget{targetId: "ttcp_web", hostId: "hst_web1b"}, wantOk: true},
+		{name: "address no default port", host: "10.0.0.2", port: 8080, want: socksTarget{targetId: "ttcp_any", hostId: "hst_any"}, wantOk: true},
+		{name: "unknown", host: "example.com", port: 443},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := index.resolve(tt.host, tt.port)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSocksSessions(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	var authorized int
+	var authzErr error
+	sessions := &socksSessions{
+		sessions: make(map[string]*socksSession),
+		authorize: func(ctx context.Context, target socksTarget) (*socksSession, error) {
+			if authzErr != nil {
+				return nil, authzErr
+			}
+			authorized++
+			sess := &socksSession{expiration: time.Now().Add(time.Hour)}
+			sess.connectionsLeft.Store(-1)
+			sess.ctx, sess.cancel = context.WithDeadline(ctx, sess.expiration)
+			return sess, nil
+		},
+	}
+	web := socksTarget{targetId: "ttcp_web"}
+
+	first, err := sessions.get(context.Background(), web)
+	require.NoError(err)
+	second, err := sessions.get(context.Background(), web)
+	require.NoError(err)
+	assert.Same(first, second)
+	assert.Equal(1, authorized)
+
+	// A session for a specific host is a different session
+	_, err = sessions.get(context.Background(), socksTarget{targetId: "ttcp_web", hostId: "hst_1"})
+	require.NoError(err)
+	assert.Equal(2, authorized)
+
+	// A session without connections left is replaced
+	first.connectionsLeft.Store(0)
+	third, err := sessions.get(context.Background(), web)
+	require.NoError(err)
+	assert.NotSame(first, third)
+	assert.Equal(3, authorized)
+
+	// A session close to its expiration is replaced
+	third.expiration = time.Now().Add(socksSessionExpiryMargin / 2)
+	fourth, err := sessions.get(context.Background(), web)
+	require.NoError(err)
+	assert.NotSame(third, fourth)
+	assert.Equal(4, authorized)
+
+	// Removing a session which was already replaced keeps the replacement
+	sessions.remove(web, third)
+	same, err := sessions.get(context.Background(), web)
+	require.NoError(err)
+	assert.Same(fourth, same)
+
+	sessions.remove(web, fourth)
+	authzErr = errors.New("not authorized")
+	_, err = sessions.get(context.Background(), web)
+	assert.Error(err)
+}
@@ -0,0 +1,278 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/proxy"
+	"go.uber.org/atomic"
+	"nhooyr.io/websocket"
+)
+
+// udpFlowIdleTimeout is how long the source address of a flow is remembered
+// without datagrams in either direction.
+const udpFlowIdleTimeout = 2 * time.Minute
+
+// runUdp proxies the datagrams received on a local UDP socket to the target
+// of a udp-type session. All datagrams are relayed over a single connection
+// to the worker; each source address is a flow of its own, so that replies
+// are sent back to the client they are for.
+func (c *Command) runUdp(listenAddr net.IP, workerAddr, tofuToken string, transport *http.Transport, passthroughArgs []string) int {
+	if c.Func != "connect" {
+		c.UI.Error(fmt.Sprintf("Target %s is a udp-type target, which can only be connected to with \"boundary connect\"", c.sessionAuthzData.GetTargetId()))
+		return 1
+	}
+
+	localConn, err := net.ListenUDP("udp", &net.UDPAddr{
+		IP:   listenAddr,
+		Port: c.flagListenPort,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error starting listening port: %w", err).Error())
+		return 1
+	}
+	defer localConn.Close()
+	localAddr := localConn.LocalAddr().(*net.UDPAddr)
+	// handleExec only uses the IP address and port of the listener
+	c.listenerAddr = &net.TCPAddr{IP: localAddr.IP, Port: localAddr.Port}
+
+	if c.flagExec == "" {
+		sessInfo := SessionInfo{
+			Protocol:        "udp",
+			Address:         localAddr.IP.String(),
+			Port:            localAddr.Port,
+			Expiration:      c.expiration,
+			ConnectionLimit: c.sessionAuthzData.GetConnectionLimit(),
+			SessionId:       c.sessionAuthzData.GetSessionId(),
+		}
+
+		switch base.Format(c.UI) {
+		case "table":
+			c.UI.Output(generateSessionInfoTableOutput(sessInfo))
+		case "json":
+			out, err := json.Marshal(&sessInfo)
+			if err != nil {
+				c.UI.Error(fmt.Errorf("error marshaling session information: %w", err).Error())
+				return 1
+			}
+			c.UI.Output(string(out))
+		}
+	}
+
+	workerConn, connsLeft, err := DialWorkerUdp(c.proxyCtx, workerAddr, tofuToken, transport)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 2
+	}
+	defer workerConn.Close(websocket.StatusNormalClosure, "done")
+	if connsLeft != -1 {
+		c.updateConnsLeft(connsLeft)
+	}
+
+	c.connWg = new(sync.WaitGroup)
+	if c.flagExec != "" {
+		c.connWg.Add(1)
+		c.execCmdReturnValue = new(atomic.Int32)
+		go c.handleExec(passthroughArgs)
+	}
+
+	relay := newUdpLocalRelay(localConn, udpFlowIdleTimeout, func(ctx context.Context, frame []byte) error {
+		return workerConn.Write(ctx, websocket.MessageBinary, frame)
+	})
+	relayErr := relay.run(c.proxyCtx, func(ctx context.Context) ([]byte, error) {
+		_, frame, err := workerConn.Read(ctx)
+		return frame, err
+	})
+	c.connWg.Wait()
+
+	retCode := 0
+	if c.execCmdReturnValue != nil {
+		retCode = int(c.execCmdReturnValue.Load())
+	}
+
+	termInfo := TerminationInfo{Reason: "Connection to the worker was closed"}
+	switch {
+	case c.Context.Err() != nil:
+		termInfo.Reason = "Received shutdown signal"
+	case !time.Now().Before(c.expiration):
+		termInfo.Reason = "Session has expired"
+	case c.execCmdReturnValue != nil:
+		termInfo.Reason = ""
+	case relayErr != nil:
+		termInfo.Reason = fmt.Sprintf("Connection to the worker was closed: %s", relayErr)
+	}
+	if termInfo.Reason != "" {
+		switch base.Format(c.UI) {
+		case "table":
+			c.UI.Output(generateTerminationInfoTableOutput(termInfo))
+		case "json":
+			out, err := json.Marshal(&termInfo)
+			if err != nil {
+				c.UI.Error(fmt.Errorf("error marshaling termination information: %w", err).Error())
+				return 1
+			}
+			c.UI.Output(string(out))
+		}
+	}
+	return retCode
+}
+
+// udpLocalFlow is the source address of a flow.
+type udpLocalFlow struct {
+	id   uint32
+	addr *net.UDPAddr
+
+	// lastActive is the unix time in nanoseconds a datagram of the flow was
+	// last relayed at
+	lastActive atomic.Int64
+}
+
+func (f *udpLocalFlow) touch() {
+	f.lastActive.Store(time.Now().UnixNano())
+}
+
+// udpLocalRelay relays the datagrams received on a local socket as frames
+// and sends the datagrams of the frames it is given back to the source
+// address of their flow.
+type udpLocalRelay struct {
+	conn        *net.UDPConn
+	idleTimeout time.Duration
+	send        func(context.Context, []byte) error
+
+	l      sync.Mutex
+	nextId uint32
+	byAddr map[string]*udpLocalFlow
+	byId   map[uint32]*udpLocalFlow
+}
+
+func newUdpLocalRelay(conn *net.UDPConn, idleTimeout time.Duration, send func(context.Context, []byte) error) *udpLocalRelay {
+	return &udpLocalRelay{
+		conn:        conn,
+		idleTimeout: idleTimeout,
+		send:        send,
+		byAddr:      make(map[string]*udpLocalFlow),
+		byId:        make(map[uint32]*udpLocalFlow),
+	}
+}
+
+// run relays datagrams until ctx is done or relaying in either direction
+// fails, and returns the error it failed with.
+func (r *udpLocalRelay) run(ctx context.Context, read func(context.Context) ([]byte, error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var errOnce sync.Once
+	var relayErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			if ctx.Err() == nil {
+				relayErr = err
+			}
+			cancel()
+		})
+	}
+
+	wg := new(sync.WaitGroup)
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		// Unblocks reading from the local socket
+		r.conn.SetReadDeadline(time.Now())
+	}()
+	go func() {
+		defer wg.Done()
+		fail(r.fromLocal(ctx))
+	}()
+	go func() {
+		defer wg.Done()
+		fail(r.toLocal(ctx, read))
+	}()
+	go func() {
+		defer wg.Done()
+		r.expire(ctx)
+	}()
+	wg.Wait()
+	return relayErr
+}
+
+func (r *udpLocalRelay) fromLocal(ctx context.Context) error {
+	buf := make([]byte, proxy.MaxUdpDatagramSize)
+	for {
+		n, addr, err := r.conn.ReadFromUDP(buf)
+		if err != nil {
+			return err
+		}
+		f := r.flow(addr)
+		f.touch()
+		if err := r.send(ctx, proxy.EncodeUdpFrame(f.id, buf[:n])); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *udpLocalRelay) toLocal(ctx context.Context, read func(context.Context) ([]byte, error)) error {
+	for {
+		frame, err := read(ctx)
+		if err != nil {
+			return err
+		}
+		flowId, datagram, err := proxy.DecodeUdpFrame(frame)
+		if err != nil {
+			return err
+		}
+		r.l.Lock()
+		f, ok := r.byId[flowId]
+		r.l.Unlock()
+		if !ok {
+			// The flow expired; there is nobody to relay the reply to
+			continue
+		}
+		f.touch()
+		if _, err := r.conn.WriteToUDP(datagram, f.addr); err != nil {
+			return err
+		}
+	}
+}
+
+// flow returns the flow of the source address, creating it if needed.
+func (r *udpLocalRelay) flow(addr *net.UDPAddr) *udpLocalFlow {
+	r.l.Lock()
+	defer r.l.Unlock()
+	if f, ok := r.byAddr[addr.String()]; ok {
+		return f
+	}
+	r.nextId++
+	f := &udpLocalFlow{id: r.nextId, addr: addr}
+	r.byAddr[addr.String()] = f
+	r.byId[f.id] = f
+	return f
+}
+
+// expire forgets the flows idle for longer than the idle timeout.
+func (r *udpLocalRelay) expire(ctx context.Context) {
+	ticker := time.NewTicker(r.idleTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cutoff := time.Now().Add(-r.idleTimeout).UnixNano()
+		r.l.Lock()
+		for id, f := range r.byId {
+			if f.lastActive.Load() < cutoff {
+				delete(r.byId, id)
+				delete(r.byAddr, f.addr.String())
+			}
+		}
+		r.l.Unlock()
+	}
+}
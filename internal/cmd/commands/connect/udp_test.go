@@ -0,0 +1,97 @@
+package connect
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUdpLocalRelay(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	local, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(err)
+	defer local.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan []byte)
+	out := make(chan []byte, 10)
+	relay := newUdpLocalRelay(local, 200*time.Millisecond, func(ctx context.Context, frame []byte) error {
+		out <- frame
+		return nil
+	})
+	done := make(chan error)
+	go func() {
+		done <- relay.run(ctx, func(ctx context.Context) ([]byte, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case frame := <-in:
+				return frame, nil
+			}
+		})
+	}()
+
+	dial := func() *net.UDPConn {
+		c, err := net.DialUDP("udp", nil, local.LocalAddr().(*net.UDPAddr))
+		require.NoError(err)
+		return c
+	}
+	sent := func(c *net.UDPConn, msg string) uint32 {
+		_, err := c.Write([]byte(msg))
+		require.NoError(err)
+		select {
+		case frame := <-out:
+			flowId, datagram, err := proxy.DecodeUdpFrame(frame)
+			require.NoError(err)
+			assert.Equal(msg, string(datagram))
+			return flowId
+		case <-time.After(5 * time.Second):
+			require.FailNow("no frame from relay")
+		}
+		return 0
+	}
+	received := func(c *net.UDPConn) string {
+		require.NoError(c.SetReadDeadline(time.Now().Add(5 * time.Second)))
+		buf := make([]byte, 100)
+		n, err := c.Read(buf)
+		require.NoError(err)
+		return string(buf[:n])
+	}
+
+	// Each source address is a flow of its own
+	a, b := dial(), dial()
+	defer a.Close()
+	defer b.Close()
+	idA := sent(a, "a1")
+	assert.Equal(idA, sent(a, "a2"))
+	idB := sent(b, "b1")
+	assert.NotEqual(idA, idB)
+
+	// Replies are relayed to the source address of their flow
+	in <- proxy.EncodeUdpFrame(idB, []byte("to b"))
+	in <- proxy.EncodeUdpFrame(idA, []byte("to a"))
+	assert.Equal("to b", received(b))
+	assert.Equal("to a", received(a))
+
+	// Idle flows are expired
+	require.Eventually(func() bool {
+		relay.l.Lock()
+		defer relay.l.Unlock()
+		return len(relay.byId) == 0 && len(relay.byAddr) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NotEqual(idA, sent(a, "a3"))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(err)
+	case <-time.After(5 * time.Second):
+		require.FailNow("relay did not stop when its context was done")
+	}
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/sdk/wrapper"
+	"github.com/posener/complete"
+)
+
+// addConfigFlags adds the flags used to locate the controller configuration
+// to commands which operate on an initialized database.
+func addConfigFlags(f *base.FlagSet, flagConfig, flagConfigKms *string) {
+	f.StringVar(&base.StringVar{
+		Name:   "config",
+		Target: flagConfig,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: "Path to the configuration file.",
+	})
+
+	f.StringVar(&base.StringVar{
+		Name:   "config-kms",
+		Target: flagConfigKms,
+		Completion: complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		Usage: `Path to a configuration file containing a "kms" block marked for "config" purpose, to perform decryption of the main configuration file. If not set, will look for such a block in the main configuration file, which has some drawbacks; see the help output for "boundary config encrypt -h" for details.`,
+	})
+}
+
+// loadConfig loads the controller configuration, decrypting it with the
+// config KMS if there is one. The returned cleanup function must be called
+// once finished with the configuration.
+func loadConfig(c *base.Command, flagConfig, flagConfigKms string) (*config.Config, func(), error) {
+	if flagConfig == "" {
+		return nil, nil, errors.New("Must specify a config file using -config")
+	}
+
+	wrapperPath := flagConfig
+	if flagConfigKms != "" {
+		wrapperPath = flagConfigKms
+	}
+	configWrapper, err := wrapper.GetWrapperFromPath(wrapperPath, "config")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {}
+	if configWrapper != nil {
+		if err := configWrapper.Init(c.Context); err != nil {
+			return nil, nil, fmt.Errorf("Could not initialize kms: %w", err)
+		}
+		cleanup = func() {
+			if err := configWrapper.Finalize(c.Context); err != nil {
+				c.UI.Warn(fmt.Errorf("Error finalizing config kms: %w", err).Error())
+			}
+		}
+	}
+
+	conf, err := config.LoadFile(flagConfig, configWrapper)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("Error parsing config: %w", err)
+	}
+	return conf, cleanup, nil
+}
+
+// openDatabase loads the controller configuration, sets up its KMSes and
+// connects to its database. The returned kms can decrypt values encrypted by
+// any scope. The returned cleanup function must be called once finished with
+// the database.
+func openDatabase(c *base.Command, flagConfig, flagConfigKms string) (*base.Server, *kms.Kms, func(), error) {
+	conf, cleanup, err := loadConfig(c, flagConfig, flagConfigKms)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	srv := base.NewServer(&base.Command{UI: c.UI, Context: c.Context})
+	if err := srv.SetupLogging("err", "", "", ""); err != nil {
+		cleanup()
+		return nil, nil, nil, err
+	}
+	if err := srv.SetupKMSes(c.UI, conf); err != nil {
+		cleanup()
+		return nil, nil, nil, err
+	}
+	if srv.RootKms == nil {
+		cleanup()
+		return nil, nil, nil, errors.New("Root KMS not found after parsing KMS blocks")
+	}
+
+	if conf.Controller == nil || conf.Controller.Database == nil {
+		cleanup()
+		return nil, nil, nil, errors.New(`"controller.database" config block not found`)
+	}
+	if conf.Controller.Database.Url == "" {
+		cleanup()
+		return nil, nil, nil, errors.New(`"url" not specified in "database" config block`)
+	}
+	dbaseUrl, err := config.ParseAddress(conf.Controller.Database.Url)
+	if err != nil && err != config.ErrNotAUrl {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("Error parsing database url: %w", err)
+	}
+	srv.DatabaseUrl = strings.TrimSpace(dbaseUrl)
+	if err := srv.ConnectToDatabase("postgres"); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("Error connecting to database: %w", err)
+	}
+	dbCleanup := cleanup
+	cleanup = func() {
+		if err := srv.Database.Close(); err != nil {
+			c.UI.Warn(fmt.Errorf("Error closing database: %w", err).Error())
+		}
+		dbCleanup()
+	}
+
+	rw := db.New(srv.Database)
+	kmsRepo, err := kms.NewRepository(rw, rw)
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("Error creating kms repository: %w", err)
+	}
+	kmsCache, err := kms.NewKms(kmsRepo)
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("Error creating kms cache: %w", err)
+	}
+	if err := kmsCache.AddExternalWrappers(kms.WithRootWrapper(srv.RootKms)); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("Error adding config keys to kms: %w", err)
+	}
+
+	return srv, kmsCache, cleanup, nil
+}
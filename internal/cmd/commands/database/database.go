@@ -27,6 +27,18 @@ func (c *Command) Help() string {
 		"",
 		`      $ boundary database init`,
 		"",
+		"    Migrate the database after upgrading Boundary:",
+		"",
+		`      $ boundary database migrate -config=/etc/boundary/controller.hcl`,
+		"",
+		"    List recent changes recorded in the operation log:",
+		"",
+		`      $ boundary database oplog list -config=/etc/boundary/controller.hcl`,
+		"",
+		"    Export resources to be imported into another database:",
+		"",
+		`      $ boundary database export -config=c.hcl -file=boundary.export -key-file=export.key`,
+		"",
 		"  Please see the database subcommand help for detailed usage information.",
 	})
 }
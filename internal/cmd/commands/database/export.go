@@ -0,0 +1,226 @@
+package database
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/export"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ExportCommand)(nil)
+var _ cli.CommandAutocomplete = (*ExportCommand)(nil)
+
+type ExportCommand struct {
+	*base.Command
+
+	flagConfig    string
+	flagConfigKms string
+	flagFile      string
+	flagKeyFile   string
+}
+
+func (c *ExportCommand) Synopsis() string {
+	return "Export Boundary's resources to an archive"
+}
+
+func (c *ExportCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database export [options]",
+		"",
+		"  Export the scopes, users, groups, roles, password auth methods and",
+		"  accounts, static host catalogs, hosts and host sets, and targets of a",
+		"  controller's database to a signed archive file, to be imported into",
+		`  another database with "boundary database import". Example:`,
+		"",
+		`    $ boundary database export -config=controller.hcl \`,
+		`        -file=boundary.export -key-file=export.key`,
+		"",
+		"  The archive is signed with the export key read from -key-file, which is",
+		"  generated if the file does not exist. Password salts are decrypted with",
+		"  the controller's KMS keys and encrypted with the export key, so the key",
+		"  is needed to import the archive and must be kept as secret as the",
+		"  archive itself. Auth tokens, sessions and the operation log are not",
+		"  exported.",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *ExportCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+	addConfigFlags(f, &c.flagConfig, &c.flagConfigKms)
+	addArchiveFlags(f, &c.flagFile, &c.flagKeyFile)
+	return set
+}
+
+// addArchiveFlags adds the flags locating the archive and export key files.
+func addArchiveFlags(f *base.FlagSet, flagFile, flagKeyFile *string) {
+	f.StringVar(&base.StringVar{
+		Name:       "file",
+		Target:     flagFile,
+		Completion: complete.PredictFiles("*"),
+		Usage:      "Path to the archive file.",
+	})
+	f.StringVar(&base.StringVar{
+		Name:       "key-file",
+		Target:     flagKeyFile,
+		Completion: complete.PredictFiles("*"),
+		Usage:      "Path to the file holding the base64 encoded export key.",
+	})
+}
+
+func (c *ExportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ExportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ExportCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	switch {
+	case c.flagFile == "":
+		c.UI.Error("File is required but not passed in via -file")
+		return 1
+	case c.flagKeyFile == "":
+		c.UI.Error("Key file is required but not passed in via -key-file")
+		return 1
+	}
+
+	var generatedKey bool
+	if _, err := os.Stat(c.flagKeyFile); errors.Is(err, os.ErrNotExist) {
+		b, err := export.GenerateKey(rand.Reader)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error generating export key: %w", err).Error())
+			return 1
+		}
+		if err := export.WriteKeyFile(c.flagKeyFile, b); err != nil {
+			c.UI.Error(fmt.Errorf("Error writing export key: %w", err).Error())
+			return 1
+		}
+		generatedKey = true
+	}
+	key, err := export.ReadKeyFile(c.flagKeyFile)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error reading export key: %w", err).Error())
+		return 1
+	}
+
+	srv, kmsCache, cleanup, err := openDatabase(c.Command, c.flagConfig, c.flagConfigKms)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer cleanup()
+
+	rw := db.New(srv.Database)
+	repo, err := export.NewRepository(rw, rw, kmsCache)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating export repository: %w", err).Error())
+		return 1
+	}
+	a, err := repo.Export(c.Context, key)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error exporting resources: %w", err).Error())
+		return 2
+	}
+
+	// The archive holds password hashes, so only its owner may read it.
+	file, err := os.OpenFile(c.flagFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating archive file: %w", err).Error())
+		return 1
+	}
+	if err := export.WriteArchive(file, a, key); err != nil {
+		file.Close()
+		os.Remove(c.flagFile)
+		c.UI.Error(fmt.Errorf("Error writing archive file: %w", err).Error())
+		return 1
+	}
+	if err := file.Close(); err != nil {
+		c.UI.Error(fmt.Errorf("Error writing archive file: %w", err).Error())
+		return 1
+	}
+
+	if generatedKey {
+		c.UI.Warn(fmt.Sprintf("A new export key was written to %s. It is needed to import the archive.", c.flagKeyFile))
+	}
+	return outputArchive(c.Command, "Exported resources:", c.flagFile, a, a.Count())
+}
+
+// archiveInfo is the JSON representation of an exported or imported archive.
+type archiveInfo struct {
+	File            string         `json:"file"`
+	CreateTime      time.Time      `json:"create_time"`
+	BoundaryVersion string         `json:"boundary_version"`
+	SchemaVersion   int            `json:"schema_version"`
+	Rows            map[string]int `json:"rows"`
+}
+
+func outputArchive(c *base.Command, title, file string, a *export.Archive, counts map[string]int) int {
+	switch base.Format(c.UI) {
+	case "json":
+		b, err := base.JsonFormatter{}.Format(&archiveInfo{
+			File:            file,
+			CreateTime:      a.CreateTime,
+			BoundaryVersion: a.BoundaryVersion,
+			SchemaVersion:   a.SchemaVersion,
+			Rows:            counts,
+		})
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		nonAttributeMap := map[string]interface{}{
+			"File":             file,
+			"Created Time":     a.CreateTime.Local().Format(time.RFC1123),
+			"Boundary Version": a.BoundaryVersion,
+			"Schema Version":   a.SchemaVersion,
+		}
+		maxLength := 0
+		for k := range nonAttributeMap {
+			if len(k) > maxLength {
+				maxLength = len(k)
+			}
+		}
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+			if len(name) > maxLength {
+				maxLength = len(name)
+			}
+		}
+		sort.Strings(names)
+		rows := make(map[string]interface{}, len(counts))
+		for _, name := range names {
+			rows[name] = counts[name]
+		}
+		c.UI.Output(base.WrapForHelpText([]string{
+			"",
+			"Archive information:",
+			base.WrapMap(2, maxLength+2, nonAttributeMap),
+			"",
+			title,
+			base.WrapMap(2, maxLength+2, rows),
+		}))
+	}
+	return 0
+}
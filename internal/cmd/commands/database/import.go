@@ -0,0 +1,123 @@
+package database
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/export"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*ImportCommand)(nil)
+var _ cli.CommandAutocomplete = (*ImportCommand)(nil)
+
+type ImportCommand struct {
+	*base.Command
+
+	flagConfig    string
+	flagConfigKms string
+	flagFile      string
+	flagKeyFile   string
+}
+
+func (c *ImportCommand) Synopsis() string {
+	return "Import Boundary's resources from an archive"
+}
+
+func (c *ImportCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database import [options]",
+		"",
+		`  Import the resources of an archive written by "boundary database export"`,
+		"  into a controller's database, keeping their IDs. Example:",
+		"",
+		`    $ boundary database import -config=controller.hcl \`,
+		`        -file=boundary.export -key-file=export.key`,
+		"",
+		"  The signature of the archive is verified with the export key before",
+		"  anything is imported. Imported scopes get new KMS keys encrypted with",
+		"  the root KMS of the configuration, password salts are encrypted with",
+		"  them, and an operation log entry is written for each resource.",
+		"",
+		"  The database must have been initialized by the same version of Boundary",
+		"  as the exporting database, and none of the resources may already exist.",
+		"  Initialize it with all the -skip flags of \"boundary database init\" to",
+		"  avoid conflicts with the generated resources. Everything is imported in",
+		"  a single transaction, so on failure nothing is imported.",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *ImportCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+	addConfigFlags(f, &c.flagConfig, &c.flagConfigKms)
+	addArchiveFlags(f, &c.flagFile, &c.flagKeyFile)
+	return set
+}
+
+func (c *ImportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ImportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ImportCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	switch {
+	case c.flagFile == "":
+		c.UI.Error("File is required but not passed in via -file")
+		return 1
+	case c.flagKeyFile == "":
+		c.UI.Error("Key file is required but not passed in via -key-file")
+		return 1
+	}
+
+	key, err := export.ReadKeyFile(c.flagKeyFile)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error reading export key: %w", err).Error())
+		return 1
+	}
+	file, err := os.Open(c.flagFile)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error opening archive file: %w", err).Error())
+		return 1
+	}
+	a, err := export.ReadArchive(file, key)
+	file.Close()
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error reading archive file: %w", err).Error())
+		return 1
+	}
+
+	srv, kmsCache, cleanup, err := openDatabase(c.Command, c.flagConfig, c.flagConfigKms)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer cleanup()
+
+	rw := db.New(srv.Database)
+	repo, err := export.NewRepository(rw, rw, kmsCache)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating export repository: %w", err).Error())
+		return 1
+	}
+	counts, err := repo.Import(c.Context, a, key)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error importing resources: %w", err).Error())
+		return 2
+	}
+	return outputArchive(c.Command, "Imported resources:", c.flagFile, a, counts)
+}
@@ -0,0 +1,204 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/migrations"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*MigrateCommand)(nil)
+var _ cli.CommandAutocomplete = (*MigrateCommand)(nil)
+
+type MigrateCommand struct {
+	*base.Command
+
+	flagConfig       string
+	flagConfigKms    string
+	flagMigrationUrl string
+	flagDryRun       bool
+}
+
+func (c *MigrateCommand) Synopsis() string {
+	return "Migrate Boundary's database to the schema version of this binary"
+}
+
+func (c *MigrateCommand) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database migrate [options]",
+		"",
+		"  Upgrade an initialized database to the schema version of this Boundary",
+		"  binary by running the migrations which have not been run yet. The",
+		"  current and target schema versions are printed first. Example:",
+		"",
+		`    $ boundary database migrate -config=/etc/boundary/controller.hcl`,
+		"",
+		"  With -dry-run, the SQL of the pending migrations is printed and nothing",
+		"  is run. Migrations are run while holding a database advisory lock, so",
+		"  if another process is migrating the database this command fails rather",
+		"  than running them twice. Controllers refuse to start against a schema",
+		"  newer than their binary, so all controllers should be upgraded after",
+		"  the database is migrated.",
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *MigrateCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+	addConfigFlags(f, &c.flagConfig, &c.flagConfigKms)
+
+	f.StringVar(&base.StringVar{
+		Name:   "migration-url",
+		Target: &c.flagMigrationUrl,
+		Usage:  `If set, overrides a migration URL set in config, and specifies the URL used to connect to the database for migration. This can allow different permissions for the user running migrations vs. normal operation. This can refer to a file on disk (file://) from which a URL will be read; an env var (env://) from which the URL will be read; or a direct database URL.`,
+	})
+	f.BoolVar(&base.BoolVar{
+		Name:   "dry-run",
+		Target: &c.flagDryRun,
+		Usage:  "Print the SQL of the pending migrations without running them.",
+	})
+	return set
+}
+
+func (c *MigrateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *MigrateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *MigrateCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	conf, cleanup, err := loadConfig(c.Command, c.flagConfig, c.flagConfigKms)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer cleanup()
+
+	if conf.Controller == nil || conf.Controller.Database == nil {
+		c.UI.Error(`"controller.database" config block not found`)
+		return 1
+	}
+	urlToParse := conf.Controller.Database.MigrationUrl
+	if c.flagMigrationUrl != "" {
+		urlToParse = c.flagMigrationUrl
+	}
+	// Fallback to using database URL for everything
+	if urlToParse == "" {
+		urlToParse = conf.Controller.Database.Url
+	}
+	if urlToParse == "" {
+		c.UI.Error(`"url" not specified in "database" config block`)
+		return 1
+	}
+	migrationUrl, err := config.ParseAddress(urlToParse)
+	if err != nil && err != config.ErrNotAUrl {
+		c.UI.Error(fmt.Errorf("Error parsing migration url: %w", err).Error())
+		return 1
+	}
+	migrationUrl = strings.TrimSpace(migrationUrl)
+
+	d, err := sql.Open("postgres", migrationUrl)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error opening database: %w", err).Error())
+		return 1
+	}
+	v, err := db.CheckSchemaVersion(c.Context, d, "postgres")
+	d.Close()
+	switch {
+	case errors.Is(err, db.ErrSchemaNotInitialized):
+		c.UI.Error(`Database is not initialized; initialize it with "boundary database init"`)
+		return 1
+	case err != nil:
+		c.UI.Error(fmt.Errorf("Error checking database schema version: %w", err).Error())
+		return 2
+	}
+	pending, err := v.Pending("postgres")
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error reading migrations: %w", err).Error())
+		return 1
+	}
+
+	if c.flagDryRun || len(pending) == 0 {
+		return c.output(v, pending, false)
+	}
+
+	if _, err := db.MigrateStore(c.Context, "postgres", migrationUrl); err != nil {
+		c.UI.Error(fmt.Errorf("Error migrating database: %w", err).Error())
+		return 2
+	}
+	return c.output(v, pending, true)
+}
+
+// migrationInfo is the JSON representation of a pending migration.
+type migrationInfo struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+	Sql     string `json:"sql,omitempty"`
+}
+
+func (c *MigrateCommand) output(v *db.SchemaVersion, pending []*migrations.Migration, migrated bool) int {
+	switch base.Format(c.UI) {
+	case "json":
+		out := struct {
+			CurrentVersion int              `json:"current_version"`
+			TargetVersion  int              `json:"target_version"`
+			Pending        []*migrationInfo `json:"pending_migrations"`
+			Migrated       bool             `json:"migrated"`
+		}{
+			CurrentVersion: v.Current,
+			TargetVersion:  v.Latest,
+			Pending:        make([]*migrationInfo, 0, len(pending)),
+			Migrated:       migrated,
+		}
+		for _, m := range pending {
+			info := &migrationInfo{Version: m.Version, Name: m.Name}
+			if c.flagDryRun {
+				info.Sql = m.Sql
+			}
+			out.Pending = append(out.Pending, info)
+		}
+		b, err := base.JsonFormatter{}.Format(out)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		c.UI.Output(fmt.Sprintf("Current schema version: %d", v.Current))
+		c.UI.Output(fmt.Sprintf("Target schema version:  %d", v.Latest))
+		if len(pending) == 0 {
+			c.UI.Info("Database schema is up to date.")
+			return 0
+		}
+		if c.flagDryRun {
+			for _, m := range pending {
+				c.UI.Output(fmt.Sprintf("\n-- Migration %s\n%s", m.Name, strings.TrimSpace(m.Sql)))
+			}
+			return 0
+		}
+		names := make([]string, 0, len(pending))
+		for _, m := range pending {
+			names = append(names, m.Name)
+		}
+		c.UI.Info(fmt.Sprintf("Ran %d migrations: %s", len(pending), strings.Join(names, ", ")))
+	}
+	return 0
+}
@@ -0,0 +1,589 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var _ cli.Command = (*OplogCommand)(nil)
+var _ cli.CommandAutocomplete = (*OplogCommand)(nil)
+
+type OplogCommand struct {
+	*base.Command
+
+	Func string
+
+	flagConfig        string
+	flagConfigKms     string
+	flagEntryId       uint
+	flagAggregateName string
+	flagResourceId    string
+	flagStart         string
+	flagEnd           string
+	flagLimit         int
+	flagTicketName    string
+}
+
+func (c *OplogCommand) Synopsis() string {
+	switch c.Func {
+	case "list":
+		return "List entries of Boundary's operation log"
+	case "show":
+		return "Show a decoded entry of Boundary's operation log"
+	case "verify":
+		return "Verify the hash chains of Boundary's operation log"
+	}
+	return "Inspect Boundary's operation log"
+}
+
+func (c *OplogCommand) Help() string {
+	switch c.Func {
+	case "list":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary database oplog list [options]",
+			"",
+			"  List entries of the operation log, newest first. Entries are read directly",
+			"  from the database and decrypted using the KMS blocks of the controller",
+			"  configuration. Example:",
+			"",
+			`    $ boundary database oplog list -config=controller.hcl -resource-id=ttcp_1234567890`,
+			"",
+		}) + c.Flags().Help()
+	case "show":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary database oplog show [options]",
+			"",
+			"  Show an entry of the operation log with every operation it records,",
+			"  including the field masks of updates and the values written. Example:",
+			"",
+			`    $ boundary database oplog show -config=/etc/boundary/controller.hcl -id=42`,
+			"",
+		}) + c.Flags().Help()
+	case "verify":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary database oplog verify [options]",
+			"",
+			"  Verify that the entries of the operation log have not been modified or",
+			"  removed. Each entry stores a hash of its contents linked to the hash of",
+			"  the entry written before it for the same aggregate, and controllers",
+			"  configured with an oplog chain_signing_interval periodically sign the",
+			"  newest hash of each chain. This command recomputes every hash and reports",
+			"  gaps, mismatches, invalid signatures and chains which end before their",
+			"  signed head. Entries are not decrypted. The command exits with status 1",
+			"  if any problem is found. Example:",
+			"",
+			`    $ boundary database oplog verify -config=controller.hcl`,
+			"",
+		}) + c.Flags().Help()
+	}
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database oplog [sub command] [options]",
+		"",
+		"  This command allows inspecting the operation log, which records every",
+		"  change made to Boundary's resources. Example:",
+		"",
+		"    List the changes made since a date:",
+		"",
+		`      $ boundary database oplog list -config=c.hcl -start=2020-10-01`,
+		"",
+		"    Verify that no entries were modified or removed:",
+		"",
+		`      $ boundary database oplog verify -config=c.hcl`,
+		"",
+		"    List archives of entries pruned after their retention period:",
+		"",
+		`      $ boundary database oplog archives list -config=c.hcl`,
+		"",
+		"  Please see the oplog subcommand help for detailed usage information.",
+	})
+}
+
+func (c *OplogCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+	if c.Func == "" {
+		return set
+	}
+	f := set.NewFlagSet("Command Options")
+	addConfigFlags(f, &c.flagConfig, &c.flagConfigKms)
+
+	switch c.Func {
+	case "show":
+		f.UintVar(&base.UintVar{
+			Name:   "id",
+			Target: &c.flagEntryId,
+			Usage:  "The ID of the entry to show.",
+		})
+	case "verify":
+		f.StringVar(&base.StringVar{
+			Name:   "aggregate",
+			Target: &c.flagTicketName,
+			Usage:  `Only verify the chain of entries for this aggregate, e.g. "iam_scope" or "target_tcp".`,
+		})
+	case "list":
+		f = set.NewFlagSet("Filter Options")
+		f.StringVar(&base.StringVar{
+			Name:   "scope-id",
+			Target: &c.FlagScopeId,
+			Usage:  "Only list entries for resources within this scope.",
+		})
+		f.StringVar(&base.StringVar{
+			Name:   "aggregate",
+			Target: &c.flagAggregateName,
+			Usage:  `Only list entries for this aggregate, which is the table of the resource changed, e.g. "iam_scope" or "target_tcp".`,
+		})
+		f.StringVar(&base.StringVar{
+			Name:   "resource-id",
+			Target: &c.flagResourceId,
+			Usage:  "Only list entries which changed the resource with this ID.",
+		})
+		f.StringVar(&base.StringVar{
+			Name:   "start",
+			Target: &c.flagStart,
+			Usage:  "Only list entries created at or after this time, given as an RFC 3339 timestamp or a YYYY-MM-DD date.",
+		})
+		f.StringVar(&base.StringVar{
+			Name:   "end",
+			Target: &c.flagEnd,
+			Usage:  "Only list entries created before this time, given as an RFC 3339 timestamp or a YYYY-MM-DD date.",
+		})
+		f.IntVar(&base.IntVar{
+			Name:    "limit",
+			Target:  &c.flagLimit,
+			Default: 0,
+			Usage:   "The maximum number of entries to list. If zero the default limit is used; if negative all matching entries are listed.",
+		})
+	}
+
+	return set
+}
+
+func (c *OplogCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OplogCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OplogCommand) Run(args []string) int {
+	if c.Func == "" {
+		return cli.RunResultHelp
+	}
+
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	var opts []inspect.Option
+	switch c.Func {
+	case "show":
+		if c.flagEntryId == 0 {
+			c.UI.Error("ID is required but not passed in via -id")
+			return 1
+		}
+	case "list":
+		if c.FlagScopeId != "" {
+			opts = append(opts, inspect.WithScopeId(c.FlagScopeId))
+		}
+		if c.flagAggregateName != "" {
+			opts = append(opts, inspect.WithAggregateName(c.flagAggregateName))
+		}
+		if c.flagResourceId != "" {
+			opts = append(opts, inspect.WithResourceId(c.flagResourceId))
+		}
+		if c.flagStart != "" {
+			t, err := parseTime(c.flagStart)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error parsing -start: %s", err))
+				return 1
+			}
+			opts = append(opts, inspect.WithStartTime(t))
+		}
+		if c.flagEnd != "" {
+			t, err := parseTime(c.flagEnd)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error parsing -end: %s", err))
+				return 1
+			}
+			opts = append(opts, inspect.WithEndTime(t))
+		}
+		if c.flagLimit != 0 {
+			opts = append(opts, inspect.WithLimit(c.flagLimit))
+		}
+	}
+
+	srv, kmsCache, cleanup, err := openDatabase(c.Command, c.flagConfig, c.flagConfigKms)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer cleanup()
+
+	rw := db.New(srv.Database)
+	repo, err := inspect.NewRepository(rw, rw, kmsCache)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating oplog repository: %w", err).Error())
+		return 1
+	}
+
+	if c.Func == "verify" {
+		return c.verify(repo)
+	}
+
+	var entries []*inspect.Entry
+	switch c.Func {
+	case "show":
+		var e *inspect.Entry
+		e, err = repo.LookupEntry(c.Context, uint32(c.flagEntryId))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			c.UI.Error(fmt.Sprintf("Oplog entry %d not found", c.flagEntryId))
+			return 1
+		}
+		entries = append(entries, e)
+	case "list":
+		entries, err = repo.ListEntries(c.Context, opts...)
+	}
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error reading oplog: %w", err).Error())
+		return 2
+	}
+
+	switch base.Format(c.UI) {
+	case "json":
+		out := make([]*oplogEntryInfo, 0, len(entries))
+		for _, e := range entries {
+			info, err := newOplogEntryInfo(e)
+			if err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting entry %d: %w", e.Id, err).Error())
+				return 1
+			}
+			out = append(out, info)
+		}
+		var v interface{} = out
+		if c.Func == "show" {
+			v = out[0]
+		}
+		b, err := base.JsonFormatter{}.Format(v)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		if len(entries) == 0 {
+			c.UI.Output("No oplog entries found")
+			return 0
+		}
+		switch c.Func {
+		case "show":
+			out, err := oplogEntryTableOutput(entries[0])
+			if err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting entry %d: %w", entries[0].Id, err).Error())
+				return 1
+			}
+			c.UI.Output(out)
+		case "list":
+			c.UI.Output(oplogListTableOutput(entries))
+		}
+	}
+	return 0
+}
+
+func (c *OplogCommand) verify(repo *inspect.Repository) int {
+	var opts []inspect.Option
+	if c.flagTicketName != "" {
+		opts = append(opts, inspect.WithTicketName(c.flagTicketName))
+	}
+	v, err := repo.VerifyChains(c.Context, opts...)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error verifying oplog: %w", err).Error())
+		return 2
+	}
+
+	switch base.Format(c.UI) {
+	case "json":
+		b, err := base.JsonFormatter{}.Format(newOplogVerificationInfo(v))
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		c.UI.Output(oplogVerificationTableOutput(v))
+	}
+	if !v.Valid() {
+		return 1
+	}
+	return 0
+}
+
+// oplogVerificationInfo is the JSON representation of a chain verification.
+type oplogVerificationInfo struct {
+	Valid            bool              `json:"valid"`
+	UnchainedEntries int               `json:"unchained_entries"`
+	Chains           []*oplogChainInfo `json:"chains"`
+}
+
+type oplogChainInfo struct {
+	AggregateName string              `json:"aggregate_name"`
+	Entries       int                 `json:"entries"`
+	FirstVersion  uint32              `json:"first_version"`
+	LastVersion   uint32              `json:"last_version"`
+	SignedVersion uint32              `json:"signed_version"`
+	Problems      []*oplogProblemInfo `json:"problems,omitempty"`
+}
+
+type oplogProblemInfo struct {
+	Kind    string `json:"kind"`
+	Version uint32 `json:"version"`
+	EntryId uint32 `json:"entry_id,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+func newOplogVerificationInfo(v *inspect.ChainVerification) *oplogVerificationInfo {
+	info := &oplogVerificationInfo{
+		Valid:            v.Valid(),
+		UnchainedEntries: v.UnchainedEntries,
+		Chains:           make([]*oplogChainInfo, 0, len(v.Chains)),
+	}
+	for _, c := range v.Chains {
+		ci := &oplogChainInfo{
+			AggregateName: c.TicketName,
+			Entries:       c.Entries,
+			FirstVersion:  c.FirstVersion,
+			LastVersion:   c.LastVersion,
+			SignedVersion: c.SignedVersion,
+		}
+		for _, p := range c.Problems {
+			ci.Problems = append(ci.Problems, &oplogProblemInfo{
+				Kind:    string(p.Kind),
+				Version: p.TicketVersion,
+				EntryId: p.EntryId,
+				Detail:  p.Detail,
+			})
+		}
+		info.Chains = append(info.Chains, ci)
+	}
+	return info
+}
+
+func oplogVerificationTableOutput(v *inspect.ChainVerification) string {
+	result := "valid"
+	if !v.Valid() {
+		result = "problems found"
+	}
+	output := []string{
+		"",
+		"Oplog verification:",
+		fmt.Sprintf("  Result:                %s", result),
+		fmt.Sprintf("  Unchained Entries:     %d", v.UnchainedEntries),
+	}
+	if len(v.Chains) == 0 {
+		output = append(output, "", "  No chained oplog entries found")
+		return base.WrapForHelpText(output)
+	}
+	output = append(output, "", "  Chains:")
+	for i, c := range v.Chains {
+		if i > 0 {
+			output = append(output, "")
+		}
+		output = append(output,
+			fmt.Sprintf("    Aggregate Name:      %s", c.TicketName),
+			fmt.Sprintf("      Entries:           %d", c.Entries),
+			fmt.Sprintf("      Versions:          %d-%d", c.FirstVersion, c.LastVersion),
+		)
+		if c.SignedVersion > 0 {
+			output = append(output, fmt.Sprintf("      Signed Version:    %d", c.SignedVersion))
+		}
+		for _, p := range c.Problems {
+			line := fmt.Sprintf("      Problem:           %s at version %d: %s", p.Kind, p.TicketVersion, p.Detail)
+			if p.EntryId != 0 {
+				line = fmt.Sprintf("      Problem:           %s in entry %d at version %d: %s", p.Kind, p.EntryId, p.TicketVersion, p.Detail)
+			}
+			output = append(output, line)
+		}
+	}
+	return base.WrapForHelpText(output)
+}
+
+// oplogEntryInfo is the JSON representation of a decoded oplog entry.
+type oplogEntryInfo struct {
+	Id            uint32              `json:"id"`
+	CreateTime    time.Time           `json:"create_time"`
+	Version       string              `json:"version"`
+	AggregateName string              `json:"aggregate_name"`
+	KeyId         string              `json:"key_id"`
+	Metadata      map[string][]string `json:"metadata,omitempty"`
+	Messages      []*oplogMessageInfo `json:"messages"`
+	DecodeError   string              `json:"decode_error,omitempty"`
+}
+
+type oplogMessageInfo struct {
+	OpType         string          `json:"op_type"`
+	TypeName       string          `json:"type_name"`
+	FieldMaskPaths []string        `json:"field_mask_paths,omitempty"`
+	SetToNullPaths []string        `json:"set_to_null_paths,omitempty"`
+	Value          json.RawMessage `json:"value"`
+}
+
+func newOplogEntryInfo(e *inspect.Entry) (*oplogEntryInfo, error) {
+	info := &oplogEntryInfo{
+		Id:            e.Id,
+		CreateTime:    e.CreateTime,
+		Version:       e.Version,
+		AggregateName: e.AggregateName,
+		KeyId:         e.KeyId,
+		Metadata:      e.Metadata,
+		Messages:      make([]*oplogMessageInfo, 0, len(e.Messages)),
+	}
+	if e.DecodeError != nil {
+		info.DecodeError = e.DecodeError.Error()
+	}
+	for _, m := range e.Messages {
+		v, err := protojson.Marshal(m.Message)
+		if err != nil {
+			return nil, err
+		}
+		info.Messages = append(info.Messages, &oplogMessageInfo{
+			OpType:         opTypeString(m.OpType),
+			TypeName:       m.TypeName,
+			FieldMaskPaths: m.FieldMaskPaths,
+			SetToNullPaths: m.SetToNullPaths,
+			Value:          v,
+		})
+	}
+	return info, nil
+}
+
+// opTypeString returns the lower case operation name, e.g. "create", of the
+// op type.
+func opTypeString(t oplog.OpType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "OP_TYPE_"))
+}
+
+func operationsSummary(e *inspect.Entry) string {
+	ops := make([]string, 0, len(e.Messages))
+	for _, m := range e.Messages {
+		ops = append(ops, fmt.Sprintf("%s %s", opTypeString(m.OpType), m.TypeName))
+	}
+	return strings.Join(ops, ", ")
+}
+
+func metadataLines(indent int, md oplog.Metadata) []string {
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var lines []string
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s%s: %s", strings.Repeat(" ", indent), k, strings.Join(md[k], ", ")))
+	}
+	return lines
+}
+
+func oplogListTableOutput(entries []*inspect.Entry) string {
+	output := []string{
+		"",
+		"Oplog entries:",
+	}
+	for i, e := range entries {
+		if i > 0 {
+			output = append(output, "")
+		}
+		output = append(output,
+			fmt.Sprintf("  ID:                  %d", e.Id),
+			fmt.Sprintf("    Created Time:      %s", e.CreateTime.Local().Format(time.RFC1123)),
+			fmt.Sprintf("    Aggregate Name:    %s", e.AggregateName),
+		)
+		if e.DecodeError != nil {
+			output = append(output, fmt.Sprintf("    Decode Error:      %s", e.DecodeError))
+		} else {
+			output = append(output, fmt.Sprintf("    Operations:        %s", operationsSummary(e)))
+		}
+		if ids := e.Metadata[inspect.MetadataResourceId]; len(ids) > 0 {
+			output = append(output, fmt.Sprintf("    Resource ID:       %s", strings.Join(ids, ", ")))
+		}
+		if ids := e.Metadata[inspect.MetadataScopeId]; len(ids) > 0 {
+			output = append(output, fmt.Sprintf("    Scope ID:          %s", strings.Join(ids, ", ")))
+		}
+	}
+	return base.WrapForHelpText(output)
+}
+
+func oplogEntryTableOutput(e *inspect.Entry) (string, error) {
+	nonAttributeMap := map[string]interface{}{
+		"ID":             e.Id,
+		"Created Time":   e.CreateTime.Local().Format(time.RFC1123),
+		"Version":        e.Version,
+		"Aggregate Name": e.AggregateName,
+		"Key ID":         e.KeyId,
+	}
+	maxLength := base.MaxAttributesLength(nonAttributeMap, nil, nil)
+
+	output := []string{
+		"",
+		"Oplog entry information:",
+		base.WrapMap(2, maxLength+2, nonAttributeMap),
+	}
+	if len(e.Metadata) > 0 {
+		output = append(output, "", "  Metadata:")
+		output = append(output, metadataLines(4, e.Metadata)...)
+	}
+	if e.DecodeError != nil {
+		output = append(output, "", fmt.Sprintf("  Decode Error:  %s", e.DecodeError))
+		return base.WrapForHelpText(output), nil
+	}
+	output = append(output, "", "  Messages:")
+	for i, m := range e.Messages {
+		if i > 0 {
+			output = append(output, "")
+		}
+		v, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(m.Message)
+		if err != nil {
+			return "", err
+		}
+		output = append(output, fmt.Sprintf("    %d. %s %s", i+1, opTypeString(m.OpType), m.TypeName))
+		if len(m.FieldMaskPaths) > 0 {
+			output = append(output, fmt.Sprintf("      Field Mask:     %s", strings.Join(m.FieldMaskPaths, ", ")))
+		}
+		if len(m.SetToNullPaths) > 0 {
+			output = append(output, fmt.Sprintf("      Set To Null:    %s", strings.Join(m.SetToNullPaths, ", ")))
+		}
+		output = append(output, "      Value:")
+		for _, l := range strings.Split(string(v), "\n") {
+			output = append(output, "        "+l)
+		}
+	}
+	return base.WrapForHelpText(output), nil
+}
+
+// parseTime accepts either an RFC 3339 timestamp or a date, which is
+// interpreted as midnight UTC.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 timestamp nor a YYYY-MM-DD date", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,293 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/oplog/retention"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*OplogArchivesCommand)(nil)
+var _ cli.CommandAutocomplete = (*OplogArchivesCommand)(nil)
+
+type OplogArchivesCommand struct {
+	*base.Command
+
+	Func string
+
+	flagConfig        string
+	flagConfigKms     string
+	flagFile          string
+	flagAggregateName string
+	flagLimit         int
+}
+
+func (c *OplogArchivesCommand) Synopsis() string {
+	switch c.Func {
+	case "list":
+		return "List archives of pruned operation log entries"
+	case "restore":
+		return "Restore the entries of an operation log archive"
+	case "release":
+		return "Delete the restored entries of an operation log archive"
+	}
+	return "Manage archives of pruned operation log entries"
+}
+
+func (c *OplogArchivesCommand) Help() string {
+	switch c.Func {
+	case "list":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary database oplog archives list [options]",
+			"",
+			"  List the archive files written by controllers when pruning operation log",
+			"  entries past their retention period, newest first. Example:",
+			"",
+			`    $ boundary database oplog archives list -config=controller.hcl`,
+			"",
+		}) + c.Flags().Help()
+	case "restore":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary database oplog archives restore [options]",
+			"",
+			"  Restore the entries of an archive file into the operation log with their",
+			"  original IDs and creation times, so they can be inspected with the",
+			`  "database oplog list" and "database oplog show" commands. The hash of`,
+			"  every entry is verified before anything is restored. Restored entries",
+			"  are not pruned again until the archive is released. Example:",
+			"",
+			`    $ boundary database oplog archives restore -config=c.hcl \`,
+			`        -file=/var/lib/boundary/oplog/oplog-iam_user-1-999.jsonl.gz`,
+			"",
+		}) + c.Flags().Help()
+	case "release":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary database oplog archives release [options]",
+			"",
+			"  Delete the entries restored from an archive file once they are no",
+			"  longer needed. The archive file is kept. Example:",
+			"",
+			`    $ boundary database oplog archives release -config=c.hcl \`,
+			`        -file=/var/lib/boundary/oplog/oplog-iam_user-1-999.jsonl.gz`,
+			"",
+		}) + c.Flags().Help()
+	}
+	return base.WrapForHelpText([]string{
+		"Usage: boundary database oplog archives [sub command] [options]",
+		"",
+		"  Controllers configured with an oplog retention_period write entries past",
+		"  their retention period to compressed archive files in the oplog",
+		"  archive_path and then delete them from the database. Archived entries",
+		"  remain encrypted. This command allows listing archives and restoring",
+		"  their entries for forensic queries. Example:",
+		"",
+		"    List archives:",
+		"",
+		`      $ boundary database oplog archives list -config=c.hcl`,
+		"",
+		"  Please see the archives subcommand help for detailed usage information.",
+	})
+}
+
+func (c *OplogArchivesCommand) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetOutputFormat)
+	if c.Func == "" {
+		return set
+	}
+	f := set.NewFlagSet("Command Options")
+	addConfigFlags(f, &c.flagConfig, &c.flagConfigKms)
+
+	switch c.Func {
+	case "list":
+		f.StringVar(&base.StringVar{
+			Name:   "aggregate",
+			Target: &c.flagAggregateName,
+			Usage:  `Only list archives of entries for this aggregate, e.g. "iam_scope" or "target_tcp".`,
+		})
+		f.IntVar(&base.IntVar{
+			Name:    "limit",
+			Target:  &c.flagLimit,
+			Default: 0,
+			Usage:   "The maximum number of archives to list. If zero the default limit is used; if negative all archives are listed.",
+		})
+	case "restore", "release":
+		f.StringVar(&base.StringVar{
+			Name:       "file",
+			Target:     &c.flagFile,
+			Completion: complete.PredictFiles("*.jsonl.gz"),
+			Usage:      "Path to the archive file.",
+		})
+	}
+	return set
+}
+
+func (c *OplogArchivesCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OplogArchivesCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OplogArchivesCommand) Run(args []string) int {
+	if c.Func == "" {
+		return cli.RunResultHelp
+	}
+
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	switch c.Func {
+	case "restore", "release":
+		if c.flagFile == "" {
+			c.UI.Error("File is required but not passed in via -file")
+			return 1
+		}
+	}
+
+	srv, _, cleanup, err := openDatabase(c.Command, c.flagConfig, c.flagConfigKms)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer cleanup()
+
+	rw := db.New(srv.Database)
+	repo, err := retention.NewRepository(rw, rw)
+	if err != nil {
+		c.UI.Error(fmt.Errorf("Error creating oplog retention repository: %w", err).Error())
+		return 1
+	}
+
+	var archives []*retention.Archive
+	switch c.Func {
+	case "list":
+		var opts []retention.Option
+		if c.flagAggregateName != "" {
+			opts = append(opts, retention.WithAggregateName(c.flagAggregateName))
+		}
+		if c.flagLimit != 0 {
+			opts = append(opts, retention.WithLimit(c.flagLimit))
+		}
+		archives, err = repo.ListArchives(c.Context, opts...)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error listing oplog archives: %w", err).Error())
+			return 2
+		}
+
+	case "restore":
+		a, err := repo.RestoreArchive(c.Context, c.flagFile)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error restoring oplog archive: %w", err).Error())
+			return 2
+		}
+		archives = append(archives, a)
+
+	case "release":
+		deleted, err := repo.ReleaseArchive(c.Context, c.flagFile)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error releasing oplog archive: %w", err).Error())
+			return 2
+		}
+		switch base.Format(c.UI) {
+		case "json":
+			b, err := base.JsonFormatter{}.Format(map[string]int{"entries_deleted": deleted})
+			if err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+				return 1
+			}
+			c.UI.Output(string(b))
+		case "table":
+			c.UI.Output(fmt.Sprintf("%d restored entries deleted.", deleted))
+		}
+		return 0
+	}
+
+	switch base.Format(c.UI) {
+	case "json":
+		out := make([]*oplogArchiveInfo, 0, len(archives))
+		for _, a := range archives {
+			out = append(out, newOplogArchiveInfo(a))
+		}
+		var v interface{} = out
+		if c.Func == "restore" {
+			v = out[0]
+		}
+		b, err := base.JsonFormatter{}.Format(v)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		if len(archives) == 0 {
+			c.UI.Output("No oplog archives found")
+			return 0
+		}
+		title := "Oplog archives:"
+		if c.Func == "restore" {
+			title = "Restored oplog archive:"
+		}
+		c.UI.Output(oplogArchivesTableOutput(title, archives))
+	}
+	return 0
+}
+
+// oplogArchiveInfo is the JSON representation of an oplog archive.
+type oplogArchiveInfo struct {
+	FileName      string     `json:"file_name"`
+	AggregateName string     `json:"aggregate_name"`
+	FirstEntryId  uint32     `json:"first_entry_id"`
+	LastEntryId   uint32     `json:"last_entry_id"`
+	EntryCount    int        `json:"entry_count"`
+	CreateTime    time.Time  `json:"create_time"`
+	RestoreTime   *time.Time `json:"restore_time,omitempty"`
+}
+
+func newOplogArchiveInfo(a *retention.Archive) *oplogArchiveInfo {
+	info := &oplogArchiveInfo{
+		FileName:      a.FileName,
+		AggregateName: a.AggregateName,
+		FirstEntryId:  a.FirstEntryId,
+		LastEntryId:   a.LastEntryId,
+		EntryCount:    a.EntryCount,
+		CreateTime:    a.CreateTime,
+	}
+	if !a.RestoreTime.IsZero() {
+		t := a.RestoreTime
+		info.RestoreTime = &t
+	}
+	return info
+}
+
+func oplogArchivesTableOutput(title string, archives []*retention.Archive) string {
+	output := []string{
+		"",
+		title,
+	}
+	for i, a := range archives {
+		if i > 0 {
+			output = append(output, "")
+		}
+		output = append(output,
+			fmt.Sprintf("  File Name:           %s", a.FileName),
+			fmt.Sprintf("    Aggregate Name:    %s", a.AggregateName),
+			fmt.Sprintf("    Entry IDs:         %d-%d", a.FirstEntryId, a.LastEntryId),
+			fmt.Sprintf("    Entries:           %d", a.EntryCount),
+			fmt.Sprintf("    Created Time:      %s", a.CreateTime.Local().Format(time.RFC1123)),
+		)
+		if !a.RestoreTime.IsZero() {
+			output = append(output, fmt.Sprintf("    Restored Time:     %s", a.RestoreTime.Local().Format(time.RFC1123)))
+		}
+	}
+	return base.WrapForHelpText(output)
+}
@@ -0,0 +1,183 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/history"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/common"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+}
+
+func (c *Command) Synopsis() string {
+	return "Show the change history of a role, user, target or host set"
+}
+
+func (c *Command) Help() string {
+	return base.WrapForHelpText([]string{
+		"Usage: boundary history [options] [args]",
+		"",
+		"  Show the changes made to a role, user, target or host set, oldest first, as",
+		"  recorded in the operation log. Changes to the resource's associations, such",
+		"  as a role's grants and principals or a host set's hosts, are included. For",
+		"  each change the fields changed, their previous and new values where known,",
+		"  and the user who made the change are shown. Example:",
+		"",
+		`    $ boundary history -id r_1234567890`,
+		"",
+	}) + c.Flags().Help()
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+	common.PopulateCommonFlags(c.Command, f, "role, user, target or host set", []string{"id"})
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.FlagId == "" {
+		c.UI.Error("ID is required but not passed in via -id")
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	result, err := history.NewClient(client).Get(c.Context, c.FlagId)
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			c.UI.Error(fmt.Sprintf("Error from controller when performing history lookup: %s", base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error trying to look up history: %s", err.Error()))
+		return 2
+	}
+
+	items := result.Items
+	switch base.Format(c.UI) {
+	case "json":
+		if len(items) == 0 {
+			c.UI.Output("null")
+			return 0
+		}
+		b, err := base.JsonFormatter{}.Format(items)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		if len(items) == 0 {
+			c.UI.Output("No changes found")
+			return 0
+		}
+		c.UI.Output(historyTableOutput(items))
+	}
+
+	return 0
+}
+
+func historyTableOutput(items []*history.Change) string {
+	output := []string{
+		"",
+		"Change history:",
+	}
+	for i, ch := range items {
+		if i > 0 {
+			output = append(output, "")
+		}
+		m := map[string]interface{}{
+			"Operation":    ch.Operation,
+			"Type":         ch.TypeName,
+			"Created Time": ch.CreatedTime.Local().Format(time.RFC1123),
+		}
+		if ch.UserId != "" {
+			m["User ID"] = ch.UserId
+		}
+		if ch.AuthTokenId != "" {
+			m["Auth Token ID"] = ch.AuthTokenId
+		}
+		output = append(output,
+			fmt.Sprintf("  Entry ID: %d", ch.EntryId),
+			base.WrapMap(4, 0, m),
+		)
+
+		switch ch.Operation {
+		case "update":
+			fields := append(append([]string{}, ch.ChangedFields...), ch.NulledFields...)
+			if len(fields) == 0 {
+				continue
+			}
+			output = append(output, "    Changes:")
+			for _, name := range fields {
+				if ch.Before != nil {
+					output = append(output, fmt.Sprintf("      %s: %s -> %s", name, formatValue(ch.Before[name]), formatValue(ch.After[name])))
+				} else {
+					output = append(output, fmt.Sprintf("      %s: %s", name, formatValue(ch.After[name])))
+				}
+			}
+		case "create":
+			output = append(output, valuesOutput("Values", ch.After)...)
+		case "delete":
+			output = append(output, valuesOutput("Previous Values", ch.Before)...)
+		}
+	}
+	return base.WrapForHelpText(output)
+}
+
+func valuesOutput(title string, values map[string]interface{}) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	output := []string{fmt.Sprintf("    %s:", title)}
+	for _, name := range names {
+		output = append(output, fmt.Sprintf("      %s: %s", name, formatValue(values[name])))
+	}
+	return output
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "<null>"
+	case string:
+		return fmt.Sprintf("%q", v)
+	}
+	return fmt.Sprintf("%v", v)
+}
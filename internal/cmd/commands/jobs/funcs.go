@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/api/jobs"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+)
+
+func generateJobTableOutput(in *jobs.Job) string {
+	nonAttributeMap := map[string]interface{}{
+		"ID":                 in.Id,
+		"Description":        in.Description,
+		"Next Scheduled Run": in.NextScheduledRun.Local().Format(time.RFC1123),
+	}
+	if in.RunningControllerId != "" {
+		nonAttributeMap["Running On"] = in.RunningControllerId
+	}
+
+	maxLength := base.MaxAttributesLength(nonAttributeMap, nil, nil)
+
+	ret := []string{
+		"",
+		"Job information:",
+		base.WrapMap(2, maxLength+2, nonAttributeMap),
+		"",
+		"  Scope:",
+		base.ScopeInfoForOutput(in.Scope, maxLength),
+	}
+
+	if len(in.Runs) > 0 {
+		ret = append(ret,
+			"",
+			"  Runs:",
+		)
+		for _, r := range in.Runs {
+			ret = append(ret, runOutput("    ", r)...)
+		}
+	}
+
+	return base.WrapForHelpText(ret)
+}
+
+func generateJobListTableOutput(in []*jobs.Job) string {
+	output := []string{
+		"",
+		"Job information:",
+	}
+	for i, j := range in {
+		if i > 0 {
+			output = append(output, "")
+		}
+		output = append(output,
+			fmt.Sprintf("  ID:                    %s", j.Id),
+			fmt.Sprintf("    Description:         %s", j.Description),
+			fmt.Sprintf("    Next Scheduled Run:  %s", j.NextScheduledRun.Local().Format(time.RFC1123)),
+		)
+		if j.RunningControllerId != "" {
+			output = append(output,
+				fmt.Sprintf("    Running On:          %s", j.RunningControllerId),
+			)
+		}
+		if j.LastRun != nil {
+			output = append(output, "    Last Run:")
+			output = append(output, runOutput("      ", j.LastRun)...)
+		}
+	}
+	return base.WrapForHelpText(output)
+}
+
+// runOutput returns the lines describing the run, indented by the prefix.
+func runOutput(prefix string, r *jobs.JobRun) []string {
+	ret := []string{
+		fmt.Sprintf("%sID:             %s", prefix, r.Id),
+		fmt.Sprintf("%s  Status:       %s", prefix, r.Status),
+		fmt.Sprintf("%s  Controller:   %s", prefix, r.ControllerId),
+		fmt.Sprintf("%s  Start Time:   %s", prefix, r.StartTime.Local().Format(time.RFC1123)),
+	}
+	if !r.EndTime.IsZero() {
+		ret = append(ret,
+			fmt.Sprintf("%s  Duration:     %s", prefix, time.Duration(r.DurationMilliseconds)*time.Millisecond),
+		)
+	}
+	if r.Error != "" {
+		ret = append(ret,
+			fmt.Sprintf("%s  Error:        %s", prefix, r.Error),
+		)
+	}
+	return ret
+}
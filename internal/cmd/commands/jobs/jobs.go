@@ -0,0 +1,193 @@
+package jobs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/jobs"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+
+	Func string
+}
+
+func (c *Command) Synopsis() string {
+	switch c.Func {
+	case "read":
+		return "Read a job's details and latest runs"
+	case "list":
+		return "List jobs"
+	}
+	return "Inspect the background jobs of the controllers"
+}
+
+func (c *Command) Help() string {
+	switch c.Func {
+	case "read":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary jobs read [options] [args]",
+			"",
+			"  Read a job given its ID, which is its name, along with its latest runs,",
+			"  the controller each ran on, how long it took and the error it failed",
+			"  with, if any. Example:",
+			"",
+			`    $ boundary jobs read -id terminate-completed-sessions`,
+			"",
+			"",
+		}) + c.Flags().Help()
+	case "list":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary jobs list [options] [args]",
+			"",
+			"  List the background jobs run by the controllers along with their latest",
+			"  run. Jobs are in the global scope. Example:",
+			"",
+			`    $ boundary jobs list`,
+			"",
+			"",
+		}) + c.Flags().Help()
+	}
+	return base.WrapForHelpText([]string{
+		"Usage: boundary jobs [sub command] [options] [args]",
+		"",
+		"  This command allows inspecting the background jobs of the controllers.",
+		"  Each run of a job happens on a single controller. Example:",
+		"",
+		"    List jobs:",
+		"",
+		`      $ boundary jobs list`,
+		"",
+		"  Please see the jobs subcommand help for detailed usage information.",
+	})
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	if c.Func == "" {
+		return set
+	}
+	f := set.NewFlagSet("Command Options")
+
+	switch c.Func {
+	case "read":
+		f.StringVar(&base.StringVar{
+			Name:   "id",
+			Target: &c.FlagId,
+			Usage:  "ID of the job, which is its name.",
+		})
+	case "list":
+		f.StringVar(&base.StringVar{
+			Name:    "scope-id",
+			Target:  &c.FlagScopeId,
+			Default: scope.Global.String(),
+			Usage:   "Scope in which to list jobs, which can only be global.",
+		})
+	}
+
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	if c.Func == "" {
+		return cli.RunResultHelp
+	}
+
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.Func == "read" && c.FlagId == "" {
+		c.UI.Error("ID is required but not passed in via -id")
+		return 1
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	jobClient := jobs.NewClient(client)
+
+	var result api.GenericResult
+	var listResult api.GenericListResult
+	switch c.Func {
+	case "read":
+		result, err = jobClient.Read(c.Context, c.FlagId)
+	case "list":
+		listResult, err = jobClient.List(c.Context, c.FlagScopeId)
+	}
+
+	plural := "job"
+	if c.Func == "list" {
+		plural = "jobs"
+	}
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			c.UI.Error(fmt.Sprintf("Error from controller when performing %s on %s: %s", c.Func, plural, base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error trying to %s %s: %s", c.Func, plural, err.Error()))
+		return 2
+	}
+
+	if c.Func == "list" {
+		listedJobs := listResult.GetItems().([]*jobs.Job)
+		switch base.Format(c.UI) {
+		case "json":
+			if len(listedJobs) == 0 {
+				c.UI.Output("null")
+				return 0
+			}
+			b, err := base.JsonFormatter{}.Format(listedJobs)
+			if err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+				return 1
+			}
+			c.UI.Output(string(b))
+
+		case "table":
+			if len(listedJobs) == 0 {
+				c.UI.Output("No jobs found")
+				return 0
+			}
+			c.UI.Output(generateJobListTableOutput(listedJobs))
+		}
+		return 0
+	}
+
+	job := result.GetItem().(*jobs.Job)
+	switch base.Format(c.UI) {
+	case "table":
+		c.UI.Output(generateJobTableOutput(job))
+	case "json":
+		b, err := base.JsonFormatter{}.Format(job)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	}
+
+	return 0
+}
@@ -0,0 +1,233 @@
+package keys
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/keys"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+
+	Func string
+}
+
+func (c *Command) Synopsis() string {
+	switch c.Func {
+	case "list":
+		return "List the versions of a scope's keys"
+	case "rotate":
+		return "Rotate a scope's keys"
+	case "destroy":
+		return "Destroy a key version which is no longer in use"
+	}
+	return "Manage the encryption keys of scopes"
+}
+
+func (c *Command) Help() string {
+	switch c.Func {
+	case "list":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary keys list [options] [args]",
+			"",
+			"  List the versions of the root key and data encryption keys of a scope,",
+			"  along with the number of items still depending on each version and",
+			"  whether it can be destroyed, or why not. Example:",
+			"",
+			`    $ boundary keys list -scope-id o_1234567890`,
+			"",
+		}) + c.Flags().Help()
+	case "rotate":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary keys rotate [options] [args]",
+			"",
+			"  Create a new version of the root key and of every data encryption key of",
+			"  a scope. New values are encrypted with the new versions straight away;",
+			"  values and oplog entries encrypted with previous versions are rewrapped in",
+			"  the background, after which the previous versions can be destroyed.",
+			"  Example:",
+			"",
+			`    $ boundary keys rotate -scope-id o_1234567890`,
+			"",
+		}) + c.Flags().Help()
+	case "destroy":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary keys destroy [options] [args]",
+			"",
+			"  Destroy a key version. Only versions reported as destroyable by",
+			"  \"boundary keys list\" can be destroyed. Oplog archive files are never",
+			"  rewrapped, so archived oplog entries encrypted with a destroyed oplog",
+			"  key version can no longer be decrypted once restored. Example:",
+			"",
+			`    $ boundary keys destroy -id krkv_1234567890`,
+			"",
+		}) + c.Flags().Help()
+	}
+	return base.WrapForHelpText([]string{
+		"Usage: boundary keys [sub command] [options] [args]",
+		"",
+		"  This command allows operations on the encryption keys of scopes. Example:",
+		"",
+		"    Rotate the keys of an org:",
+		"",
+		`      $ boundary keys rotate -scope-id o_1234567890`,
+		"",
+		"  Please see the keys subcommand help for detailed usage information.",
+	})
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	if c.Func == "" {
+		return set
+	}
+	f := set.NewFlagSet("Command Options")
+
+	switch c.Func {
+	case "list", "rotate":
+		f.StringVar(&base.StringVar{
+			Name:    "scope-id",
+			Target:  &c.FlagScopeId,
+			Default: "global",
+			EnvVar:  "BOUNDARY_SCOPE_ID",
+			Usage:   "The scope whose keys to operate on.",
+		})
+	case "destroy":
+		f.StringVar(&base.StringVar{
+			Name:   "id",
+			Target: &c.FlagId,
+			Usage:  "ID of the key version to destroy.",
+		})
+	}
+
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	if c.Func == "" {
+		return cli.RunResultHelp
+	}
+
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	switch c.Func {
+	case "list", "rotate":
+		if c.FlagScopeId == "" {
+			c.UI.Error("Scope ID must be passed in via -scope-id")
+			return 1
+		}
+	case "destroy":
+		if c.FlagId == "" {
+			c.UI.Error("ID is required but not passed in via -id")
+			return 1
+		}
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	keyClient := keys.NewClient(client)
+
+	var listResult *keys.KeyVersionListResult
+	switch c.Func {
+	case "list":
+		listResult, err = keyClient.List(c.Context, c.FlagScopeId)
+	case "rotate":
+		listResult, err = keyClient.Rotate(c.Context, c.FlagScopeId)
+	case "destroy":
+		_, err = keyClient.Delete(c.Context, c.FlagId)
+	}
+
+	target := "keys"
+	if c.Func == "destroy" {
+		target = "key version"
+	}
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			c.UI.Error(fmt.Sprintf("Error from controller when performing %s on %s: %s", c.Func, target, base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error trying to %s %s: %s", c.Func, target, err.Error()))
+		return 2
+	}
+
+	if c.Func == "destroy" {
+		switch base.Format(c.UI) {
+		case "json":
+			c.UI.Output("null")
+		case "table":
+			c.UI.Output("The destroy operation completed successfully.")
+		}
+		return 0
+	}
+
+	versions := listResult.Items
+	switch base.Format(c.UI) {
+	case "json":
+		if len(versions) == 0 {
+			c.UI.Output("null")
+			return 0
+		}
+		b, err := base.JsonFormatter{}.Format(versions)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		if len(versions) == 0 {
+			c.UI.Output("No key versions found")
+			return 0
+		}
+		output := []string{
+			"",
+			"Key Version information:",
+		}
+		for i, v := range versions {
+			if i > 0 {
+				output = append(output, "")
+			}
+			output = append(output,
+				fmt.Sprintf("  ID:               %s", v.Id),
+				fmt.Sprintf("    Purpose:        %s", v.Purpose),
+				fmt.Sprintf("    Version:        %d", v.Version),
+				fmt.Sprintf("    Created Time:   %s", v.CreatedTime.Local().Format(time.RFC1123)),
+				fmt.Sprintf("    Current:        %t", v.Current),
+				fmt.Sprintf("    References:     %d", v.References),
+				fmt.Sprintf("    Destroyable:    %t", v.Destroyable),
+			)
+			if v.NotDestroyableReason != "" {
+				output = append(output,
+					fmt.Sprintf("    Reason:         %s", v.NotDestroyableReason),
+				)
+			}
+		}
+		c.UI.Output(base.WrapForHelpText(output))
+	}
+	return 0
+}
@@ -0,0 +1,288 @@
+package reports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/reports"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+
+	Func string
+
+	flagGroupBy string
+	flagStart   string
+	flagEnd     string
+	flagLimit   uint
+}
+
+func (c *Command) Synopsis() string {
+	switch c.Func {
+	case "usage":
+		return "Report session usage grouped by user, target, host or day"
+	}
+	return "Run reports over Boundary session history"
+}
+
+func (c *Command) Help() string {
+	switch c.Func {
+	case "usage":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary reports usage [options] [args]",
+			"",
+			"  Report session counts, connection counts, failed connection rates, total",
+			"  connected time and bytes transferred, grouped by user, target, host or day.",
+			"  Results are ordered by session count with the busiest first. Example:",
+			"",
+			`    $ boundary reports usage -scope-id o_1234567890 -group-by target -limit 10`,
+			"",
+			"  In addition to \"table\" and \"json\", this command supports \"csv\" as an",
+			"  output format.",
+			"",
+		}) + c.Flags().Help()
+	}
+	return base.WrapForHelpText([]string{
+		"Usage: boundary reports [sub command] [options] [args]",
+		"",
+		"  This command allows running reports over the Boundary session history.",
+		"",
+		"    Report the top targets in an org by session count:",
+		"",
+		`      $ boundary reports usage -scope-id o_1234567890 -group-by target -limit 10`,
+		"",
+		"  Please see the reports subcommand help for detailed usage information.",
+	})
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:    "scope-id",
+		Target:  &c.FlagScopeId,
+		Default: "global",
+		EnvVar:  "BOUNDARY_SCOPE_ID",
+		Usage:   "The scope to report on. May be the global scope, an org or a project.",
+	})
+	f.StringVar(&base.StringVar{
+		Name:       "group-by",
+		Target:     &c.flagGroupBy,
+		Default:    reports.GroupByUser,
+		Completion: complete.PredictSet(reports.GroupByUser, reports.GroupByTarget, reports.GroupByHost, reports.GroupByDay),
+		Usage:      `How to group the results. One of "user", "target", "host" or "day".`,
+	})
+	f.StringVar(&base.StringVar{
+		Name:   "start",
+		Target: &c.flagStart,
+		Usage:  "Only include sessions created at or after this time, given as an RFC 3339 timestamp or a YYYY-MM-DD date.",
+	})
+	f.StringVar(&base.StringVar{
+		Name:   "end",
+		Target: &c.flagEnd,
+		Usage:  "Only include sessions created before this time, given as an RFC 3339 timestamp or a YYYY-MM-DD date.",
+	})
+	f.UintVar(&base.UintVar{
+		Name:   "limit",
+		Target: &c.flagLimit,
+		Usage:  "The maximum number of rows to return. If not set the controller's default limit is used.",
+	})
+
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	if c.Func == "" {
+		return cli.RunResultHelp
+	}
+
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	var opts []reports.Option
+	if c.flagStart != "" {
+		t, err := parseTime(c.flagStart)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error parsing -start: %s", err))
+			return 1
+		}
+		opts = append(opts, reports.WithStartTime(t))
+	}
+	if c.flagEnd != "" {
+		t, err := parseTime(c.flagEnd)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error parsing -end: %s", err))
+			return 1
+		}
+		opts = append(opts, reports.WithEndTime(t))
+	}
+	if c.flagLimit > 0 {
+		opts = append(opts, reports.WithLimit(uint32(c.flagLimit)))
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	result, err := reports.NewClient(client).Usage(c.Context, c.FlagScopeId, c.flagGroupBy, opts...)
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			c.UI.Error(fmt.Sprintf("Error from controller when performing usage report: %s", base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error trying to run usage report: %s", err.Error()))
+		return 2
+	}
+
+	items := result.Items
+	switch base.Format(c.UI) {
+	case "json":
+		if len(items) == 0 {
+			c.UI.Output("null")
+			return 0
+		}
+		b, err := base.JsonFormatter{}.Format(items)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "csv":
+		out, err := usageCsvOutput(c.flagGroupBy, items)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as CSV: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(out)
+
+	case "table":
+		if len(items) == 0 {
+			c.UI.Output("No sessions found")
+			return 0
+		}
+		c.UI.Output(usageTableOutput(c.flagGroupBy, items))
+	}
+
+	return 0
+}
+
+// parseTime accepts either an RFC 3339 timestamp or a date, which is
+// interpreted as midnight UTC.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 timestamp nor a YYYY-MM-DD date", s)
+	}
+	return t, nil
+}
+
+func keyHeader(groupBy string) (string, string) {
+	switch groupBy {
+	case reports.GroupByDay:
+		return "Date", "Day of Week"
+	case reports.GroupByUser:
+		return "User ID", "User Name"
+	case reports.GroupByTarget:
+		return "Target ID", "Target Name"
+	case reports.GroupByHost:
+		return "Host ID", "Host Name"
+	}
+	return "Key", "Name"
+}
+
+func usageTableOutput(groupBy string, items []*reports.Usage) string {
+	keyTitle, nameTitle := keyHeader(groupBy)
+	output := []string{
+		"",
+		"Usage information:",
+	}
+	for i, u := range items {
+		if i > 0 {
+			output = append(output, "")
+		}
+		m := map[string]interface{}{
+			nameTitle:                u.Name,
+			"Sessions":               u.SessionCount,
+			"Connections":            u.ConnectionCount,
+			"Failed Connections":     u.FailedConnectionCount,
+			"Failed Connection Rate": fmt.Sprintf("%.1f%%", u.FailedConnectionRate*100),
+			"Connected Time":         (time.Duration(u.ConnectedSeconds) * time.Second).String(),
+			"Bytes Up":               u.BytesUp,
+			"Bytes Down":             u.BytesDown,
+		}
+		output = append(output,
+			fmt.Sprintf("  %s: %s", keyTitle, u.Key),
+			base.WrapMap(4, 0, m),
+		)
+	}
+	return base.WrapForHelpText(output)
+}
+
+func usageCsvOutput(groupBy string, items []*reports.Usage) (string, error) {
+	keyTitle, nameTitle := keyHeader(groupBy)
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write([]string{
+		keyTitle,
+		nameTitle,
+		"Sessions",
+		"Connections",
+		"Failed Connections",
+		"Failed Connection Rate",
+		"Connected Seconds",
+		"Bytes Up",
+		"Bytes Down",
+	}); err != nil {
+		return "", err
+	}
+	for _, u := range items {
+		if err := w.Write([]string{
+			u.Key,
+			u.Name,
+			strconv.FormatUint(u.SessionCount, 10),
+			strconv.FormatUint(u.ConnectionCount, 10),
+			strconv.FormatUint(u.FailedConnectionCount, 10),
+			strconv.FormatFloat(u.FailedConnectionRate, 'f', -1, 64),
+			strconv.FormatUint(u.ConnectedSeconds, 10),
+			strconv.FormatUint(u.BytesUp, 10),
+			strconv.FormatUint(u.BytesDown, 10),
+		}); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+)
+
+// restartSettings returns the settings of the config which are only applied
+// when the server starts, keyed by the name they are reported with when they
+// change in a reloaded config. It must be called before the listeners are
+// set up, as setting them up changes their config.
+func restartSettings(conf *config.Config) map[string]string {
+	settings := make(map[string]string)
+	set := func(key string, v interface{}) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			b = []byte(fmt.Sprintf("%v", v))
+		}
+		settings[key] = string(b)
+	}
+
+	set("disable_mlock", conf.DisableMlock)
+	set("default_max_request_duration", conf.DefaultMaxRequestDuration)
+	set("pid_file", conf.PidFile)
+	set("telemetry", conf.Telemetry)
+	set("tracing", conf.Tracing)
+	if conf.Controller != nil {
+		set("controller.name", conf.Controller.Name)
+		set("controller.description", conf.Controller.Description)
+		set("controller.oplog", conf.Controller.Oplog)
+	}
+	if conf.Worker != nil {
+		set("worker.name", conf.Worker.Name)
+		set("worker.description", conf.Worker.Description)
+		set("worker.public_addr", conf.Worker.PublicAddr)
+	}
+	for i, l := range conf.Listeners {
+		set(fmt.Sprintf("listener %d", i), listenerRestartSettings(l))
+	}
+	for i, k := range conf.Seals {
+		set(fmt.Sprintf("kms %d", i), k)
+	}
+	return settings
+}
+
+// listenerRestartSettings returns the settings of a listener which are only
+// applied when it is set up; its TLS and CORS settings can be reloaded.
+func listenerRestartSettings(l *configutil.Listener) interface{} {
+	return struct {
+		Type                          string
+		Purpose                       []string
+		Address                       string
+		TLSDisable                    bool
+		MaxRequestSize                int64
+		MaxRequestDuration            interface{}
+		HTTPReadTimeout               interface{}
+		HTTPReadHeaderTimeout         interface{}
+		HTTPWriteTimeout              interface{}
+		HTTPIdleTimeout               interface{}
+		XForwardedForAuthorizedAddrs  string
+		XForwardedForHopSkips         int64
+		XForwardedForRejectNotPresent bool
+		ProxyProtocolBehavior         string
+	}{
+		Type:                          l.Type,
+		Purpose:                       l.Purpose,
+		Address:                       l.Address,
+		TLSDisable:                    l.TLSDisable,
+		MaxRequestSize:                l.MaxRequestSize,
+		MaxRequestDuration:            l.MaxRequestDuration,
+		HTTPReadTimeout:               l.HTTPReadTimeout,
+		HTTPReadHeaderTimeout:         l.HTTPReadHeaderTimeout,
+		HTTPWriteTimeout:              l.HTTPWriteTimeout,
+		HTTPIdleTimeout:               l.HTTPIdleTimeout,
+		XForwardedForAuthorizedAddrs:  fmt.Sprintf("%v", l.XForwardedForAuthorizedAddrs),
+		XForwardedForHopSkips:         l.XForwardedForHopSkips,
+		XForwardedForRejectNotPresent: l.XForwardedForRejectNotPresent,
+		ProxyProtocolBehavior:         l.ProxyProtocolBehavior,
+	}
+}
+
+// changedRestartSettings returns the sorted names of the settings which differ
+// between the two sets of restart settings.
+func changedRestartSettings(old, new map[string]string) []string {
+	var changed []string
+	for k, v := range old {
+		if nv, ok := new[k]; !ok || nv != v {
+			changed = append(changed, k)
+		}
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// reloadConfig applies the reloadable settings of a reloaded config: the TLS
+// and CORS settings of the API listeners and the worker's controllers, egress
+// allowlist and bandwidth limits. The changes to settings which require a
+// restart are reported and not applied.
+func (c *Command) reloadConfig(newConf *config.Config) {
+	newSettings := restartSettings(newConf)
+	changed := changedRestartSettings(c.restartSettings, newSettings)
+	if len(changed) > 0 {
+		c.UI.Warn(fmt.Sprintf("The following settings changed but require a restart to take effect: %s", strings.Join(changed, ", ")))
+		c.Logger.Warn("config settings changed which require a restart", "settings", changed)
+	}
+
+	if c.controller != nil && len(newConf.Listeners) == len(c.Listeners) {
+		for i, ln := range c.Listeners {
+			if strutil.StrListContains(changed, fmt.Sprintf("listener %d", i)) {
+				continue
+			}
+			if err := c.controller.ReloadListener(ln, newConf.Listeners[i], c.UI); err != nil {
+				c.UI.Error(err.Error())
+				c.Logger.Error("could not reload listener", "address", ln.Config.Address, "error", err)
+			}
+		}
+	}
+
+	// A combined worker always connects to the controller's cluster listener
+	if c.worker != nil && c.controller == nil && newConf.Worker != nil &&
+		!strutil.EquivalentSlices(newConf.Worker.Controllers, c.workerControllers) {
+		if err := c.worker.ReloadControllers(newConf.Worker.Controllers); err != nil {
+			c.UI.Error(fmt.Errorf("Error reloading worker controllers: %w", err).Error())
+		} else {
+			c.workerControllers = newConf.Worker.Controllers
+		}
+	}
+	if c.worker != nil && newConf.Worker != nil {
+		c.worker.ReloadEgress(newConf.Worker.Egress)
+		c.worker.ReloadBandwidth(newConf.Worker.Bandwidth)
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadTestConfig = `
+disable_mlock = true
+
+controller {
+	name = "reload-controller"
+	database {
+		url = "postgres://localhost/boundary"
+	}
+}
+
+listener "tcp" {
+	address = "127.0.0.1:9200"
+	purpose = "api"
+	tls_disable = true
+	cors_enabled = true
+	cors_allowed_origins = ["https://first.example.com"]
+}
+
+listener "tcp" {
+	address = "127.0.0.1:9201"
+	purpose = "cluster"
+	tls_disable = true
+}
+`
+
+func TestChangedRestartSettings(t *testing.T) {
+	orig, err := config.Parse(reloadTestConfig)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		change []string
+	}{
+		{
+			name: "unchanged",
+		},
+		{
+			name: "cors",
+			from: `cors_allowed_origins = ["https://first.example.com"]`,
+			to:   `cors_allowed_origins = ["https://second.example.com"]`,
+		},
+		{
+			name:   "listener address",
+			from:   `address = "127.0.0.1:9201"`,
+			to:     `address = "127.0.0.1:9301"`,
+			change: []string{"listener 1"},
+		},
+		{
+			name:   "controller name and mlock",
+			from:   "disable_mlock = true\n\ncontroller {\n\tname = \"reload-controller\"",
+			to:     "disable_mlock = false\n\ncontroller {\n\tname = \"renamed-controller\"",
+			change: []string{"controller.name", "disable_mlock"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			raw := reloadTestConfig
+			if tt.from != "" {
+				require.Contains(raw, tt.from)
+				raw = strings.Replace(raw, tt.from, tt.to, 1)
+			}
+			reloaded, err := config.Parse(raw)
+			require.NoError(err)
+			assert.Equal(tt.change, changedRestartSettings(restartSettings(orig), restartSettings(reloaded)))
+		})
+	}
+}
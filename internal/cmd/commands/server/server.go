@@ -1,6 +1,7 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
@@ -8,6 +9,8 @@ import (
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller"
 	"github.com/hashicorp/boundary/internal/servers/worker"
 	"github.com/hashicorp/boundary/sdk/wrapper"
@@ -28,6 +31,7 @@ type Command struct {
 	ExtShutdownCh chan struct{}
 	SighupCh      chan struct{}
 	ReloadedCh    chan struct{}
+	SigUSR1Ch     chan struct{}
 	SigUSR2Ch     chan struct{}
 
 	Config     *config.Config
@@ -36,6 +40,14 @@ type Command struct {
 
 	configWrapper wrapping.Wrapper
 
+	// restartSettings are the settings of the config the server was started
+	// with which are only applied at startup, to report their changes when
+	// the config is reloaded
+	restartSettings map[string]string
+	// workerControllers are the controllers of the worker's config, last
+	// applied when the server started or the config was reloaded
+	workerControllers []string
+
 	flagConfig      string
 	flagConfigKms   string
 	flagLogLevel    string
@@ -140,6 +152,11 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	if err := c.SetupTracing(c.Config.Tracing); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
 	if err := c.SetupKMSes(c.UI, c.Config); err != nil {
 		c.UI.Error(err.Error())
 		return 1
@@ -171,69 +188,26 @@ func (c *Command) Run(args []string) int {
 				"in a Docker container, provide the IPC_LOCK cap to the container."))
 	}
 
-	// Perform controller-specific listener checks here before setup
+	c.restartSettings = restartSettings(c.Config)
+	if c.Config.Worker != nil {
+		c.workerControllers = c.Config.Worker.Controllers
+	}
+
+	// The listeners were checked when the config was validated; find the
+	// cluster address for a combined controller and worker
 	var clusterAddr string
-	var foundApi bool
-	var foundProxy bool
 	for _, lnConfig := range c.Config.Listeners {
-		switch len(lnConfig.Purpose) {
-		case 0:
-			c.UI.Error("Listener specified without a purpose")
-			return 1
-
-		case 1:
-			purpose := lnConfig.Purpose[0]
-			switch purpose {
-			case "cluster":
-				clusterAddr = lnConfig.Address
-				if clusterAddr == "" {
-					clusterAddr = "127.0.0.1:9201"
-				}
-			case "api":
-				foundApi = true
-			case "proxy":
-				foundProxy = true
-			default:
-				c.UI.Error(fmt.Sprintf("Unknown listener purpose %q", lnConfig.Purpose[0]))
-				return 1
+		if lnConfig.Purpose[0] == "cluster" {
+			clusterAddr = lnConfig.Address
+			if clusterAddr == "" {
+				clusterAddr = "127.0.0.1:9201"
 			}
-
-		default:
-			c.UI.Error("Specifying a listener with more than one purpose is not supported")
-			return 1
 		}
 	}
-	if c.Config.Controller != nil {
-		if !foundApi {
-			c.UI.Error(`Config activates controller but no listener with "api" purpose found`)
-			return 1
-		}
-		if clusterAddr == "" {
-			c.UI.Error(`Config activates controller but no listener with "cluster" purpose found`)
-			return 1
-		}
+	if c.Config.Controller != nil && c.Config.Worker != nil {
+		c.Config.Worker.Controllers = []string{clusterAddr}
 	}
-	if c.Config.Worker != nil {
-		if !foundProxy {
-			c.UI.Error(`Config activates worker but no listener with "proxy" purpose found`)
-			return 1
-		}
-		if c.Config.Controller != nil {
-			switch len(c.Config.Worker.Controllers) {
-			case 0:
-			case 1:
-				if c.Config.Worker.Controllers[0] == clusterAddr {
-					break
-				}
-				fallthrough
-			default:
-				c.UI.Error(`When running a combined controller and worker, it's invalid to specify a "controllers" key in the worker block with any value other than the controller cluster address`)
-				return 1
-			}
-			c.Config.Worker.Controllers = []string{clusterAddr}
-		}
-	}
-	if err := c.SetupListeners(c.UI, c.Config.SharedConfig, []string{"api", "cluster", "proxy"}); err != nil {
+	if err := c.SetupListeners(c.UI, c.Config.SharedConfig, []string{"api", "cluster", "proxy", "metrics"}); err != nil {
 		c.UI.Error(err.Error())
 		return 1
 	}
@@ -264,10 +238,32 @@ func (c *Command) Run(args []string) int {
 			return 1
 		}
 		c.DatabaseUrl = strings.TrimSpace(dbaseUrl)
+		for _, replica := range c.Config.Controller.Database.ReadReplicas {
+			replicaUrl, err := config.ParseAddress(replica)
+			if err != nil && err != config.ErrNotAUrl {
+				c.UI.Error(fmt.Errorf("Error parsing database read replica url: %w", err).Error())
+				return 1
+			}
+			c.DatabaseReplicaUrls = append(c.DatabaseReplicaUrls, strings.TrimSpace(replicaUrl))
+		}
 		if err := c.ConnectToDatabase("postgres"); err != nil {
 			c.UI.Error(fmt.Errorf("Error connecting to database: %w", err).Error())
 			return 1
 		}
+		schemaVersion, err := db.CheckSchemaVersion(c.Context, c.Database.DB(), "postgres")
+		switch {
+		case errors.Is(err, db.ErrSchemaNotInitialized):
+			c.UI.Error(`Database is not initialized; initialize it with "boundary database init"`)
+			return 1
+		case errors.Is(err, db.ErrSchemaNewer):
+			c.UI.Error(fmt.Errorf("Refusing to start controller: %w; upgrade Boundary before starting this controller", err).Error())
+			return 1
+		case err != nil:
+			c.UI.Error(fmt.Errorf("Error checking database schema version: %w", err).Error())
+			return 1
+		case schemaVersion.Current < schemaVersion.Latest:
+			c.UI.Warn(fmt.Sprintf("Database schema version %d is older than the version %d of this binary; migrate it with \"boundary database migrate\"", schemaVersion.Current, schemaVersion.Latest))
+		}
 	}
 
 	defer func() {
@@ -337,16 +333,8 @@ func (c *Command) ParseFlagsAndConfig(args []string) int {
 		return 1
 	}
 
-	if c.Config.Controller == nil && c.Config.Worker == nil {
-		c.UI.Error("Neither worker nor controller specified in configuration file.")
-		return 1
-	}
-	if c.Config.Controller != nil && c.Config.Controller.Name == "" {
-		c.UI.Error("Controller has no name set. It must be the unique name of this instance.")
-		return 1
-	}
-	if c.Config.Worker != nil && c.Config.Worker.Name == "" {
-		c.UI.Error("Worker has no name set. It must be the unique name of this instance.")
+	if err := c.Config.Validate(); err != nil {
+		c.UI.Error("Invalid config: " + err.Error())
 		return 1
 	}
 
@@ -453,6 +441,11 @@ func (c *Command) WaitForInterrupt() int {
 				goto RUNRELOADFUNCS
 			}
 
+			c.reloadConfig(newConf)
+			if err := c.reloadDatabase(newConf); err != nil {
+				c.Logger.Error("could not reload database urls", "error", err)
+			}
+
 			if newConf.LogLevel != "" {
 				configLogLevel := strings.ToLower(strings.TrimSpace(newConf.LogLevel))
 				switch configLogLevel {
@@ -478,6 +471,13 @@ func (c *Command) WaitForInterrupt() int {
 				c.UI.Error(fmt.Errorf("Error(s) were encountered during controller reload: %w", err).Error())
 			}
 
+		case <-c.SigUSR1Ch:
+			if c.worker == nil {
+				break
+			}
+			c.UI.Output("==> Boundary worker drain triggered")
+			c.worker.RequestOperationalState(servers.OperationalStateDraining)
+
 		case <-c.SigUSR2Ch:
 			buf := make([]byte, 32*1024*1024)
 			n := runtime.Stack(buf[:], true)
@@ -488,6 +488,34 @@ func (c *Command) WaitForInterrupt() int {
 	return 0
 }
 
+// reloadDatabase changes the URLs new database connections are made with to
+// those of a reloaded config, so that a rotated database password is used
+// without a restart.
+func (c *Command) reloadDatabase(newConf *config.Config) error {
+	if c.Config.Controller == nil || c.DatabaseConnector == nil {
+		return nil
+	}
+	if newConf.Controller == nil || newConf.Controller.Database == nil || newConf.Controller.Database.Url == "" {
+		return errors.New(`"url" not specified in "controller.database" config block`)
+	}
+	url := strings.TrimSpace(newConf.Controller.Database.Url)
+	changed := url != c.DatabaseUrl
+	replicaUrls := make([]string, 0, len(newConf.Controller.Database.ReadReplicas))
+	for i, replica := range newConf.Controller.Database.ReadReplicas {
+		replicaUrls = append(replicaUrls, strings.TrimSpace(replica))
+		changed = changed || i >= len(c.DatabaseReplicaUrls) || replicaUrls[i] != c.DatabaseReplicaUrls[i]
+	}
+	changed = changed || len(replicaUrls) != len(c.DatabaseReplicaUrls)
+	if !changed {
+		return nil
+	}
+	if err := c.ReloadDatabaseUrls(url, replicaUrls); err != nil {
+		return err
+	}
+	c.Logger.Info("reloaded database urls")
+	return nil
+}
+
 func (c *Command) Reload() error {
 	c.ReloadFuncsLock.RLock()
 	defer c.ReloadFuncsLock.RUnlock()
@@ -2,6 +2,7 @@ package targets
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,6 +25,84 @@ func hostSetSynopsisFunc(inFunc string) string {
 	return wordwrap.WrapString(fmt.Sprintf("%s a target", in), base.TermWidth)
 }
 
+// bandwidthLimitFlagNames are the names of the flags setting the bandwidth
+// limits of a target.
+var bandwidthLimitFlagNames = []string{
+	"connection-upstream-bytes-per-second",
+	"connection-downstream-bytes-per-second",
+	"session-upstream-bytes-per-second",
+	"session-downstream-bytes-per-second",
+}
+
+// bandwidthLimitFlags holds the values of the flags setting the bandwidth
+// limits of a target.
+type bandwidthLimitFlags struct {
+	connectionUpstream   string
+	connectionDownstream string
+	sessionUpstream      string
+	sessionDownstream    string
+}
+
+// populate adds the bandwidth limit flag with the name to the flag set, if it
+// is one.
+func (b *bandwidthLimitFlags) populate(f *base.FlagSet, name string) {
+	switch name {
+	case "connection-upstream-bytes-per-second":
+		f.StringVar(&base.StringVar{
+			Name:   name,
+			Target: &b.connectionUpstream,
+			Usage:  "The maximum rate at which each connection of a session sends data from the client to the endpoint, in bytes per second. 0 means unlimited.",
+		})
+	case "connection-downstream-bytes-per-second":
+		f.StringVar(&base.StringVar{
+			Name:   name,
+			Target: &b.connectionDownstream,
+			Usage:  "The maximum rate at which each connection of a session sends data from the endpoint to the client, in bytes per second. 0 means unlimited.",
+		})
+	case "session-upstream-bytes-per-second":
+		f.StringVar(&base.StringVar{
+			Name:   name,
+			Target: &b.sessionUpstream,
+			Usage:  "The maximum rate at which all connections of a session together send data from the client to the endpoint, in bytes per second. 0 means unlimited.",
+		})
+	case "session-downstream-bytes-per-second":
+		f.StringVar(&base.StringVar{
+			Name:   name,
+			Target: &b.sessionDownstream,
+			Usage:  "The maximum rate at which all connections of a session together send data from the endpoint to the client, in bytes per second. 0 means unlimited.",
+		})
+	}
+}
+
+// options returns the options setting the bandwidth limits given by the
+// flags.
+func (b *bandwidthLimitFlags) options() ([]targets.Option, error) {
+	var opts []targets.Option
+	for _, l := range []struct {
+		value     string
+		withFn    func(uint32) targets.Option
+		defaultFn func() targets.Option
+	}{
+		{b.connectionUpstream, targets.WithConnectionUpstreamBytesPerSecond, targets.DefaultConnectionUpstreamBytesPerSecond},
+		{b.connectionDownstream, targets.WithConnectionDownstreamBytesPerSecond, targets.DefaultConnectionDownstreamBytesPerSecond},
+		{b.sessionUpstream, targets.WithSessionUpstreamBytesPerSecond, targets.DefaultSessionUpstreamBytesPerSecond},
+		{b.sessionDownstream, targets.WithSessionDownstreamBytesPerSecond, targets.DefaultSessionDownstreamBytesPerSecond},
+	} {
+		switch l.value {
+		case "":
+		case "null":
+			opts = append(opts, l.defaultFn())
+		default:
+			limit, err := strconv.ParseUint(l.value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing %q: %s", l.value, err)
+			}
+			opts = append(opts, l.withFn(uint32(limit)))
+		}
+	}
+	return opts, nil
+}
+
 func generateTargetTableOutput(in *targets.Target) string {
 	nonAttributeMap := map[string]interface{}{
 		"ID":                       in.Id,
@@ -41,6 +120,18 @@ func generateTargetTableOutput(in *targets.Target) string {
 	if in.Description != "" {
 		nonAttributeMap["Description"] = in.Description
 	}
+	if in.ConnectionUpstreamBytesPerSecond != 0 {
+		nonAttributeMap["Connection Upstream Bytes Per Second"] = in.ConnectionUpstreamBytesPerSecond
+	}
+	if in.ConnectionDownstreamBytesPerSecond != 0 {
+		nonAttributeMap["Connection Downstream Bytes Per Second"] = in.ConnectionDownstreamBytesPerSecond
+	}
+	if in.SessionUpstreamBytesPerSecond != 0 {
+		nonAttributeMap["Session Upstream Bytes Per Second"] = in.SessionUpstreamBytesPerSecond
+	}
+	if in.SessionDownstreamBytesPerSecond != 0 {
+		nonAttributeMap["Session Downstream Bytes Per Second"] = in.SessionDownstreamBytesPerSecond
+	}
 
 	maxLength := base.MaxAttributesLength(nonAttributeMap, in.Attributes, keySubstMap)
 
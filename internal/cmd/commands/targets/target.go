@@ -74,6 +74,10 @@ func (c *Command) Help() string {
 			"",
 			`      $ boundary targets create tcp -name prodops -description "For ProdOps usage"`,
 			"",
+			"    Create a udp-type target:",
+			"",
+			`      $ boundary targets create udp -name dns -description "For DNS lookups"`,
+			"",
 			"  Please see the typed subcommand help for detailed usage information.",
 		})
 	case "update":
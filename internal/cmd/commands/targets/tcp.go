@@ -25,6 +25,7 @@ type TcpCommand struct {
 	flagDefaultPort            string
 	flagSessionMaxSeconds      string
 	flagSessionConnectionLimit string
+	flagBandwidthLimits        bandwidthLimitFlags
 }
 
 func (c *TcpCommand) Synopsis() string {
@@ -32,8 +33,8 @@ func (c *TcpCommand) Synopsis() string {
 }
 
 var tcpFlagsMap = map[string][]string{
-	"create": {"scope-id", "name", "description", "default-port", "session-max-seconds", "session-connection-limit"},
-	"update": {"id", "name", "description", "version", "default-port", "session-max-seconds", "session-connection-limit"},
+	"create": append([]string{"scope-id", "name", "description", "default-port", "session-max-seconds", "session-connection-limit"}, bandwidthLimitFlagNames...),
+	"update": append([]string{"id", "name", "description", "version", "default-port", "session-max-seconds", "session-connection-limit"}, bandwidthLimitFlagNames...),
 }
 
 func (c *TcpCommand) Help() string {
@@ -89,6 +90,8 @@ func (c *TcpCommand) Flags() *base.FlagSets {
 				Target: &c.flagSessionConnectionLimit,
 				Usage:  "The maximum number of connections allowed for a session. -1 means unlimited.",
 			})
+		default:
+			c.flagBandwidthLimits.populate(f, name)
 		}
 	}
 
@@ -194,6 +197,13 @@ func (c *TcpCommand) Run(args []string) int {
 		opts = append(opts, targets.WithSessionConnectionLimit(int32(limit)))
 	}
 
+	bandwidthOpts, err := c.flagBandwidthLimits.options()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	opts = append(opts, bandwidthOpts...)
+
 	targetClient := targets.NewClient(client)
 
 	// Perform check-and-set when needed
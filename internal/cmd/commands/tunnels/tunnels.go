@@ -0,0 +1,215 @@
+package tunnels
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/cmd/commands/agent"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+
+	Func string
+
+	flagSocket string
+	flagName   string
+}
+
+func (c *Command) Synopsis() string {
+	switch c.Func {
+	case "list":
+		return "List the tunnels of the running agent"
+	case "up":
+		return "Bring a tunnel of the running agent up"
+	case "down":
+		return "Bring a tunnel of the running agent down"
+	default:
+		return "Manage the tunnels of the running agent"
+	}
+}
+
+func (c *Command) Help() string {
+	switch c.Func {
+	case "":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary tunnels [sub command] [options]",
+			"",
+			`  This command allows managing the tunnels of a running "boundary agent". Example:`,
+			"",
+			"    Bring a tunnel up:",
+			"",
+			`      $ boundary tunnels up -name web`,
+			"",
+			"  Please see the tunnels subcommand help for detailed usage information.",
+		})
+	case "list":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary tunnels list [options]",
+			"",
+			"  List the tunnels declared in the running agent's profile with their status and, for tunnels which are up, their session and connection information. Example:",
+			"",
+			`      $ boundary tunnels list`,
+			"",
+			"",
+		}) + c.Flags().Help()
+	default:
+		return base.WrapForHelpText([]string{
+			fmt.Sprintf("Usage: boundary tunnels %s [options]", c.Func),
+			"",
+			fmt.Sprintf("  %s. Example:", c.Synopsis()),
+			"",
+			fmt.Sprintf(`      $ boundary tunnels %s -name web`, c.Func),
+			"",
+			"",
+		}) + c.Flags().Help()
+	}
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	if c.Func == "" {
+		return base.NewFlagSets(c.UI)
+	}
+
+	set := c.FlagSet(base.FlagSetOutputFormat)
+	f := set.NewFlagSet("Command Options")
+
+	f.StringVar(&base.StringVar{
+		Name:       "socket",
+		Target:     &c.flagSocket,
+		EnvVar:     agent.EnvAgentSocket,
+		Completion: complete.PredictFiles("*"),
+		Usage:      "Path of the Unix socket the agent listens on. Defaults to the agent's default path.",
+	})
+
+	if c.Func != "list" {
+		f.StringVar(&base.StringVar{
+			Name:   "name",
+			Target: &c.flagName,
+			Usage:  "The name of the tunnel, as declared in the agent's profile.",
+		})
+	}
+
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	if c.Func == "" {
+		return cli.RunResultHelp
+	}
+
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.Func != "list" && c.flagName == "" {
+		c.UI.Error("Tunnel name must be passed in via -name")
+		return 1
+	}
+	if c.flagSocket == "" {
+		c.flagSocket = agent.DefaultSocketPath()
+	}
+	client := agent.NewControlClient(c.flagSocket)
+
+	var statuses []*agent.TunnelStatus
+	var status *agent.TunnelStatus
+	var err error
+	switch c.Func {
+	case "list":
+		statuses, err = client.List(c.Context)
+	case "up":
+		status, err = client.Up(c.Context, c.flagName)
+	case "down":
+		status, err = client.Down(c.Context, c.flagName)
+	}
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error trying to %s tunnels: %s", c.Func, err.Error()))
+		return 2
+	}
+
+	switch base.Format(c.UI) {
+	case "json":
+		var out interface{} = status
+		if c.Func == "list" {
+			out = statuses
+		}
+		b, err := base.JsonFormatter{}.Format(out)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		if c.Func == "list" {
+			if len(statuses) == 0 {
+				c.UI.Output("No tunnels found")
+				return 0
+			}
+			c.UI.Output(generateStatusTableOutput(statuses...))
+			return 0
+		}
+		c.UI.Output(generateStatusTableOutput(status))
+	}
+
+	return 0
+}
+
+func generateStatusTableOutput(in ...*agent.TunnelStatus) string {
+	output := []string{
+		"",
+		"Tunnel information:",
+	}
+	for i, t := range in {
+		if i > 0 {
+			output = append(output, "")
+		}
+		output = append(output,
+			fmt.Sprintf("  Name:                 %s", t.Name),
+			fmt.Sprintf("    Status:             %s", t.Status),
+			fmt.Sprintf("    Target ID:          %s", t.TargetId),
+		)
+		if t.HostId != "" {
+			output = append(output,
+				fmt.Sprintf("    Host ID:            %s", t.HostId),
+			)
+		}
+		if t.Session != nil {
+			output = append(output,
+				fmt.Sprintf("    Address:            %s", t.Session.Address),
+				fmt.Sprintf("    Port:               %d", t.Session.Port),
+				fmt.Sprintf("    Session ID:         %s", t.Session.SessionId),
+				fmt.Sprintf("    Expiration:         %s", t.Session.Expiration.Local().Format(time.RFC1123)),
+				fmt.Sprintf("    Connection Limit:   %d", t.Session.ConnectionLimit),
+			)
+		}
+		if t.Connections != nil {
+			output = append(output,
+				fmt.Sprintf("    Connections Left:   %d", t.Connections.ConnectionsLeft),
+			)
+		}
+		if t.Error != "" {
+			output = append(output,
+				fmt.Sprintf("    Last Error:         %s", t.Error),
+			)
+		}
+	}
+	return base.WrapForHelpText(output)
+}
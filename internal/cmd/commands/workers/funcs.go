@@ -0,0 +1,83 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/api/workers"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+)
+
+func generateWorkerTableOutput(in *workers.Worker) string {
+	nonAttributeMap := map[string]interface{}{
+		"ID":                      in.Id,
+		"Name":                    in.Name,
+		"Address":                 in.Address,
+		"Created Time":            in.CreatedTime.Local().Format(time.RFC1123),
+		"Updated Time":            in.UpdatedTime.Local().Format(time.RFC1123),
+		"Operational State":       in.OperationalState,
+		"Active Session Count":    in.ActiveSessionCount,
+		"Active Connection Count": in.ActiveConnectionCount,
+	}
+	if in.Description != "" {
+		nonAttributeMap["Description"] = in.Description
+	}
+	if !in.DrainDeadline.IsZero() {
+		nonAttributeMap["Drain Deadline"] = in.DrainDeadline.Local().Format(time.RFC1123)
+	}
+	if in.ReleaseVersion != "" {
+		nonAttributeMap["Release Version"] = in.ReleaseVersion
+	}
+
+	maxLength := base.MaxAttributesLength(nonAttributeMap, in.Tags, nil)
+
+	ret := []string{
+		"",
+		"Worker information:",
+		base.WrapMap(2, maxLength+2, nonAttributeMap),
+		"",
+		"  Scope:",
+		base.ScopeInfoForOutput(in.Scope, maxLength),
+	}
+
+	if len(in.Tags) > 0 {
+		ret = append(ret,
+			"",
+			"  Tags:",
+			base.WrapMap(4, maxLength, in.Tags),
+		)
+	}
+
+	return base.WrapForHelpText(ret)
+}
+
+func generateWorkerListTableOutput(in []*workers.Worker) string {
+	output := []string{
+		"",
+		"Worker information:",
+	}
+	for i, w := range in {
+		if i > 0 {
+			output = append(output, "")
+		}
+		output = append(output,
+			fmt.Sprintf("  ID:                    %s", w.Id),
+			fmt.Sprintf("    Address:             %s", w.Address),
+			fmt.Sprintf("    Operational State:   %s", w.OperationalState),
+			fmt.Sprintf("    Last Status Time:    %s", w.UpdatedTime.Local().Format(time.RFC1123)),
+			fmt.Sprintf("    Active Sessions:     %d", w.ActiveSessionCount),
+			fmt.Sprintf("    Active Connections:  %d", w.ActiveConnectionCount),
+		)
+		if w.ReleaseVersion != "" {
+			output = append(output,
+				fmt.Sprintf("    Release Version:     %s", w.ReleaseVersion),
+			)
+		}
+		if w.Description != "" {
+			output = append(output,
+				fmt.Sprintf("    Description:         %s", w.Description),
+			)
+		}
+	}
+	return base.WrapForHelpText(output)
+}
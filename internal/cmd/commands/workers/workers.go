@@ -0,0 +1,264 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/workers"
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var _ cli.Command = (*Command)(nil)
+var _ cli.CommandAutocomplete = (*Command)(nil)
+
+type Command struct {
+	*base.Command
+
+	Func string
+
+	flagState        string
+	flagDrainTimeout time.Duration
+}
+
+func (c *Command) Synopsis() string {
+	switch c.Func {
+	case "read":
+		return "Read a worker's details"
+	case "list":
+		return "List workers"
+	case "set-operational-state":
+		return "Set the operational state of a worker"
+	}
+	return "Manage Boundary workers"
+}
+
+func (c *Command) Help() string {
+	switch c.Func {
+	case "read":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary workers read [options] [args]",
+			"",
+			"  Read a worker given its ID, which is its name. Example:",
+			"",
+			`    $ boundary workers read -id worker-1`,
+			"",
+			"",
+		}) + c.Flags().Help()
+	case "list":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary workers list [options] [args]",
+			"",
+			"  List the workers which have reported to the controllers, including the",
+			"  time of their last status. Workers are in the global scope. Example:",
+			"",
+			`    $ boundary workers list`,
+			"",
+			"",
+		}) + c.Flags().Help()
+	case "set-operational-state":
+		return base.WrapForHelpText([]string{
+			"Usage: boundary workers set-operational-state [options] [args]",
+			"",
+			"  Set the operational state of a worker to active, draining or disabled.",
+			"  Only active workers are given new sessions. A draining worker only",
+			"  accepts connections of the sessions it already proxies, and closes them",
+			"  when the drain timeout passes if one is given. A disabled worker closes",
+			"  all of its connections. Example:",
+			"",
+			`    $ boundary workers set-operational-state -id worker-1 -state draining -drain-timeout 1h`,
+			"",
+			"  A worker can also be set to draining by sending it the SIGUSR1 signal.",
+			"",
+		}) + c.Flags().Help()
+	}
+	return base.WrapForHelpText([]string{
+		"Usage: boundary workers [sub command] [options] [args]",
+		"",
+		"  This command allows operations on Boundary workers. Example:",
+		"",
+		"    List workers:",
+		"",
+		`      $ boundary workers list`,
+		"",
+		"    Drain a worker:",
+		"",
+		`      $ boundary workers set-operational-state -id worker-1 -state draining`,
+		"",
+		"  Please see the workers subcommand help for detailed usage information.",
+	})
+}
+
+func (c *Command) Flags() *base.FlagSets {
+	set := c.FlagSet(base.FlagSetHTTP | base.FlagSetClient | base.FlagSetOutputFormat)
+	if c.Func == "" {
+		return set
+	}
+	f := set.NewFlagSet("Command Options")
+
+	switch c.Func {
+	case "read":
+		f.StringVar(&base.StringVar{
+			Name:   "id",
+			Target: &c.FlagId,
+			Usage:  "ID of the worker, which is its name.",
+		})
+	case "list":
+		f.StringVar(&base.StringVar{
+			Name:    "scope-id",
+			Target:  &c.FlagScopeId,
+			Default: scope.Global.String(),
+			Usage:   "Scope in which to list workers, which can only be global.",
+		})
+	case "set-operational-state":
+		f.StringVar(&base.StringVar{
+			Name:   "id",
+			Target: &c.FlagId,
+			Usage:  "ID of the worker, which is its name.",
+		})
+		f.StringVar(&base.StringVar{
+			Name:       "state",
+			Target:     &c.flagState,
+			Completion: complete.PredictSet(workers.OperationalStateActive, workers.OperationalStateDraining, workers.OperationalStateDisabled),
+			Usage:      `The operational state to set, one of "active", "draining" or "disabled".`,
+		})
+		f.DurationVar(&base.DurationVar{
+			Name:   "drain-timeout",
+			Target: &c.flagDrainTimeout,
+			Usage:  "How long a draining worker waits for its connections to finish before closing them. If not set, it waits for as long as they take.",
+		})
+	}
+
+	return set
+}
+
+func (c *Command) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *Command) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *Command) Run(args []string) int {
+	if c.Func == "" {
+		return cli.RunResultHelp
+	}
+
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	switch c.Func {
+	case "read":
+		if c.FlagId == "" {
+			c.UI.Error("ID is required but not passed in via -id")
+			return 1
+		}
+	case "set-operational-state":
+		if c.FlagId == "" {
+			c.UI.Error("ID is required but not passed in via -id")
+			return 1
+		}
+		switch c.flagState {
+		case workers.OperationalStateActive, workers.OperationalStateDraining, workers.OperationalStateDisabled:
+		case "":
+			c.UI.Error("State is required but not passed in via -state")
+			return 1
+		default:
+			c.UI.Error(fmt.Sprintf("Unknown operational state %q", c.flagState))
+			return 1
+		}
+		if c.flagDrainTimeout < 0 {
+			c.UI.Error("Drain timeout must not be negative")
+			return 1
+		}
+		if c.flagDrainTimeout > 0 && c.flagState != workers.OperationalStateDraining {
+			c.UI.Error("Drain timeout can only be set when draining")
+			return 1
+		}
+	}
+
+	client, err := c.Client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error creating API client: %s", err.Error()))
+		return 2
+	}
+
+	var opts []workers.Option
+	if c.flagDrainTimeout > 0 {
+		opts = append(opts, workers.WithDrainTimeout(c.flagDrainTimeout))
+	}
+
+	workerClient := workers.NewClient(client)
+
+	var result api.GenericResult
+	var listResult api.GenericListResult
+	switch c.Func {
+	case "read":
+		result, err = workerClient.Read(c.Context, c.FlagId, opts...)
+	case "list":
+		listResult, err = workerClient.List(c.Context, c.FlagScopeId, opts...)
+	case "set-operational-state":
+		result, err = workerClient.SetOperationalState(c.Context, c.FlagId, c.flagState, opts...)
+	}
+
+	plural := "worker"
+	if c.Func == "list" {
+		plural = "workers"
+	}
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			c.UI.Error(fmt.Sprintf("Error from controller when performing %s on %s: %s", c.Func, plural, base.PrintApiError(apiErr)))
+			return 1
+		}
+		c.UI.Error(fmt.Sprintf("Error trying to %s %s: %s", c.Func, plural, err.Error()))
+		return 2
+	}
+
+	if c.Func == "list" {
+		listedWorkers := listResult.GetItems().([]*workers.Worker)
+		switch base.Format(c.UI) {
+		case "json":
+			if len(listedWorkers) == 0 {
+				c.UI.Output("null")
+				return 0
+			}
+			b, err := base.JsonFormatter{}.Format(listedWorkers)
+			if err != nil {
+				c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+				return 1
+			}
+			c.UI.Output(string(b))
+
+		case "table":
+			if len(listedWorkers) == 0 {
+				c.UI.Output("No workers found")
+				return 0
+			}
+			c.UI.Output(generateWorkerListTableOutput(listedWorkers))
+		}
+		return 0
+	}
+
+	worker := result.GetItem().(*workers.Worker)
+	switch base.Format(c.UI) {
+	case "table":
+		c.UI.Output(generateWorkerTableOutput(worker))
+	case "json":
+		b, err := base.JsonFormatter{}.Format(worker)
+		if err != nil {
+			c.UI.Error(fmt.Errorf("Error formatting as JSON: %w", err).Error())
+			return 1
+		}
+		c.UI.Output(string(b))
+	}
+
+	return 0
+}
@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net/url"
-	"os"
+	"net"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/boundary/sdk/parseutil"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/shared-secure-libs/configutil"
 )
@@ -85,6 +88,7 @@ type Config struct {
 
 	Worker     *Worker     `hcl:"worker"`
 	Controller *Controller `hcl:"controller"`
+	Tracing    *Tracing    `hcl:"tracing"`
 
 	// Dev-related options
 	DevController        bool   `hcl:"-"`
@@ -98,6 +102,7 @@ type Controller struct {
 	Name        string    `hcl:"name"`
 	Description string    `hcl:"description"`
 	Database    *Database `hcl:"database"`
+	Oplog       *Oplog    `hcl:"oplog"`
 }
 
 type Worker struct {
@@ -105,11 +110,211 @@ type Worker struct {
 	Description string   `hcl:"description"`
 	Controllers []string `hcl:"controllers"`
 	PublicAddr  string   `hcl:"public_addr"`
+
+	// Egress restricts the endpoints the worker connects to for sessions.
+	// Any endpoint is allowed if it is not set.
+	Egress *WorkerEgress `hcl:"egress"`
+
+	// Bandwidth limits the traffic of all connections the worker proxies
+	// together. It is not limited if it is not set.
+	Bandwidth *WorkerBandwidth `hcl:"bandwidth"`
+
+	// Tags are reported to the controllers to describe the worker, as keys
+	// with any number of values.
+	Tags map[string][]string `hcl:"tags"`
+}
+
+// WorkerBandwidth limits the traffic a worker proxies from clients to
+// endpoints (upstream) and from endpoints to clients (downstream), in bytes
+// per second. 0 means no limit.
+type WorkerBandwidth struct {
+	UpstreamBytesPerSecond   int64 `hcl:"upstream_bytes_per_second"`
+	DownstreamBytesPerSecond int64 `hcl:"downstream_bytes_per_second"`
+}
+
+// WorkerEgress lists the endpoints a worker is allowed to connect to. An
+// endpoint is allowed if its port is allowed and its host is allowed: an IP
+// address in one of the CIDRs, or a host name that ends with one of the DNS
+// suffixes or whose addresses are all in the CIDRs. Any port is allowed if no
+// ports are listed, and any host if neither CIDRs nor DNS suffixes are.
+type WorkerEgress struct {
+	AllowedCidrs    []string     `hcl:"allowed_cidrs"`
+	AllowedCidrNets []*net.IPNet `hcl:"-"`
+
+	// AllowedPorts are port numbers or ranges of them, e.g. "8000-8100"
+	AllowedPorts      []string    `hcl:"allowed_ports"`
+	AllowedPortRanges [][2]uint16 `hcl:"-"`
+
+	// AllowedDnsSuffixes are domains whose names, and subdomains' names, are
+	// allowed, e.g. "internal.example.com"
+	AllowedDnsSuffixes []string `hcl:"allowed_dns_suffixes"`
+}
+
+// parse parses the allowed CIDRs and ports.
+func (e *WorkerEgress) parse() error {
+	e.AllowedCidrNets = make([]*net.IPNet, 0, len(e.AllowedCidrs))
+	for _, c := range e.AllowedCidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return fmt.Errorf("error parsing worker egress allowed_cidrs: %w", err)
+		}
+		e.AllowedCidrNets = append(e.AllowedCidrNets, n)
+	}
+	e.AllowedPortRanges = make([][2]uint16, 0, len(e.AllowedPorts))
+	for _, p := range e.AllowedPorts {
+		low, high := p, p
+		if i := strings.Index(p, "-"); i >= 0 {
+			low, high = p[:i], p[i+1:]
+		}
+		lowPort, err := strconv.ParseUint(strings.TrimSpace(low), 10, 16)
+		if err != nil {
+			return fmt.Errorf("error parsing worker egress allowed_ports %q: %w", p, err)
+		}
+		highPort, err := strconv.ParseUint(strings.TrimSpace(high), 10, 16)
+		if err != nil {
+			return fmt.Errorf("error parsing worker egress allowed_ports %q: %w", p, err)
+		}
+		if lowPort == 0 || lowPort > highPort {
+			return fmt.Errorf("invalid worker egress allowed_ports %q", p)
+		}
+		e.AllowedPortRanges = append(e.AllowedPortRanges, [2]uint16{uint16(lowPort), uint16(highPort)})
+	}
+	for i, suffix := range e.AllowedDnsSuffixes {
+		suffix = strings.Trim(strings.ToLower(suffix), ".")
+		if suffix == "" {
+			return errors.New("invalid empty worker egress allowed_dns_suffixes")
+		}
+		e.AllowedDnsSuffixes[i] = suffix
+	}
+	return nil
+}
+
+// AllowsPort returns true if the port is allowed.
+func (e *WorkerEgress) AllowsPort(port uint16) bool {
+	if len(e.AllowedPortRanges) == 0 {
+		return true
+	}
+	for _, r := range e.AllowedPortRanges {
+		if port >= r[0] && port <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsAnyHost returns true if hosts are not restricted.
+func (e *WorkerEgress) AllowsAnyHost() bool {
+	return len(e.AllowedCidrNets) == 0 && len(e.AllowedDnsSuffixes) == 0
+}
+
+// AllowsIp returns true if the IP address is in one of the CIDRs, or hosts
+// are not restricted.
+func (e *WorkerEgress) AllowsIp(ip net.IP) bool {
+	if e.AllowsAnyHost() {
+		return true
+	}
+	for _, n := range e.AllowedCidrNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsDnsName returns true if the host name ends with one of the DNS
+// suffixes, or hosts are not restricted.
+func (e *WorkerEgress) AllowsDnsName(name string) bool {
+	if e.AllowsAnyHost() {
+		return true
+	}
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	for _, suffix := range e.AllowedDnsSuffixes {
+		if name == suffix || strings.HasSuffix(name, "."+suffix) {
+			return true
+		}
+	}
+	return false
 }
 
 type Database struct {
 	Url          string `hcl:"url"`
 	MigrationUrl string `hcl:"migration_url"`
+	// ReadReplicas are the URLs of read replicas of the database, to which
+	// lookups and searches are sent. Like Url, each can refer to a file
+	// (file://) or an env var (env://) from which the URL is read.
+	ReadReplicas []string `hcl:"read_replica"`
+}
+
+// Oplog configures the maintenance of the operation log
+type Oplog struct {
+	// ChainSigningInterval is how often the heads of the oplog hash chains
+	// are signed with the global scope's oplog key. Signing is disabled if it
+	// is not set.
+	ChainSigningInterval         interface{}   `hcl:"chain_signing_interval"`
+	ChainSigningIntervalDuration time.Duration `hcl:"-"`
+
+	// RetentionPeriod is how long entries are kept before they are archived
+	// and deleted. Entries are kept forever if it is not set.
+	RetentionPeriod         interface{}   `hcl:"retention_period"`
+	RetentionPeriodDuration time.Duration `hcl:"-"`
+
+	// AggregateRetentionPeriods overrides RetentionPeriod for the aggregates
+	// named by its keys, e.g. "iam_user". A period of "0" keeps the entries of
+	// the aggregate forever.
+	AggregateRetentionPeriods         map[string]string        `hcl:"aggregate_retention_periods"`
+	AggregateRetentionPeriodDurations map[string]time.Duration `hcl:"-"`
+
+	// RetentionInterval is how often entries past their retention period are
+	// archived; defaults to an hour
+	RetentionInterval         interface{}   `hcl:"retention_interval"`
+	RetentionIntervalDuration time.Duration `hcl:"-"`
+
+	// ArchivePath is the directory archives of deleted entries are written
+	// to. It must be set if a retention period is set. Each run of the
+	// retention job happens on one of the controllers, so with several
+	// controllers it should be storage they share.
+	ArchivePath string `hcl:"archive_path"`
+}
+
+// RetentionEnabled returns true if entries of any aggregate are deleted after
+// a retention period.
+func (o *Oplog) RetentionEnabled() bool {
+	if o == nil {
+		return false
+	}
+	if o.RetentionPeriodDuration > 0 {
+		return true
+	}
+	for _, d := range o.AggregateRetentionPeriodDurations {
+		if d > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Tracing configures the export of OpenTelemetry spans
+type Tracing struct {
+	// Exporter is one of "otlp", "stdout" or "file"
+	Exporter string `hcl:"exporter"`
+
+	// ServiceName is the service name reported with each span; defaults to
+	// "boundary"
+	ServiceName string `hcl:"service_name"`
+
+	// SampleRatio is the fraction of new traces which are sampled. Spans
+	// whose parent was sampled are always sampled. Defaults to 1.
+	SampleRatio *float64 `hcl:"sample_ratio"`
+
+	// OtlpAddress is the host:port of the OTLP collector
+	OtlpAddress string `hcl:"otlp_address"`
+
+	// OtlpInsecure disables TLS when connecting to the OTLP collector
+	OtlpInsecure bool `hcl:"otlp_insecure"`
+
+	// FilePath is the file spans are appended to when using the "file"
+	// exporter
+	FilePath string `hcl:"file_path"`
 }
 
 // DevWorker is a Config that is used for dev mode of Boundary
@@ -216,9 +421,175 @@ func Parse(d string) (*Config, error) {
 	}
 	result.SharedConfig = sharedConfig
 
+	if err := result.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
+	if result.Worker != nil && result.Worker.Egress != nil {
+		if err := result.Worker.Egress.parse(); err != nil {
+			return nil, err
+		}
+	}
+	if result.Worker != nil && result.Worker.Bandwidth != nil {
+		if result.Worker.Bandwidth.UpstreamBytesPerSecond < 0 || result.Worker.Bandwidth.DownstreamBytesPerSecond < 0 {
+			return nil, errors.New("worker bandwidth limits must not be negative")
+		}
+	}
+	if result.Worker != nil {
+		for k, vs := range result.Worker.Tags {
+			if strings.TrimSpace(k) == "" {
+				return nil, errors.New("worker tag keys must not be empty")
+			}
+			for _, v := range vs {
+				if strings.TrimSpace(v) == "" {
+					return nil, fmt.Errorf("values of worker tag %q must not be empty", k)
+				}
+			}
+		}
+	}
+
+	if result.Controller != nil && result.Controller.Oplog != nil {
+		oplog := result.Controller.Oplog
+		if oplog.ChainSigningInterval != nil {
+			oplog.ChainSigningIntervalDuration, err = parseutil.ParseDurationSecond(oplog.ChainSigningInterval)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing oplog chain_signing_interval: %w", err)
+			}
+		}
+		if oplog.RetentionPeriod != nil {
+			oplog.RetentionPeriodDuration, err = parseutil.ParseDurationSecond(oplog.RetentionPeriod)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing oplog retention_period: %w", err)
+			}
+		}
+		if len(oplog.AggregateRetentionPeriods) > 0 {
+			oplog.AggregateRetentionPeriodDurations = make(map[string]time.Duration, len(oplog.AggregateRetentionPeriods))
+			for name, period := range oplog.AggregateRetentionPeriods {
+				oplog.AggregateRetentionPeriodDurations[name], err = parseutil.ParseDurationSecond(period)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing oplog aggregate_retention_periods for %q: %w", name, err)
+				}
+			}
+		}
+		if oplog.RetentionInterval != nil {
+			oplog.RetentionIntervalDuration, err = parseutil.ParseDurationSecond(oplog.RetentionInterval)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing oplog retention_interval: %w", err)
+			}
+		}
+		if oplog.RetentionEnabled() && oplog.ArchivePath == "" {
+			return nil, errors.New("oplog archive_path must be set when a retention period is set")
+		}
+	}
+
 	return result, nil
 }
 
+// Validate checks that the config can be used to start a server: that a
+// controller or worker is configured and named, that the listeners and KMSes
+// they require are present, and that listeners and KMSes have known purposes.
+// All problems found are returned.
+func (c *Config) Validate() error {
+	var mErr *multierror.Error
+	if c.Controller == nil && c.Worker == nil {
+		mErr = multierror.Append(mErr, errors.New("Neither worker nor controller specified in configuration file."))
+	}
+	if c.Controller != nil {
+		if c.Controller.Name == "" {
+			mErr = multierror.Append(mErr, errors.New("Controller has no name set. It must be the unique name of this instance."))
+		}
+		if c.Controller.Database == nil || c.Controller.Database.Url == "" {
+			mErr = multierror.Append(mErr, errors.New(`"url" not specified in "controller.database" config block`))
+		}
+	}
+	if c.Worker != nil && c.Worker.Name == "" {
+		mErr = multierror.Append(mErr, errors.New("Worker has no name set. It must be the unique name of this instance."))
+	}
+
+	var clusterAddr string
+	var foundApi, foundCluster, foundProxy bool
+	if c.SharedConfig != nil {
+		for _, ln := range c.Listeners {
+			switch len(ln.Purpose) {
+			case 0:
+				mErr = multierror.Append(mErr, errors.New("Listener specified without a purpose"))
+			case 1:
+				switch ln.Purpose[0] {
+				case "cluster":
+					foundCluster = true
+					clusterAddr = ln.Address
+					if clusterAddr == "" {
+						clusterAddr = "127.0.0.1:9201"
+					}
+				case "api":
+					foundApi = true
+				case "proxy":
+					foundProxy = true
+				case "metrics":
+				default:
+					mErr = multierror.Append(mErr, fmt.Errorf("Unknown listener purpose %q", ln.Purpose[0]))
+				}
+			default:
+				mErr = multierror.Append(mErr, errors.New("Specifying a listener with more than one purpose is not supported"))
+			}
+		}
+	}
+	if c.Controller != nil {
+		if !foundApi {
+			mErr = multierror.Append(mErr, errors.New(`Config activates controller but no listener with "api" purpose found`))
+		}
+		if !foundCluster {
+			mErr = multierror.Append(mErr, errors.New(`Config activates controller but no listener with "cluster" purpose found`))
+		}
+	}
+	if c.Worker != nil {
+		if !foundProxy {
+			mErr = multierror.Append(mErr, errors.New(`Config activates worker but no listener with "proxy" purpose found`))
+		}
+		if c.Controller != nil && foundCluster {
+			switch len(c.Worker.Controllers) {
+			case 0:
+			case 1:
+				if c.Worker.Controllers[0] == clusterAddr {
+					break
+				}
+				fallthrough
+			default:
+				mErr = multierror.Append(mErr, errors.New(`When running a combined controller and worker, it's invalid to specify a "controllers" key in the worker block with any value other than the controller cluster address`))
+			}
+		}
+	}
+
+	var foundRoot, foundWorkerAuth bool
+	if c.SharedConfig != nil {
+		for _, kms := range c.Seals {
+			if len(kms.Purpose) == 0 {
+				mErr = multierror.Append(mErr, errors.New("KMS block missing 'purpose'"))
+			}
+			for _, purpose := range kms.Purpose {
+				switch strings.ToLower(purpose) {
+				case "root":
+					foundRoot = true
+				case "worker-auth":
+					foundWorkerAuth = true
+				case "recovery", "config":
+				case "":
+					mErr = multierror.Append(mErr, errors.New("KMS block missing 'purpose'"))
+				default:
+					mErr = multierror.Append(mErr, fmt.Errorf("Unknown KMS purpose %q", purpose))
+				}
+			}
+		}
+	}
+	if c.Controller != nil && !foundRoot {
+		mErr = multierror.Append(mErr, errors.New(`Config activates controller but no KMS with "root" purpose found`))
+	}
+	if (c.Controller != nil || c.Worker != nil) && !foundWorkerAuth {
+		mErr = multierror.Append(mErr, errors.New(`No KMS with "worker-auth" purpose found`))
+	}
+	return mErr.ErrorOrNil()
+}
+
 // Sanitized returns a copy of the config with all values that are considered
 // sensitive stripped. It also strips all `*Raw` values that are mainly
 // used for parsing.
@@ -241,14 +612,17 @@ func (c *Config) Sanitized() map[string]interface{} {
 	return result
 }
 
-var ErrNotAUrl = errors.New("not a url")
+var ErrNotAUrl = parseutil.ErrNotAUrl
 
 // ParseAddress parses a URL with schemes file://, env://, or any other.
 // Depending on the scheme it will return specific types of data:
 //
 // * file:// will return a string with the file's contents * env:// will return
-// a string with the env var's contents * anything else will return the string
-// as it was
+// a string with the env var's contents, or an error if the env var is not set
+// * anything else will return the string as it was
+//
+// Leading and trailing whitespace is trimmed from the contents of files and
+// env vars.
 //
 // On error, we return the original string along with the error. The caller can
 // switch on ErrNotAUrl to understand whether it was the parsing step that
@@ -256,21 +630,58 @@ var ErrNotAUrl = errors.New("not a url")
 // from some resource, but where the original input may simply be a valid string
 // of that type.
 func ParseAddress(addr string) (string, error) {
-	addr = strings.TrimSpace(addr)
-	parsed, err := url.Parse(addr)
-	if err != nil {
-		return addr, ErrNotAUrl
+	value, err := parseutil.ParsePath(addr)
+	if errors.Is(err, parseutil.ErrNotParsed) {
+		return value, nil
 	}
-	switch parsed.Scheme {
-	case "file":
-		contents, err := ioutil.ReadFile(strings.TrimPrefix(addr, "file://"))
-		if err != nil {
-			return addr, fmt.Errorf("error reading file at %s: %w", addr, err)
-		}
-		return string(contents), nil
-	case "env":
-		return os.Getenv(strings.TrimPrefix(addr, "env://")), nil
+	return value, err
+}
+
+// parseSecret resolves a secret-bearing config value which may be a reference
+// to a file (file:///path) or an env var (env://NAME) rather than the secret
+// itself. name is used in errors.
+func parseSecret(name, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	resolved, err := ParseAddress(value)
+	switch {
+	case errors.Is(err, ErrNotAUrl):
+		return value, nil
+	case err != nil:
+		return "", fmt.Errorf("error reading %s: %w", name, err)
 	}
+	return resolved, nil
+}
 
-	return addr, nil
+// resolveSecrets replaces the references in the secret-bearing fields of the
+// config with the values they refer to: the database URLs and the values of
+// KMS blocks, such as the key of an "aead" KMS.
+func (c *Config) resolveSecrets() error {
+	var err error
+	if c.Controller != nil && c.Controller.Database != nil {
+		d := c.Controller.Database
+		if d.Url, err = parseSecret("database url", d.Url); err != nil {
+			return err
+		}
+		if d.MigrationUrl, err = parseSecret("database migration_url", d.MigrationUrl); err != nil {
+			return err
+		}
+		for i, replica := range d.ReadReplicas {
+			if d.ReadReplicas[i], err = parseSecret("database read_replica", replica); err != nil {
+				return err
+			}
+		}
+	}
+	if c.SharedConfig != nil {
+		for _, kms := range c.SharedConfig.Seals {
+			for k, v := range kms.Config {
+				name := fmt.Sprintf("%s of kms %q with purpose %q", k, kms.Type, strings.Join(kms.Purpose, ","))
+				if kms.Config[k], err = parseSecret(name, v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
 }
@@ -1,11 +1,17 @@
 package config
 
 import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/shared-secure-libs/configutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDevController(t *testing.T) {
@@ -115,3 +121,272 @@ func TestDevWorker(t *testing.T) {
 
 	assert.Equal(t, exp, actual)
 }
+
+func TestParseTracing(t *testing.T) {
+	actual, err := Parse(`
+tracing {
+	exporter = "otlp"
+	service_name = "boundary-controller"
+	sample_ratio = 0.25
+	otlp_address = "collector:55680"
+	otlp_insecure = true
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ratio := 0.25
+	assert.Equal(t, &Tracing{
+		Exporter:     "otlp",
+		ServiceName:  "boundary-controller",
+		SampleRatio:  &ratio,
+		OtlpAddress:  "collector:55680",
+		OtlpInsecure: true,
+	}, actual.Tracing)
+}
+
+func TestParseOplog(t *testing.T) {
+	actual, err := Parse(`
+controller {
+	name = "boundary-controller"
+	oplog {
+		chain_signing_interval = "10m"
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 10*time.Minute, actual.Controller.Oplog.ChainSigningIntervalDuration)
+	assert.False(t, actual.Controller.Oplog.RetentionEnabled())
+
+	actual, err = Parse(`
+controller {
+	oplog {
+		retention_period = "2160h"
+		aggregate_retention_periods = {
+			iam_user = "8760h"
+			target_tcp = "0"
+		}
+		retention_interval = "30m"
+		archive_path = "/var/lib/boundary/oplog"
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oplog := actual.Controller.Oplog
+	assert.True(t, oplog.RetentionEnabled())
+	assert.Equal(t, 2160*time.Hour, oplog.RetentionPeriodDuration)
+	assert.Equal(t, map[string]time.Duration{
+		"iam_user":   8760 * time.Hour,
+		"target_tcp": 0,
+	}, oplog.AggregateRetentionPeriodDurations)
+	assert.Equal(t, 30*time.Minute, oplog.RetentionIntervalDuration)
+	assert.Equal(t, "/var/lib/boundary/oplog", oplog.ArchivePath)
+
+	_, err = Parse(`
+controller {
+	oplog {
+		retention_period = "2160h"
+	}
+}
+`)
+	assert.Error(t, err)
+
+	_, err = Parse(`
+controller {
+	oplog {
+		chain_signing_interval = "ten minutes"
+	}
+}
+`)
+	assert.Error(t, err)
+}
+
+func TestParseWorkerBandwidth(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	actual, err := Parse(`
+worker {
+	bandwidth {
+		upstream_bytes_per_second = 1000000
+		downstream_bytes_per_second = 5000000
+	}
+}
+`)
+	require.NoError(err)
+	require.NotNil(actual.Worker.Bandwidth)
+	assert.Equal(int64(1000000), actual.Worker.Bandwidth.UpstreamBytesPerSecond)
+	assert.Equal(int64(5000000), actual.Worker.Bandwidth.DownstreamBytesPerSecond)
+
+	_, err = Parse(`
+worker {
+	bandwidth {
+		upstream_bytes_per_second = -1
+	}
+}
+`)
+	assert.Error(err)
+}
+
+func TestParseWorkerTags(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	actual, err := Parse(`
+worker {
+	tags {
+		type = ["prod", "webservers"]
+		region = ["us-east-1"]
+	}
+}
+`)
+	require.NoError(err)
+	assert.Equal(map[string][]string{
+		"type":   {"prod", "webservers"},
+		"region": {"us-east-1"},
+	}, actual.Worker.Tags)
+
+	_, err = Parse(`
+worker {
+	tags {
+		type = [""]
+	}
+}
+`)
+	assert.Error(err)
+}
+
+func TestParseWorkerEgress(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	actual, err := Parse(`
+worker {
+	name = "boundary-worker"
+	egress {
+		allowed_cidrs = ["10.0.0.0/8", "fd00::/8"]
+		allowed_ports = [22, "8000-8100"]
+		allowed_dns_suffixes = [".Internal.Example.com."]
+	}
+}
+`)
+	require.NoError(err)
+	egress := actual.Worker.Egress
+	require.NotNil(egress)
+	assert.Equal([][2]uint16{{22, 22}, {8000, 8100}}, egress.AllowedPortRanges)
+	assert.Equal([]string{"internal.example.com"}, egress.AllowedDnsSuffixes)
+
+	assert.True(egress.AllowsPort(22))
+	assert.True(egress.AllowsPort(8050))
+	assert.False(egress.AllowsPort(23))
+
+	assert.True(egress.AllowsIp(net.ParseIP("10.1.2.3")))
+	assert.True(egress.AllowsIp(net.ParseIP("fd00::1")))
+	assert.False(egress.AllowsIp(net.ParseIP("192.168.1.1")))
+	assert.False(egress.AllowsIp(net.ParseIP("169.254.169.254")))
+
+	assert.True(egress.AllowsDnsName("internal.example.com"))
+	assert.True(egress.AllowsDnsName("db.INTERNAL.example.com."))
+	assert.False(egress.AllowsDnsName("notinternal.example.com"))
+	assert.False(egress.AllowsDnsName("internal.example.com.evil.com"))
+
+	actual, err = Parse(`
+worker {
+	egress {
+		allowed_ports = ["5432"]
+	}
+}
+`)
+	require.NoError(err)
+	egress = actual.Worker.Egress
+	assert.True(egress.AllowsAnyHost())
+	assert.True(egress.AllowsIp(net.ParseIP("192.168.1.1")))
+	assert.True(egress.AllowsDnsName("anything.example.com"))
+	assert.False(egress.AllowsPort(22))
+
+	for _, invalid := range []string{
+		`allowed_cidrs = ["10.0.0.0"]`,
+		`allowed_ports = ["http"]`,
+		`allowed_ports = ["100-10"]`,
+		`allowed_ports = [0]`,
+		`allowed_ports = [70000]`,
+		`allowed_dns_suffixes = ["."]`,
+	} {
+		_, err = Parse(fmt.Sprintf("worker {\n\tegress {\n\t\t%s\n\t}\n}\n", invalid))
+		assert.Error(err, invalid)
+	}
+}
+
+func TestParseDatabase(t *testing.T) {
+	actual, err := Parse(`
+controller {
+	database {
+		url = "postgresql://primary.example.com/boundary"
+		read_replica = [
+			"postgresql://replica-1.example.com/boundary",
+			"postgresql://replica-2.example.com/boundary",
+		]
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "postgresql://primary.example.com/boundary", actual.Controller.Database.Url)
+	assert.Equal(t, []string{
+		"postgresql://replica-1.example.com/boundary",
+		"postgresql://replica-2.example.com/boundary",
+	}, actual.Controller.Database.ReadReplicas)
+}
+
+func TestParseSecretReferences(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boundary-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "root.key")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("8fZBjCUfN0TzjEGLQldGY4+iE9AkOvCfjh7+p0GtRBQ=\n"), 0600))
+
+	os.Setenv("BOUNDARY_TEST_PG_URL", "postgresql://primary.example.com/boundary")
+	defer os.Unsetenv("BOUNDARY_TEST_PG_URL")
+	os.Setenv("BOUNDARY_TEST_PG_REPLICA_URL", "postgresql://replica.example.com/boundary")
+	defer os.Unsetenv("BOUNDARY_TEST_PG_REPLICA_URL")
+
+	actual, err := Parse(fmt.Sprintf(`
+controller {
+	database {
+		url = "env://BOUNDARY_TEST_PG_URL"
+		migration_url = "file://%s"
+		read_replica = ["env://BOUNDARY_TEST_PG_REPLICA_URL"]
+	}
+}
+
+kms "aead" {
+	purpose = "root"
+	aead_type = "aes-gcm"
+	key = "file://%s"
+}
+`, keyFile, keyFile))
+	require.NoError(t, err)
+	assert.Equal(t, "postgresql://primary.example.com/boundary", actual.Controller.Database.Url)
+	assert.Equal(t, "8fZBjCUfN0TzjEGLQldGY4+iE9AkOvCfjh7+p0GtRBQ=", actual.Controller.Database.MigrationUrl)
+	assert.Equal(t, []string{"postgresql://replica.example.com/boundary"}, actual.Controller.Database.ReadReplicas)
+	require.Len(t, actual.Seals, 1)
+	assert.Equal(t, "8fZBjCUfN0TzjEGLQldGY4+iE9AkOvCfjh7+p0GtRBQ=", actual.Seals[0].Config["key"])
+	assert.Equal(t, "aes-gcm", actual.Seals[0].Config["aead_type"])
+
+	_, err = Parse(`
+controller {
+	database {
+		url = "env://BOUNDARY_TEST_PG_URL_NOT_SET"
+	}
+}
+`)
+	assert.Error(t, err)
+
+	_, err = Parse(fmt.Sprintf(`
+kms "aead" {
+	purpose = "root"
+	key = "file://%s"
+}
+`, filepath.Join(dir, "missing.key")))
+	assert.Error(t, err)
+}
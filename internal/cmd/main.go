@@ -82,6 +82,25 @@ func setupEnv(args []string) (retArgs []string, format string, outputCurlString
 	return args, format, outputCurlString
 }
 
+// csvFormatCommands are the commands which support "csv" in addition to the
+// "table" and "json" output formats.
+var csvFormatCommands = map[string]bool{
+	"reports usage": true,
+}
+
+// commandName returns the name of the command being run, made of the
+// arguments before the first flag.
+func commandName(args []string) string {
+	var name []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		name = append(name, arg)
+	}
+	return strings.Join(name, " ")
+}
+
 type RunOptions struct {
 	Stdout  io.Writer
 	Stderr  io.Writer
@@ -161,6 +180,11 @@ func RunCustom(args []string, runOpts *RunOptions) int {
 
 	switch format {
 	case "table", "json":
+	case "csv":
+		if !csvFormatCommands[commandName(args)] {
+			ui.Error(fmt.Sprintf("Invalid output format for this command: %s", format))
+			return 1
+		}
 	default:
 		ui.Error(fmt.Sprintf("Invalid output format: %s", format))
 		return 1
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+// Connector is a database/sql connector for Postgres whose URL can be changed
+// while the database is open, e.g. when the password in it is rotated. Open
+// connections are not affected by a change; new connections use the new URL.
+type Connector struct {
+	l   sync.RWMutex
+	url string
+}
+
+var _ driver.Connector = (*Connector)(nil)
+
+// NewConnector returns a Connector for the URL.
+func NewConnector(url string) (*Connector, error) {
+	if _, err := pq.NewConnector(url); err != nil {
+		return nil, fmt.Errorf("new connector: %w", err)
+	}
+	return &Connector{url: url}, nil
+}
+
+// Url returns the URL new connections are made with.
+func (c *Connector) Url() string {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	return c.url
+}
+
+// SetUrl changes the URL new connections are made with.
+func (c *Connector) SetUrl(url string) error {
+	if _, err := pq.NewConnector(url); err != nil {
+		return fmt.Errorf("set url: %w", err)
+	}
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.url = url
+	return nil
+}
+
+// Connect implements driver.Connector.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	connector, err := pq.NewConnector(c.Url())
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(ctx)
+}
+
+// Driver implements driver.Connector.
+func (c *Connector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
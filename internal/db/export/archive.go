@@ -0,0 +1,126 @@
+package export
+
+import (
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// archiveFormat identifies the format of archive files. An archive is a gzip
+// compressed JSON envelope holding the archive contents as its payload and an
+// HMAC-SHA256 signature of the exact bytes of the payload.
+const archiveFormat = "boundary-export-v1"
+
+// Archive is an export of the domain resources of a controller database.
+type Archive struct {
+	// CreateTime is when the archive was exported.
+	CreateTime time.Time `json:"create_time"`
+	// BoundaryVersion is the version of Boundary which exported the archive.
+	BoundaryVersion string `json:"boundary_version"`
+	// SchemaVersion is the database schema version of the exporting
+	// controller. Archives can only be imported into databases with the same
+	// schema version.
+	SchemaVersion int `json:"schema_version"`
+	// Tables holds the rows of each exported table, in the order they are
+	// imported.
+	Tables []*Table `json:"tables"`
+}
+
+// Table holds the rows exported from a table. Each row is the JSON encoding
+// of the domain type stored in the table.
+type Table struct {
+	Name string            `json:"name"`
+	Rows []json.RawMessage `json:"rows"`
+}
+
+// Count returns the number of rows in the archive by table name.
+func (a *Archive) Count() map[string]int {
+	counts := make(map[string]int, len(a.Tables))
+	for _, t := range a.Tables {
+		counts[t.Name] = len(t.Rows)
+	}
+	return counts
+}
+
+// envelope is the top level JSON object of an archive file.
+type envelope struct {
+	Format    string          `json:"format"`
+	KeyId     string          `json:"key_id"`
+	Payload   json.RawMessage `json:"payload"`
+	Signature []byte          `json:"signature"`
+}
+
+// WriteArchive writes the archive to w, signed with the key.
+func WriteArchive(w io.Writer, a *Archive, key *Key) error {
+	if a == nil {
+		return errors.New("write archive: missing archive")
+	}
+	if key == nil {
+		return errors.New("write archive: missing key")
+	}
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	env := &envelope{
+		Format:    archiveFormat,
+		KeyId:     key.Id(),
+		Payload:   payload,
+		Signature: sign(key, payload),
+	}
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(env); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return nil
+}
+
+// ReadArchive reads an archive from r, verifying that it was signed with the
+// key and has not been modified since.
+func ReadArchive(r io.Reader, key *Key) (*Archive, error) {
+	if key == nil {
+		return nil, errors.New("read archive: missing key")
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	defer zr.Close()
+	b, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	if env.Format != archiveFormat {
+		return nil, fmt.Errorf("read archive: unsupported archive format %q", env.Format)
+	}
+	if env.KeyId != key.Id() {
+		return nil, fmt.Errorf("read archive: %w", errKeyMismatch)
+	}
+	if !hmac.Equal(env.Signature, sign(key, env.Payload)) {
+		return nil, errors.New("read archive: signature is not valid")
+	}
+	a := new(Archive)
+	if err := json.Unmarshal(env.Payload, a); err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	return a, nil
+}
+
+func sign(key *Key, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key.signing)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
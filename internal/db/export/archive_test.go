@@ -0,0 +1,108 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(t *testing.T) *Key {
+	t.Helper()
+	b, err := GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	k, err := NewKey(b)
+	require.NoError(t, err)
+	return k
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	key := testKey(t)
+	a := &Archive{
+		CreateTime:      time.Now().UTC(),
+		BoundaryVersion: "0.1.0",
+		SchemaVersion:   71,
+		Tables: []*Table{
+			{Name: "iam_user", Rows: []json.RawMessage{[]byte(`{"public_id":"u_1234567890","scope_id":"global"}`)}},
+			{Name: "iam_group", Rows: []json.RawMessage{}},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(WriteArchive(&buf, a, key))
+
+	got, err := ReadArchive(bytes.NewReader(buf.Bytes()), key)
+	require.NoError(err)
+	assert.True(a.CreateTime.Equal(got.CreateTime))
+	assert.Equal(a.SchemaVersion, got.SchemaVersion)
+	assert.Equal(map[string]int{"iam_user": 1, "iam_group": 0}, got.Count())
+	assert.JSONEq(string(a.Tables[0].Rows[0]), string(got.Tables[0].Rows[0]))
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := ReadArchive(bytes.NewReader(buf.Bytes()), testKey(t))
+		require.Error(err)
+		assert.Contains(err.Error(), errKeyMismatch.Error())
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		zr, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+		require.NoError(err)
+		raw, err := ioutil.ReadAll(zr)
+		require.NoError(err)
+		raw = bytes.Replace(raw, []byte("u_1234567890"), []byte("u_0987654321"), 1)
+		var modified bytes.Buffer
+		zw := gzip.NewWriter(&modified)
+		_, err = zw.Write(raw)
+		require.NoError(err)
+		require.NoError(zw.Close())
+
+		_, err = ReadArchive(&modified, key)
+		assert.EqualError(err, "read archive: signature is not valid")
+	})
+}
+
+func TestKeyFile(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	dir, err := ioutil.TempDir("", "boundary-export")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "export.key")
+
+	b, err := GenerateKey(rand.Reader)
+	require.NoError(err)
+	require.NoError(WriteKeyFile(path, b))
+	assert.Error(WriteKeyFile(path, b), "existing key files are not overwritten")
+
+	fi, err := os.Stat(path)
+	require.NoError(err)
+	assert.Equal(os.FileMode(0600), fi.Mode().Perm())
+
+	k, err := ReadKeyFile(path)
+	require.NoError(err)
+	want, err := NewKey(b)
+	require.NoError(err)
+	assert.Equal(want.Id(), k.Id())
+
+	_, err = NewKey(b[:16])
+	assert.Error(err)
+}
+
+func TestTables(t *testing.T) {
+	assert := assert.New(t)
+	names := map[string]bool{}
+	for _, tbl := range tables {
+		name := tbl.name()
+		assert.False(names[name], "table %s listed more than once", name)
+		names[name] = true
+		assert.NotNil(tbl.alloc().ProtoReflect().Descriptor().Fields().ByName(tbl.idField), "table %s has no field %s", name, tbl.idField)
+		assert.NotEmpty(tbl.order, "table %s has no order", name)
+	}
+}
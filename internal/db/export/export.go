@@ -0,0 +1,102 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/version"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// exportState is the state shared by the tables while exporting.
+type exportState struct {
+	kms *kms.Kms
+	key *Key
+	// methodScopes maps the IDs of exported auth methods to their scope IDs.
+	methodScopes map[string]string
+}
+
+// Export exports the domain resources of the database to an archive: scopes
+// other than the global scope, users other than the well known users, groups,
+// roles and their grants and principals, password auth methods, accounts and
+// credentials, static host catalogs, hosts and host sets, and targets.
+// Secrets are encrypted with the key.
+func (r *Repository) Export(ctx context.Context, key *Key) (*Archive, error) {
+	if key == nil {
+		return nil, errors.New("export: missing key")
+	}
+	schemaVersion, err := r.schemaVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("export: %w", err)
+	}
+	a := &Archive{
+		CreateTime:      time.Now().UTC(),
+		BoundaryVersion: version.Get().VersionNumber(),
+		SchemaVersion:   schemaVersion,
+	}
+	s := &exportState{
+		kms:          r.kms,
+		key:          key,
+		methodScopes: map[string]string{},
+	}
+	for _, t := range tables {
+		exported, err := r.exportTable(ctx, s, t)
+		if err != nil {
+			return nil, fmt.Errorf("export: %s: %w", t.name(), err)
+		}
+		a.Tables = append(a.Tables, exported)
+	}
+	return a, nil
+}
+
+func (r *Repository) exportTable(ctx context.Context, s *exportState, t *table) (*Table, error) {
+	rows := reflect.New(reflect.SliceOf(reflect.TypeOf(t.alloc())))
+	if err := r.reader.SearchWhere(ctx, rows.Interface(), t.where, t.args, db.WithLimit(-1), db.WithOrder(t.order)); err != nil {
+		return nil, err
+	}
+	rows = rows.Elem()
+	exported := &Table{
+		Name: t.name(),
+		Rows: make([]json.RawMessage, 0, rows.Len()),
+	}
+	for i := 0; i < rows.Len(); i++ {
+		m := rows.Index(i).Interface().(resource)
+		if t.exportRow != nil {
+			if err := t.exportRow(ctx, s, m); err != nil {
+				return nil, err
+			}
+		}
+		b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		exported.Rows = append(exported.Rows, b)
+	}
+	return exported, nil
+}
+
+// schemaVersion returns the version of the database schema.
+func (r *Repository) schemaVersion(ctx context.Context) (int, error) {
+	rows, err := r.reader.Query(ctx, "select version from schema_migrations", nil)
+	if err != nil {
+		return 0, fmt.Errorf("unable to query schema version: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("unable to query schema version: %w", err)
+		}
+		return 0, errors.New("database schema version not found")
+	}
+	var v int
+	if err := rows.Scan(&v); err != nil {
+		return 0, fmt.Errorf("unable to read schema version: %w", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/auth/password"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ExportImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert, require := assert.New(t), require.New(t)
+
+	// Source database
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, prj := iam.TestScopes(t, iamRepo)
+	user := iam.TestUser(t, iamRepo, org.PublicId)
+	role := iam.TestRole(t, conn, prj.PublicId)
+	iam.TestRoleGrant(t, conn, role.PublicId, "id=*;actions=*")
+	iam.TestUserRole(t, conn, role.PublicId, user.PublicId)
+
+	pwRepo, err := password.NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	authMethod := password.TestAuthMethods(t, conn, org.PublicId, 1)[0]
+	acct, err := password.NewAccount(authMethod.PublicId, password.WithLoginName("alice"))
+	require.NoError(err)
+	acct, err = pwRepo.CreateAccount(ctx, org.PublicId, acct, password.WithPassword("correct horse"))
+	require.NoError(err)
+	_, err = iamRepo.AddUserAccounts(ctx, user.PublicId, user.Version, []string{acct.PublicId})
+	require.NoError(err)
+
+	catalog := static.TestCatalogs(t, conn, prj.PublicId, 1)[0]
+	hosts := static.TestHosts(t, conn, catalog.PublicId, 2)
+	set := static.TestSets(t, conn, catalog.PublicId, 1)[0]
+	static.TestSetMembers(t, conn, set.PublicId, hosts)
+	tgt := target.TestTcpTarget(t, conn, prj.PublicId, "test", target.WithHostSets([]string{set.PublicId}))
+	udpTgt := target.TestUdpTarget(t, conn, prj.PublicId, "test-udp", target.WithHostSets([]string{set.PublicId}))
+
+	repo, err := NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	key := testKey(t)
+	a, err := repo.Export(ctx, key)
+	require.NoError(err)
+	counts := a.Count()
+	assert.Equal(2, counts["iam_scope"])
+	assert.Equal(1, counts["iam_user"])
+	assert.Equal(1, counts["auth_account"])
+	assert.Equal(1, counts["auth_password_argon2_cred"])
+	assert.Equal(2, counts["static_host"])
+	assert.Equal(2, counts["static_host_set_member"])
+	assert.Equal(1, counts["target_tcp"])
+	assert.Equal(1, counts["target_udp"])
+	assert.Equal(2, counts["target_host_set"])
+
+	// Destination database, with its own root key
+	conn2, _ := db.TestSetup(t, "postgres")
+	rw2 := db.New(conn2)
+	wrapper2 := db.TestWrapper(t)
+	kmsCache2 := kms.TestKms(t, conn2, wrapper2)
+	iamRepo2 := iam.TestRepo(t, conn2, wrapper2)
+
+	repo2, err := NewRepository(rw2, rw2, kmsCache2)
+	require.NoError(err)
+	imported, err := repo2.Import(ctx, a, key)
+	require.NoError(err)
+	assert.Equal(counts, imported)
+
+	gotUser, gotAccounts, err := iamRepo2.LookupUser(ctx, user.PublicId)
+	require.NoError(err)
+	assert.Equal(user.Name, gotUser.Name)
+	assert.Equal([]string{acct.PublicId}, gotAccounts)
+
+	pwRepo2, err := password.NewRepository(rw2, rw2, kmsCache2)
+	require.NoError(err)
+	authed, err := pwRepo2.Authenticate(ctx, org.PublicId, authMethod.PublicId, "alice", "correct horse")
+	require.NoError(err)
+	require.NotNil(authed, "the password must still authenticate after the salt is re-encrypted")
+	assert.Equal(acct.PublicId, authed.PublicId)
+
+	targetRepo2, err := target.NewRepository(rw2, rw2, kmsCache2)
+	require.NoError(err)
+	for _, id := range []string{tgt.PublicId, udpTgt.PublicId} {
+		got, gotSets, err := targetRepo2.LookupTarget(ctx, id)
+		require.NoError(err)
+		require.NotNil(got)
+		require.Len(gotSets, 1)
+		assert.Equal(set.PublicId, gotSets[0].PublicId)
+	}
+
+	require.NoError(db.TestVerifyOplog(t, rw2, org.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE)))
+
+	// Importing again fails as the resources exist, and imports nothing.
+	_, err = repo2.Import(ctx, a, key)
+	require.Error(err)
+	assert.Contains(err.Error(), "already exists")
+}
@@ -0,0 +1,151 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// importState is the state shared by the tables while importing.
+type importState struct {
+	reader       db.Reader
+	writer       db.Writer
+	kms          *kms.Kms
+	key          *Key
+	rootWrapper  wrapping.Wrapper
+	randomReader io.Reader
+	// methodScopes maps the IDs of imported auth methods to their scope IDs.
+	methodScopes map[string]string
+}
+
+// Import imports the resources of an archive exported with the key, keeping
+// their IDs. Everything is imported in a single transaction, so either all
+// resources are imported or none are. Imported scopes get new KMS keys, and
+// secrets are encrypted with them. An oplog entry is written for every
+// imported resource. The database must have the same schema version as the
+// exporting database, and none of the resources may already exist. Returns
+// the number of rows imported by table name.
+func (r *Repository) Import(ctx context.Context, a *Archive, key *Key) (map[string]int, error) {
+	if a == nil {
+		return nil, errors.New("import: missing archive")
+	}
+	if key == nil {
+		return nil, errors.New("import: missing key")
+	}
+	schemaVersion, err := r.schemaVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+	if schemaVersion != a.SchemaVersion {
+		return nil, fmt.Errorf("import: archive has schema version %d but the database has schema version %d", a.SchemaVersion, schemaVersion)
+	}
+	byName := make(map[string]*table, len(tables))
+	for _, t := range tables {
+		byName[t.name()] = t
+	}
+	for _, exported := range a.Tables {
+		if _, ok := byName[exported.Name]; !ok {
+			return nil, fmt.Errorf("import: archive contains unknown table %q", exported.Name)
+		}
+	}
+	rootWrapper := r.kms.GetExternalWrappers().Root()
+	if rootWrapper == nil {
+		return nil, errors.New("import: missing root wrapper")
+	}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scope.Global.String(), kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, fmt.Errorf("import: unable to get oplog wrapper: %w", err)
+	}
+
+	var counts map[string]int
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			counts = make(map[string]int, len(a.Tables))
+			s := &importState{
+				reader:       reader,
+				writer:       w,
+				kms:          r.kms,
+				key:          key,
+				rootWrapper:  rootWrapper,
+				randomReader: r.randomReader,
+				methodScopes: map[string]string{},
+			}
+			// Tables are imported in the order of tables rather than the
+			// order of the archive, so references are always satisfied.
+			for _, t := range tables {
+				for _, exported := range a.Tables {
+					if exported.Name != t.name() {
+						continue
+					}
+					for _, row := range exported.Rows {
+						if err := importRow(ctx, s, t, row, oplogWrapper, a.CreateTime); err != nil {
+							return fmt.Errorf("%s: %w", t.name(), err)
+						}
+					}
+					counts[t.name()] += len(exported.Rows)
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+	return counts, nil
+}
+
+func importRow(ctx context.Context, s *importState, t *table, row []byte, oplogWrapper wrapping.Wrapper, archiveTime time.Time) error {
+	m := t.alloc()
+	if err := protojson.Unmarshal(row, m); err != nil {
+		return fmt.Errorf("unable to decode row: %w", err)
+	}
+	id := m.ProtoReflect().Get(m.ProtoReflect().Descriptor().Fields().ByName(t.idField)).String()
+	if t.beforeImport != nil {
+		if err := t.beforeImport(ctx, s, m); err != nil {
+			return err
+		}
+	}
+
+	opType := oplog.OpType_OP_TYPE_CREATE
+	if len(t.updateFields) > 0 {
+		opType = oplog.OpType_OP_TYPE_UPDATE
+	}
+	metadata := oplog.Metadata{
+		"resource-public-id":         []string{id},
+		"resource-type":              []string{t.name()},
+		"op-type":                    []string{opType.String()},
+		"import-archive-create-time": []string{archiveTime.Format(time.RFC3339Nano)},
+	}
+	if len(t.updateFields) > 0 {
+		n, err := s.writer.Update(ctx, m, t.updateFields, nil, db.WithOplog(oplogWrapper, metadata))
+		if err != nil {
+			return fmt.Errorf("unable to update %s: %w", id, err)
+		}
+		if n != 1 {
+			return fmt.Errorf("unable to update %s: %d rows updated: %w", id, n, db.ErrRecordNotFound)
+		}
+	} else {
+		if err := s.writer.Create(ctx, m, db.WithOplog(oplogWrapper, metadata)); err != nil {
+			if db.IsUniqueError(err) {
+				return fmt.Errorf("%s already exists: %w", id, err)
+			}
+			return fmt.Errorf("unable to create %s: %w", id, err)
+		}
+	}
+
+	if t.afterImport != nil {
+		if err := t.afterImport(ctx, s, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
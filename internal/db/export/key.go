@@ -0,0 +1,117 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-kms-wrapping/wrappers/aead"
+)
+
+// KeySize is the size in bytes of an export key.
+const KeySize = 32
+
+// Key is the key shared by the controllers exporting and importing an archive.
+// Archives are signed with a key derived from it and the secrets in archives,
+// such as the salts of password credentials, are encrypted with another key
+// derived from it, so that they are never written in plaintext or under the
+// KMS keys of the exporting controller.
+type Key struct {
+	id      string
+	signing []byte
+	wrapper *aead.Wrapper
+}
+
+// NewKey creates a Key from KeySize bytes of key material.
+func NewKey(b []byte) (*Key, error) {
+	if len(b) != KeySize {
+		return nil, fmt.Errorf("new export key: key must be %d bytes, got %d", KeySize, len(b))
+	}
+	root := aead.NewWrapper(nil)
+	if err := root.SetAESGCMKeyBytes(b); err != nil {
+		return nil, fmt.Errorf("new export key: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	id := hex.EncodeToString(sum[:8])
+
+	signing, err := root.NewDerivedWrapper(&aead.DerivedWrapperOptions{
+		KeyID: id,
+		Info:  []byte("boundary-export-signing"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("new export key: unable to derive signing key: %w", err)
+	}
+	wrapper, err := root.NewDerivedWrapper(&aead.DerivedWrapperOptions{
+		KeyID: id,
+		Info:  []byte("boundary-export-encryption"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("new export key: unable to derive encryption key: %w", err)
+	}
+	return &Key{
+		id:      id,
+		signing: signing.GetKeyBytes(),
+		wrapper: wrapper,
+	}, nil
+}
+
+// Id returns an identifier of the key, which is recorded in archives so that
+// importing with the wrong key can be reported as such.
+func (k *Key) Id() string {
+	return k.id
+}
+
+// GenerateKey returns KeySize bytes of new key material read from r.
+func GenerateKey(r io.Reader) ([]byte, error) {
+	b := make([]byte, KeySize)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("generate export key: %w", err)
+	}
+	return b, nil
+}
+
+// ReadKeyFile reads a Key from a file holding base64 encoded key material.
+func ReadKeyFile(path string) (*Key, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read export key file: %w", err)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("read export key file: key is not base64 encoded: %w", err)
+	}
+	k, err := NewKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("read export key file: %w", err)
+	}
+	return k, nil
+}
+
+// WriteKeyFile writes base64 encoded key material to a new file which only
+// its owner can read. It fails if the file already exists.
+func WriteKeyFile(path string, b []byte) error {
+	if len(b) != KeySize {
+		return fmt.Errorf("write export key file: key must be %d bytes, got %d", KeySize, len(b))
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("write export key file: %w", err)
+	}
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(b) + "\n"); err != nil {
+		f.Close()
+		return fmt.Errorf("write export key file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write export key file: %w", err)
+	}
+	return nil
+}
+
+// errKeyMismatch is returned when reading an archive written with another key.
+var errKeyMismatch = errors.New("archive was written with a different export key")
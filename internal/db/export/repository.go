@@ -0,0 +1,44 @@
+package export
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+)
+
+// Repository exports the domain resources of a controller database to an
+// archive and imports archives exported by other controllers.
+type Repository struct {
+	reader       db.Reader
+	writer       db.Writer
+	kms          *kms.Kms
+	randomReader io.Reader
+}
+
+// NewRepository creates a new export Repository. Supports the options:
+// WithRandomReader which sets the source of randomness used to generate the
+// keys of imported scopes.
+func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repository, error) {
+	if r == nil {
+		return nil, errors.New("error creating db repository with nil reader")
+	}
+	if w == nil {
+		return nil, errors.New("error creating db repository with nil writer")
+	}
+	if kms == nil {
+		return nil, errors.New("error creating db repository with nil kms")
+	}
+	opts := getOpts(opt...)
+	if opts.withRandomReader == nil {
+		opts.withRandomReader = rand.Reader
+	}
+	return &Repository{
+		reader:       r,
+		writer:       w,
+		kms:          kms,
+		randomReader: opts.withRandomReader,
+	}, nil
+}
@@ -0,0 +1,283 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth/password"
+	pwStore "github.com/hashicorp/boundary/internal/auth/password/store"
+	authStore "github.com/hashicorp/boundary/internal/auth/store"
+	"github.com/hashicorp/boundary/internal/host/static"
+	staticStore "github.com/hashicorp/boundary/internal/host/static/store"
+	"github.com/hashicorp/boundary/internal/iam"
+	iamStore "github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/target"
+	targetStore "github.com/hashicorp/boundary/internal/target/store"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/hashicorp/go-kms-wrapping/structwrapping"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// wellKnownUserIds are the users created by the database migrations, which
+// exist in every database and so are not exported.
+var wellKnownUserIds = []string{"u_anon", "u_auth", "u_recovery"}
+
+// resource is a domain type stored in a table.
+type resource interface {
+	oplog.ReplayableMessage
+	proto.Message
+}
+
+// table describes how the rows of a table are exported and imported. Rows are
+// read and written as the domain type stored in the table, so importing
+// writes the same oplog entries as creating the resources through their
+// repositories would.
+type table struct {
+	// alloc returns an empty domain type stored in the table.
+	alloc func() resource
+	// where and args restrict the exported rows. Since the db package ignores
+	// a where clause without arguments, a clause must have at least one.
+	where string
+	args  []interface{}
+	// order orders the exported rows, which are imported in the same order.
+	order string
+	// idField is the field holding the resource ID recorded in the oplog
+	// entries written when importing.
+	idField protoreflect.Name
+	// updateFields are set for rows which are created as a side effect of
+	// importing another table. These rows are imported by updating the
+	// fields of the existing row rather than creating it.
+	updateFields []string
+
+	// exportRow, if set, is called with each exported row before it is
+	// encoded.
+	exportRow func(ctx context.Context, s *exportState, m resource) error
+	// beforeImport, if set, is called with each imported row before it is
+	// written.
+	beforeImport func(ctx context.Context, s *importState, m resource) error
+	// afterImport, if set, is called with each imported row after it is
+	// written.
+	afterImport func(ctx context.Context, s *importState, m resource) error
+}
+
+func (t *table) name() string {
+	return t.alloc().TableName()
+}
+
+// tables lists the exported tables in the order they are imported, which
+// satisfies their references to each other.
+var tables = []*table{
+	{
+		alloc:       func() resource { return &iam.Scope{Scope: &iamStore.Scope{}} },
+		where:       "public_id <> ?",
+		args:        []interface{}{scope.Global.String()},
+		order:       "type, public_id",
+		idField:     "public_id",
+		afterImport: importScopeKeys,
+	},
+	{
+		alloc:   func() resource { return &iam.User{User: &iamStore.User{}} },
+		where:   "public_id not in (?)",
+		args:    []interface{}{wellKnownUserIds},
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:   func() resource { return &iam.Group{Group: &iamStore.Group{}} },
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:   func() resource { return &iam.GroupMemberUser{GroupMemberUser: &iamStore.GroupMemberUser{}} },
+		order:   "group_id, member_id",
+		idField: "group_id",
+	},
+	{
+		alloc:   func() resource { return &iam.Role{Role: &iamStore.Role{}} },
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:   func() resource { return &iam.RoleGrant{RoleGrant: &iamStore.RoleGrant{}} },
+		order:   "role_id, canonical_grant",
+		idField: "role_id",
+	},
+	{
+		alloc:   func() resource { return &iam.UserRole{UserRole: &iamStore.UserRole{}} },
+		order:   "role_id, principal_id",
+		idField: "role_id",
+	},
+	{
+		alloc:   func() resource { return &iam.GroupRole{GroupRole: &iamStore.GroupRole{}} },
+		order:   "role_id, principal_id",
+		idField: "role_id",
+	},
+	{
+		alloc: func() resource {
+			return &password.Argon2Configuration{Argon2Configuration: &pwStore.Argon2Configuration{}}
+		},
+		order:   "password_method_id, create_time",
+		idField: "private_id",
+	},
+	{
+		alloc:     func() resource { return &password.AuthMethod{AuthMethod: &pwStore.AuthMethod{}} },
+		order:     "public_id",
+		idField:   "public_id",
+		exportRow: exportAuthMethodScope,
+		beforeImport: func(_ context.Context, s *importState, m resource) error {
+			am := m.(*password.AuthMethod)
+			s.methodScopes[am.PublicId] = am.ScopeId
+			return nil
+		},
+	},
+	{
+		alloc:   func() resource { return &password.Account{Account: &pwStore.Account{}} },
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:        func() resource { return &authAccount{Account: &authStore.Account{}} },
+		where:        "iam_user_id <> ?",
+		args:         []interface{}{""},
+		order:        "public_id",
+		idField:      "public_id",
+		updateFields: []string{"IamUserId"},
+	},
+	{
+		alloc: func() resource {
+			return &password.Argon2Credential{Argon2Credential: &pwStore.Argon2Credential{}}
+		},
+		order:        "password_account_id, create_time",
+		idField:      "private_id",
+		exportRow:    exportCredential,
+		beforeImport: importCredential,
+	},
+	{
+		alloc:   func() resource { return &static.HostCatalog{HostCatalog: &staticStore.HostCatalog{}} },
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:   func() resource { return &static.Host{Host: &staticStore.Host{}} },
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:   func() resource { return &static.HostSet{HostSet: &staticStore.HostSet{}} },
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:   func() resource { return &static.HostSetMember{HostSetMember: &staticStore.HostSetMember{}} },
+		order:   "set_id, host_id",
+		idField: "set_id",
+	},
+	{
+		alloc:   func() resource { return &target.TcpTarget{TcpTarget: &targetStore.TcpTarget{}} },
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:   func() resource { return &target.UdpTarget{UdpTarget: &targetStore.UdpTarget{}} },
+		order:   "public_id",
+		idField: "public_id",
+	},
+	{
+		alloc:   func() resource { return &target.TargetHostSet{TargetHostSet: &targetStore.TargetHostSet{}} },
+		order:   "target_id, host_set_id",
+		idField: "target_id",
+	},
+}
+
+// authAccount is the auth_account row of an account, which records the user
+// the account is associated with. It is created when the account is created.
+type authAccount struct {
+	*authStore.Account
+	tableName string `gorm:"-"`
+}
+
+// TableName returns the table name.
+func (a *authAccount) TableName() string {
+	if a.tableName != "" {
+		return a.tableName
+	}
+	return "auth_account"
+}
+
+// SetTableName sets the table name.
+func (a *authAccount) SetTableName(n string) {
+	a.tableName = n
+}
+
+// importScopeKeys creates the KMS keys of an imported scope, encrypted with
+// the root KMS of the importing controller.
+func importScopeKeys(ctx context.Context, s *importState, m resource) error {
+	sc := m.(*iam.Scope)
+	if _, err := kms.CreateKeysTx(ctx, s.reader, s.writer, s.rootWrapper, s.randomReader, sc.PublicId); err != nil {
+		return fmt.Errorf("unable to create keys of scope %s: %w", sc.PublicId, err)
+	}
+	return nil
+}
+
+func exportAuthMethodScope(_ context.Context, s *exportState, m resource) error {
+	am := m.(*password.AuthMethod)
+	s.methodScopes[am.PublicId] = am.ScopeId
+	return nil
+}
+
+// exportCredential decrypts the salt of a password credential with the
+// database key of its scope and encrypts it with the export key.
+func exportCredential(ctx context.Context, s *exportState, m resource) error {
+	c := m.(*password.Argon2Credential)
+	scopeId, ok := s.methodScopes[c.PasswordMethodId]
+	if !ok {
+		return fmt.Errorf("credential %s: unknown auth method %s", c.PrivateId, c.PasswordMethodId)
+	}
+	wrapper, err := s.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase, kms.WithKeyId(c.KeyId))
+	if err != nil {
+		return fmt.Errorf("credential %s: unable to get database wrapper: %w", c.PrivateId, err)
+	}
+	if err := structwrapping.UnwrapStruct(ctx, wrapper, c.Argon2Credential, nil); err != nil {
+		return fmt.Errorf("credential %s: unable to decrypt: %w", c.PrivateId, err)
+	}
+	if err := structwrapping.WrapStruct(ctx, s.key.wrapper, c.Argon2Credential, nil); err != nil {
+		return fmt.Errorf("credential %s: unable to encrypt with export key: %w", c.PrivateId, err)
+	}
+	c.KeyId = s.key.Id()
+	c.Salt = nil
+	return nil
+}
+
+// importCredential decrypts the salt of a password credential with the export
+// key and encrypts it with the database key of its scope.
+func importCredential(ctx context.Context, s *importState, m resource) error {
+	c := m.(*password.Argon2Credential)
+	scopeId, ok := s.methodScopes[c.PasswordMethodId]
+	if !ok {
+		return fmt.Errorf("credential %s: unknown auth method %s", c.PrivateId, c.PasswordMethodId)
+	}
+	if c.KeyId != s.key.Id() {
+		return fmt.Errorf("credential %s: %w", c.PrivateId, errKeyMismatch)
+	}
+	// The keys of imported scopes are only visible within the transaction.
+	kmsRepo, err := kms.NewRepository(s.reader, s.writer)
+	if err != nil {
+		return fmt.Errorf("credential %s: unable to create kms repository: %w", c.PrivateId, err)
+	}
+	wrapper, err := s.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase, kms.WithRepository(kmsRepo))
+	if err != nil {
+		return fmt.Errorf("credential %s: unable to get database wrapper: %w", c.PrivateId, err)
+	}
+	if err := structwrapping.UnwrapStruct(ctx, s.key.wrapper, c.Argon2Credential, nil); err != nil {
+		return fmt.Errorf("credential %s: unable to decrypt with export key: %w", c.PrivateId, err)
+	}
+	if err := structwrapping.WrapStruct(ctx, wrapper, c.Argon2Credential, nil); err != nil {
+		return fmt.Errorf("credential %s: unable to encrypt: %w", c.PrivateId, err)
+	}
+	c.KeyId = wrapper.KeyID()
+	c.Salt = nil
+	return nil
+}
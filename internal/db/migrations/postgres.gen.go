@@ -5355,6 +5355,1213 @@ begin;
 
 commit;
 
+`),
+	},
+	"migrations/70_oplog_hash_chain.down.sql": {
+		name: "70_oplog_hash_chain.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table oplog_chain_head;
+
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data');
+
+  drop index oplog_entry_ticket_idx;
+
+  alter table oplog_entry
+    drop column ticket_name,
+    drop column ticket_version,
+    drop column prev_hash,
+    drop column hash;
+
+commit;
+
+`),
+	},
+	"migrations/70_oplog_hash_chain.up.sql": {
+		name: "70_oplog_hash_chain.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- Entries are linked into a hash chain per ticket: each entry stores the
+  -- ticket version it was written with, the hash of the entry written with
+  -- the previous version of the ticket and its own hash. Entries written
+  -- before this migration have null values and are not part of any chain.
+  alter table oplog_entry
+    add column ticket_name text,
+    add column ticket_version bigint,
+    add column prev_hash bytea,
+    add column hash bytea;
+
+  create index oplog_entry_ticket_idx
+    on oplog_entry (ticket_name, ticket_version);
+
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data',
+                                                     'ticket_name', 'ticket_version', 'prev_hash', 'hash');
+
+  -- oplog_chain_head records signed chain heads. The signature is the head's
+  -- ticket name, ticket version and hash encrypted with the global scope's
+  -- oplog key, which can only be produced by a holder of that key. Truncating
+  -- a chain below a signed head can therefore be detected.
+  create table oplog_chain_head (
+    id bigint generated always as identity primary key,
+    create_time wt_timestamp,
+    ticket_name text not null,
+    ticket_version bigint not null,
+    hash bytea not null,
+    key_id text not null,
+    signature bytea not null,
+    unique(ticket_name, ticket_version)
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_chain_head
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_chain_head
+    for each row execute procedure immutable_columns('id','create_time','ticket_name','ticket_version','hash','key_id','signature');
+
+commit;
+
+`),
+	},
+	"migrations/71_oplog_archive.down.sql": {
+		name: "71_oplog_archive.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger default_create_time_column on oplog_entry;
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_entry
+    for each row execute procedure default_create_time();
+
+  drop function oplog_entry_default_create_time;
+
+  drop index oplog_entry_aggregate_create_time_idx;
+
+  drop table oplog_archive;
+
+commit;
+
+`),
+	},
+	"migrations/71_oplog_archive.up.sql": {
+		name: "71_oplog_archive.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- oplog_archive records the archive files written by the oplog retention
+  -- job. Each archive holds entries of one aggregate, and restore_time is set
+  -- while the entries of an archive are restored into oplog_entry. Restored
+  -- entries are not archived again until they are released.
+  create table oplog_archive (
+    id bigint generated always as identity primary key,
+    create_time wt_timestamp,
+    file_name text not null unique,
+    aggregate_name text not null,
+    first_entry_id bigint not null,
+    last_entry_id bigint not null,
+    entry_count int not null,
+    restore_time timestamp with time zone,
+    constraint entry_range check(first_entry_id <= last_entry_id)
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_archive
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_archive
+    for each row execute procedure immutable_columns('id','create_time','file_name','aggregate_name','first_entry_id','last_entry_id','entry_count');
+
+  create index oplog_entry_aggregate_create_time_idx
+    on oplog_entry (aggregate_name, create_time);
+
+  -- oplog_entry_default_create_time replaces default_create_time for
+  -- oplog_entry. Restoring an archive must keep the original create_time of
+  -- its entries, so a transaction which sets boundary.oplog_restore to 'true'
+  -- may insert entries with any create_time.
+  create or replace function
+    oplog_entry_default_create_time()
+    returns trigger
+  as $$
+  begin
+    if current_setting('boundary.oplog_restore', true) = 'true' then
+      return new;
+    end if;
+    if new.create_time is distinct from now() then
+      raise warning 'create_time cannot be set to %', new.create_time;
+      new.create_time = now();
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  drop trigger default_create_time_column on oplog_entry;
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_entry
+    for each row execute procedure oplog_entry_default_create_time();
+
+commit;
+
+`),
+	},
+	"migrations/72_target_udp.down.sql": {
+		name: "72_target_udp.down.sql",
+		bytes: []byte(`
+begin;
+
+create or replace view whx_host_dimension_source as
+select -- id is the first column in the target view
+       h.public_id                     as host_id,
+       'static host'                   as host_type,
+       coalesce(h.name, 'None')        as host_name,
+       coalesce(h.description, 'None') as host_description,
+       coalesce(h.address, 'Unknown')  as host_address,
+       s.public_id                     as host_set_id,
+       'static host set'               as host_set_type,
+       coalesce(s.name, 'None')        as host_set_name,
+       coalesce(s.description, 'None') as host_set_description,
+       c.public_id                     as host_catalog_id,
+       'static host catalog'           as host_catalog_type,
+       coalesce(c.name, 'None')        as host_catalog_name,
+       coalesce(c.description, 'None') as host_catalog_description,
+       t.public_id                     as target_id,
+       'tcp target'                    as target_type,
+       coalesce(t.name, 'None')        as target_name,
+       coalesce(t.description, 'None') as target_description,
+       coalesce(t.default_port, 0)     as target_default_port_number,
+       t.session_max_seconds           as target_session_max_seconds,
+       t.session_connection_limit      as target_session_connection_limit,
+       p.public_id                     as project_id,
+       coalesce(p.name, 'None')        as project_name,
+       coalesce(p.description, 'None') as project_description,
+       o.public_id                     as host_organization_id,
+       coalesce(o.name, 'None')        as host_organization_name,
+       coalesce(o.description, 'None') as host_organization_description
+  from static_host as h,
+       static_host_catalog as c,
+       static_host_set_member as m,
+       static_host_set as s,
+       target_host_set as ts,
+       target_tcp as t,
+       iam_scope as p,
+       iam_scope as o
+ where h.catalog_id = c.public_id
+   and h.public_id = m.host_id
+   and s.public_id = m.set_id
+   and t.public_id = ts.target_id
+   and s.public_id = ts.host_set_id
+   and p.public_id = t.scope_id
+   and p.type = 'project'
+   and o.public_id = p.parent_id
+   and o.type = 'org'
+;
+
+create or replace view target_all_subtypes
+as
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'tcp' as type
+  from target_tcp;
+
+drop table target_udp;
+
+delete
+from oplog_ticket
+where name in (
+        'target_udp'
+    );
+
+commit;
+
+`),
+	},
+	"migrations/72_target_udp.up.sql": {
+		name: "72_target_udp.up.sql",
+		bytes: []byte(`
+begin;
+
+create table target_udp (
+  public_id wt_public_id primary key
+    references target(public_id)
+    on delete cascade
+    on update cascade,
+  scope_id wt_scope_id not null
+    references iam_scope(public_id)
+    on delete cascade
+    on update cascade,
+  name text not null, -- name is not optional for a target subtype
+  description text,
+  default_port int, -- default_port can be null
+  -- max duration of the session in seconds.
+  -- default is 8 hours
+  session_max_seconds int not null default 28800
+    constraint session_max_seconds_must_be_greater_than_0
+    check(session_max_seconds > 0),
+  -- limit on number of session connections allowed. -1 equals no limit
+  session_connection_limit int not null default 1
+    constraint session_connection_limit_must_be_greater_than_0_or_negative_1
+    check(session_connection_limit > 0 or session_connection_limit = -1),
+  create_time wt_timestamp,
+  update_time wt_timestamp,
+  version wt_version,
+  unique(scope_id, name) -- name must be unique within a scope
+);
+
+create trigger
+  insert_target_subtype
+before insert on target_udp
+  for each row execute procedure insert_target_subtype();
+
+create trigger
+  delete_target_subtype
+after delete on target_udp
+  for each row execute procedure delete_target_subtype();
+
+create trigger
+  immutable_columns
+before
+update on target_udp
+  for each row execute procedure immutable_columns('public_id', 'scope_id', 'create_time');
+
+create trigger
+  update_version_column
+after update on target_udp
+  for each row execute procedure update_version_column();
+
+create trigger
+  update_time_column
+before update on target_udp
+  for each row execute procedure update_time_column();
+
+create trigger
+  default_create_time_column
+before
+insert on target_udp
+  for each row execute procedure default_create_time();
+
+create trigger
+  target_scope_valid
+before insert on target_udp
+  for each row execute procedure target_scope_valid();
+
+-- target_all_subtypes is a union of all target subtypes
+create or replace view target_all_subtypes
+as
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'tcp' as type
+  from target_tcp
+union all
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'udp' as type
+  from target_udp;
+
+-- whx_host_dimension_source is replaced to include the hosts of all target
+-- subtypes.
+create or replace view whx_host_dimension_source as
+select -- id is the first column in the target view
+       h.public_id                     as host_id,
+       'static host'                   as host_type,
+       coalesce(h.name, 'None')        as host_name,
+       coalesce(h.description, 'None') as host_description,
+       coalesce(h.address, 'Unknown')  as host_address,
+       s.public_id                     as host_set_id,
+       'static host set'               as host_set_type,
+       coalesce(s.name, 'None')        as host_set_name,
+       coalesce(s.description, 'None') as host_set_description,
+       c.public_id                     as host_catalog_id,
+       'static host catalog'           as host_catalog_type,
+       coalesce(c.name, 'None')        as host_catalog_name,
+       coalesce(c.description, 'None') as host_catalog_description,
+       t.public_id                     as target_id,
+       t.type || ' target'             as target_type,
+       coalesce(t.name, 'None')        as target_name,
+       coalesce(t.description, 'None') as target_description,
+       coalesce(t.default_port, 0)     as target_default_port_number,
+       t.session_max_seconds           as target_session_max_seconds,
+       t.session_connection_limit      as target_session_connection_limit,
+       p.public_id                     as project_id,
+       coalesce(p.name, 'None')        as project_name,
+       coalesce(p.description, 'None') as project_description,
+       o.public_id                     as host_organization_id,
+       coalesce(o.name, 'None')        as host_organization_name,
+       coalesce(o.description, 'None') as host_organization_description
+  from static_host as h,
+       static_host_catalog as c,
+       static_host_set_member as m,
+       static_host_set as s,
+       target_host_set as ts,
+       target_all_subtypes as t,
+       iam_scope as p,
+       iam_scope as o
+ where h.catalog_id = c.public_id
+   and h.public_id = m.host_id
+   and s.public_id = m.set_id
+   and t.public_id = ts.target_id
+   and s.public_id = ts.host_set_id
+   and p.public_id = t.scope_id
+   and p.type = 'project'
+   and o.public_id = p.parent_id
+   and o.type = 'org'
+;
+
+insert into oplog_ticket
+  (name, version)
+values
+  ('target_udp', 1);
+
+commit;
+
+`),
+	},
+	"migrations/73_connection_egress_denied.down.sql": {
+		name: "73_connection_egress_denied.down.sql",
+		bytes: []byte(`
+begin;
+
+  update session_connection
+     set closed_reason = 'unknown'
+   where closed_reason = 'egress denied';
+
+  delete from session_connection_closed_reason_enm
+   where name = 'egress denied';
+
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed;
+
+  alter table session_connection_closed_reason_enm
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error'
+        )
+      );
+
+commit;
+
+`),
+	},
+	"migrations/73_connection_egress_denied.up.sql": {
+		name: "73_connection_egress_denied.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- Workers close the connections to endpoints their egress configuration
+  -- does not allow with the 'egress denied' reason.
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed;
+
+  alter table session_connection_closed_reason_enm
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error',
+          'egress denied'
+        )
+      );
+
+  insert into session_connection_closed_reason_enm (name)
+  values
+    ('egress denied');
+
+commit;
+
+`),
+	},
+	"migrations/74_bandwidth_limits.down.sql": {
+		name: "74_bandwidth_limits.down.sql",
+		bytes: []byte(`
+begin;
+
+alter table session_connection
+  drop column throttled_up_ms,
+  drop column throttled_down_ms;
+
+drop view session_with_state;
+
+create view session_with_state as
+select
+  s.public_id,
+  s.user_id,
+  s.host_id,
+  s.server_id,
+  s.server_type,
+  s.target_id,
+  s.host_set_id,
+  s.auth_token_id,
+  s.scope_id,
+  s.certificate,
+  s.expiration_time,
+  s.connection_limit,
+  s.tofu_token,
+  s.key_id,
+  s.termination_reason,
+  s.version,
+  s.create_time,
+  s.update_time,
+  s.endpoint,
+  ss.state,
+  ss.previous_end_time,
+  ss.start_time,
+  ss.end_time
+from
+  session s,
+  session_state ss
+where
+  s.public_id = ss.session_id;
+
+drop trigger immutable_columns on session;
+
+create trigger
+  immutable_columns
+before
+update on session
+  for each row execute procedure immutable_columns('public_id', 'certificate', 'expiration_time', 'connection_limit', 'create_time', 'endpoint');
+
+alter table session
+  drop column connection_upstream_bytes_per_second,
+  drop column connection_downstream_bytes_per_second,
+  drop column session_upstream_bytes_per_second,
+  drop column session_downstream_bytes_per_second;
+
+drop view whx_host_dimension_source;
+drop view target_all_subtypes;
+
+-- target_all_subtypes is a union of all target subtypes
+create view target_all_subtypes
+as
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'tcp' as type
+  from target_tcp
+union all
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'udp' as type
+  from target_udp;
+
+-- whx_host_dimension_source is replaced to include the hosts of all target
+-- subtypes.
+create view whx_host_dimension_source as
+select -- id is the first column in the target view
+       h.public_id                     as host_id,
+       'static host'                   as host_type,
+       coalesce(h.name, 'None')        as host_name,
+       coalesce(h.description, 'None') as host_description,
+       coalesce(h.address, 'Unknown')  as host_address,
+       s.public_id                     as host_set_id,
+       'static host set'               as host_set_type,
+       coalesce(s.name, 'None')        as host_set_name,
+       coalesce(s.description, 'None') as host_set_description,
+       c.public_id                     as host_catalog_id,
+       'static host catalog'           as host_catalog_type,
+       coalesce(c.name, 'None')        as host_catalog_name,
+       coalesce(c.description, 'None') as host_catalog_description,
+       t.public_id                     as target_id,
+       t.type || ' target'             as target_type,
+       coalesce(t.name, 'None')        as target_name,
+       coalesce(t.description, 'None') as target_description,
+       coalesce(t.default_port, 0)     as target_default_port_number,
+       t.session_max_seconds           as target_session_max_seconds,
+       t.session_connection_limit      as target_session_connection_limit,
+       p.public_id                     as project_id,
+       coalesce(p.name, 'None')        as project_name,
+       coalesce(p.description, 'None') as project_description,
+       o.public_id                     as host_organization_id,
+       coalesce(o.name, 'None')        as host_organization_name,
+       coalesce(o.description, 'None') as host_organization_description
+  from static_host as h,
+       static_host_catalog as c,
+       static_host_set_member as m,
+       static_host_set as s,
+       target_host_set as ts,
+       target_all_subtypes as t,
+       iam_scope as p,
+       iam_scope as o
+ where h.catalog_id = c.public_id
+   and h.public_id = m.host_id
+   and s.public_id = m.set_id
+   and t.public_id = ts.target_id
+   and s.public_id = ts.host_set_id
+   and p.public_id = t.scope_id
+   and p.type = 'project'
+   and o.public_id = p.parent_id
+   and o.type = 'org'
+;
+
+alter table target_tcp
+  drop column connection_upstream_bytes_per_second,
+  drop column connection_downstream_bytes_per_second,
+  drop column session_upstream_bytes_per_second,
+  drop column session_downstream_bytes_per_second;
+
+alter table target_udp
+  drop column connection_upstream_bytes_per_second,
+  drop column connection_downstream_bytes_per_second,
+  drop column session_upstream_bytes_per_second,
+  drop column session_downstream_bytes_per_second;
+
+commit;
+
+`),
+	},
+	"migrations/74_bandwidth_limits.up.sql": {
+		name: "74_bandwidth_limits.up.sql",
+		bytes: []byte(`
+begin;
+
+-- Bandwidth limits are in bytes per second, where 0 equals no limit. Upstream
+-- is the traffic from the client to the endpoint and downstream the traffic
+-- from the endpoint to the client. Connection limits apply to each connection
+-- of a session and session limits to all of them together.
+alter table target_tcp
+  add column connection_upstream_bytes_per_second bigint not null default 0
+    constraint connection_upstream_bytes_per_second_must_be_0_or_greater
+    check(connection_upstream_bytes_per_second >= 0),
+  add column connection_downstream_bytes_per_second bigint not null default 0
+    constraint connection_downstream_bytes_per_second_must_be_0_or_greater
+    check(connection_downstream_bytes_per_second >= 0),
+  add column session_upstream_bytes_per_second bigint not null default 0
+    constraint session_upstream_bytes_per_second_must_be_0_or_greater
+    check(session_upstream_bytes_per_second >= 0),
+  add column session_downstream_bytes_per_second bigint not null default 0
+    constraint session_downstream_bytes_per_second_must_be_0_or_greater
+    check(session_downstream_bytes_per_second >= 0);
+
+alter table target_udp
+  add column connection_upstream_bytes_per_second bigint not null default 0
+    constraint connection_upstream_bytes_per_second_must_be_0_or_greater
+    check(connection_upstream_bytes_per_second >= 0),
+  add column connection_downstream_bytes_per_second bigint not null default 0
+    constraint connection_downstream_bytes_per_second_must_be_0_or_greater
+    check(connection_downstream_bytes_per_second >= 0),
+  add column session_upstream_bytes_per_second bigint not null default 0
+    constraint session_upstream_bytes_per_second_must_be_0_or_greater
+    check(session_upstream_bytes_per_second >= 0),
+  add column session_downstream_bytes_per_second bigint not null default 0
+    constraint session_downstream_bytes_per_second_must_be_0_or_greater
+    check(session_downstream_bytes_per_second >= 0);
+
+-- target_all_subtypes is a union of all target subtypes
+create or replace view target_all_subtypes
+as
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'tcp' as type,
+  connection_upstream_bytes_per_second,
+  connection_downstream_bytes_per_second,
+  session_upstream_bytes_per_second,
+  session_downstream_bytes_per_second
+  from target_tcp
+union all
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'udp' as type,
+  connection_upstream_bytes_per_second,
+  connection_downstream_bytes_per_second,
+  session_upstream_bytes_per_second,
+  session_downstream_bytes_per_second
+  from target_udp;
+
+-- sessions keep the bandwidth limits of their target at the time they were
+-- created, for the worker to enforce.
+alter table session
+  add column connection_upstream_bytes_per_second bigint not null default 0
+    constraint connection_upstream_bytes_per_second_must_be_0_or_greater
+    check(connection_upstream_bytes_per_second >= 0),
+  add column connection_downstream_bytes_per_second bigint not null default 0
+    constraint connection_downstream_bytes_per_second_must_be_0_or_greater
+    check(connection_downstream_bytes_per_second >= 0),
+  add column session_upstream_bytes_per_second bigint not null default 0
+    constraint session_upstream_bytes_per_second_must_be_0_or_greater
+    check(session_upstream_bytes_per_second >= 0),
+  add column session_downstream_bytes_per_second bigint not null default 0
+    constraint session_downstream_bytes_per_second_must_be_0_or_greater
+    check(session_downstream_bytes_per_second >= 0);
+
+drop trigger immutable_columns on session;
+
+create trigger
+  immutable_columns
+before
+update on session
+  for each row execute procedure immutable_columns('public_id', 'certificate', 'expiration_time', 'connection_limit', 'create_time', 'endpoint',
+    'connection_upstream_bytes_per_second', 'connection_downstream_bytes_per_second', 'session_upstream_bytes_per_second', 'session_downstream_bytes_per_second');
+
+create or replace view session_with_state as
+select
+  s.public_id,
+  s.user_id,
+  s.host_id,
+  s.server_id,
+  s.server_type,
+  s.target_id,
+  s.host_set_id,
+  s.auth_token_id,
+  s.scope_id,
+  s.certificate,
+  s.expiration_time,
+  s.connection_limit,
+  s.tofu_token,
+  s.key_id,
+  s.termination_reason,
+  s.version,
+  s.create_time,
+  s.update_time,
+  s.endpoint,
+  ss.state,
+  ss.previous_end_time,
+  ss.start_time,
+  ss.end_time,
+  s.connection_upstream_bytes_per_second,
+  s.connection_downstream_bytes_per_second,
+  s.session_upstream_bytes_per_second,
+  s.session_downstream_bytes_per_second
+from
+  session s,
+  session_state ss
+where
+  s.public_id = ss.session_id;
+
+-- the time in milliseconds the worker held back the traffic of the
+-- connection in each direction to keep to the bandwidth limits
+alter table session_connection
+  add column throttled_up_ms bigint -- can be null
+    constraint throttled_up_ms_must_be_null_or_a_non_negative_number
+    check (
+      throttled_up_ms is null
+      or
+      throttled_up_ms >= 0
+    ),
+  add column throttled_down_ms bigint -- can be null
+    constraint throttled_down_ms_must_be_null_or_a_non_negative_number
+    check (
+      throttled_down_ms is null
+      or
+      throttled_down_ms >= 0
+    );
+
+commit;
+
+`),
+	},
+	"migrations/75_worker_operational_state.down.sql": {
+		name: "75_worker_operational_state.down.sql",
+		bytes: []byte(`
+begin;
+
+  update session_connection
+     set closed_reason = 'unknown'
+   where closed_reason = 'worker drained';
+
+  delete from session_connection_closed_reason_enm
+   where name = 'worker drained';
+
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed;
+
+  alter table session_connection_closed_reason_enm
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error',
+          'egress denied'
+        )
+      );
+
+  alter table server
+    drop column active_connection_count,
+    drop column active_session_count,
+    drop column drain_deadline,
+    drop column operational_state;
+
+  drop table server_operational_state_enm;
+
+commit;
+
+`),
+	},
+	"migrations/75_worker_operational_state.up.sql": {
+		name: "75_worker_operational_state.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- A worker is active, draining or disabled. Only active workers are given
+  -- new sessions; a draining worker lets the connections of the sessions it
+  -- already proxies finish, until its drain deadline if it has one, and a
+  -- disabled worker proxies no connections.
+  create table server_operational_state_enm (
+    name text primary key
+      constraint only_predefined_server_operational_states_allowed
+      check (
+        name in (
+          'active',
+          'draining',
+          'disabled'
+        )
+      )
+  );
+
+  insert into server_operational_state_enm (name)
+  values
+    ('active'),
+    ('draining'),
+    ('disabled');
+
+  -- The operational state is set through the API or by the worker when it is
+  -- signaled. The session and connection counts are reported by the worker
+  -- with its status, which shows the progress of a drain.
+  alter table server
+    add column operational_state text not null default 'active'
+      references server_operational_state_enm (name)
+      on delete restrict
+      on update cascade,
+    add column drain_deadline timestamp with time zone,
+    add column active_session_count integer not null default 0
+      constraint active_session_count_must_not_be_negative
+      check (active_session_count >= 0),
+    add column active_connection_count integer not null default 0
+      constraint active_connection_count_must_not_be_negative
+      check (active_connection_count >= 0);
+
+  -- Workers close the connections left when their drain deadline passes, or
+  -- when they are disabled, with the 'worker drained' reason.
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed;
+
+  alter table session_connection_closed_reason_enm
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error',
+          'egress denied',
+          'worker drained'
+        )
+      );
+
+  insert into session_connection_closed_reason_enm (name)
+  values
+    ('worker drained');
+
+commit;
+
+`),
+	},
+	"migrations/76_server_tags.down.sql": {
+		name: "76_server_tags.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table server_tag;
+
+  alter table server
+    drop column release_version;
+
+commit;
+
+`),
+	},
+	"migrations/76_server_tags.up.sql": {
+		name: "76_server_tags.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- The release version of the Boundary binary a server runs, as reported
+  -- with its status.
+  alter table server
+    add column release_version text;
+
+  -- Tags describe a worker, as keys with any number of values, and are set in
+  -- its configuration. They are replaced when the worker reports them with
+  -- its first status after starting.
+  create table server_tag (
+    server_id text,
+    server_type text,
+    key text not null
+      constraint server_tag_key_must_not_be_empty
+      check(length(trim(key)) > 0),
+    value text not null
+      constraint server_tag_value_must_not_be_empty
+      check(length(trim(value)) > 0),
+    primary key (server_id, server_type, key, value),
+    foreign key (server_id, server_type)
+      references server (private_id, type)
+      on delete cascade
+      on update cascade
+  );
+
+commit;
+
+`),
+	},
+	"migrations/77_job.down.sql": {
+		name: "77_job.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop table job_run;
+  drop table job_run_status_enm;
+  drop table job;
+
+commit;
+
+`),
+	},
+	"migrations/77_job.up.sql": {
+		name: "77_job.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- A job is background work, such as cleaning up expired data, which the
+  -- controllers run periodically. Every controller schedules every job, and a
+  -- controller runs a job after taking its lease: the lease can only be taken
+  -- when the job is due and no other controller holds an unexpired lease on
+  -- it, so each run of the job happens on a single controller.
+  create table job (
+    name text primary key
+      constraint name_must_not_be_empty
+      check (length(trim(name)) > 0),
+    description text not null,
+    next_scheduled_run timestamp with time zone not null default current_timestamp,
+    -- The private id of the controller holding the lease, if any.
+    lease_owner text,
+    lease_expiration timestamp with time zone,
+    create_time wt_timestamp,
+    constraint lease_owner_and_expiration_must_be_set_together
+      check ((lease_owner is null) = (lease_expiration is null))
+  );
+
+  -- A run is running until the controller running it records its end. A run
+  -- whose controller lost its lease without recording an end, e.g. because it
+  -- was stopped, is marked interrupted when the job is next run.
+  create table job_run_status_enm (
+    name text primary key
+      constraint only_predefined_job_run_statuses_allowed
+      check (
+        name in (
+          'running',
+          'completed',
+          'failed',
+          'interrupted'
+        )
+      )
+  );
+
+  insert into job_run_status_enm (name)
+  values
+    ('running'),
+    ('completed'),
+    ('failed'),
+    ('interrupted');
+
+  create table job_run (
+    private_id text primary key,
+    job_name text not null
+      references job (name)
+      on delete cascade
+      on update cascade,
+    -- The private id of the controller which ran the job.
+    server_id text not null,
+    status text not null default 'running'
+      references job_run_status_enm (name)
+      on delete restrict
+      on update cascade,
+    start_time timestamp with time zone not null default current_timestamp,
+    end_time timestamp with time zone,
+    error text,
+    constraint end_time_must_not_be_before_start_time
+      check (end_time is null or end_time >= start_time)
+  );
+
+  create index job_run_job_name_start_time_ix
+    on job_run (job_name, start_time desc);
+
+commit;
+
+`),
+	},
+	"migrations/78_auth_token_rewrap.down.sql": {
+		name: "78_auth_token_rewrap.down.sql",
+		bytes: []byte(`
+begin;
+
+  create or replace function
+    immutable_auth_token_columns()
+    returns trigger
+  as $$
+  begin
+    if new.auth_account_id is distinct from old.auth_account_id then
+      raise exception 'auth_account_id is read-only';
+    end if;
+    if new.token is distinct from old.token then
+      raise exception 'token is read-only';
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+commit;
+
+`),
+	},
+	"migrations/78_auth_token_rewrap.up.sql": {
+		name: "78_auth_token_rewrap.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- The token of an auth token may now be changed when its key_id changes as
+  -- well, which happens when the token is rewrapped with a new version of the
+  -- scope's database key. The plaintext token is unchanged by rewrapping.
+  create or replace function
+    immutable_auth_token_columns()
+    returns trigger
+  as $$
+  begin
+    if new.auth_account_id is distinct from old.auth_account_id then
+      raise exception 'auth_account_id is read-only';
+    end if;
+    if new.token is distinct from old.token and new.key_id is not distinct from old.key_id then
+      raise exception 'token is read-only';
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+commit;
+
+`),
+	},
+	"migrations/79_oplog_rewrap.down.sql": {
+		name: "79_oplog_rewrap.down.sql",
+		bytes: []byte(`
+begin;
+
+  drop trigger oplog_rewrap_columns on oplog_chain_head;
+  drop trigger immutable_columns on oplog_chain_head;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_chain_head
+    for each row execute procedure immutable_columns('id','create_time','ticket_name','ticket_version','hash','key_id','signature');
+
+  drop trigger oplog_rewrap_columns on oplog_entry;
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data',
+                                                     'ticket_name', 'ticket_version', 'prev_hash', 'hash');
+
+  drop function oplog_rewrap_columns;
+
+  drop index oplog_entry_key_id_idx;
+
+  alter table oplog_entry
+    drop column key_id;
+
+commit;
+
+`),
+	},
+	"migrations/79_oplog_rewrap.up.sql": {
+		name: "79_oplog_rewrap.up.sql",
+		bytes: []byte(`
+begin;
+
+  -- key_id is the id of the oplog key version the data of an entry is
+  -- encrypted with. Entries written before this migration have a null key_id
+  -- until the key rewrap job sets it from their data.
+  alter table oplog_entry
+    add column key_id text;
+
+  create index oplog_entry_key_id_idx
+    on oplog_entry (key_id);
+
+  -- oplog_rewrap_columns() makes the columns passed as parameters immutable,
+  -- except in a transaction which sets boundary.oplog_rewrap to 'true'. The
+  -- key rewrap job re-encrypts the data of entries with the current oplog key
+  -- versions, which changes their hashes and the hashes of the entries
+  -- chained after them, and re-signs chain heads with the current oplog key
+  -- of the global scope.
+  create or replace function
+    oplog_rewrap_columns()
+    returns trigger
+  as $$
+  declare
+    col_name text;
+    new_value text;
+    old_value text;
+  begin
+    if current_setting('boundary.oplog_rewrap', true) = 'true' then
+      return new;
+    end if;
+    foreach col_name in array tg_argv loop
+      execute format('SELECT $1.%I', col_name) into new_value using new;
+      execute format('SELECT $1.%I', col_name) into old_value using old;
+      if new_value is distinct from old_value then
+        raise exception 'immutable column: %.%', tg_table_name, col_name using
+          errcode = '23601',
+          schema = tg_table_schema,
+          table = tg_table_name,
+          column = col_name;
+      end if;
+    end loop;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    oplog_rewrap_columns()
+  is
+    'function used in before update triggers to make columns immutable outside of oplog key rewrapping';
+
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name',
+                                                     'ticket_name', 'ticket_version');
+
+  create trigger
+    oplog_rewrap_columns
+  before
+  update on oplog_entry
+    for each row execute procedure oplog_rewrap_columns('data', 'prev_hash', 'hash', 'key_id');
+
+  drop trigger immutable_columns on oplog_chain_head;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_chain_head
+    for each row execute procedure immutable_columns('id','create_time','ticket_name','ticket_version');
+
+  create trigger
+    oplog_rewrap_columns
+  before
+  update on oplog_chain_head
+    for each row execute procedure oplog_rewrap_columns('hash', 'key_id', 'signature');
+
+commit;
+
 `),
 	},
 }
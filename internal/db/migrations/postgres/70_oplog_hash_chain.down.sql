@@ -0,0 +1,21 @@
+begin;
+
+  drop table oplog_chain_head;
+
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data');
+
+  drop index oplog_entry_ticket_idx;
+
+  alter table oplog_entry
+    drop column ticket_name,
+    drop column ticket_version,
+    drop column prev_hash,
+    drop column hash;
+
+commit;
@@ -0,0 +1,52 @@
+begin;
+
+  -- Entries are linked into a hash chain per ticket: each entry stores the
+  -- ticket version it was written with, the hash of the entry written with
+  -- the previous version of the ticket and its own hash. Entries written
+  -- before this migration have null values and are not part of any chain.
+  alter table oplog_entry
+    add column ticket_name text,
+    add column ticket_version bigint,
+    add column prev_hash bytea,
+    add column hash bytea;
+
+  create index oplog_entry_ticket_idx
+    on oplog_entry (ticket_name, ticket_version);
+
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data',
+                                                     'ticket_name', 'ticket_version', 'prev_hash', 'hash');
+
+  -- oplog_chain_head records signed chain heads. The signature is the head's
+  -- ticket name, ticket version and hash encrypted with the global scope's
+  -- oplog key, which can only be produced by a holder of that key. Truncating
+  -- a chain below a signed head can therefore be detected.
+  create table oplog_chain_head (
+    id bigint generated always as identity primary key,
+    create_time wt_timestamp,
+    ticket_name text not null,
+    ticket_version bigint not null,
+    hash bytea not null,
+    key_id text not null,
+    signature bytea not null,
+    unique(ticket_name, ticket_version)
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_chain_head
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_chain_head
+    for each row execute procedure immutable_columns('id','create_time','ticket_name','ticket_version','hash','key_id','signature');
+
+commit;
@@ -0,0 +1,17 @@
+begin;
+
+  drop trigger default_create_time_column on oplog_entry;
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_entry
+    for each row execute procedure default_create_time();
+
+  drop function oplog_entry_default_create_time;
+
+  drop index oplog_entry_aggregate_create_time_idx;
+
+  drop table oplog_archive;
+
+commit;
@@ -0,0 +1,62 @@
+begin;
+
+  -- oplog_archive records the archive files written by the oplog retention
+  -- job. Each archive holds entries of one aggregate, and restore_time is set
+  -- while the entries of an archive are restored into oplog_entry. Restored
+  -- entries are not archived again until they are released.
+  create table oplog_archive (
+    id bigint generated always as identity primary key,
+    create_time wt_timestamp,
+    file_name text not null unique,
+    aggregate_name text not null,
+    first_entry_id bigint not null,
+    last_entry_id bigint not null,
+    entry_count int not null,
+    restore_time timestamp with time zone,
+    constraint entry_range check(first_entry_id <= last_entry_id)
+  );
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_archive
+    for each row execute procedure default_create_time();
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_archive
+    for each row execute procedure immutable_columns('id','create_time','file_name','aggregate_name','first_entry_id','last_entry_id','entry_count');
+
+  create index oplog_entry_aggregate_create_time_idx
+    on oplog_entry (aggregate_name, create_time);
+
+  -- oplog_entry_default_create_time replaces default_create_time for
+  -- oplog_entry. Restoring an archive must keep the original create_time of
+  -- its entries, so a transaction which sets boundary.oplog_restore to 'true'
+  -- may insert entries with any create_time.
+  create or replace function
+    oplog_entry_default_create_time()
+    returns trigger
+  as $$
+  begin
+    if current_setting('boundary.oplog_restore', true) = 'true' then
+      return new;
+    end if;
+    if new.create_time is distinct from now() then
+      raise warning 'create_time cannot be set to %', new.create_time;
+      new.create_time = now();
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  drop trigger default_create_time_column on oplog_entry;
+
+  create trigger
+    default_create_time_column
+  before
+  insert on oplog_entry
+    for each row execute procedure oplog_entry_default_create_time();
+
+commit;
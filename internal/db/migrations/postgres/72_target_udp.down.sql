@@ -0,0 +1,74 @@
+begin;
+
+create or replace view whx_host_dimension_source as
+select -- id is the first column in the target view
+       h.public_id                     as host_id,
+       'static host'                   as host_type,
+       coalesce(h.name, 'None')        as host_name,
+       coalesce(h.description, 'None') as host_description,
+       coalesce(h.address, 'Unknown')  as host_address,
+       s.public_id                     as host_set_id,
+       'static host set'               as host_set_type,
+       coalesce(s.name, 'None')        as host_set_name,
+       coalesce(s.description, 'None') as host_set_description,
+       c.public_id                     as host_catalog_id,
+       'static host catalog'           as host_catalog_type,
+       coalesce(c.name, 'None')        as host_catalog_name,
+       coalesce(c.description, 'None') as host_catalog_description,
+       t.public_id                     as target_id,
+       'tcp target'                    as target_type,
+       coalesce(t.name, 'None')        as target_name,
+       coalesce(t.description, 'None') as target_description,
+       coalesce(t.default_port, 0)     as target_default_port_number,
+       t.session_max_seconds           as target_session_max_seconds,
+       t.session_connection_limit      as target_session_connection_limit,
+       p.public_id                     as project_id,
+       coalesce(p.name, 'None')        as project_name,
+       coalesce(p.description, 'None') as project_description,
+       o.public_id                     as host_organization_id,
+       coalesce(o.name, 'None')        as host_organization_name,
+       coalesce(o.description, 'None') as host_organization_description
+  from static_host as h,
+       static_host_catalog as c,
+       static_host_set_member as m,
+       static_host_set as s,
+       target_host_set as ts,
+       target_tcp as t,
+       iam_scope as p,
+       iam_scope as o
+ where h.catalog_id = c.public_id
+   and h.public_id = m.host_id
+   and s.public_id = m.set_id
+   and t.public_id = ts.target_id
+   and s.public_id = ts.host_set_id
+   and p.public_id = t.scope_id
+   and p.type = 'project'
+   and o.public_id = p.parent_id
+   and o.type = 'org'
+;
+
+create or replace view target_all_subtypes
+as
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'tcp' as type
+  from target_tcp;
+
+drop table target_udp;
+
+delete
+from oplog_ticket
+where name in (
+        'target_udp'
+    );
+
+commit;
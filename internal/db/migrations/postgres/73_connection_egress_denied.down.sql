@@ -0,0 +1,26 @@
+begin;
+
+  update session_connection
+     set closed_reason = 'unknown'
+   where closed_reason = 'egress denied';
+
+  delete from session_connection_closed_reason_enm
+   where name = 'egress denied';
+
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed;
+
+  alter table session_connection_closed_reason_enm
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error'
+        )
+      );
+
+commit;
@@ -0,0 +1,26 @@
+begin;
+
+  -- Workers close the connections to endpoints their egress configuration
+  -- does not allow with the 'egress denied' reason.
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed;
+
+  alter table session_connection_closed_reason_enm
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error',
+          'egress denied'
+        )
+      );
+
+  insert into session_connection_closed_reason_enm (name)
+  values
+    ('egress denied');
+
+commit;
@@ -0,0 +1,152 @@
+begin;
+
+-- Bandwidth limits are in bytes per second, where 0 equals no limit. Upstream
+-- is the traffic from the client to the endpoint and downstream the traffic
+-- from the endpoint to the client. Connection limits apply to each connection
+-- of a session and session limits to all of them together.
+alter table target_tcp
+  add column connection_upstream_bytes_per_second bigint not null default 0
+    constraint connection_upstream_bytes_per_second_must_be_0_or_greater
+    check(connection_upstream_bytes_per_second >= 0),
+  add column connection_downstream_bytes_per_second bigint not null default 0
+    constraint connection_downstream_bytes_per_second_must_be_0_or_greater
+    check(connection_downstream_bytes_per_second >= 0),
+  add column session_upstream_bytes_per_second bigint not null default 0
+    constraint session_upstream_bytes_per_second_must_be_0_or_greater
+    check(session_upstream_bytes_per_second >= 0),
+  add column session_downstream_bytes_per_second bigint not null default 0
+    constraint session_downstream_bytes_per_second_must_be_0_or_greater
+    check(session_downstream_bytes_per_second >= 0);
+
+alter table target_udp
+  add column connection_upstream_bytes_per_second bigint not null default 0
+    constraint connection_upstream_bytes_per_second_must_be_0_or_greater
+    check(connection_upstream_bytes_per_second >= 0),
+  add column connection_downstream_bytes_per_second bigint not null default 0
+    constraint connection_downstream_bytes_per_second_must_be_0_or_greater
+    check(connection_downstream_bytes_per_second >= 0),
+  add column session_upstream_bytes_per_second bigint not null default 0
+    constraint session_upstream_bytes_per_second_must_be_0_or_greater
+    check(session_upstream_bytes_per_second >= 0),
+  add column session_downstream_bytes_per_second bigint not null default 0
+    constraint session_downstream_bytes_per_second_must_be_0_or_greater
+    check(session_downstream_bytes_per_second >= 0);
+
+-- target_all_subtypes is a union of all target subtypes
+create or replace view target_all_subtypes
+as
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'tcp' as type,
+  connection_upstream_bytes_per_second,
+  connection_downstream_bytes_per_second,
+  session_upstream_bytes_per_second,
+  session_downstream_bytes_per_second
+  from target_tcp
+union all
+select
+  public_id,
+  scope_id,
+  name,
+  description,
+  default_port,
+  session_max_seconds,
+  session_connection_limit,
+  version,
+  create_time,
+  update_time,
+  'udp' as type,
+  connection_upstream_bytes_per_second,
+  connection_downstream_bytes_per_second,
+  session_upstream_bytes_per_second,
+  session_downstream_bytes_per_second
+  from target_udp;
+
+-- sessions keep the bandwidth limits of their target at the time they were
+-- created, for the worker to enforce.
+alter table session
+  add column connection_upstream_bytes_per_second bigint not null default 0
+    constraint connection_upstream_bytes_per_second_must_be_0_or_greater
+    check(connection_upstream_bytes_per_second >= 0),
+  add column connection_downstream_bytes_per_second bigint not null default 0
+    constraint connection_downstream_bytes_per_second_must_be_0_or_greater
+    check(connection_downstream_bytes_per_second >= 0),
+  add column session_upstream_bytes_per_second bigint not null default 0
+    constraint session_upstream_bytes_per_second_must_be_0_or_greater
+    check(session_upstream_bytes_per_second >= 0),
+  add column session_downstream_bytes_per_second bigint not null default 0
+    constraint session_downstream_bytes_per_second_must_be_0_or_greater
+    check(session_downstream_bytes_per_second >= 0);
+
+drop trigger immutable_columns on session;
+
+create trigger
+  immutable_columns
+before
+update on session
+  for each row execute procedure immutable_columns('public_id', 'certificate', 'expiration_time', 'connection_limit', 'create_time', 'endpoint',
+    'connection_upstream_bytes_per_second', 'connection_downstream_bytes_per_second', 'session_upstream_bytes_per_second', 'session_downstream_bytes_per_second');
+
+create or replace view session_with_state as
+select
+  s.public_id,
+  s.user_id,
+  s.host_id,
+  s.server_id,
+  s.server_type,
+  s.target_id,
+  s.host_set_id,
+  s.auth_token_id,
+  s.scope_id,
+  s.certificate,
+  s.expiration_time,
+  s.connection_limit,
+  s.tofu_token,
+  s.key_id,
+  s.termination_reason,
+  s.version,
+  s.create_time,
+  s.update_time,
+  s.endpoint,
+  ss.state,
+  ss.previous_end_time,
+  ss.start_time,
+  ss.end_time,
+  s.connection_upstream_bytes_per_second,
+  s.connection_downstream_bytes_per_second,
+  s.session_upstream_bytes_per_second,
+  s.session_downstream_bytes_per_second
+from
+  session s,
+  session_state ss
+where
+  s.public_id = ss.session_id;
+
+-- the time in milliseconds the worker held back the traffic of the
+-- connection in each direction to keep to the bandwidth limits
+alter table session_connection
+  add column throttled_up_ms bigint -- can be null
+    constraint throttled_up_ms_must_be_null_or_a_non_negative_number
+    check (
+      throttled_up_ms is null
+      or
+      throttled_up_ms >= 0
+    ),
+  add column throttled_down_ms bigint -- can be null
+    constraint throttled_down_ms_must_be_null_or_a_non_negative_number
+    check (
+      throttled_down_ms is null
+      or
+      throttled_down_ms >= 0
+    );
+
+commit;
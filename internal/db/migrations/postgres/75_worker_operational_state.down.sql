@@ -0,0 +1,35 @@
+begin;
+
+  update session_connection
+     set closed_reason = 'unknown'
+   where closed_reason = 'worker drained';
+
+  delete from session_connection_closed_reason_enm
+   where name = 'worker drained';
+
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed;
+
+  alter table session_connection_closed_reason_enm
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error',
+          'egress denied'
+        )
+      );
+
+  alter table server
+    drop column active_connection_count,
+    drop column active_session_count,
+    drop column drain_deadline,
+    drop column operational_state;
+
+  drop table server_operational_state_enm;
+
+commit;
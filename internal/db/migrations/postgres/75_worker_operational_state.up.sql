@@ -0,0 +1,65 @@
+begin;
+
+  -- A worker is active, draining or disabled. Only active workers are given
+  -- new sessions; a draining worker lets the connections of the sessions it
+  -- already proxies finish, until its drain deadline if it has one, and a
+  -- disabled worker proxies no connections.
+  create table server_operational_state_enm (
+    name text primary key
+      constraint only_predefined_server_operational_states_allowed
+      check (
+        name in (
+          'active',
+          'draining',
+          'disabled'
+        )
+      )
+  );
+
+  insert into server_operational_state_enm (name)
+  values
+    ('active'),
+    ('draining'),
+    ('disabled');
+
+  -- The operational state is set through the API or by the worker when it is
+  -- signaled. The session and connection counts are reported by the worker
+  -- with its status, which shows the progress of a drain.
+  alter table server
+    add column operational_state text not null default 'active'
+      references server_operational_state_enm (name)
+      on delete restrict
+      on update cascade,
+    add column drain_deadline timestamp with time zone,
+    add column active_session_count integer not null default 0
+      constraint active_session_count_must_not_be_negative
+      check (active_session_count >= 0),
+    add column active_connection_count integer not null default 0
+      constraint active_connection_count_must_not_be_negative
+      check (active_connection_count >= 0);
+
+  -- Workers close the connections left when their drain deadline passes, or
+  -- when they are disabled, with the 'worker drained' reason.
+  alter table session_connection_closed_reason_enm
+    drop constraint only_predefined_session_connection_closed_reasons_allowed;
+
+  alter table session_connection_closed_reason_enm
+    add constraint only_predefined_session_connection_closed_reasons_allowed
+      check (
+        name in (
+          'unknown',
+          'timed out',
+          'closed by end-user',
+          'canceled',
+          'network error',
+          'system error',
+          'egress denied',
+          'worker drained'
+        )
+      );
+
+  insert into session_connection_closed_reason_enm (name)
+  values
+    ('worker drained');
+
+commit;
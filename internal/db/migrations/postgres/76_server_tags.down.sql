@@ -0,0 +1,8 @@
+begin;
+
+  drop table server_tag;
+
+  alter table server
+    drop column release_version;
+
+commit;
@@ -0,0 +1,27 @@
+begin;
+
+  -- The release version of the Boundary binary a server runs, as reported
+  -- with its status.
+  alter table server
+    add column release_version text;
+
+  -- Tags describe a worker, as keys with any number of values, and are set in
+  -- its configuration. They are replaced when the worker reports them with
+  -- its first status after starting.
+  create table server_tag (
+    server_id text,
+    server_type text,
+    key text not null
+      constraint server_tag_key_must_not_be_empty
+      check(length(trim(key)) > 0),
+    value text not null
+      constraint server_tag_value_must_not_be_empty
+      check(length(trim(value)) > 0),
+    primary key (server_id, server_type, key, value),
+    foreign key (server_id, server_type)
+      references server (private_id, type)
+      on delete cascade
+      on update cascade
+  );
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+  drop table job_run;
+  drop table job_run_status_enm;
+  drop table job;
+
+commit;
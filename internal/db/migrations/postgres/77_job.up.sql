@@ -0,0 +1,67 @@
+begin;
+
+  -- A job is background work, such as cleaning up expired data, which the
+  -- controllers run periodically. Every controller schedules every job, and a
+  -- controller runs a job after taking its lease: the lease can only be taken
+  -- when the job is due and no other controller holds an unexpired lease on
+  -- it, so each run of the job happens on a single controller.
+  create table job (
+    name text primary key
+      constraint name_must_not_be_empty
+      check (length(trim(name)) > 0),
+    description text not null,
+    next_scheduled_run timestamp with time zone not null default current_timestamp,
+    -- The private id of the controller holding the lease, if any.
+    lease_owner text,
+    lease_expiration timestamp with time zone,
+    create_time wt_timestamp,
+    constraint lease_owner_and_expiration_must_be_set_together
+      check ((lease_owner is null) = (lease_expiration is null))
+  );
+
+  -- A run is running until the controller running it records its end. A run
+  -- whose controller lost its lease without recording an end, e.g. because it
+  -- was stopped, is marked interrupted when the job is next run.
+  create table job_run_status_enm (
+    name text primary key
+      constraint only_predefined_job_run_statuses_allowed
+      check (
+        name in (
+          'running',
+          'completed',
+          'failed',
+          'interrupted'
+        )
+      )
+  );
+
+  insert into job_run_status_enm (name)
+  values
+    ('running'),
+    ('completed'),
+    ('failed'),
+    ('interrupted');
+
+  create table job_run (
+    private_id text primary key,
+    job_name text not null
+      references job (name)
+      on delete cascade
+      on update cascade,
+    -- The private id of the controller which ran the job.
+    server_id text not null,
+    status text not null default 'running'
+      references job_run_status_enm (name)
+      on delete restrict
+      on update cascade,
+    start_time timestamp with time zone not null default current_timestamp,
+    end_time timestamp with time zone,
+    error text,
+    constraint end_time_must_not_be_before_start_time
+      check (end_time is null or end_time >= start_time)
+  );
+
+  create index job_run_job_name_start_time_ix
+    on job_run (job_name, start_time desc);
+
+commit;
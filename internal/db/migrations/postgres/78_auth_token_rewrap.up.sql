@@ -0,0 +1,21 @@
+begin;
+
+  -- The token of an auth token may now be changed when its key_id changes as
+  -- well, which happens when the token is rewrapped with a new version of the
+  -- scope's database key. The plaintext token is unchanged by rewrapping.
+  create or replace function
+    immutable_auth_token_columns()
+    returns trigger
+  as $$
+  begin
+    if new.auth_account_id is distinct from old.auth_account_id then
+      raise exception 'auth_account_id is read-only';
+    end if;
+    if new.token is distinct from old.token and new.key_id is not distinct from old.key_id then
+      raise exception 'token is read-only';
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+commit;
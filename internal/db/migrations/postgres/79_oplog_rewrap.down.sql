@@ -0,0 +1,29 @@
+begin;
+
+  drop trigger oplog_rewrap_columns on oplog_chain_head;
+  drop trigger immutable_columns on oplog_chain_head;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_chain_head
+    for each row execute procedure immutable_columns('id','create_time','ticket_name','ticket_version','hash','key_id','signature');
+
+  drop trigger oplog_rewrap_columns on oplog_entry;
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name', 'data',
+                                                     'ticket_name', 'ticket_version', 'prev_hash', 'hash');
+
+  drop function oplog_rewrap_columns;
+
+  drop index oplog_entry_key_id_idx;
+
+  alter table oplog_entry
+    drop column key_id;
+
+commit;
@@ -0,0 +1,79 @@
+begin;
+
+  -- key_id is the id of the oplog key version the data of an entry is
+  -- encrypted with. Entries written before this migration have a null key_id
+  -- until the key rewrap job sets it from their data.
+  alter table oplog_entry
+    add column key_id text;
+
+  create index oplog_entry_key_id_idx
+    on oplog_entry (key_id);
+
+  -- oplog_rewrap_columns() makes the columns passed as parameters immutable,
+  -- except in a transaction which sets boundary.oplog_rewrap to 'true'. The
+  -- key rewrap job re-encrypts the data of entries with the current oplog key
+  -- versions, which changes their hashes and the hashes of the entries
+  -- chained after them, and re-signs chain heads with the current oplog key
+  -- of the global scope.
+  create or replace function
+    oplog_rewrap_columns()
+    returns trigger
+  as $$
+  declare
+    col_name text;
+    new_value text;
+    old_value text;
+  begin
+    if current_setting('boundary.oplog_rewrap', true) = 'true' then
+      return new;
+    end if;
+    foreach col_name in array tg_argv loop
+      execute format('SELECT $1.%I', col_name) into new_value using new;
+      execute format('SELECT $1.%I', col_name) into old_value using old;
+      if new_value is distinct from old_value then
+        raise exception 'immutable column: %.%', tg_table_name, col_name using
+          errcode = '23601',
+          schema = tg_table_schema,
+          table = tg_table_name,
+          column = col_name;
+      end if;
+    end loop;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  comment on function
+    oplog_rewrap_columns()
+  is
+    'function used in before update triggers to make columns immutable outside of oplog key rewrapping';
+
+  drop trigger immutable_columns on oplog_entry;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_entry
+    for each row execute procedure immutable_columns('id','update_time','create_time','version','aggregate_name',
+                                                     'ticket_name', 'ticket_version');
+
+  create trigger
+    oplog_rewrap_columns
+  before
+  update on oplog_entry
+    for each row execute procedure oplog_rewrap_columns('data', 'prev_hash', 'hash', 'key_id');
+
+  drop trigger immutable_columns on oplog_chain_head;
+
+  create trigger
+    immutable_columns
+  before
+  update on oplog_chain_head
+    for each row execute procedure immutable_columns('id','create_time','ticket_name','ticket_version');
+
+  create trigger
+    oplog_rewrap_columns
+  before
+  update on oplog_chain_head
+    for each row execute procedure oplog_rewrap_columns('hash', 'key_id', 'signature');
+
+commit;
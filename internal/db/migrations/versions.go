@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is an up migration embedded in the binary.
+type Migration struct {
+	// Version is the schema version after the migration is run.
+	Version int
+	// Name is the name of the migration file without its extension, e.g.
+	// "71_oplog_archive".
+	Name string
+	// Sql is the contents of the migration file.
+	Sql string
+}
+
+// Migrations returns the up migrations embedded for the dialect, ordered by
+// version.
+func Migrations(dialect string) ([]*Migration, error) {
+	var migrationsMap map[string]*fakeFile
+	switch dialect {
+	case "postgres":
+		migrationsMap = postgresMigrations
+	default:
+		return nil, fmt.Errorf("unknown migrations dialect %s", dialect)
+	}
+
+	var ms []*Migration
+	for key, f := range migrationsMap {
+		name := strings.TrimPrefix(key, "migrations/")
+		if !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		name = strings.TrimSuffix(name, ".up.sql")
+		i := strings.Index(name, "_")
+		if i < 0 {
+			return nil, fmt.Errorf("migration %s has no version prefix", name)
+		}
+		v, err := strconv.Atoi(name[:i])
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version prefix: %w", name, err)
+		}
+		ms = append(ms, &Migration{
+			Version: v,
+			Name:    name,
+			Sql:     string(f.bytes),
+		})
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+// LatestVersion returns the schema version after all of the migrations
+// embedded for the dialect are run.
+func LatestVersion(dialect string) (int, error) {
+	ms, err := Migrations(dialect)
+	if err != nil {
+		return 0, err
+	}
+	if len(ms) == 0 {
+		return 0, fmt.Errorf("no migrations found for dialect %s", dialect)
+	}
+	return ms[len(ms)-1].Version, nil
+}
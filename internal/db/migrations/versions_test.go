@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ms, err := Migrations("postgres")
+	require.NoError(err)
+	require.NotEmpty(ms)
+	assert.Equal(1, ms[0].Version)
+	assert.Equal("01_domain_types", ms[0].Name)
+	for i := 1; i < len(ms); i++ {
+		assert.Less(ms[i-1].Version, ms[i].Version)
+		assert.NotEmpty(strings.TrimSpace(ms[i].Sql))
+	}
+
+	latest, err := LatestVersion("postgres")
+	require.NoError(err)
+	assert.Equal(ms[len(ms)-1].Version, latest)
+
+	_, err = Migrations("mysql")
+	assert.Error(err)
+	_, err = LatestVersion("")
+	assert.Error(err)
+}
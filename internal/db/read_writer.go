@@ -10,10 +10,13 @@ import (
 	"time"
 
 	"github.com/hashicorp/boundary/internal/db/common"
+	"github.com/hashicorp/boundary/internal/metrics"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/hashicorp/boundary/internal/tracing"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel/label"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -163,23 +166,34 @@ type VetForWriter interface {
 	VetForWrite(ctx context.Context, r Reader, opType OpType, opt ...Option) error
 }
 
-// Db uses a gorm DB connection for read/write
+// Db uses a gorm DB connection for read/write. Reads outside of a transaction
+// are sent to the read replicas, if any; see NewReadYourWritesContext.
 type Db struct {
 	underlying *gorm.DB
+	replicas   []*gorm.DB
+	// next is used to round robin reads over the replicas.
+	next uint32
 }
 
 // ensure that Db implements the interfaces of: Reader and Writer
 var _ Reader = (*Db)(nil)
 var _ Writer = (*Db)(nil)
 
-func New(underlying *gorm.DB) *Db {
-	return &Db{underlying: underlying}
+// New returns a Db which writes to the underlying primary. LookupById,
+// LookupByPublicId, LookupWhere, SearchWhere and Query are sent to the
+// replicas in turn when any are given, except within transactions and for
+// contexts which read from the primary.
+func New(underlying *gorm.DB, replicas ...*gorm.DB) *Db {
+	return &Db{underlying: underlying, replicas: replicas}
 }
 
 // Exec will execute the sql with the values as parameters. The int returned
 // is the number of rows affected by the sql. No options are currently
 // supported.
 func (rw *Db) Exec(ctx context.Context, sql string, values []interface{}, opt ...Option) (int, error) {
+	_, span := startSpan(ctx, "Exec", nil)
+	defer span.End()
+	markWritten(ctx)
 	if sql == "" {
 		return NoRowsAffected, fmt.Errorf("missing sql: %w", ErrInvalidParameter)
 	}
@@ -195,10 +209,12 @@ func (rw *Db) Exec(ctx context.Context, sql string, values []interface{}, opt ..
 // caller must close the returned *sql.Rows. Query can/should be used in
 // combination with ScanRows.
 func (rw *Db) Query(ctx context.Context, sql string, values []interface{}, opt ...Option) (*sql.Rows, error) {
+	_, span := startSpan(ctx, "Query", nil)
+	defer span.End()
 	if sql == "" {
 		return nil, fmt.Errorf("raw missing sql: %w", ErrInvalidParameter)
 	}
-	gormDb := rw.underlying.Raw(sql, values...)
+	gormDb := rw.reader(ctx).Raw(sql, values...)
 	if gormDb.Error != nil {
 		return nil, fmt.Errorf("exec: failed: %w", gormDb.Error)
 	}
@@ -223,7 +239,8 @@ func (rw *Db) lookupAfterWrite(ctx context.Context, i interface{}, opt ...Option
 	if !withLookup {
 		return nil
 	}
-	if err := rw.LookupById(ctx, i, opt...); err != nil {
+	// The write may not have reached the replicas yet, whatever the context
+	if err := rw.LookupById(WithPrimary(ctx), i, opt...); err != nil {
 		return fmt.Errorf("lookup after write: %w", err)
 	}
 	return nil
@@ -234,6 +251,9 @@ func (rw *Db) lookupAfterWrite(ctx context.Context, i interface{}, opt ...Option
 // NewOplogMsg will return in-memory oplog message.  WithOplog and NewOplogMsg
 // cannot be used together.  WithLookup with to force a lookup after create.
 func (rw *Db) Create(ctx context.Context, i interface{}, opt ...Option) error {
+	ctx, span := startSpan(ctx, "Create", i)
+	defer span.End()
+	markWritten(ctx)
 	if rw.underlying == nil {
 		return fmt.Errorf("create: missing underlying db: %w", ErrInvalidParameter)
 	}
@@ -296,6 +316,9 @@ func (rw *Db) Create(ctx context.Context, i interface{}, opt ...Option) error {
 // WithOplog and WithOplogMsgs.  WithOplog and WithOplogMsgs may not be used
 // together.  WithLookup is not a supported option.
 func (rw *Db) CreateItems(ctx context.Context, createItems []interface{}, opt ...Option) error {
+	ctx, span := startSpan(ctx, "CreateItems", nil)
+	defer span.End()
+	markWritten(ctx)
 	if rw.underlying == nil {
 		return fmt.Errorf("create items: missing underlying db: %w", ErrInvalidParameter)
 	}
@@ -375,6 +398,9 @@ func (rw *Db) CreateItems(ctx context.Context, createItems []interface{}, opt ..
 // version matches the WithVersion option.  Zero is not a valid value for the
 // WithVersion option and will return an error.
 func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string, setToNullPaths []string, opt ...Option) (int, error) {
+	ctx, span := startSpan(ctx, "Update", i)
+	defer span.End()
+	markWritten(ctx)
 	if rw.underlying == nil {
 		return NoRowsAffected, fmt.Errorf("update: missing underlying db %w", ErrInvalidParameter)
 	}
@@ -509,6 +535,9 @@ func (rw *Db) Update(ctx context.Context, i interface{}, fieldMaskPaths []string
 // WithWhere allows specifying a constraint. Delete returns the number of rows
 // deleted and any errors.
 func (rw *Db) Delete(ctx context.Context, i interface{}, opt ...Option) (int, error) {
+	ctx, span := startSpan(ctx, "Delete", i)
+	defer span.End()
+	markWritten(ctx)
 	if rw.underlying == nil {
 		return NoRowsAffected, fmt.Errorf("delete: missing underlying db %w", ErrInvalidParameter)
 	}
@@ -572,6 +601,9 @@ func (rw *Db) Delete(ctx context.Context, i interface{}, opt ...Option) (int, er
 // WithOplog and WithOplogMsgs.  WithOplog and WithOplogMsgs may not be used
 // together.
 func (rw *Db) DeleteItems(ctx context.Context, deleteItems []interface{}, opt ...Option) (int, error) {
+	ctx, span := startSpan(ctx, "DeleteItems", nil)
+	defer span.End()
+	markWritten(ctx)
 	if rw.underlying == nil {
 		return NoRowsAffected, fmt.Errorf("delete items: missing underlying db: %w", ErrInvalidParameter)
 	}
@@ -800,6 +832,7 @@ func (rw *Db) addOplog(ctx context.Context, opType OpType, opts Options, ticket
 // WriteOplogEntryWith will write an oplog entry with the msgs provided for
 // the ticket's aggregateName. No options are currently supported.
 func (rw *Db) WriteOplogEntryWith(ctx context.Context, wrapper wrapping.Wrapper, ticket *store.Ticket, metadata oplog.Metadata, msgs []*oplog.Message, opt ...Option) error {
+	markWritten(ctx)
 	if wrapper == nil {
 		return fmt.Errorf("write oplog: wrapper is unset %w", ErrInvalidParameter)
 	}
@@ -875,6 +908,15 @@ func (rw *Db) newOplogMessage(ctx context.Context, opType OpType, i interface{},
 // means that the object may be sent to the db several times (retried), so things like the primary key must
 // be reset before retry
 func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler TxHandler) (RetryInfo, error) {
+	ctx, span := startSpan(ctx, "DoTx", nil)
+	markWritten(ctx)
+	info, err := w.doTx(ctx, retries, backOff, Handler)
+	span.SetAttributes(label.Int("db.retries", info.Retries))
+	tracing.End(ctx, span, err)
+	return info, err
+}
+
+func (w *Db) doTx(ctx context.Context, retries uint, backOff Backoff, Handler TxHandler) (RetryInfo, error) {
 	if w.underlying == nil {
 		return RetryInfo{}, errors.New("do underlying db is nil")
 	}
@@ -887,7 +929,7 @@ func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler Tx
 		// step one of this, start a transaction...
 		newTx := w.underlying.BeginTx(ctx, nil)
 
-		rw := &Db{newTx}
+		rw := &Db{underlying: newTx}
 		if err := Handler(rw, rw); err != nil {
 			if err := newTx.Rollback().Error; err != nil {
 				return info, err
@@ -896,6 +938,7 @@ func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler Tx
 				d := backOff.Duration(attempts)
 				info.Retries++
 				info.Backoff = info.Backoff + d
+				metrics.IncDbTransactionRetries()
 				time.Sleep(d)
 				continue
 			}
@@ -915,6 +958,8 @@ func (w *Db) DoTx(ctx context.Context, retries uint, backOff Backoff, Handler Tx
 // LookupByPublicId will lookup resource by its public_id or private_id, which
 // must be unique. Options are ignored.
 func (rw *Db) LookupById(ctx context.Context, resourceWithIder interface{}, opt ...Option) error {
+	_, span := startSpan(ctx, "LookupById", resourceWithIder)
+	defer span.End()
 	if rw.underlying == nil {
 		return fmt.Errorf("lookup by id: underlying db nil %w", ErrInvalidParameter)
 	}
@@ -925,7 +970,7 @@ func (rw *Db) LookupById(ctx context.Context, resourceWithIder interface{}, opt
 	if err != nil {
 		return fmt.Errorf("lookup by id: %w", err)
 	}
-	if err := rw.underlying.Where(where, primaryKey).First(resourceWithIder).Error; err != nil {
+	if err := rw.reader(ctx).Where(where, primaryKey).First(resourceWithIder).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return ErrRecordNotFound
 		}
@@ -960,13 +1005,15 @@ func (rw *Db) LookupByPublicId(ctx context.Context, resource ResourcePublicIder,
 
 // LookupWhere will lookup the first resource using a where clause with parameters (it only returns the first one)
 func (rw *Db) LookupWhere(ctx context.Context, resource interface{}, where string, args ...interface{}) error {
+	_, span := startSpan(ctx, "LookupWhere", resource)
+	defer span.End()
 	if rw.underlying == nil {
 		return errors.New("error underlying db nil for lookup by")
 	}
 	if reflect.ValueOf(resource).Kind() != reflect.Ptr {
 		return errors.New("error interface parameter must to be a pointer for lookup by")
 	}
-	if err := rw.underlying.Where(where, args...).First(resource).Error; err != nil {
+	if err := rw.reader(ctx).Where(where, args...).First(resource).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return ErrRecordNotFound
 		}
@@ -980,6 +1027,8 @@ func (rw *Db) LookupWhere(ctx context.Context, resource interface{}, where strin
 // WithLimit < 0, then unlimited results are returned.  If WithLimit == 0, then
 // default limits are used for results.  Supports the WithOrder option.
 func (rw *Db) SearchWhere(ctx context.Context, resources interface{}, where string, args []interface{}, opt ...Option) error {
+	_, span := startSpan(ctx, "SearchWhere", resources)
+	defer span.End()
 	opts := GetOpts(opt...)
 	if rw.underlying == nil {
 		return errors.New("error underlying db nil for search by")
@@ -988,7 +1037,7 @@ func (rw *Db) SearchWhere(ctx context.Context, resources interface{}, where stri
 		return errors.New("error interface parameter must to be a pointer for search by")
 	}
 	var err error
-	db := rw.underlying.Order(opts.withOrder)
+	db := rw.reader(ctx).Order(opts.withOrder)
 
 	// Perform limiting
 	switch {
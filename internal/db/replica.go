@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jinzhu/gorm"
+)
+
+type readYourWritesKey struct{}
+
+type readPrimaryKey struct{}
+
+// writeTracker records whether a write has been made with a context.
+type writeTracker struct {
+	written int32
+}
+
+// NewReadYourWritesContext returns a context which tracks writes made with
+// it, such as the context of a request. Once a write has been made with the
+// context, reads made with it are sent to the primary rather than to a read
+// replica, so that they see the write even when the replicas lag behind.
+func NewReadYourWritesContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, &writeTracker{})
+}
+
+// WithPrimary returns a context whose reads are always sent to the primary.
+// It is used for lookups which must see the latest writes, e.g. of other
+// controllers.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPrimaryKey{}, true)
+}
+
+// markWritten records that a write has been made with the context, if it
+// was returned by NewReadYourWritesContext.
+func markWritten(ctx context.Context) {
+	if t, ok := ctx.Value(readYourWritesKey{}).(*writeTracker); ok {
+		atomic.StoreInt32(&t.written, 1)
+	}
+}
+
+// readFromPrimary returns true if reads made with the context must be sent to
+// the primary.
+func readFromPrimary(ctx context.Context) bool {
+	if primary, ok := ctx.Value(readPrimaryKey{}).(bool); ok && primary {
+		return true
+	}
+	if t, ok := ctx.Value(readYourWritesKey{}).(*writeTracker); ok {
+		return atomic.LoadInt32(&t.written) == 1
+	}
+	return false
+}
+
+// reader returns the connection a read made with the context is sent to: the
+// next read replica in turn, or the primary when there are no replicas or the
+// context reads from the primary.
+func (rw *Db) reader(ctx context.Context) *gorm.DB {
+	if len(rw.replicas) == 0 || readFromPrimary(ctx) {
+		return rw.underlying
+	}
+	n := atomic.AddUint32(&rw.next, 1)
+	return rw.replicas[n%uint32(len(rw.replicas))]
+}
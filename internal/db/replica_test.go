@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db/db_test"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDb_reader(t *testing.T) {
+	t.Parallel()
+	primary, replica1, replica2 := &gorm.DB{}, &gorm.DB{}, &gorm.DB{}
+
+	t.Run("no-replicas", func(t *testing.T) {
+		assert := assert.New(t)
+		rw := New(primary)
+		assert.Same(primary, rw.reader(context.Background()))
+		assert.Same(primary, rw.reader(context.Background()))
+	})
+	t.Run("round-robin", func(t *testing.T) {
+		assert := assert.New(t)
+		rw := New(primary, replica1, replica2)
+		seen := map[*gorm.DB]int{}
+		for i := 0; i < 4; i++ {
+			seen[rw.reader(context.Background())]++
+		}
+		assert.Equal(map[*gorm.DB]int{replica1: 2, replica2: 2}, seen)
+	})
+	t.Run("with-primary", func(t *testing.T) {
+		assert := assert.New(t)
+		rw := New(primary, replica1)
+		assert.Same(primary, rw.reader(WithPrimary(context.Background())))
+	})
+	t.Run("read-your-writes", func(t *testing.T) {
+		assert := assert.New(t)
+		rw := New(primary, replica1)
+		ctx := NewReadYourWritesContext(context.Background())
+		assert.Same(replica1, rw.reader(ctx))
+		markWritten(ctx)
+		assert.Same(primary, rw.reader(ctx))
+		// Other requests still read from the replicas.
+		assert.Same(replica1, rw.reader(NewReadYourWritesContext(context.Background())))
+	})
+}
+
+func TestDb_ReadReplicas(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	primary, _ := TestSetup(t, "postgres")
+	// A separate database stands in for a replica which has not yet received
+	// the writes made to the primary.
+	replica, _ := TestSetup(t, "postgres")
+	rw := New(primary, replica)
+
+	ctx := NewReadYourWritesContext(context.Background())
+	user, err := db_test.NewTestUser()
+	require.NoError(err)
+
+	// Before a write, reads go to the replica.
+	foundUser := db_test.AllocTestUser()
+	foundUser.PublicId = user.PublicId
+	assert.True(errors.Is(rw.LookupById(ctx, &foundUser), ErrRecordNotFound))
+
+	require.NoError(rw.Create(ctx, user))
+
+	// After a write, reads with the same context go to the primary.
+	foundUser = db_test.AllocTestUser()
+	foundUser.PublicId = user.PublicId
+	require.NoError(rw.LookupById(ctx, &foundUser))
+	assert.Equal(user.Id, foundUser.Id)
+
+	var users []db_test.TestUser
+	require.NoError(rw.SearchWhere(ctx, &users, "public_id = ?", []interface{}{user.PublicId}))
+	assert.Len(users, 1)
+
+	// Reads with other contexts still go to the replica.
+	foundUser = db_test.AllocTestUser()
+	foundUser.PublicId = user.PublicId
+	assert.True(errors.Is(rw.LookupById(context.Background(), &foundUser), ErrRecordNotFound))
+	require.NoError(rw.LookupById(WithPrimary(context.Background()), &foundUser))
+
+	// Lookups after writes read from the primary, even with contexts which
+	// otherwise read from the replicas.
+	lookupUser, err := db_test.NewTestUser()
+	require.NoError(err)
+	require.NoError(rw.Create(context.Background(), lookupUser, WithLookup(true)))
+	assert.NotZero(lookupUser.Id)
+	lookupUser.Name = "updated"
+	rowsUpdated, err := rw.Update(context.Background(), lookupUser, []string{"Name"}, nil, WithLookup(true))
+	require.NoError(err)
+	assert.Equal(1, rowsUpdated)
+	assert.Equal("updated", lookupUser.Name)
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/hashicorp/boundary/internal/db/migrations"
+)
+
+// migrationLockId is the key of the Postgres advisory lock held while
+// migrating, so that only one process migrates a database at a time.
+const migrationLockId int64 = 0x626f756e64617279 // "boundary"
+
+var (
+	// ErrSchemaNotInitialized is returned when the database has not been
+	// initialized.
+	ErrSchemaNotInitialized = errors.New("database is not initialized")
+
+	// ErrSchemaDirty is returned when a migration of the database failed part
+	// way through.
+	ErrSchemaDirty = errors.New("database schema is dirty")
+
+	// ErrSchemaNewer is returned when the database schema is newer than the
+	// migrations embedded in the binary.
+	ErrSchemaNewer = errors.New("database schema is newer than this binary")
+
+	// ErrMigrationLocked is returned when another process is migrating the
+	// database.
+	ErrMigrationLocked = errors.New("another migration of the database is in progress")
+)
+
+// SchemaVersion is the schema version of a database compared to the
+// migrations embedded in the binary.
+type SchemaVersion struct {
+	// Current is the schema version of the database, zero if it is not
+	// initialized.
+	Current int
+	// Dirty is true if a migration failed part way through.
+	Dirty bool
+	// Latest is the version after all embedded migrations are run.
+	Latest int
+}
+
+// Initialized returns true if the database has been initialized.
+func (v *SchemaVersion) Initialized() bool {
+	return v.Current > 0
+}
+
+// Pending returns the embedded migrations which have not been run.
+func (v *SchemaVersion) Pending(dialect string) ([]*migrations.Migration, error) {
+	ms, err := migrations.Migrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+	var pending []*migrations.Migration
+	for _, m := range ms {
+		if m.Version > v.Current {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// GetSchemaVersion returns the schema version of the database.
+func GetSchemaVersion(ctx context.Context, d *sql.DB, dialect string) (*SchemaVersion, error) {
+	latest, err := migrations.LatestVersion(dialect)
+	if err != nil {
+		return nil, fmt.Errorf("get schema version: %w", err)
+	}
+	v := &SchemaVersion{Latest: latest}
+	err = d.QueryRowContext(ctx, "select version, dirty from schema_migrations").Scan(&v.Current, &v.Dirty)
+	switch {
+	case err == nil:
+	case errors.Is(err, sql.ErrNoRows), strings.Contains(err.Error(), "does not exist"):
+		v.Current, v.Dirty = 0, false
+	default:
+		return nil, fmt.Errorf("get schema version: %w", err)
+	}
+	return v, nil
+}
+
+// CheckSchemaVersion returns the schema version of the database and an error
+// if a controller using the embedded migrations cannot use it: if it is not
+// initialized, dirty, or newer than the embedded migrations. An older schema
+// is not an error; callers should check for pending migrations.
+func CheckSchemaVersion(ctx context.Context, d *sql.DB, dialect string) (*SchemaVersion, error) {
+	v, err := GetSchemaVersion(ctx, d, dialect)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !v.Initialized():
+		return v, ErrSchemaNotInitialized
+	case v.Dirty:
+		return v, fmt.Errorf("%w: migration to version %d failed", ErrSchemaDirty, v.Current)
+	case v.Current > v.Latest:
+		return v, fmt.Errorf("%w: database has schema version %d but this binary supports up to version %d", ErrSchemaNewer, v.Current, v.Latest)
+	}
+	return v, nil
+}
+
+// MigrateStore runs the pending migrations of an initialized database,
+// holding an advisory lock so that only one process migrates it at a time.
+// Returns ErrMigrationLocked if another process holds the lock. Returns the
+// schema version from before the migrations were run.
+func MigrateStore(ctx context.Context, dialect string, url string) (*SchemaVersion, error) {
+	d, err := sql.Open(dialect, url)
+	if err != nil {
+		return nil, fmt.Errorf("migrate store: %w", err)
+	}
+	defer d.Close()
+
+	// Advisory locks are held by a session, so the lock is taken and released
+	// on the same connection.
+	conn, err := d.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate store: %w", err)
+	}
+	defer conn.Close()
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "select pg_try_advisory_lock($1)", migrationLockId).Scan(&locked); err != nil {
+		return nil, fmt.Errorf("migrate store: unable to take migration lock: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("migrate store: %w", ErrMigrationLocked)
+	}
+	defer conn.ExecContext(context.Background(), "select pg_advisory_unlock($1)", migrationLockId)
+
+	v, err := CheckSchemaVersion(ctx, d, dialect)
+	if err != nil {
+		return v, fmt.Errorf("migrate store: %w", err)
+	}
+	if v.Current == v.Latest {
+		return v, nil
+	}
+
+	source, err := migrations.NewMigrationSource(dialect)
+	if err != nil {
+		return v, fmt.Errorf("migrate store: error creating migration driver: %w", err)
+	}
+	m, err := migrate.NewWithSourceInstance("httpfs", source, url)
+	if err != nil {
+		return v, fmt.Errorf("migrate store: error creating migrations: %w", err)
+	}
+	defer m.Close()
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return v, fmt.Errorf("migrate store: error running migrations: %w", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert, require := assert.New(t), require.New(t)
+	conn, url := TestSetup(t, "postgres")
+	d := conn.DB()
+
+	latest, err := migrations.LatestVersion("postgres")
+	require.NoError(err)
+
+	v, err := CheckSchemaVersion(ctx, d, "postgres")
+	require.NoError(err)
+	assert.Equal(latest, v.Current)
+	assert.Equal(latest, v.Latest)
+	pending, err := v.Pending("postgres")
+	require.NoError(err)
+	assert.Empty(pending)
+
+	// Migrating an up to date database does nothing.
+	v, err = MigrateStore(ctx, "postgres", url)
+	require.NoError(err)
+	assert.Equal(latest, v.Current)
+
+	// A schema newer than the binary is refused.
+	_, err = d.Exec("update schema_migrations set version = $1", latest+1)
+	require.NoError(err)
+	_, err = CheckSchemaVersion(ctx, d, "postgres")
+	assert.True(errors.Is(err, ErrSchemaNewer))
+	_, err = MigrateStore(ctx, "postgres", url)
+	assert.True(errors.Is(err, ErrSchemaNewer))
+
+	// A dirty schema is refused.
+	_, err = d.Exec("update schema_migrations set version = $1, dirty = true", latest)
+	require.NoError(err)
+	_, err = CheckSchemaVersion(ctx, d, "postgres")
+	assert.True(errors.Is(err, ErrSchemaDirty))
+
+	// Only one migration runs at a time.
+	_, err = d.Exec("update schema_migrations set dirty = false")
+	require.NoError(err)
+	lockConn, err := d.Conn(ctx)
+	require.NoError(err)
+	defer lockConn.Close()
+	_, err = lockConn.ExecContext(ctx, "select pg_advisory_lock($1)", migrationLockId)
+	require.NoError(err)
+	_, err = MigrateStore(ctx, "postgres", url)
+	assert.True(errors.Is(err, ErrMigrationLocked))
+	_, err = lockConn.ExecContext(ctx, "select pg_advisory_unlock($1)", migrationLockId)
+	require.NoError(err)
+	_, err = MigrateStore(ctx, "postgres", url)
+	assert.NoError(err)
+}
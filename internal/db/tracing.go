@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/tracing"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+// startSpan starts a span for a read/writer operation. If resource is not nil
+// the span is annotated with its table or, when it has no table name, its
+// type.
+func startSpan(ctx context.Context, op string, resource interface{}) (context.Context, trace.Span) {
+	attrs := []label.KeyValue{
+		semconv.DBSystemPostgres,
+		semconv.DBOperationKey.String(op),
+	}
+	switch r := resource.(type) {
+	case nil:
+	case interface{ TableName() string }:
+		if isNil(r) {
+			break
+		}
+		attrs = append(attrs, label.String("db.sql.table", r.TableName()))
+	default:
+		attrs = append(attrs, label.String("db.resource", fmt.Sprintf("%T", r)))
+	}
+	return tracing.Start(ctx, "db."+op, attrs...)
+}
@@ -0,0 +1,224 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// HistoryServiceClient is the client API for HistoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HistoryServiceClient interface {
+	// GetRoleHistory returns the changes made to a Role and its principals
+	// and grants, oldest first, as recorded in the operation log. If the ID is
+	// missing, malformed or references a non existing Role, an error is
+	// returned.
+	GetRoleHistory(ctx context.Context, in *GetRoleHistoryRequest, opts ...grpc.CallOption) (*GetRoleHistoryResponse, error)
+	// GetTargetHistory returns the changes made to a Target and its host
+	// sets, oldest first, as recorded in the operation log. If the ID is
+	// missing, malformed or references a non existing Target, an error is
+	// returned.
+	GetTargetHistory(ctx context.Context, in *GetTargetHistoryRequest, opts ...grpc.CallOption) (*GetTargetHistoryResponse, error)
+	// GetHostSetHistory returns the changes made to a Host Set and its
+	// members, oldest first, as recorded in the operation log. If the ID is
+	// missing, malformed or references a non existing Host Set, an error is
+	// returned.
+	GetHostSetHistory(ctx context.Context, in *GetHostSetHistoryRequest, opts ...grpc.CallOption) (*GetHostSetHistoryResponse, error)
+	// GetUserHistory returns the changes made to a User and its accounts,
+	// oldest first, as recorded in the operation log. If the ID is missing,
+	// malformed or references a non existing User, an error is returned.
+	GetUserHistory(ctx context.Context, in *GetUserHistoryRequest, opts ...grpc.CallOption) (*GetUserHistoryResponse, error)
+}
+
+type historyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHistoryServiceClient(cc grpc.ClientConnInterface) HistoryServiceClient {
+	return &historyServiceClient{cc}
+}
+
+func (c *historyServiceClient) GetRoleHistory(ctx context.Context, in *GetRoleHistoryRequest, opts ...grpc.CallOption) (*GetRoleHistoryResponse, error) {
+	out := new(GetRoleHistoryResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.HistoryService/GetRoleHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *historyServiceClient) GetTargetHistory(ctx context.Context, in *GetTargetHistoryRequest, opts ...grpc.CallOption) (*GetTargetHistoryResponse, error) {
+	out := new(GetTargetHistoryResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.HistoryService/GetTargetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *historyServiceClient) GetHostSetHistory(ctx context.Context, in *GetHostSetHistoryRequest, opts ...grpc.CallOption) (*GetHostSetHistoryResponse, error) {
+	out := new(GetHostSetHistoryResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.HistoryService/GetHostSetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *historyServiceClient) GetUserHistory(ctx context.Context, in *GetUserHistoryRequest, opts ...grpc.CallOption) (*GetUserHistoryResponse, error) {
+	out := new(GetUserHistoryResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.HistoryService/GetUserHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HistoryServiceServer is the server API for HistoryService service.
+type HistoryServiceServer interface {
+	// GetRoleHistory returns the changes made to a Role and its principals
+	// and grants, oldest first, as recorded in the operation log. If the ID is
+	// missing, malformed or references a non existing Role, an error is
+	// returned.
+	GetRoleHistory(context.Context, *GetRoleHistoryRequest) (*GetRoleHistoryResponse, error)
+	// GetTargetHistory returns the changes made to a Target and its host
+	// sets, oldest first, as recorded in the operation log. If the ID is
+	// missing, malformed or references a non existing Target, an error is
+	// returned.
+	GetTargetHistory(context.Context, *GetTargetHistoryRequest) (*GetTargetHistoryResponse, error)
+	// GetHostSetHistory returns the changes made to a Host Set and its
+	// members, oldest first, as recorded in the operation log. If the ID is
+	// missing, malformed or references a non existing Host Set, an error is
+	// returned.
+	GetHostSetHistory(context.Context, *GetHostSetHistoryRequest) (*GetHostSetHistoryResponse, error)
+	// GetUserHistory returns the changes made to a User and its accounts,
+	// oldest first, as recorded in the operation log. If the ID is missing,
+	// malformed or references a non existing User, an error is returned.
+	GetUserHistory(context.Context, *GetUserHistoryRequest) (*GetUserHistoryResponse, error)
+}
+
+// UnimplementedHistoryServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedHistoryServiceServer struct {
+}
+
+func (*UnimplementedHistoryServiceServer) GetRoleHistory(context.Context, *GetRoleHistoryRequest) (*GetRoleHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoleHistory not implemented")
+}
+func (*UnimplementedHistoryServiceServer) GetTargetHistory(context.Context, *GetTargetHistoryRequest) (*GetTargetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTargetHistory not implemented")
+}
+func (*UnimplementedHistoryServiceServer) GetHostSetHistory(context.Context, *GetHostSetHistoryRequest) (*GetHostSetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHostSetHistory not implemented")
+}
+func (*UnimplementedHistoryServiceServer) GetUserHistory(context.Context, *GetUserHistoryRequest) (*GetUserHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserHistory not implemented")
+}
+
+func RegisterHistoryServiceServer(s *grpc.Server, srv HistoryServiceServer) {
+	s.RegisterService(&_HistoryService_serviceDesc, srv)
+}
+
+func _HistoryService_GetRoleHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoleHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HistoryServiceServer).GetRoleHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.HistoryService/GetRoleHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HistoryServiceServer).GetRoleHistory(ctx, req.(*GetRoleHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HistoryService_GetTargetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTargetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HistoryServiceServer).GetTargetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.HistoryService/GetTargetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HistoryServiceServer).GetTargetHistory(ctx, req.(*GetTargetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HistoryService_GetHostSetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHostSetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HistoryServiceServer).GetHostSetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.HistoryService/GetHostSetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HistoryServiceServer).GetHostSetHistory(ctx, req.(*GetHostSetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HistoryService_GetUserHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HistoryServiceServer).GetUserHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.HistoryService/GetUserHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HistoryServiceServer).GetUserHistory(ctx, req.(*GetUserHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HistoryService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "controller.api.services.v1.HistoryService",
+	HandlerType: (*HistoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRoleHistory",
+			Handler:    _HistoryService_GetRoleHistory_Handler,
+		},
+		{
+			MethodName: "GetTargetHistory",
+			Handler:    _HistoryService_GetTargetHistory_Handler,
+		},
+		{
+			MethodName: "GetHostSetHistory",
+			Handler:    _HistoryService_GetHostSetHistory_Handler,
+		},
+		{
+			MethodName: "GetUserHistory",
+			Handler:    _HistoryService_GetUserHistory_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "controller/api/services/v1/history_service.proto",
+}
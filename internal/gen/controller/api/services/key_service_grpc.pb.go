@@ -0,0 +1,182 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// KeyServiceClient is the client API for KeyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KeyServiceClient interface {
+	// ListKeyVersions returns the status of every version of the root key and
+	// data encryption keys of the scope referenced in the request. If the scope
+	// ID is missing, malformed, or reference a non existing scope, an error is
+	// returned.
+	ListKeyVersions(ctx context.Context, in *ListKeyVersionsRequest, opts ...grpc.CallOption) (*ListKeyVersionsResponse, error)
+	// RotateKeys creates a new version of the root key and of every data
+	// encryption key of the scope referenced in the request. New values are
+	// encrypted with the new versions, and values and oplog entries encrypted
+	// with previous versions are rewrapped in the background. The status of every version
+	// after the rotation is returned.
+	RotateKeys(ctx context.Context, in *RotateKeysRequest, opts ...grpc.CallOption) (*RotateKeysResponse, error)
+	// DeleteKeyVersion destroys a key version which is no longer in use. An
+	// error is returned if the version is current or anything still depends
+	// on it.
+	DeleteKeyVersion(ctx context.Context, in *DeleteKeyVersionRequest, opts ...grpc.CallOption) (*DeleteKeyVersionResponse, error)
+}
+
+type keyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeyServiceClient(cc grpc.ClientConnInterface) KeyServiceClient {
+	return &keyServiceClient{cc}
+}
+
+func (c *keyServiceClient) ListKeyVersions(ctx context.Context, in *ListKeyVersionsRequest, opts ...grpc.CallOption) (*ListKeyVersionsResponse, error) {
+	out := new(ListKeyVersionsResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.KeyService/ListKeyVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyServiceClient) RotateKeys(ctx context.Context, in *RotateKeysRequest, opts ...grpc.CallOption) (*RotateKeysResponse, error) {
+	out := new(RotateKeysResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.KeyService/RotateKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyServiceClient) DeleteKeyVersion(ctx context.Context, in *DeleteKeyVersionRequest, opts ...grpc.CallOption) (*DeleteKeyVersionResponse, error) {
+	out := new(DeleteKeyVersionResponse)
+	err := c.cc.Invoke(ctx, "/controller.api.services.v1.KeyService/DeleteKeyVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KeyServiceServer is the server API for KeyService service.
+type KeyServiceServer interface {
+	// ListKeyVersions returns the status of every version of the root key and
+	// data encryption keys of the scope referenced in the request. If the scope
+	// ID is missing, malformed, or reference a non existing scope, an error is
+	// returned.
+	ListKeyVersions(context.Context, *ListKeyVersionsRequest) (*ListKeyVersionsResponse, error)
+	// RotateKeys creates a new version of the root key and of every data
+	// encryption key of the scope referenced in the request. New values are
+	// encrypted with the new versions, and values and oplog entries encrypted
+	// with previous versions are rewrapped in the background. The status of every version
+	// after the rotation is returned.
+	RotateKeys(context.Context, *RotateKeysRequest) (*RotateKeysResponse, error)
+	// DeleteKeyVersion destroys a key version which is no longer in use. An
+	// error is returned if the version is current or anything still depends
+	// on it.
+	DeleteKeyVersion(context.Context, *DeleteKeyVersionRequest) (*DeleteKeyVersionResponse, error)
+}
+
+// UnimplementedKeyServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedKeyServiceServer struct {
+}
+
+func (*UnimplementedKeyServiceServer) ListKeyVersions(context.Context, *ListKeyVersionsRequest) (*ListKeyVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeyVersions not implemented")
+}
+func (*UnimplementedKeyServiceServer) RotateKeys(context.Context, *RotateKeysRequest) (*RotateKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateKeys not implemented")
+}
+func (*UnimplementedKeyServiceServer) DeleteKeyVersion(context.Context, *DeleteKeyVersionRequest) (*DeleteKeyVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteKeyVersion not implemented")
+}
+
+func RegisterKeyServiceServer(s *grpc.Server, srv KeyServiceServer) {
+	s.RegisterService(&_KeyService_serviceDesc, srv)
+}
+
+func _KeyService_ListKeyVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeyVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).ListKeyVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.KeyService/ListKeyVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).ListKeyVersions(ctx, req.(*ListKeyVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyService_RotateKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).RotateKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.KeyService/RotateKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).RotateKeys(ctx, req.(*RotateKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyService_DeleteKeyVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteKeyVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).DeleteKeyVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/controller.api.services.v1.KeyService/DeleteKeyVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).DeleteKeyVersion(ctx, req.(*DeleteKeyVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _KeyService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "controller.api.services.v1.KeyService",
+	HandlerType: (*KeyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListKeyVersions",
+			Handler:    _KeyService_ListKeyVersions_Handler,
+		},
+		{
+			MethodName: "RotateKeys",
+			Handler:    _KeyService_RotateKeys_Handler,
+		},
+		{
+			MethodName: "DeleteKeyVersion",
+			Handler:    _KeyService_DeleteKeyVersion_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "controller/api/services/v1/key_service.proto",
+}
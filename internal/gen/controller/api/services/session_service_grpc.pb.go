@@ -31,6 +31,12 @@ type SessionServiceClient interface {
 	// is returned if the request attempts to cancel a Session that does
 	// not exist.
 	CancelSession(ctx context.Context, in *CancelSessionRequest, opts ...grpc.CallOption) (*CancelSessionResponse, error)
+	// WatchSessions streams state changes of Sessions and their Connections
+	// which exist inside the scope referenced inside the request. The stream
+	// can additionally be filtered by user ID and target ID. Over HTTP the
+	// stream is delivered as Server-Sent Events. If the scope ID is missing,
+	// malformed, or reference a non existing scope, an error is returned.
+	WatchSessions(ctx context.Context, in *WatchSessionsRequest, opts ...grpc.CallOption) (SessionService_WatchSessionsClient, error)
 }
 
 type sessionServiceClient struct {
@@ -68,6 +74,38 @@ func (c *sessionServiceClient) CancelSession(ctx context.Context, in *CancelSess
 	return out, nil
 }
 
+func (c *sessionServiceClient) WatchSessions(ctx context.Context, in *WatchSessionsRequest, opts ...grpc.CallOption) (SessionService_WatchSessionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SessionService_serviceDesc.Streams[0], "/controller.api.services.v1.SessionService/WatchSessions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sessionServiceWatchSessionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SessionService_WatchSessionsClient interface {
+	Recv() (*WatchSessionsResponse, error)
+	grpc.ClientStream
+}
+
+type sessionServiceWatchSessionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *sessionServiceWatchSessionsClient) Recv() (*WatchSessionsResponse, error) {
+	m := new(WatchSessionsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SessionServiceServer is the server API for SessionService service.
 type SessionServiceServer interface {
 	// GetSession returns a stored Session if present.  The provided request
@@ -84,6 +122,12 @@ type SessionServiceServer interface {
 	// is returned if the request attempts to cancel a Session that does
 	// not exist.
 	CancelSession(context.Context, *CancelSessionRequest) (*CancelSessionResponse, error)
+	// WatchSessions streams state changes of Sessions and their Connections
+	// which exist inside the scope referenced inside the request. The stream
+	// can additionally be filtered by user ID and target ID. Over HTTP the
+	// stream is delivered as Server-Sent Events. If the scope ID is missing,
+	// malformed, or reference a non existing scope, an error is returned.
+	WatchSessions(*WatchSessionsRequest, SessionService_WatchSessionsServer) error
 }
 
 // UnimplementedSessionServiceServer can be embedded to have forward compatible implementations.
@@ -99,6 +143,9 @@ func (*UnimplementedSessionServiceServer) ListSessions(context.Context, *ListSes
 func (*UnimplementedSessionServiceServer) CancelSession(context.Context, *CancelSessionRequest) (*CancelSessionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelSession not implemented")
 }
+func (*UnimplementedSessionServiceServer) WatchSessions(*WatchSessionsRequest, SessionService_WatchSessionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSessions not implemented")
+}
 
 func RegisterSessionServiceServer(s *grpc.Server, srv SessionServiceServer) {
 	s.RegisterService(&_SessionService_serviceDesc, srv)
@@ -158,6 +205,27 @@ func _SessionService_CancelSession_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SessionService_WatchSessions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSessionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SessionServiceServer).WatchSessions(m, &sessionServiceWatchSessionsServer{stream})
+}
+
+type SessionService_WatchSessionsServer interface {
+	Send(*WatchSessionsResponse) error
+	grpc.ServerStream
+}
+
+type sessionServiceWatchSessionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *sessionServiceWatchSessionsServer) Send(m *WatchSessionsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _SessionService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "controller.api.services.v1.SessionService",
 	HandlerType: (*SessionServiceServer)(nil),
@@ -175,6 +243,12 @@ var _SessionService_serviceDesc = grpc.ServiceDesc{
 			Handler:    _SessionService_CancelSession_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSessions",
+			Handler:       _SessionService_WatchSessions_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "controller/api/services/v1/session_service.proto",
 }
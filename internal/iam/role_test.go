@@ -545,6 +545,7 @@ func TestRole_Actions(t *testing.T) {
 	assert.Equal(a[action.AddPrincipals.String()], action.AddPrincipals)
 	assert.Equal(a[action.RemovePrincipals.String()], action.RemovePrincipals)
 	assert.Equal(a[action.SetPrincipals.String()], action.SetPrincipals)
+	assert.Equal(a[action.History.String()], action.History)
 }
 
 func TestRole_ResourceType(t *testing.T) {
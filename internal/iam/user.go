@@ -85,7 +85,9 @@ func (*User) ResourceType() resource.Type { return resource.User }
 
 // Actions returns the  available actions for Users
 func (*User) Actions() map[string]action.Type {
-	return CrudActions()
+	ret := CrudActions()
+	ret[action.History.String()] = action.History
+	return ret
 }
 
 // TableName returns the tablename to override the default gorm table name
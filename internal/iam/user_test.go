@@ -361,6 +361,7 @@ func TestUser_Actions(t *testing.T) {
 	assert.Equal(a[action.Update.String()], action.Update)
 	assert.Equal(a[action.Read.String()], action.Read)
 	assert.Equal(a[action.Delete.String()], action.Delete)
+	assert.Equal(a[action.History.String()], action.History)
 
 	if _, ok := a[action.List.String()]; ok {
 		t.Errorf("users should not include %s as an action", action.List.String())
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/boundary/internal/metrics"
 	"github.com/hashicorp/boundary/internal/types/scope"
 	"github.com/hashicorp/go-hclog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
@@ -43,6 +45,11 @@ func (e *ExternalWrappers) Recovery() wrapping.Wrapper {
 	return e.recovery
 }
 
+// wrapperCacheRefreshInterval is how long a cached multiwrapper is used before
+// it is reloaded from the database, so that key versions created by rotations
+// on other controllers become the encrypting keys here too.
+const wrapperCacheRefreshInterval = 5 * time.Minute
+
 // Kms is a way to access wrappers for a given scope and purpose. Since keys can
 // never change, only be added or (eventually) removed, it opportunistically
 // caches, going to the database as needed.
@@ -52,6 +59,9 @@ type Kms struct {
 	// scopePurposeCache holds a per-scope-purpose multiwrapper containing the
 	// current encrypting key and all previous key versions, for decryption
 	scopePurposeCache sync.Map
+	// scopePurposeLoadTimes holds when each entry of scopePurposeCache was
+	// loaded
+	scopePurposeLoadTimes sync.Map
 
 	externalScopeCache      map[string]*ExternalWrappers
 	externalScopeCacheMutex sync.RWMutex
@@ -161,16 +171,22 @@ func (k *Kms) GetWrapper(ctx context.Context, scopeId string, purpose KeyPurpose
 	opts := getOpts(opt...)
 	// Fast-path: we have a valid key at the scope/purpose. Verify the key with
 	// that ID is in the multiwrapper; if not, fall through to reload from the
-	// DB.
+	// DB. Entries are also periodically reloaded to pick up rotations.
 	val, ok := k.scopePurposeCache.Load(scopeId + purpose.String())
+	if loaded, found := k.scopePurposeLoadTimes.Load(scopeId + purpose.String()); found && time.Since(loaded.(time.Time)) > wrapperCacheRefreshInterval {
+		ok = false
+	}
 	if ok {
 		wrapper := val.(*multiwrapper.MultiWrapper)
 		if opts.withKeyId == "" || wrapper.WrapperForKeyID(opts.withKeyId) != nil {
+			metrics.IncKmsWrapperCache(purpose.String(), true)
 			return wrapper, nil
 		}
 		// Fall through to refresh our multiwrapper for this scope/purpose from the DB
 	}
 
+	metrics.IncKmsWrapperCache(purpose.String(), false)
+
 	// We don't have it cached, so we'll need to read from the database. Get the
 	// root for the scope as we'll need it to decrypt the value coming from the
 	// DB. We don't cache the roots as we expect that after a few calls the
@@ -188,10 +204,59 @@ func (k *Kms) GetWrapper(ctx context.Context, scopeId string, purpose KeyPurpose
 		return nil, fmt.Errorf("error loading %s for scope %s: %w", purpose.String(), scopeId, err)
 	}
 	k.scopePurposeCache.Store(scopeId+purpose.String(), wrapper)
+	k.scopePurposeLoadTimes.Store(scopeId+purpose.String(), time.Now())
 
 	return wrapper, nil
 }
 
+// RotateKeys creates new versions of the root key and DEKs of the scope, which
+// are used for all encryption in the scope from then on. Values encrypted with
+// previous versions of the database DEK are re-encrypted by Rewrap, and oplog
+// entries encrypted with previous versions of the oplog DEK by the oplog
+// inspect repository's RewrapEntries. Supports the WithRandomReader option.
+func (k *Kms) RotateKeys(ctx context.Context, scopeId string, opt ...Option) (Keys, error) {
+	rootWrapper := k.GetExternalWrappers().Root()
+	if rootWrapper == nil {
+		return nil, errors.New("rotate keys: no root wrapper configured")
+	}
+	keys, err := k.repo.RotateKeys(ctx, rootWrapper, scopeId, opt...)
+	if err != nil {
+		return nil, err
+	}
+	k.clearScopeCache(scopeId)
+	return keys, nil
+}
+
+// ListKeyVersionStatus returns the status of every version of the scope's root
+// key and DEKs.
+func (k *Kms) ListKeyVersionStatus(ctx context.Context, scopeId string) ([]*KeyVersionStatus, error) {
+	return k.repo.ListKeyVersionStatus(ctx, scopeId)
+}
+
+// LookupKeyVersionScope returns the id of the scope which owns the key version.
+func (k *Kms) LookupKeyVersionScope(ctx context.Context, keyVersionId string) (string, error) {
+	return k.repo.LookupKeyVersionScope(ctx, keyVersionId)
+}
+
+// DestroyKeyVersion deletes a version of the scope's root key or of one of its
+// DEKs which is no longer in use.
+func (k *Kms) DestroyKeyVersion(ctx context.Context, scopeId, keyVersionId string) error {
+	if err := k.repo.DestroyKeyVersion(ctx, scopeId, keyVersionId); err != nil {
+		return err
+	}
+	k.clearScopeCache(scopeId)
+	return nil
+}
+
+// clearScopeCache drops the cached wrappers of the scope so the next call to
+// GetWrapper reloads them from the database.
+func (k *Kms) clearScopeCache(scopeId string) {
+	for _, purpose := range dekPurposes {
+		k.scopePurposeCache.Delete(scopeId + purpose.String())
+		k.scopePurposeLoadTimes.Delete(scopeId + purpose.String())
+	}
+}
+
 func (k *Kms) loadRoot(ctx context.Context, scopeId string, opt ...Option) (*multiwrapper.MultiWrapper, string, error) {
 	opts := getOpts(opt...)
 	repo := opts.withRepository
@@ -1,6 +1,7 @@
 package kms
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,4 +28,12 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withLimit = 1
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithRandomReader", func(t *testing.T) {
+		assert := assert.New(t)
+		r := strings.NewReader("notrandom")
+		opts := getOpts(WithRandomReader(r))
+		testOpts := getDefaultOptions()
+		testOpts.withRandomReader = r
+		assert.Equal(opts, testOpts)
+	})
 }
@@ -1,6 +1,8 @@
 package kms
 
 import (
+	"io"
+
 	"github.com/hashicorp/go-hclog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 )
@@ -27,6 +29,7 @@ type options struct {
 	withRepository        *Repository
 	withOrder             string
 	withKeyId             string
+	withRandomReader      io.Reader
 }
 
 func getDefaultOptions() options {
@@ -93,3 +96,11 @@ func WithKeyId(keyId string) Option {
 		o.withKeyId = keyId
 	}
 }
+
+// WithRandomReader sets the source of randomness used when generating new
+// keys. If not set, crypto/rand.Reader is used.
+func WithRandomReader(r io.Reader) Option {
+	return func(o *options) {
+		o.withRandomReader = r
+	}
+}
@@ -0,0 +1,367 @@
+package kms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// KeyVersionRetention is how long a key version must have been superseded
+// before it can be destroyed. Controllers cache wrappers for up to
+// wrapperCacheRefreshInterval, so until then a controller may still encrypt
+// new values with the superseded version.
+var KeyVersionRetention = 2 * wrapperCacheRefreshInterval
+
+const (
+	// KeyVersionPurposeRoot is the purpose reported for root key versions in a
+	// KeyVersionStatus
+	KeyVersionPurposeRoot = "root"
+)
+
+// KeyVersionStatus describes a version of a scope's root key or of one of its
+// DEKs, and whether anything still depends on it.
+type KeyVersionStatus struct {
+	// Id is the private id of the key version
+	Id string
+	// Purpose is "root" for root key versions, otherwise the purpose of the
+	// DEK the version belongs to
+	Purpose    string
+	Version    uint32
+	CreateTime time.Time
+	// Current is true for the version used to encrypt new values
+	Current bool
+	// References is the number of items which still depend on the version.
+	// For root key versions it is the number of DEK versions encrypted by it.
+	// For database and oplog key versions it is the number of values and
+	// oplog entries still encrypted with it, which the rewrap job reduces to
+	// zero; oplog versions of the global scope also count the oplog chain
+	// heads signed with them. For session and token key versions it is the
+	// number of unterminated sessions and unexpired auth tokens created while
+	// the version was current.
+	References int
+	// Destroyable is true when the version can be destroyed
+	Destroyable bool
+	// NotDestroyableReason explains why the version can't be destroyed. It is
+	// empty for destroyable versions.
+	NotDestroyableReason string
+
+	rootKeyVersionId string
+	supersededTime   time.Time
+}
+
+const keyVersionsQuery = `
+select 'root' as purpose, v.private_id, v.version, v.create_time, '' as root_key_version_id
+  from kms_root_key_version v
+  join kms_root_key rk on rk.private_id = v.root_key_id
+ where rk.scope_id = $1
+union all
+select 'database', v.private_id, v.version, v.create_time, v.root_key_version_id
+  from kms_database_key_version v
+  join kms_database_key k on k.private_id = v.database_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where rk.scope_id = $1
+union all
+select 'oplog', v.private_id, v.version, v.create_time, v.root_key_version_id
+  from kms_oplog_key_version v
+  join kms_oplog_key k on k.private_id = v.oplog_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where rk.scope_id = $1
+union all
+select 'sessions', v.private_id, v.version, v.create_time, v.root_key_version_id
+  from kms_session_key_version v
+  join kms_session_key k on k.private_id = v.session_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where rk.scope_id = $1
+union all
+select 'tokens', v.private_id, v.version, v.create_time, v.root_key_version_id
+  from kms_token_key_version v
+  join kms_token_key k on k.private_id = v.token_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where rk.scope_id = $1
+order by 1, 3 desc;
+`
+
+const (
+	activeSessionsQuery = `
+select count(*)
+  from session s
+ where s.scope_id = $1
+   and s.create_time >= $2
+   and s.create_time < $3
+   and not exists (
+     select 1 from session_state ss
+      where ss.session_id = s.public_id
+        and ss.state = 'terminated'
+   );
+`
+	activeAuthTokensQuery = `
+select count(*)
+  from auth_token at
+  join auth_account aa on aa.public_id = at.auth_account_id
+ where aa.scope_id = $1
+   and at.create_time >= $2
+   and at.create_time < $3
+   and at.expiration_time > now();
+`
+)
+
+// ListKeyVersionStatus returns the status of every version of the scope's root
+// key and DEKs, ordered by purpose and then by version with the newest first.
+func (r *Repository) ListKeyVersionStatus(ctx context.Context, scopeId string, _ ...Option) ([]*KeyVersionStatus, error) {
+	if scopeId == "" {
+		return nil, fmt.Errorf("list key version status: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, keyVersionsQuery, []interface{}{scopeId})
+	if err != nil {
+		return nil, fmt.Errorf("list key version status: %w", err)
+	}
+	var versions []*KeyVersionStatus
+	for rows.Next() {
+		v := &KeyVersionStatus{}
+		if err := rows.Scan(&v.Purpose, &v.Id, &v.Version, &v.CreateTime, &v.rootKeyVersionId); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("list key version status: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list key version status: %w", err)
+	}
+
+	// Versions are ordered newest first within each purpose, so each version
+	// was superseded when the one before it was created.
+	for i, v := range versions {
+		if i == 0 || versions[i-1].Purpose != v.Purpose {
+			v.Current = true
+			continue
+		}
+		v.supersededTime = versions[i-1].CreateTime
+	}
+
+	for _, v := range versions {
+		if err := r.setReferences(ctx, scopeId, v, versions); err != nil {
+			return nil, fmt.Errorf("list key version status: %s: %w", v.Id, err)
+		}
+		v.NotDestroyableReason = notDestroyableReason(v)
+		v.Destroyable = v.NotDestroyableReason == ""
+	}
+	return versions, nil
+}
+
+// notDestroyableReason returns why the version can't be destroyed, or an
+// empty string if it can be.
+func notDestroyableReason(v *KeyVersionStatus) string {
+	switch {
+	case v.Current:
+		return fmt.Sprintf("it is the current %s key version", v.Purpose)
+	case v.References > 0:
+		return fmt.Sprintf("it has %d references", v.References)
+	case time.Since(v.supersededTime) <= KeyVersionRetention:
+		return fmt.Sprintf("it was superseded less than %s ago", KeyVersionRetention)
+	}
+	return ""
+}
+
+func (r *Repository) setReferences(ctx context.Context, scopeId string, v *KeyVersionStatus, all []*KeyVersionStatus) error {
+	switch v.Purpose {
+	case KeyVersionPurposeRoot:
+		v.References = 0
+		for _, dv := range all {
+			if dv.rootKeyVersionId == v.Id {
+				v.References++
+			}
+		}
+		return nil
+
+	case KeyPurposeDatabase.String():
+		v.References = 0
+		for _, t := range rewrapTargets {
+			// Values stored without a key id may use any version, so they
+			// count as references to all of them until they're rewrapped.
+			q := fmt.Sprintf(
+				"select count(*) from %s where %s and %s is not null and (%s is null or %s = $2)",
+				t.table, t.scopeCondition, t.ctColumn, t.keyIdColumn, t.keyIdColumn,
+			)
+			n, err := r.count(ctx, q, scopeId, v.Id)
+			if err != nil {
+				return err
+			}
+			v.References += n
+		}
+		return nil
+
+	case KeyPurposeOplog.String():
+		// Entries stored without a key id may use any version, so they count
+		// as references to all of them until the rewrap job sets their key id.
+		n, err := r.count(ctx, "select count(*) from oplog_entry where key_id is null or key_id = $1", v.Id)
+		if err != nil {
+			return err
+		}
+		v.References = n
+		if scopeId == scope.Global.String() {
+			n, err := r.count(ctx, "select count(*) from oplog_chain_head where key_id = $1", v.Id)
+			if err != nil {
+				return err
+			}
+			v.References += n
+		}
+		return nil
+
+	case KeyPurposeSessions.String(), KeyPurposeTokens.String():
+		if v.Current {
+			return nil
+		}
+		q := activeSessionsQuery
+		if v.Purpose == KeyPurposeTokens.String() {
+			q = activeAuthTokensQuery
+		}
+		n, err := r.count(ctx, q, scopeId, v.CreateTime, v.supersededTime)
+		if err != nil {
+			return err
+		}
+		v.References = n
+		return nil
+	}
+	return nil
+}
+
+func (r *Repository) count(ctx context.Context, q string, args ...interface{}) (int, error) {
+	rows, err := r.reader.Query(ctx, q, args)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var n sql.NullInt64
+	if rows.Next() {
+		if err := rows.Scan(&n); err != nil {
+			return 0, err
+		}
+	}
+	return int(n.Int64), rows.Err()
+}
+
+const keyVersionScopeQuery = `
+select rk.scope_id
+  from kms_root_key_version v
+  join kms_root_key rk on rk.private_id = v.root_key_id
+ where v.private_id = $1
+union all
+select rk.scope_id
+  from kms_database_key_version v
+  join kms_database_key k on k.private_id = v.database_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where v.private_id = $1
+union all
+select rk.scope_id
+  from kms_oplog_key_version v
+  join kms_oplog_key k on k.private_id = v.oplog_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where v.private_id = $1
+union all
+select rk.scope_id
+  from kms_session_key_version v
+  join kms_session_key k on k.private_id = v.session_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where v.private_id = $1
+union all
+select rk.scope_id
+  from kms_token_key_version v
+  join kms_token_key k on k.private_id = v.token_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where v.private_id = $1;
+`
+
+// LookupKeyVersionScope returns the id of the scope which owns the root key
+// or DEK version. If the version is not found db.ErrRecordNotFound is
+// returned. No options are currently supported.
+func (r *Repository) LookupKeyVersionScope(ctx context.Context, keyVersionId string, _ ...Option) (string, error) {
+	if keyVersionId == "" {
+		return "", fmt.Errorf("lookup key version scope: missing key version id: %w", db.ErrInvalidParameter)
+	}
+	rows, err := r.reader.Query(ctx, keyVersionScopeQuery, []interface{}{keyVersionId})
+	if err != nil {
+		return "", fmt.Errorf("lookup key version scope: %w", err)
+	}
+	defer rows.Close()
+	var scopeId string
+	if rows.Next() {
+		if err := rows.Scan(&scopeId); err != nil {
+			return "", fmt.Errorf("lookup key version scope: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("lookup key version scope: %w", err)
+	}
+	if scopeId == "" {
+		return "", fmt.Errorf("lookup key version scope: %s: %w", keyVersionId, db.ErrRecordNotFound)
+	}
+	return scopeId, nil
+}
+
+// DestroyKeyVersion deletes a version of the scope's root key or of one of its
+// DEKs. It fails with ErrKeyVersionInUse unless the status of the version
+// reports it as destroyable. No options are currently supported.
+func (r *Repository) DestroyKeyVersion(ctx context.Context, scopeId, keyVersionId string, _ ...Option) error {
+	if scopeId == "" {
+		return fmt.Errorf("destroy key version: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	if keyVersionId == "" {
+		return fmt.Errorf("destroy key version: missing key version id: %w", db.ErrInvalidParameter)
+	}
+	versions, err := r.ListKeyVersionStatus(ctx, scopeId)
+	if err != nil {
+		return fmt.Errorf("destroy key version: %w", err)
+	}
+	var found *KeyVersionStatus
+	for _, v := range versions {
+		if v.Id == keyVersionId {
+			found = v
+			break
+		}
+	}
+	switch {
+	case found == nil:
+		return fmt.Errorf("destroy key version: %s: %w", keyVersionId, db.ErrRecordNotFound)
+	case !found.Destroyable:
+		return fmt.Errorf("destroy key version: %s: %s: %w", keyVersionId, found.NotDestroyableReason, ErrKeyVersionInUse)
+	}
+
+	var table string
+	switch found.Purpose {
+	case KeyVersionPurposeRoot:
+		table = DefaultRootKeyVersionTableName
+	case KeyPurposeDatabase.String():
+		table = DefaultDatabaseKeyVersionTableName
+	case KeyPurposeOplog.String():
+		table = DefaultOplogKeyVersionTableName
+	case KeyPurposeSessions.String():
+		table = DefaultSessionKeyVersionTableName
+	case KeyPurposeTokens.String():
+		table = DefaultTokenKeyVersionTableName
+	}
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			// no oplog entries for key versions
+			rowsDeleted, err := w.Exec(ctx, fmt.Sprintf("delete from %s where private_id = $1", table), []interface{}{keyVersionId})
+			if err != nil {
+				return err
+			}
+			if rowsDeleted > 1 {
+				return db.ErrMultipleRecords
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("destroy key version: %s: %w", keyVersionId, err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package kms
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/aead"
+)
+
+// RotateKeys creates a new version of the root key and of every DEK in the
+// scope. The new versions become the encrypting keys returned by
+// Kms.GetWrapper; previous versions remain available for decryption until
+// they are destroyed. The new versions are returned in a Keys map. Supports
+// the WithRandomReader option.
+func (r *Repository) RotateKeys(ctx context.Context, rootWrapper wrapping.Wrapper, scopeId string, opt ...Option) (Keys, error) {
+	if rootWrapper == nil {
+		return nil, fmt.Errorf("rotate keys: missing root wrapper: %w", db.ErrInvalidParameter)
+	}
+	if scopeId == "" {
+		return nil, fmt.Errorf("rotate keys: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	randomReader := opts.withRandomReader
+	if randomReader == nil {
+		randomReader = rand.Reader
+	}
+
+	var keys Keys
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			keys = Keys{}
+			rk := AllocRootKey()
+			if err := reader.LookupWhere(ctx, &rk, "scope_id = ?", scopeId); err != nil {
+				return fmt.Errorf("unable to lookup root key: %w", err)
+			}
+
+			k, err := generateKey(randomReader)
+			if err != nil {
+				return err
+			}
+			rkv := AllocRootKeyVersion()
+			if rkv.PrivateId, err = newRootKeyVersionId(); err != nil {
+				return err
+			}
+			rkv.RootKeyId = rk.PrivateId
+			rkv.Key = k
+			if err := rkv.Encrypt(ctx, rootWrapper); err != nil {
+				return fmt.Errorf("unable to encrypt root key version: %w", err)
+			}
+			// no oplog entries for root key versions
+			if err := w.Create(ctx, &rkv); err != nil {
+				return fmt.Errorf("unable to create root key version: %w", err)
+			}
+			keys[KeyTypeRootKeyVersion] = &rkv
+
+			rkvWrapper, err := newAeadWrapper(rkv.PrivateId, rkv.Key)
+			if err != nil {
+				return err
+			}
+			for _, purpose := range dekPurposes {
+				k, err := generateKey(randomReader)
+				if err != nil {
+					return err
+				}
+				kv, err := createDekVersionTx(ctx, reader, w, rkvWrapper, rk.PrivateId, purpose, k)
+				if err != nil {
+					return err
+				}
+				keys[versionKeyType(purpose)] = kv
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("rotate keys: %w in %s", err, scopeId)
+	}
+	return keys, nil
+}
+
+// dekPurposes are the purposes with a DEK per scope.
+var dekPurposes = []KeyPurpose{
+	KeyPurposeDatabase,
+	KeyPurposeOplog,
+	KeyPurposeSessions,
+	KeyPurposeTokens,
+}
+
+func versionKeyType(purpose KeyPurpose) KeyType {
+	switch purpose {
+	case KeyPurposeDatabase:
+		return KeyTypeDatabaseKeyVersion
+	case KeyPurposeOplog:
+		return KeyTypeOplogKeyVersion
+	case KeyPurposeSessions:
+		return KeyTypeSessionKeyVersion
+	case KeyPurposeTokens:
+		return KeyTypeTokenKeyVersion
+	}
+	return KeyTypeUnknown
+}
+
+// createDekVersionTx creates a new version, encrypted by rkvWrapper, of the DEK
+// for purpose which belongs to the root key.
+func createDekVersionTx(ctx context.Context, r db.Reader, w db.Writer, rkvWrapper wrapping.Wrapper, rootKeyId string, purpose KeyPurpose, key []byte) (KeyIder, error) {
+	type dekVersion interface {
+		KeyIder
+		Encrypt(context.Context, wrapping.Wrapper) error
+	}
+
+	var kv dekVersion
+	switch purpose {
+	case KeyPurposeDatabase:
+		dk := AllocDatabaseKey()
+		if err := r.LookupWhere(ctx, &dk, "root_key_id = ?", rootKeyId); err != nil {
+			return nil, fmt.Errorf("unable to lookup database key: %w", err)
+		}
+		v := AllocDatabaseKeyVersion()
+		id, err := newDatabaseKeyVersionId()
+		if err != nil {
+			return nil, err
+		}
+		v.PrivateId, v.DatabaseKeyId, v.RootKeyVersionId, v.Key = id, dk.PrivateId, rkvWrapper.KeyID(), key
+		kv = &v
+	case KeyPurposeOplog:
+		dk := AllocOplogKey()
+		if err := r.LookupWhere(ctx, &dk, "root_key_id = ?", rootKeyId); err != nil {
+			return nil, fmt.Errorf("unable to lookup oplog key: %w", err)
+		}
+		v := AllocOplogKeyVersion()
+		id, err := newOplogKeyVersionId()
+		if err != nil {
+			return nil, err
+		}
+		v.PrivateId, v.OplogKeyId, v.RootKeyVersionId, v.Key = id, dk.PrivateId, rkvWrapper.KeyID(), key
+		kv = &v
+	case KeyPurposeSessions:
+		dk := AllocSessionKey()
+		if err := r.LookupWhere(ctx, &dk, "root_key_id = ?", rootKeyId); err != nil {
+			return nil, fmt.Errorf("unable to lookup session key: %w", err)
+		}
+		v := AllocSessionKeyVersion()
+		id, err := newSessionKeyVersionId()
+		if err != nil {
+			return nil, err
+		}
+		v.PrivateId, v.SessionKeyId, v.RootKeyVersionId, v.Key = id, dk.PrivateId, rkvWrapper.KeyID(), key
+		kv = &v
+	case KeyPurposeTokens:
+		dk := AllocTokenKey()
+		if err := r.LookupWhere(ctx, &dk, "root_key_id = ?", rootKeyId); err != nil {
+			return nil, fmt.Errorf("unable to lookup token key: %w", err)
+		}
+		v := AllocTokenKeyVersion()
+		id, err := newTokenKeyVersionId()
+		if err != nil {
+			return nil, err
+		}
+		v.PrivateId, v.TokenKeyId, v.RootKeyVersionId, v.Key = id, dk.PrivateId, rkvWrapper.KeyID(), key
+		kv = &v
+	default:
+		return nil, fmt.Errorf("unsupported purpose %q", purpose)
+	}
+
+	if err := kv.Encrypt(ctx, rkvWrapper); err != nil {
+		return nil, fmt.Errorf("unable to encrypt %s key version: %w", purpose, err)
+	}
+	// no oplog entries for key versions
+	if err := w.Create(ctx, kv); err != nil {
+		return nil, fmt.Errorf("unable to create %s key version: %w", purpose, err)
+	}
+	return kv, nil
+}
+
+func newAeadWrapper(keyId string, key []byte) (*aead.Wrapper, error) {
+	wrapper := aead.NewWrapper(nil)
+	if _, err := wrapper.SetConfig(map[string]string{
+		"key_id": keyId,
+	}); err != nil {
+		return nil, fmt.Errorf("error setting config on aead wrapper %s: %w", keyId, err)
+	}
+	if err := wrapper.SetAESGCMKeyBytes(key); err != nil {
+		return nil, fmt.Errorf("error setting key bytes on aead wrapper %s: %w", keyId, err)
+	}
+	return wrapper, nil
+}
+
+// ErrKeyVersionInUse is returned by DestroyKeyVersion when the key version is
+// still needed.
+var ErrKeyVersionInUse = errors.New("key version is still in use")
@@ -0,0 +1,87 @@
+package kms_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKms_RotateKeys(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	org, _ := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	kmsCache := kms.TestKms(t, conn, wrapper)
+
+	t.Run("missing-scope", func(t *testing.T) {
+		_, err := kmsCache.RotateKeys(ctx, "")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, db.ErrInvalidParameter))
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		before, err := kmsCache.GetWrapper(ctx, org.PublicId, kms.KeyPurposeDatabase)
+		require.NoError(err)
+		blob, err := before.Encrypt(ctx, []byte("secret"), nil)
+		require.NoError(err)
+
+		keys, err := kmsCache.RotateKeys(ctx, org.PublicId)
+		require.NoError(err)
+		for _, kt := range []kms.KeyType{
+			kms.KeyTypeRootKeyVersion,
+			kms.KeyTypeDatabaseKeyVersion,
+			kms.KeyTypeOplogKeyVersion,
+			kms.KeyTypeSessionKeyVersion,
+			kms.KeyTypeTokenKeyVersion,
+		} {
+			assert.NotNil(keys[kt], "missing %s", kt)
+		}
+
+		after, err := kmsCache.GetWrapper(ctx, org.PublicId, kms.KeyPurposeDatabase)
+		require.NoError(err)
+		assert.Equal(keys[kms.KeyTypeDatabaseKeyVersion].GetPrivateId(), after.KeyID())
+		assert.NotEqual(before.KeyID(), after.KeyID())
+
+		// values encrypted with the previous version can still be decrypted
+		pt, err := after.Decrypt(ctx, blob, nil)
+		require.NoError(err)
+		assert.Equal([]byte("secret"), pt)
+
+		versions, err := kmsCache.ListKeyVersionStatus(ctx, org.PublicId)
+		require.NoError(err)
+		assert.Len(versions, 10)
+		var current int
+		for _, v := range versions {
+			assert.Equal(!v.Destroyable, v.NotDestroyableReason != "")
+			if v.Current {
+				current++
+				assert.False(v.Destroyable)
+			}
+			// superseded versions are kept while controllers may still
+			// have them cached
+			assert.False(v.Destroyable)
+			if !v.Current {
+				err := kmsCache.DestroyKeyVersion(ctx, org.PublicId, v.Id)
+				require.Error(err)
+				assert.True(errors.Is(err, kms.ErrKeyVersionInUse))
+			}
+		}
+		assert.Equal(5, current)
+
+		err = kmsCache.DestroyKeyVersion(ctx, org.PublicId, after.KeyID())
+		require.Error(err)
+		assert.True(errors.Is(err, kms.ErrKeyVersionInUse))
+
+		scopeId, err := kmsCache.LookupKeyVersionScope(ctx, before.KeyID())
+		require.NoError(err)
+		assert.Equal(org.PublicId, scopeId)
+	})
+}
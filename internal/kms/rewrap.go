@@ -0,0 +1,203 @@
+package kms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+// rewrapTarget describes a column holding values encrypted with the database
+// DEK of a scope, along with the id of the key version used.
+type rewrapTarget struct {
+	table       string
+	idColumn    string
+	ctColumn    string
+	keyIdColumn string
+	// scopeCondition restricts rows to those encrypted by the scope passed as
+	// the first query argument
+	scopeCondition string
+}
+
+// rewrapTargets are the values encrypted with database key versions. The
+// values are written by structwrapping so each column holds a marshaled
+// wrapping.EncryptedBlobInfo without additional authenticated data. Oplog
+// entries are rewrapped by the oplog inspect repository, which also rehashes
+// their chains.
+var rewrapTargets = []rewrapTarget{
+	{
+		table:          "auth_token",
+		idColumn:       "public_id",
+		ctColumn:       "token",
+		keyIdColumn:    "key_id",
+		scopeCondition: "auth_account_id in (select public_id from auth_account where scope_id = $1)",
+	},
+	{
+		table:          "auth_password_argon2_cred",
+		idColumn:       "private_id",
+		ctColumn:       "salt",
+		keyIdColumn:    "key_id",
+		scopeCondition: "password_account_id in (select public_id from auth_password_account where scope_id = $1)",
+	},
+	{
+		table:          "session",
+		idColumn:       "public_id",
+		ctColumn:       "tofu_token",
+		keyIdColumn:    "key_id",
+		scopeCondition: "scope_id = $1",
+	},
+}
+
+const defaultRewrapBatchSize = 100
+
+// Rewrap re-encrypts values in the scope which were encrypted with a previous
+// version of the database DEK so that they use the current version, after
+// which the previous version may be destroyed. It returns the number of values
+// rewrapped. Values are processed in batches whose size can be set with
+// WithLimit; the call returns once no value in the scope needs rewrapping.
+//
+// Rewrap is safe to run concurrently from multiple controllers: a value is
+// only updated if it was not changed since it was read.
+func (k *Kms) Rewrap(ctx context.Context, scopeId string, opt ...Option) (int, error) {
+	if scopeId == "" {
+		return 0, fmt.Errorf("rewrap: missing scope id: %w", db.ErrInvalidParameter)
+	}
+	opts := getOpts(opt...)
+	batchSize := opts.withLimit
+	if batchSize <= 0 {
+		batchSize = defaultRewrapBatchSize
+	}
+
+	// Always start from the versions in the database: rewrapping with a stale
+	// cached wrapper would move values back to a previous version.
+	k.scopePurposeCache.Delete(scopeId + KeyPurposeDatabase.String())
+	wrapper, err := k.GetWrapper(ctx, scopeId, KeyPurposeDatabase)
+	if err != nil {
+		return 0, fmt.Errorf("rewrap: %w", err)
+	}
+	currentKeyId := wrapper.KeyID()
+
+	var total int
+	for _, t := range rewrapTargets {
+		for {
+			rewrapped, fetched, err := k.rewrapBatch(ctx, t, scopeId, currentKeyId, batchSize)
+			total += rewrapped
+			if err != nil {
+				return total, fmt.Errorf("rewrap: %s: %w", t.table, err)
+			}
+			// Stop when the target is exhausted, or if nothing in the batch
+			// could be rewrapped so a concurrent rotation can't keep us here.
+			if fetched < batchSize || rewrapped == 0 {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+type rewrapRow struct {
+	id string
+	ct []byte
+	// keyId is the stored key id, which is null for values written before
+	// the key id was recorded with them
+	keyId sql.NullString
+}
+
+// rewrapBatch rewraps up to batchSize values of the target, returning the
+// number rewrapped and the number which needed rewrapping when read.
+func (k *Kms) rewrapBatch(ctx context.Context, t rewrapTarget, scopeId, currentKeyId string, batchSize int) (rewrapped, fetched int, err error) {
+	q := fmt.Sprintf(
+		"select %s, %s, %s from %s where %s and %s is not null and (%s is null or %s <> $2) limit %d",
+		t.idColumn, t.ctColumn, t.keyIdColumn, t.table, t.scopeCondition, t.ctColumn, t.keyIdColumn, t.keyIdColumn, batchSize,
+	)
+	rows, err := k.repo.reader.Query(ctx, q, []interface{}{scopeId, currentKeyId})
+	if err != nil {
+		return 0, 0, err
+	}
+	var toRewrap []rewrapRow
+	for rows.Next() {
+		var row rewrapRow
+		if err := rows.Scan(&row.id, &row.ct, &row.keyId); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		toRewrap = append(toRewrap, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	for _, row := range toRewrap {
+		// Without a stored key id the wrapper decrypts with the version
+		// named by the value itself.
+		wrapper, err := k.GetWrapper(ctx, scopeId, KeyPurposeDatabase, WithKeyId(row.keyId.String))
+		if err != nil {
+			return rewrapped, len(toRewrap), err
+		}
+		ct, keyId, err := rewrapValue(ctx, wrapper, row.ct)
+		if err != nil {
+			return rewrapped, len(toRewrap), fmt.Errorf("%s %s: %w", t.idColumn, row.id, err)
+		}
+		u := fmt.Sprintf(
+			"update %s set %s = $1, %s = $2 where %s = $3 and %s is not distinct from $4",
+			t.table, t.ctColumn, t.keyIdColumn, t.idColumn, t.keyIdColumn,
+		)
+		var oldKeyId interface{}
+		if row.keyId.Valid {
+			oldKeyId = row.keyId.String
+		}
+		n, err := k.repo.writer.Exec(ctx, u, []interface{}{ct, keyId, row.id, oldKeyId})
+		if err != nil {
+			return rewrapped, len(toRewrap), err
+		}
+		// A count of zero means the row was changed or deleted since it
+		// was read, in which case it no longer needs rewrapping by us.
+		rewrapped += n
+	}
+	return rewrapped, len(toRewrap), nil
+}
+
+// rewrapValue decrypts a marshaled wrapping.EncryptedBlobInfo and encrypts the
+// plaintext with the wrapper's current encrypting key, returning the new
+// marshaled value and the id of the key used.
+func rewrapValue(ctx context.Context, wrapper wrapping.Wrapper, ct []byte) ([]byte, string, error) {
+	blob := new(wrapping.EncryptedBlobInfo)
+	if err := proto.Unmarshal(ct, blob); err != nil {
+		return nil, "", fmt.Errorf("unable to unmarshal encrypted value: %w", err)
+	}
+	pt, err := wrapper.Decrypt(ctx, blob, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to decrypt value: %w", err)
+	}
+	blob, err = wrapper.Encrypt(ctx, pt, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to encrypt value: %w", err)
+	}
+	newCt, err := proto.Marshal(blob)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to marshal encrypted value: %w", err)
+	}
+	return newCt, blob.GetKeyInfo().GetKeyID(), nil
+}
+
+// RewrapAll runs Rewrap for every scope with keys, returning the total number
+// of values rewrapped.
+func (k *Kms) RewrapAll(ctx context.Context, opt ...Option) (int, error) {
+	rootKeys, err := k.repo.ListRootKeys(ctx, WithLimit(-1))
+	if err != nil {
+		return 0, fmt.Errorf("rewrap all: %w", err)
+	}
+	var total int
+	for _, rk := range rootKeys {
+		n, err := k.Rewrap(ctx, rk.GetScopeId(), opt...)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("rewrap all: scope %s: %w", rk.GetScopeId(), err)
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,115 @@
+package kms_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth/password"
+	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKms_Rewrap is not parallel as it changes KeyVersionRetention.
+func TestKms_Rewrap(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+
+	// The session is in a project, while its auth token and the password
+	// credential are in the project's org.
+	composedOf := session.TestSessionParams(t, conn, wrapper, iamRepo)
+	prj, err := iamRepo.LookupScope(ctx, composedOf.ScopeId)
+	require.NoError(err)
+	orgId := prj.GetParentId()
+
+	sessionRepo, err := session.NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	sess := session.TestSession(t, conn, wrapper, composedOf)
+	worker := session.TestWorker(t, conn, wrapper)
+	tofu := session.TestTofu(t)
+	_, _, err = sessionRepo.ActivateSession(ctx, sess.PublicId, sess.Version, worker.PrivateId, worker.Type, tofu)
+	require.NoError(err)
+
+	at := authtoken.TestAuthToken(t, conn, kmsCache, orgId)
+
+	passwordRepo, err := password.NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	authMethod := password.TestAuthMethods(t, conn, orgId, 1)[0]
+	acct := password.TestAccounts(t, conn, authMethod.GetPublicId(), 1)[0]
+	_, err = passwordRepo.SetPassword(ctx, orgId, acct.GetPublicId(), "rewrap-password", acct.GetVersion())
+	require.NoError(err)
+
+	oldVersions := make(map[string]string)
+	for _, scopeId := range []string{orgId, prj.GetPublicId()} {
+		w, err := kmsCache.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase)
+		require.NoError(err)
+		oldVersions[scopeId] = w.KeyID()
+		_, err = kmsCache.RotateKeys(ctx, scopeId)
+		require.NoError(err)
+	}
+
+	databaseVersion := func(scopeId, id string) *kms.KeyVersionStatus {
+		versions, err := kmsCache.ListKeyVersionStatus(ctx, scopeId)
+		require.NoError(err)
+		for _, v := range versions {
+			if v.Id == id {
+				return v
+			}
+		}
+		require.FailNow("key version not found", id)
+		return nil
+	}
+
+	// the auth tokens of the org and the credential; the session's tofu token
+	wantReferences := map[string]int{orgId: 3, prj.GetPublicId(): 1}
+	for scopeId, id := range oldVersions {
+		v := databaseVersion(scopeId, id)
+		assert.Equal(wantReferences[scopeId], v.References, scopeId)
+		err := kmsCache.DestroyKeyVersion(ctx, scopeId, id)
+		require.Error(err)
+		assert.True(errors.Is(err, kms.ErrKeyVersionInUse))
+	}
+
+	for scopeId := range oldVersions {
+		n, err := kmsCache.Rewrap(ctx, scopeId, kms.WithLimit(1))
+		require.NoError(err)
+		assert.Equal(wantReferences[scopeId], n, scopeId)
+	}
+
+	defer func(retention time.Duration) { kms.KeyVersionRetention = retention }(kms.KeyVersionRetention)
+	kms.KeyVersionRetention = 0
+	for scopeId, id := range oldVersions {
+		v := databaseVersion(scopeId, id)
+		assert.Equal(0, v.References, scopeId)
+		assert.True(v.Destroyable, v.NotDestroyableReason)
+		require.NoError(kmsCache.DestroyKeyVersion(ctx, scopeId, id))
+	}
+
+	// everything can still be decrypted without the destroyed versions
+	for scopeId := range oldVersions {
+		kmsCache.GetScopePurposeCache().Delete(scopeId + kms.KeyPurposeDatabase.String())
+	}
+	authTokenRepo, err := authtoken.NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	gotToken, err := authTokenRepo.ValidateToken(ctx, at.GetPublicId(), at.GetToken())
+	require.NoError(err)
+	assert.NotNil(gotToken)
+
+	_, err = passwordRepo.Authenticate(ctx, orgId, authMethod.GetPublicId(), acct.GetLoginName(), "rewrap-password")
+	require.NoError(err)
+
+	gotSession, _, err := sessionRepo.LookupSession(ctx, sess.PublicId)
+	require.NoError(err)
+	assert.Equal(tofu, gotSession.TofuToken)
+}
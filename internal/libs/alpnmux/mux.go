@@ -52,16 +52,20 @@ type ALPNMux struct {
 	log    hclog.Logger
 	cancel context.CancelFunc
 	muxMap *sync.Map
+	// regLock serializes registering and unregistering protos, so that a
+	// listener being closed never unregisters its replacement.
+	regLock *sync.Mutex
 }
 
 func New(baseLn net.Listener, log hclog.Logger) *ALPNMux {
 	ctx, cancel := context.WithCancel(context.Background())
 	ret := &ALPNMux{
-		ctx:    ctx,
-		log:    log,
-		cancel: cancel,
-		muxMap: new(sync.Map),
-		baseLn: baseLn,
+		ctx:     ctx,
+		log:     log,
+		cancel:  cancel,
+		muxMap:  new(sync.Map),
+		baseLn:  baseLn,
+		regLock: new(sync.Mutex),
 	}
 	go ret.accept()
 	return ret
@@ -99,14 +103,16 @@ func (l *ALPNMux) RegisterProto(proto string, tlsConf *tls.Config) (net.Listener
 		connCh:    make(chan net.Conn),
 		closeOnce: new(sync.Once),
 	}
+	l.regLock.Lock()
 	_, loaded := l.muxMap.LoadOrStore(proto, sub)
+	l.regLock.Unlock()
 	if loaded {
 		close(sub.connCh)
 		return nil, fmt.Errorf("proto %q already registered", proto)
 	}
 
 	sub.closeFunc = func() {
-		go l.UnregisterProto(proto)
+		go l.unregister(proto, sub)
 	}
 
 	if l.log != nil && l.log.IsDebug() {
@@ -121,13 +127,24 @@ func (l *ALPNMux) UnregisterProto(proto string) {
 	if !ok {
 		return
 	}
-	ml := val.(*muxedListener)
+	l.unregister(proto, val.(*muxedListener))
+}
+
+// unregister closes the listener and unregisters it if it is still the one
+// registered for the proto; the proto may have been registered again since.
+func (l *ALPNMux) unregister(proto string, ml *muxedListener) {
 	ml.closeOnce.Do(func() {
 		ml.connMutex.Lock()
 		defer ml.connMutex.Unlock()
 		ml.closed = true
 		close(ml.connCh)
 	})
+	l.regLock.Lock()
+	defer l.regLock.Unlock()
+	val, ok := l.muxMap.Load(proto)
+	if !ok || val.(*muxedListener) != ml {
+		return
+	}
 	l.muxMap.Delete(proto)
 	if l.log != nil && l.log.IsDebug() {
 		l.log.Debug("unregistered", "proto", proto)
@@ -191,3 +191,28 @@ func TestListening(t *testing.T) {
 		t.Fatal("wrong number of conns")
 	}
 }
+
+func TestReregistration(t *testing.T) {
+	listener := getListener(t)
+	defer listener.Close()
+	mux := New(listener, nil)
+	p1config := getTestTLS(t, []string{"p1"})
+
+	l1, err := mux.RegisterProto("p1", p1config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace the registration, then close the replaced listener as a server
+	// using it would; the replacement must stay registered
+	mux.UnregisterProto("p1")
+	l2, err := mux.RegisterProto("p1", p1config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l1.Close()
+	time.Sleep(100 * time.Millisecond)
+	if got := mux.GetListener("p1"); got != l2 {
+		t.Fatal("replacement listener was unregistered")
+	}
+	l2.Close()
+}
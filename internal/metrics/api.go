@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var apiRequestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystemController,
+		Name:      "api_request_duration_seconds",
+		Help:      "Histogram of latencies for requests made to the controller API, partitioned by method, path and status code.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"method", "path", "code"},
+)
+
+func init() {
+	mustRegister(apiRequestDuration)
+}
+
+// idSegment matches public ids such as "ttcp_1234567890" so they can be
+// replaced in request paths to keep label cardinality bounded.
+var idSegment = regexp.MustCompile(`^[a-z]+_[0-9A-Za-z]+$`)
+
+// PathTemplate returns the request path with any resource ids replaced by
+// "{id}", e.g. "/v1/sessions/s_1234567890:cancel" becomes
+// "/v1/sessions/{id}:cancel".
+func PathTemplate(p string) string {
+	segs := strings.Split(p, "/")
+	for i, seg := range segs {
+		id, verb := seg, ""
+		if idx := strings.Index(seg, ":"); idx != -1 {
+			id, verb = seg[:idx], seg[idx:]
+		}
+		if idSegment.MatchString(id) {
+			segs[i] = "{id}" + verb
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// InstrumentApiHandler wraps the controller API handler, recording the
+// latency of each request.
+func InstrumentApiHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		apiRequestDuration.WithLabelValues(
+			r.Method,
+			PathTemplate(r.URL.Path),
+			strconv.Itoa(sw.status),
+		).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusRecorder captures the status code written by a handler. It passes
+// through flushes so streaming responses keep working.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status = code
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
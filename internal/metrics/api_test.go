@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathTemplate(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/v1/scopes", want: "/v1/scopes"},
+		{path: "/v1/scopes/global", want: "/v1/scopes/global"},
+		{path: "/v1/targets/ttcp_1234567890", want: "/v1/targets/{id}"},
+		{path: "/v1/sessions/s_1234567890:cancel", want: "/v1/sessions/{id}:cancel"},
+		{path: "/v1/sessions:watch", want: "/v1/sessions:watch"},
+		{path: "/v1/auth-methods/ampw_1234567890:authenticate", want: "/v1/auth-methods/{id}:authenticate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, PathTemplate(tt.path))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var dbTransactionRetries = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemDb,
+		Name:      "transaction_retries_total",
+		Help:      "Number of times a database transaction was retried.",
+	},
+)
+
+func init() {
+	mustRegister(dbTransactionRetries)
+}
+
+// IncDbTransactionRetries records that a transaction is being retried.
+func IncDbTransactionRetries() {
+	dbTransactionRetries.Inc()
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var kmsWrapperCacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemKms,
+		Name:      "wrapper_cache_requests_total",
+		Help:      "Number of wrapper lookups made against the KMS cache, partitioned by key purpose and whether the wrapper was cached.",
+	},
+	[]string{"purpose", "result"},
+)
+
+func init() {
+	mustRegister(kmsWrapperCacheRequests)
+}
+
+// IncKmsWrapperCache records a KMS wrapper cache lookup for the given purpose.
+func IncKmsWrapperCache(purpose string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	kmsWrapperCacheRequests.WithLabelValues(purpose, result).Inc()
+}
@@ -0,0 +1,32 @@
+// Package metrics defines the Prometheus metrics exposed by Boundary
+// controllers and workers and the helpers used to record them. All metrics are
+// registered with the default Prometheus registry, which is also where the
+// go-metrics Prometheus sink configured by the telemetry stanza registers, so
+// a single handler serves both.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "boundary"
+
+	subsystemController = "controller"
+	subsystemWorker     = "worker"
+	subsystemDb         = "db"
+	subsystemKms        = "kms"
+)
+
+// Handler returns an http.Handler which serves all registered metrics in the
+// Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func mustRegister(cs ...prometheus.Collector) {
+	prometheus.MustRegister(cs...)
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Proxy directions used to label byte counts.
+const (
+	DirectionUpstream   = "upstream"
+	DirectionDownstream = "downstream"
+)
+
+var (
+	workerActiveSessions = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystemWorker,
+			Name:      "active_sessions",
+			Help:      "Number of sessions with open connections through the worker.",
+		},
+	)
+
+	workerActiveConnections = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystemWorker,
+			Name:      "active_connections",
+			Help:      "Number of connections the worker is currently proxying.",
+		},
+	)
+
+	workerProxyBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystemWorker,
+			Name:      "proxy_bytes_total",
+			Help:      "Number of bytes proxied by the worker, partitioned by direction.",
+		},
+		[]string{"direction"},
+	)
+
+	workerProxyThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystemWorker,
+			Name:      "proxy_throttled_seconds_total",
+			Help:      "Time the worker held back proxied traffic to keep to bandwidth limits, partitioned by direction.",
+		},
+		[]string{"direction"},
+	)
+
+	workerStatusDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystemWorker,
+			Name:      "status_duration_seconds",
+			Help:      "Histogram of latencies for status requests made by the worker to a controller.",
+			Buckets:   prometheus.DefBuckets,
+		},
+	)
+
+	workerStatusFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystemWorker,
+			Name:      "status_failures_total",
+			Help:      "Number of status requests made by the worker to a controller which failed.",
+		},
+	)
+)
+
+func init() {
+	mustRegister(
+		workerActiveSessions,
+		workerActiveConnections,
+		workerProxyBytes,
+		workerProxyThrottled,
+		workerStatusDuration,
+		workerStatusFailures,
+	)
+}
+
+// SetWorkerActive records the number of sessions and connections the worker
+// is currently handling.
+func SetWorkerActive(sessions, connections int) {
+	workerActiveSessions.Set(float64(sessions))
+	workerActiveConnections.Set(float64(connections))
+}
+
+// AddProxyBytes records bytes proxied in the given direction.
+func AddProxyBytes(direction string, n int64) {
+	if n <= 0 {
+		return
+	}
+	workerProxyBytes.WithLabelValues(direction).Add(float64(n))
+}
+
+// ProxyBytesWriter returns a writer recording the bytes written to w as
+// proxied in the given direction as they flow, so traffic of long-lived
+// connections is accounted for before they are closed.
+func ProxyBytesWriter(direction string, w io.Writer) io.Writer {
+	return &proxyBytesWriter{
+		w:       w,
+		counter: workerProxyBytes.WithLabelValues(direction),
+	}
+}
+
+type proxyBytesWriter struct {
+	w       io.Writer
+	counter prometheus.Counter
+}
+
+func (w *proxyBytesWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if n > 0 {
+		w.counter.Add(float64(n))
+	}
+	return n, err
+}
+
+// AddProxyThrottled records time proxied traffic in the given direction was
+// held back for.
+func AddProxyThrottled(direction string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	workerProxyThrottled.WithLabelValues(direction).Add(d.Seconds())
+}
+
+// ObserveWorkerStatus records the latency and outcome of a status request.
+func ObserveWorkerStatus(start time.Time, err error) {
+	workerStatusDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		workerStatusFailures.Inc()
+	}
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyBytesWriter(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	counter := workerProxyBytes.WithLabelValues(DirectionUpstream)
+	before := testutil.ToFloat64(counter)
+
+	var buf bytes.Buffer
+	w := ProxyBytesWriter(DirectionUpstream, &buf)
+	n, err := w.Write([]byte("hello"))
+	require.NoError(err)
+	assert.Equal(5, n)
+	// Bytes are counted as they are written, not when the copy ends
+	assert.Equal(before+5, testutil.ToFloat64(counter))
+
+	_, err = w.Write([]byte(" world"))
+	require.NoError(err)
+	assert.Equal(before+11, testutil.ToFloat64(counter))
+	assert.Equal("hello world", buf.String())
+}
@@ -0,0 +1,110 @@
+package oplog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/jinzhu/gorm"
+)
+
+// chainHashPrefix versions the encoding hashed by ComputeHash
+const chainHashPrefix = "boundary-oplog-chain-v1"
+
+// ComputeHash returns the hash of the entry. The hash covers the entry's
+// encrypted data, aggregate name, version, metadata, the ticket version it
+// was written with and the hash of the previous entry written with the same
+// ticket, so that entries written with a ticket form a hash chain. Changing
+// or removing an entry breaks the chain at that point, which can be detected
+// without decrypting any entries.
+func (e *Entry) ComputeHash() []byte {
+	h := sha256.New()
+	writeField := func(b []byte) {
+		var l [8]byte
+		binary.BigEndian.PutUint64(l[:], uint64(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+	writeField([]byte(chainHashPrefix))
+	writeField([]byte(e.GetTicketName()))
+	var v [4]byte
+	binary.BigEndian.PutUint32(v[:], e.GetTicketVersion())
+	writeField(v[:])
+	writeField([]byte(e.GetAggregateName()))
+	writeField([]byte(e.GetVersion()))
+
+	md := make([]*store.Metadata, len(e.GetMetadata()))
+	copy(md, e.GetMetadata())
+	sort.Slice(md, func(i, j int) bool {
+		if md[i].GetKey() != md[j].GetKey() {
+			return md[i].GetKey() < md[j].GetKey()
+		}
+		return md[i].GetValue() < md[j].GetValue()
+	})
+	var mdCount [8]byte
+	binary.BigEndian.PutUint64(mdCount[:], uint64(len(md)))
+	h.Write(mdCount[:])
+	for _, m := range md {
+		writeField([]byte(m.GetKey()))
+		writeField([]byte(m.GetValue()))
+	}
+
+	writeField(e.GetCtData())
+	writeField(e.GetPrevHash())
+	return h.Sum(nil)
+}
+
+// VerifyHash returns an error if the hash stored with the entry doesn't match
+// the hash computed from its contents.
+func (e *Entry) VerifyHash() error {
+	if len(e.GetHash()) == 0 {
+		return errors.New("entry has no hash")
+	}
+	if !bytes.Equal(e.GetHash(), e.ComputeHash()) {
+		return errors.New("entry hash does not match its contents")
+	}
+	return nil
+}
+
+// chain links the entry to the previous entry written with the ticket version
+// and sets its hash. It must be called after the entry data is encrypted and
+// after the ticket is redeemed; redeeming the ticket serializes writers, so
+// the previous entry has always been committed by the time its hash is read.
+func (e *Entry) chain(tx Writer, ticketName string, ticketVersion uint32) error {
+	e.TicketName = ticketName
+	e.TicketVersion = ticketVersion
+	prev, err := tx.entryHash(e.TicketName, e.TicketVersion-1)
+	if err != nil {
+		return fmt.Errorf("error reading previous entry hash: %w", err)
+	}
+	e.PrevHash = prev
+	e.Hash = e.ComputeHash()
+	return nil
+}
+
+// entryHash returns the hash of the entry written with the ticket version, or
+// nil if there is no such entry.
+func (w *GormWriter) entryHash(ticketName string, ticketVersion uint32) ([]byte, error) {
+	if w.Tx == nil {
+		return nil, errors.New("entry hash Tx is nil")
+	}
+	if ticketVersion == 0 {
+		return nil, nil
+	}
+	var prev store.Entry
+	err := w.Tx.Select("hash").
+		Where("ticket_name = ? and ticket_version = ?", ticketName, ticketVersion).
+		Order("id desc").
+		First(&prev).Error
+	switch {
+	case gorm.IsRecordNotFoundError(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return prev.Hash, nil
+}
@@ -0,0 +1,119 @@
+package oplog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/oplog/oplog_test"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ComputeHash(t *testing.T) {
+	newEntry := func() *Entry {
+		return &Entry{
+			Entry: &store.Entry{
+				Version:       "v1",
+				AggregateName: "test-users",
+				TicketName:    "test-users",
+				TicketVersion: 2,
+				CtData:        []byte("ciphertext"),
+				PrevHash:      []byte("previous"),
+				Metadata: []*store.Metadata{
+					{Key: "scope-id", Value: "o_1234567890"},
+					{Key: "op-type", Value: "create"},
+				},
+			},
+		}
+	}
+	base := newEntry().ComputeHash()
+	require.Len(t, base, 32)
+
+	tests := []struct {
+		name   string
+		modify func(e *Entry)
+		equal  bool
+	}{
+		{name: "unchanged", modify: func(e *Entry) {}, equal: true},
+		{
+			name: "metadata-order",
+			modify: func(e *Entry) {
+				e.Metadata[0], e.Metadata[1] = e.Metadata[1], e.Metadata[0]
+			},
+			equal: true,
+		},
+		{name: "ciphertext", modify: func(e *Entry) { e.CtData = []byte("other") }},
+		{name: "prev-hash", modify: func(e *Entry) { e.PrevHash = nil }},
+		{name: "ticket-version", modify: func(e *Entry) { e.TicketVersion = 3 }},
+		{name: "ticket-name", modify: func(e *Entry) { e.TicketName = "other" }},
+		{name: "aggregate-name", modify: func(e *Entry) { e.AggregateName = "other" }},
+		{name: "version", modify: func(e *Entry) { e.Version = "v2" }},
+		{name: "metadata-value", modify: func(e *Entry) { e.Metadata[0].Value = "o_0987654321" }},
+		{name: "metadata-removed", modify: func(e *Entry) { e.Metadata = e.Metadata[:1] }},
+		{
+			name: "fields-shifted",
+			modify: func(e *Entry) {
+				e.AggregateName, e.Version = "test-usersv", "1"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			e := newEntry()
+			tt.modify(e)
+			if tt.equal {
+				assert.Equal(base, e.ComputeHash())
+			} else {
+				assert.NotEqual(base, e.ComputeHash())
+			}
+		})
+	}
+}
+
+func Test_VerifyHash(t *testing.T) {
+	assert := assert.New(t)
+	e := &Entry{Entry: &store.Entry{AggregateName: "test-users", CtData: []byte("ciphertext")}}
+	assert.Error(e.VerifyHash())
+	e.Hash = e.ComputeHash()
+	assert.NoError(e.VerifyHash())
+	e.CtData = []byte("tampered")
+	assert.Error(e.VerifyHash())
+}
+
+func Test_WriteEntryWithChain(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	cleanup, db := setup(t)
+	defer testCleanup(t, cleanup, db)
+	cipherer := testWrapper(t)
+
+	ticketer, err := NewGormTicketer(db, WithAggregateNames(true))
+	require.NoError(err)
+
+	var entries []*Entry
+	for i := 0; i < 3; i++ {
+		ticket, err := ticketer.GetTicket("default")
+		require.NoError(err)
+		u := oplog_test.TestUser{Name: "foo-" + testId(t)}
+		e, err := NewEntry("test-users", Metadata{"deployment": []string{"amex"}}, cipherer, ticketer)
+		require.NoError(err)
+		err = e.WriteEntryWith(context.Background(), &GormWriter{db}, ticket,
+			&Message{Message: &u, TypeName: "user", OpType: OpType_OP_TYPE_CREATE})
+		require.NoError(err)
+		entries = append(entries, e)
+	}
+
+	for i, e := range entries {
+		var found Entry
+		require.NoError(db.Where("id = ?", e.Id).First(&found).Error)
+		assert.Equal("default", found.TicketName)
+		assert.Equal(e.TicketVersion, found.TicketVersion)
+		assert.Equal(e.Hash, found.Hash)
+		assert.Equal(cipherer.KeyID(), found.KeyId)
+		if i > 0 {
+			assert.Equal(entries[i-1].Hash, found.PrevHash)
+			assert.Equal(entries[i-1].TicketVersion+1, found.TicketVersion)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package inspect
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth/password"
+	pwstore "github.com/hashicorp/boundary/internal/auth/password/store"
+	authstore "github.com/hashicorp/boundary/internal/auth/store"
+	"github.com/hashicorp/boundary/internal/host/static"
+	staticstore "github.com/hashicorp/boundary/internal/host/static/store"
+	"github.com/hashicorp/boundary/internal/iam"
+	iamstore "github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/target"
+	targetstore "github.com/hashicorp/boundary/internal/target/store"
+)
+
+// NewTypeCatalog returns a catalog of every type written to the oplog. Entries
+// identify their messages by the table name of the resource, so each table
+// name maps to the underlying store message which the resource marshals as.
+func NewTypeCatalog() (*oplog.TypeCatalog, error) {
+	types, err := oplog.NewTypeCatalog(
+		// iam
+		oplog.Type{Interface: new(iamstore.Scope), Name: new(iam.Scope).TableName()},
+		oplog.Type{Interface: new(iamstore.User), Name: new(iam.User).TableName()},
+		oplog.Type{Interface: new(iamstore.Group), Name: new(iam.Group).TableName()},
+		oplog.Type{Interface: new(iamstore.GroupMemberUser), Name: new(iam.GroupMemberUser).TableName()},
+		oplog.Type{Interface: new(iamstore.Role), Name: new(iam.Role).TableName()},
+		oplog.Type{Interface: new(iamstore.RoleGrant), Name: new(iam.RoleGrant).TableName()},
+		oplog.Type{Interface: new(iamstore.UserRole), Name: new(iam.UserRole).TableName()},
+		oplog.Type{Interface: new(iamstore.GroupRole), Name: new(iam.GroupRole).TableName()},
+		// Written by iam when associating accounts with users, which happens
+		// on a user's first login. iam's account type is unexported.
+		oplog.Type{Interface: new(authstore.Account), Name: "auth_account"},
+
+		// password auth method
+		oplog.Type{Interface: new(pwstore.AuthMethod), Name: new(password.AuthMethod).TableName()},
+		oplog.Type{Interface: new(pwstore.Account), Name: new(password.Account).TableName()},
+		oplog.Type{Interface: new(pwstore.Credential), Name: new(password.Credential).TableName()},
+		oplog.Type{Interface: new(pwstore.Argon2Configuration), Name: new(password.Argon2Configuration).TableName()},
+		oplog.Type{Interface: new(pwstore.Argon2Credential), Name: new(password.Argon2Credential).TableName()},
+
+		// static hosts
+		oplog.Type{Interface: new(staticstore.HostCatalog), Name: new(static.HostCatalog).TableName()},
+		oplog.Type{Interface: new(staticstore.Host), Name: new(static.Host).TableName()},
+		oplog.Type{Interface: new(staticstore.HostSet), Name: new(static.HostSet).TableName()},
+		oplog.Type{Interface: new(staticstore.HostSetMember), Name: new(static.HostSetMember).TableName()},
+
+		// targets
+		oplog.Type{Interface: new(targetstore.TcpTarget), Name: new(target.TcpTarget).TableName()},
+		oplog.Type{Interface: new(targetstore.UdpTarget), Name: new(target.UdpTarget).TableName()},
+		oplog.Type{Interface: new(targetstore.TargetHostSet), Name: new(target.TargetHostSet).TableName()},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new type catalog: %w", err)
+	}
+	return types, nil
+}
@@ -0,0 +1,95 @@
+package inspect
+
+import (
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewTypeCatalog(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	types, err := NewTypeCatalog()
+	require.NoError(err)
+
+	for _, name := range []string{
+		"iam_scope",
+		"iam_user",
+		"iam_role_grant",
+		"auth_account",
+		"auth_password_account",
+		"static_host_set_member",
+		"target_tcp",
+		"target_udp",
+	} {
+		i, err := types.Get(name)
+		require.NoError(err, name)
+		_, ok := i.(proto.Message)
+		assert.True(ok, "%s is not a proto message", name)
+	}
+
+	// Resources are written to the oplog as their store message, so a
+	// resource must round trip through the catalog.
+	user, err := iam.NewUser("o_1234567890", iam.WithName("alice"))
+	require.NoError(err)
+	user.PublicId = "u_1234567890"
+	queue := oplog.Queue{Catalog: types}
+	require.NoError(queue.Add(user, user.TableName(), oplog.OpType_OP_TYPE_CREATE))
+
+	m, typ, _, _, err := queue.Remove()
+	require.NoError(err)
+	assert.Equal(oplog.OpType_OP_TYPE_CREATE, typ)
+	got, ok := m.(*store.User)
+	require.True(ok)
+	assert.Equal("u_1234567890", got.GetPublicId())
+	assert.Equal("alice", got.GetName())
+}
+
+func Test_parseMetadata(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		in      string
+		want    oplog.Metadata
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			want: oplog.Metadata{},
+		},
+		{
+			name: "values",
+			in:   `[["scope-id", "o_1"], ["resource-public-id", "u_1"], ["scope-id", "o_2"]]`,
+			want: oplog.Metadata{
+				"scope-id":           {"o_1", "o_2"},
+				"resource-public-id": {"u_1"},
+			},
+		},
+		{
+			name: "null-value",
+			in:   `[["op-type", null]]`,
+			want: oplog.Metadata{"op-type": nil},
+		},
+		{
+			name:    "invalid",
+			in:      `{"scope-id": "o_1"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMetadata([]byte(tt.in))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
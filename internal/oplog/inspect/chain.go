@@ -0,0 +1,447 @@
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+// ChainProblemKind identifies a problem found while verifying a chain.
+type ChainProblemKind string
+
+const (
+	// ChainProblemGap means entries for one or more ticket versions are
+	// missing from the chain.
+	ChainProblemGap ChainProblemKind = "gap"
+
+	// ChainProblemDuplicate means more than one entry was found for a ticket
+	// version.
+	ChainProblemDuplicate ChainProblemKind = "duplicate"
+
+	// ChainProblemHashMismatch means the hash stored with an entry does not
+	// match its contents.
+	ChainProblemHashMismatch ChainProblemKind = "hash-mismatch"
+
+	// ChainProblemPrevHashMismatch means an entry does not link to the hash of
+	// the entry before it.
+	ChainProblemPrevHashMismatch ChainProblemKind = "prev-hash-mismatch"
+
+	// ChainProblemHeadMismatch means a signed chain head does not match the
+	// entry with its ticket version.
+	ChainProblemHeadMismatch ChainProblemKind = "head-mismatch"
+
+	// ChainProblemHeadSignature means the signature of a chain head is not
+	// valid.
+	ChainProblemHeadSignature ChainProblemKind = "head-signature"
+
+	// ChainProblemTruncated means a signed chain head is newer than the last
+	// entry of its chain, so entries were removed from the end of the chain.
+	ChainProblemTruncated ChainProblemKind = "truncated"
+)
+
+// ChainProblem is a problem found while verifying a chain.
+type ChainProblem struct {
+	Kind          ChainProblemKind
+	TicketVersion uint32
+	// EntryId is the id of the entry with the problem, if there is one
+	EntryId uint32
+	Detail  string
+}
+
+// Chain is the result of verifying the entries written with a ticket.
+type Chain struct {
+	TicketName   string
+	Entries      int
+	FirstVersion uint32
+	LastVersion  uint32
+	// SignedVersion is the newest ticket version with a valid signed head
+	SignedVersion uint32
+	Problems      []*ChainProblem
+}
+
+// ChainVerification is the result of verifying the oplog hash chains.
+type ChainVerification struct {
+	Chains []*Chain
+	// UnchainedEntries is the number of entries written before hash chaining
+	// was introduced, which can't be verified
+	UnchainedEntries int
+}
+
+// Valid returns true if no problems were found in any chain.
+func (v *ChainVerification) Valid() bool {
+	for _, c := range v.Chains {
+		if len(c.Problems) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+const chainEntriesQuery = `
+select e.id, e.ticket_name, e.ticket_version, e.version, e.aggregate_name, e.data, e.prev_hash, e.hash,
+       (select json_agg(json_build_array(m.key, m.value) order by m.id)
+          from oplog_metadata m
+         where m.entry_id = e.id) as metadata
+  from oplog_entry e
+ where e.ticket_name is not null%s
+ order by e.ticket_name, e.ticket_version, e.id;
+`
+
+const chainHeadsQuery = `
+select ticket_name, ticket_version, hash, key_id, signature
+  from oplog_chain_head
+ where true%s
+ order by ticket_name, ticket_version;
+`
+
+type chainHead struct {
+	ticketName    string
+	ticketVersion uint32
+	hash          []byte
+	keyId         string
+	signature     []byte
+	valid         bool
+}
+
+// VerifyChains recomputes the hash of every chained entry and checks that the
+// entries written with each ticket form an unbroken chain which matches the
+// chain heads signed by SignChainHeads. Entries are not decrypted. Entries
+// older than the oldest remaining entry of a chain are assumed to have been
+// deleted deliberately and are not reported. Supports the WithTicketName
+// option.
+func (r *Repository) VerifyChains(ctx context.Context, opt ...Option) (*ChainVerification, error) {
+	opts := getOpts(opt...)
+	var where string
+	var args []interface{}
+	if opts.withTicketName != "" {
+		where = "\n   and ticket_name = $1"
+		args = append(args, opts.withTicketName)
+	}
+
+	heads, err := r.chainHeads(ctx, fmt.Sprintf(chainHeadsQuery, where), args)
+	if err != nil {
+		return nil, fmt.Errorf("verify chains: %w", err)
+	}
+	unchained, err := r.countUnchained(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("verify chains: %w", err)
+	}
+
+	rows, err := r.reader.Query(ctx, fmt.Sprintf(chainEntriesQuery, where), args)
+	if err != nil {
+		return nil, fmt.Errorf("verify chains: %w", err)
+	}
+	defer rows.Close()
+
+	v := &ChainVerification{UnchainedEntries: unchained}
+	var chain *Chain
+	var prev *oplog.Entry
+	for rows.Next() {
+		e := &oplog.Entry{Entry: &store.Entry{}}
+		var md []byte
+		if err := rows.Scan(&e.Id, &e.TicketName, &e.TicketVersion, &e.Version, &e.AggregateName, &e.CtData, &e.PrevHash, &e.Hash, &md); err != nil {
+			return nil, fmt.Errorf("verify chains: scan row failed: %w", err)
+		}
+		if e.Metadata, err = parseMetadataPairs(md); err != nil {
+			return nil, fmt.Errorf("verify chains: entry %d: %w", e.Id, err)
+		}
+		if chain == nil || chain.TicketName != e.TicketName {
+			chain = &Chain{TicketName: e.TicketName, FirstVersion: e.TicketVersion}
+			v.Chains = append(v.Chains, chain)
+			prev = nil
+		}
+		verifyChainEntry(chain, prev, e, heads[e.TicketName])
+		chain.Entries++
+		chain.LastVersion = e.TicketVersion
+		prev = e
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("verify chains: %w", err)
+	}
+
+	chains := make(map[string]*Chain, len(v.Chains))
+	for _, c := range v.Chains {
+		chains[c.TicketName] = c
+	}
+	for name, hs := range heads {
+		c, ok := chains[name]
+		if !ok {
+			c = &Chain{TicketName: name}
+			v.Chains = append(v.Chains, c)
+		}
+		for _, h := range hs {
+			if h.valid && h.ticketVersion > c.SignedVersion && h.ticketVersion <= c.LastVersion {
+				c.SignedVersion = h.ticketVersion
+			}
+			switch {
+			case !h.valid:
+				c.Problems = append(c.Problems, &ChainProblem{
+					Kind:          ChainProblemHeadSignature,
+					TicketVersion: h.ticketVersion,
+					Detail:        "chain head signature is not valid",
+				})
+			case h.ticketVersion > c.LastVersion:
+				c.Problems = append(c.Problems, &ChainProblem{
+					Kind:          ChainProblemTruncated,
+					TicketVersion: h.ticketVersion,
+					Detail:        fmt.Sprintf("chain head was signed at version %d but the last entry has version %d", h.ticketVersion, c.LastVersion),
+				})
+			}
+		}
+	}
+	return v, nil
+}
+
+// verifyChainEntry checks e against the entry before it in the chain and
+// against any signed head for its ticket version, adding problems found to
+// the chain.
+func verifyChainEntry(c *Chain, prev, e *oplog.Entry, heads []*chainHead) {
+	addProblem := func(kind ChainProblemKind, detail string) {
+		c.Problems = append(c.Problems, &ChainProblem{
+			Kind:          kind,
+			TicketVersion: e.TicketVersion,
+			EntryId:       e.Id,
+			Detail:        detail,
+		})
+	}
+	if err := e.VerifyHash(); err != nil {
+		addProblem(ChainProblemHashMismatch, err.Error())
+	}
+	if prev != nil {
+		switch {
+		case e.TicketVersion == prev.TicketVersion:
+			addProblem(ChainProblemDuplicate, fmt.Sprintf("entries %d and %d have the same ticket version", prev.Id, e.Id))
+		case e.TicketVersion != prev.TicketVersion+1:
+			addProblem(ChainProblemGap, fmt.Sprintf("entries for ticket versions %d to %d are missing", prev.TicketVersion+1, e.TicketVersion-1))
+		case !bytes.Equal(e.PrevHash, prev.Hash):
+			addProblem(ChainProblemPrevHashMismatch, fmt.Sprintf("entry does not link to entry %d", prev.Id))
+		}
+	}
+	for _, h := range heads {
+		if h.ticketVersion == e.TicketVersion && !bytes.Equal(h.hash, e.Hash) {
+			addProblem(ChainProblemHeadMismatch, "entry hash does not match the signed chain head")
+		}
+	}
+}
+
+// chainHeads returns the signed chain heads by ticket name, with the validity
+// of each signature checked.
+func (r *Repository) chainHeads(ctx context.Context, q string, args []interface{}) (map[string][]*chainHead, error) {
+	rows, err := r.reader.Query(ctx, q, args)
+	if err != nil {
+		return nil, err
+	}
+	var all []*chainHead
+	for rows.Next() {
+		h := &chainHead{}
+		if err := rows.Scan(&h.ticketName, &h.ticketVersion, &h.hash, &h.keyId, &h.signature); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan row failed: %w", err)
+		}
+		all = append(all, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Check signatures after the rows are closed, since loading wrappers
+	// queries the database and may need the connection.
+	wrappers := map[string]wrapping.Wrapper{}
+	heads := map[string][]*chainHead{}
+	for _, h := range all {
+		wrapper, ok := wrappers[h.keyId]
+		if !ok {
+			wrapper, err = r.kms.GetWrapper(ctx, scope.Global.String(), kms.KeyPurposeOplog, kms.WithKeyId(h.keyId))
+			if err != nil {
+				return nil, fmt.Errorf("unable to get oplog wrapper for key %s: %w", h.keyId, err)
+			}
+			wrappers[h.keyId] = wrapper
+		}
+		h.valid = verifyChainHeadSignature(ctx, wrapper, h)
+		heads[h.ticketName] = append(heads[h.ticketName], h)
+	}
+	return heads, nil
+}
+
+func (r *Repository) countUnchained(ctx context.Context) (int, error) {
+	rows, err := r.reader.Query(ctx, "select count(*) from oplog_entry where hash is null;", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var n sql.NullInt64
+	if rows.Next() {
+		if err := rows.Scan(&n); err != nil {
+			return 0, err
+		}
+	}
+	return int(n.Int64), rows.Err()
+}
+
+const unsignedChainHeadsQuery = `
+select ticket_name, ticket_version, hash
+  from (select distinct on (ticket_name) ticket_name, ticket_version, hash
+          from oplog_entry
+         where hash is not null
+         order by ticket_name, ticket_version desc, id desc) heads
+ where not exists (
+   select 1 from oplog_chain_head h
+    where h.ticket_name = heads.ticket_name
+      and h.ticket_version = heads.ticket_version
+ );
+`
+
+// insertChainHeadQuery only inserts the head if its entry still has the hash,
+// as rewrapping may have rehashed the entry since it was read.
+const insertChainHeadQuery = `
+insert into oplog_chain_head (ticket_name, ticket_version, hash, key_id, signature)
+select $1::text, $2::bigint, $3::bytea, $4::text, $5::bytea
+ where exists (
+   select 1 from oplog_entry
+    where ticket_name = $1
+      and ticket_version = $2
+      and hash = $3
+ )
+on conflict (ticket_name, ticket_version) do nothing;
+`
+
+// shareTicketQuery waits for any transaction rewrapping the chain to commit
+// and keeps the chain from being rewrapped until the head is inserted.
+const shareTicketQuery = `
+select 1 from oplog_ticket where name = $1 for share;
+`
+
+// SignChainHeads records a signed chain head for the newest entry of every
+// chain which has changed since its head was last signed, returning the
+// number of heads signed. Heads are signed with the oplog key of the global
+// scope. It is safe to run concurrently from multiple controllers and with
+// RewrapEntries.
+func (r *Repository) SignChainHeads(ctx context.Context) (int, error) {
+	rows, err := r.reader.Query(ctx, unsignedChainHeadsQuery, nil)
+	if err != nil {
+		return 0, fmt.Errorf("sign chain heads: %w", err)
+	}
+	var heads []*chainHead
+	for rows.Next() {
+		h := &chainHead{}
+		if err := rows.Scan(&h.ticketName, &h.ticketVersion, &h.hash); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("sign chain heads: scan row failed: %w", err)
+		}
+		heads = append(heads, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("sign chain heads: %w", err)
+	}
+	if len(heads) == 0 {
+		return 0, nil
+	}
+
+	wrapper, err := r.kms.GetWrapper(ctx, scope.Global.String(), kms.KeyPurposeOplog)
+	if err != nil {
+		return 0, fmt.Errorf("sign chain heads: unable to get oplog wrapper: %w", err)
+	}
+	var signed int
+	for _, h := range heads {
+		keyId, sig, err := signChainHead(ctx, wrapper, h)
+		if err != nil {
+			return signed, fmt.Errorf("sign chain heads: %s: %w", h.ticketName, err)
+		}
+		var n int
+		_, err = r.writer.DoTx(
+			ctx,
+			db.StdRetryCnt,
+			db.ExpBackoff{},
+			func(_ db.Reader, w db.Writer) error {
+				if _, err := w.Exec(ctx, shareTicketQuery, []interface{}{h.ticketName}); err != nil {
+					return err
+				}
+				n, err = w.Exec(ctx, insertChainHeadQuery, []interface{}{h.ticketName, h.ticketVersion, h.hash, keyId, sig})
+				return err
+			},
+		)
+		if err != nil {
+			return signed, fmt.Errorf("sign chain heads: %s: %w", h.ticketName, err)
+		}
+		signed += n
+	}
+	return signed, nil
+}
+
+// signChainHead signs the head with the wrapper, returning the id of the key
+// used and the signature.
+func signChainHead(ctx context.Context, wrapper wrapping.Wrapper, h *chainHead) (string, []byte, error) {
+	blob, err := wrapper.Encrypt(ctx, chainHeadPayload(h), nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to sign: %w", err)
+	}
+	sig, err := proto.Marshal(blob)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to marshal signature: %w", err)
+	}
+	return blob.GetKeyInfo().GetKeyID(), sig, nil
+}
+
+// chainHeadPayload returns the value signed for a chain head. The AEAD
+// encryption of the payload with the oplog key serves as the signature: only a
+// holder of the key can produce a ciphertext which decrypts to the payload.
+func chainHeadPayload(h *chainHead) []byte {
+	var v [4]byte
+	binary.BigEndian.PutUint32(v[:], h.ticketVersion)
+	sum := sha256.New()
+	sum.Write([]byte(h.ticketName))
+	sum.Write([]byte{0})
+	sum.Write(v[:])
+	sum.Write(h.hash)
+	return sum.Sum(nil)
+}
+
+func verifyChainHeadSignature(ctx context.Context, wrapper wrapping.Wrapper, h *chainHead) bool {
+	blob := new(wrapping.EncryptedBlobInfo)
+	if err := proto.Unmarshal(h.signature, blob); err != nil {
+		return false
+	}
+	pt, err := wrapper.Decrypt(ctx, blob, nil)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(pt, chainHeadPayload(h))
+}
+
+// parseMetadataPairs parses the metadata of an entry aggregated as a JSON
+// array of [key, value] pairs into the form it was hashed in.
+func parseMetadataPairs(md []byte) ([]*store.Metadata, error) {
+	if len(md) == 0 {
+		return nil, nil
+	}
+	var pairs [][2]*string
+	if err := json.Unmarshal(md, &pairs); err != nil {
+		return nil, fmt.Errorf("unable to parse metadata: %w", err)
+	}
+	metadata := make([]*store.Metadata, 0, len(pairs))
+	for _, p := range pairs {
+		m := &store.Metadata{}
+		if p[0] != nil {
+			m.Key = *p[0]
+		}
+		if p[1] != nil {
+			m.Value = *p[1]
+		}
+		metadata = append(metadata, m)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,76 @@
+package inspect_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_VerifyChains(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	var users []*iam.User
+	for i := 0; i < 3; i++ {
+		users = append(users, iam.TestUser(t, iamRepo, org.PublicId))
+	}
+
+	repo, err := inspect.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+	ticketName := new(iam.User).TableName()
+
+	verify := func() *inspect.Chain {
+		t.Helper()
+		v, err := repo.VerifyChains(ctx, inspect.WithTicketName(ticketName))
+		require.NoError(err)
+		require.Len(v.Chains, 1)
+		return v.Chains[0]
+	}
+
+	chain := verify()
+	assert.Empty(chain.Problems)
+	assert.GreaterOrEqual(chain.Entries, 3)
+	assert.Zero(chain.SignedVersion)
+
+	signed, err := repo.SignChainHeads(ctx)
+	require.NoError(err)
+	assert.Greater(signed, 0)
+	signed, err = repo.SignChainHeads(ctx)
+	require.NoError(err)
+	assert.Zero(signed)
+
+	chain = verify()
+	assert.Empty(chain.Problems)
+	assert.Equal(chain.LastVersion, chain.SignedVersion)
+
+	deleteEntry := func(u *iam.User) {
+		t.Helper()
+		entries, err := repo.ListEntries(ctx, inspect.WithResourceId(u.PublicId))
+		require.NoError(err)
+		require.Len(entries, 1)
+		_, err = rw.Exec(ctx, "delete from oplog_entry where id = $1", []interface{}{entries[0].Id})
+		require.NoError(err)
+	}
+
+	deleteEntry(users[1])
+	chain = verify()
+	require.Len(chain.Problems, 1)
+	assert.Equal(inspect.ChainProblemGap, chain.Problems[0].Kind)
+
+	deleteEntry(users[2])
+	chain = verify()
+	require.Len(chain.Problems, 2)
+	assert.Equal(inspect.ChainProblemGap, chain.Problems[0].Kind)
+	assert.Equal(inspect.ChainProblemTruncated, chain.Problems[1].Kind)
+}
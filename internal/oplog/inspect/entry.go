@@ -0,0 +1,234 @@
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// MetadataScopeId is the metadata key holding the id of the scope of the
+	// resource modified by an entry.
+	MetadataScopeId = "scope-id"
+
+	// MetadataResourceId is the metadata key holding the public id of the
+	// resource modified by an entry.
+	MetadataResourceId = "resource-public-id"
+)
+
+// Entry is a decrypted and decoded oplog entry.
+type Entry struct {
+	Id            uint32
+	CreateTime    time.Time
+	Version       string
+	AggregateName string
+	Metadata      oplog.Metadata
+	// KeyId is the id of the oplog key version the entry was encrypted with
+	KeyId string
+	// Messages are the operations recorded by the entry, in the order they
+	// were applied
+	Messages []oplog.Message
+	// DecodeError is the error the entry could not be decrypted or decoded
+	// with, if any, in which case it has no messages
+	DecodeError error
+}
+
+const entriesQuery = `
+select e.id, e.create_time, e.version, e.aggregate_name, e.data,
+       (select json_agg(json_build_array(m.key, m.value) order by m.id)
+          from oplog_metadata m
+         where m.entry_id = e.id) as metadata
+  from oplog_entry e
+ where true%s
+ order by e.id desc
+%s;
+`
+
+const metadataCondition = `
+   and exists (
+     select 1 from oplog_metadata m
+      where m.entry_id = e.id
+        and m.key = '%s'
+        and m.value = $%d
+   )`
+
+// ListEntries returns oplog entries, newest first. Supports the options:
+// WithScopeId, WithAggregateName, WithResourceId, WithStartTime, WithEndTime
+// and WithLimit.
+func (r *Repository) ListEntries(ctx context.Context, opt ...Option) ([]*Entry, error) {
+	opts := getOpts(opt...)
+
+	var where []string
+	var args []interface{}
+	if opts.withScopeId != "" {
+		args = append(args, opts.withScopeId)
+		where = append(where, fmt.Sprintf(metadataCondition, MetadataScopeId, len(args)))
+	}
+	if opts.withResourceId != "" {
+		args = append(args, opts.withResourceId)
+		where = append(where, fmt.Sprintf(metadataCondition, MetadataResourceId, len(args)))
+	}
+	if opts.withAggregateName != "" {
+		args = append(args, opts.withAggregateName)
+		where = append(where, fmt.Sprintf("\n   and e.aggregate_name = $%d", len(args)))
+	}
+	if !opts.withStartTime.IsZero() {
+		args = append(args, opts.withStartTime)
+		where = append(where, fmt.Sprintf("\n   and e.create_time >= $%d", len(args)))
+	}
+	if !opts.withEndTime.IsZero() {
+		args = append(args, opts.withEndTime)
+		where = append(where, fmt.Sprintf("\n   and e.create_time < $%d", len(args)))
+	}
+
+	var limit string
+	switch {
+	case opts.withLimit < 0: // any negative number signals unlimited results
+	case opts.withLimit == 0: // zero signals the default value and default limits
+		limit = fmt.Sprintf("limit %d", r.defaultLimit)
+	default:
+		// non-zero signals an override of the default limit for the repo.
+		limit = fmt.Sprintf("limit %d", opts.withLimit)
+	}
+
+	entries, err := r.queryEntries(ctx, fmt.Sprintf(entriesQuery, strings.Join(where, ""), limit), args)
+	if err != nil {
+		return nil, fmt.Errorf("list entries: %w", err)
+	}
+	return entries, nil
+}
+
+// LookupEntry returns the oplog entry with the id. If the entry is not found
+// db.ErrRecordNotFound is returned.
+func (r *Repository) LookupEntry(ctx context.Context, id uint32) (*Entry, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("lookup entry: missing id: %w", db.ErrInvalidParameter)
+	}
+	entries, err := r.queryEntries(ctx, fmt.Sprintf(entriesQuery, "\n   and e.id = $1", ""), []interface{}{id})
+	if err != nil {
+		return nil, fmt.Errorf("lookup entry: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("lookup entry: %d: %w", id, db.ErrRecordNotFound)
+	}
+	return entries[0], nil
+}
+
+func (r *Repository) queryEntries(ctx context.Context, q string, args []interface{}) ([]*Entry, error) {
+	rows, err := r.reader.Query(ctx, q, args)
+	if err != nil {
+		return nil, err
+	}
+	type row struct {
+		entry *Entry
+		ct    []byte
+	}
+	var results []row
+	for rows.Next() {
+		e := &Entry{}
+		var ct, md []byte
+		if err := rows.Scan(&e.Id, &e.CreateTime, &e.Version, &e.AggregateName, &ct, &md); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan row failed: %w", err)
+		}
+		if e.Metadata, err = parseMetadata(md); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("entry %d: %w", e.Id, err)
+		}
+		results = append(results, row{entry: e, ct: ct})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Decrypt after the rows are closed, since loading wrappers queries the
+	// database and may need the connection. An entry which cannot be decoded
+	// is returned with its error, so that it does not hide the others.
+	wrappers := map[string]wrapping.Wrapper{}
+	entries := make([]*Entry, 0, len(results))
+	for _, res := range results {
+		if err := r.decode(ctx, res.entry, res.ct, wrappers); err != nil {
+			res.entry.DecodeError = err
+		}
+		entries = append(entries, res.entry)
+	}
+	return entries, nil
+}
+
+// parseMetadata parses the metadata of an entry aggregated as a JSON array of
+// [key, value] pairs.
+func parseMetadata(md []byte) (oplog.Metadata, error) {
+	metadata := oplog.Metadata{}
+	if len(md) == 0 {
+		return metadata, nil
+	}
+	var pairs [][2]*string
+	if err := json.Unmarshal(md, &pairs); err != nil {
+		return nil, fmt.Errorf("unable to parse metadata: %w", err)
+	}
+	for _, p := range pairs {
+		if p[0] == nil {
+			continue
+		}
+		if p[1] == nil {
+			if _, ok := metadata[*p[0]]; !ok {
+				metadata[*p[0]] = nil
+			}
+			continue
+		}
+		metadata[*p[0]] = append(metadata[*p[0]], *p[1])
+	}
+	return metadata, nil
+}
+
+// decode decrypts the entry data and unmarshals its messages. Wrappers are
+// cached by key id across calls.
+func (r *Repository) decode(ctx context.Context, e *Entry, ct []byte, wrappers map[string]wrapping.Wrapper) error {
+	blob := new(wrapping.EncryptedBlobInfo)
+	if err := proto.Unmarshal(ct, blob); err != nil {
+		return fmt.Errorf("unable to unmarshal encrypted data: %w", err)
+	}
+	e.KeyId = blob.GetKeyInfo().GetKeyID()
+
+	wrapper, ok := wrappers[e.KeyId]
+	if !ok {
+		scopeId, err := r.kms.LookupKeyVersionScope(ctx, e.KeyId)
+		if err != nil {
+			return fmt.Errorf("unable to find scope of key %s: %w", e.KeyId, err)
+		}
+		wrapper, err = r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog, kms.WithKeyId(e.KeyId))
+		if err != nil {
+			return fmt.Errorf("unable to get oplog wrapper for key %s: %w", e.KeyId, err)
+		}
+		wrappers[e.KeyId] = wrapper
+	}
+
+	oe := &oplog.Entry{
+		Entry: &store.Entry{
+			Id:            e.Id,
+			Version:       e.Version,
+			AggregateName: e.AggregateName,
+			CtData:        ct,
+		},
+		Cipherer: wrapper,
+	}
+	if err := oe.DecryptData(ctx); err != nil {
+		return err
+	}
+	msgs, err := oe.UnmarshalData(r.types)
+	if err != nil {
+		return fmt.Errorf("unable to decode entry data: %w", err)
+	}
+	e.Messages = msgs
+	return nil
+}
@@ -0,0 +1,105 @@
+package inspect_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth/password"
+	authstore "github.com/hashicorp/boundary/internal/auth/store"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	iamstore "github.com/hashicorp/boundary/internal/iam/store"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ListEntries(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	start := time.Now().Add(-time.Second)
+	user := iam.TestUser(t, iamRepo, org.PublicId, iam.WithName("alice"))
+
+	repo, err := inspect.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(t, err)
+
+	t.Run("by-resource", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		entries, err := repo.ListEntries(ctx, inspect.WithResourceId(user.PublicId))
+		require.NoError(err)
+		require.Len(entries, 1)
+		e := entries[0]
+		assert.Equal([]string{org.PublicId}, e.Metadata[inspect.MetadataScopeId])
+		assert.NotEmpty(e.KeyId)
+		require.Len(e.Messages, 1)
+		assert.Equal(oplog.OpType_OP_TYPE_CREATE, e.Messages[0].OpType)
+		u, ok := e.Messages[0].Message.(*iamstore.User)
+		require.True(ok)
+		assert.Equal("alice", u.GetName())
+
+		got, err := repo.LookupEntry(ctx, e.Id)
+		require.NoError(err)
+		assert.Equal(e.Id, got.Id)
+		assert.Len(got.Messages, 1)
+	})
+
+	t.Run("by-scope-and-time", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		entries, err := repo.ListEntries(ctx, inspect.WithScopeId(org.PublicId), inspect.WithStartTime(start))
+		require.NoError(err)
+		require.NotEmpty(entries)
+		for _, e := range entries {
+			assert.Contains(e.Metadata[inspect.MetadataScopeId], org.PublicId)
+			assert.False(e.CreateTime.Before(start))
+		}
+
+		entries, err = repo.ListEntries(ctx, inspect.WithScopeId(org.PublicId), inspect.WithEndTime(start.Add(-time.Hour)))
+		require.NoError(err)
+		assert.Empty(entries)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		entries, err := repo.ListEntries(ctx, inspect.WithLimit(1))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("user-accounts", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		// Associating an account with a user writes the auth account, as on
+		// a user's first login
+		authMethod := password.TestAuthMethods(t, conn, org.PublicId, 1)[0]
+		acct := password.TestAccounts(t, conn, authMethod.PublicId, 1)[0]
+		_, err := iamRepo.AddUserAccounts(ctx, user.PublicId, user.Version, []string{acct.PublicId})
+		require.NoError(err)
+
+		entries, err := repo.ListEntries(ctx, inspect.WithResourceId(acct.PublicId))
+		require.NoError(err)
+		var found bool
+		for _, e := range entries {
+			require.NoError(e.DecodeError)
+			for _, m := range e.Messages {
+				if a, ok := m.Message.(*authstore.Account); ok {
+					found = true
+					assert.Equal(acct.PublicId, a.GetPublicId())
+				}
+			}
+		}
+		assert.True(found)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		_, err := repo.LookupEntry(ctx, 1<<31)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, db.ErrRecordNotFound))
+	})
+}
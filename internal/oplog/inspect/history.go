@@ -0,0 +1,212 @@
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Change is a change made to a resource or to one of its associations, decoded
+// from a message of an oplog entry.
+type Change struct {
+	// EntryId is the id of the oplog entry which recorded the change
+	EntryId    uint32
+	CreateTime time.Time
+	OpType     oplog.OpType
+	// TypeName is the type of the item changed, which is the name of its
+	// table, e.g. "iam_role" for the role itself or "iam_role_grant" for one
+	// of its grants
+	TypeName string
+	// ChangedFields are the fields set by an update
+	ChangedFields []string
+	// NulledFields are the fields cleared by an update
+	NulledFields []string
+	// Before holds the values of the fields changed by an update or of the
+	// item deleted, keyed by field name. It is nil if the previous values
+	// can't be derived from the oplog, e.g. because earlier entries were
+	// pruned.
+	Before map[string]interface{}
+	// After holds the values of the fields set by an update or of the item
+	// created, keyed by field name.
+	After map[string]interface{}
+	// UserId and AuthTokenId identify the principal whose request made the
+	// change, if it was recorded
+	UserId      string
+	AuthTokenId string
+}
+
+// ResourceHistory returns the changes recorded in the oplog for the resource,
+// oldest first. Previous values are derived by replaying the changes, so they
+// are only available for fields whose value was recorded by an earlier change.
+// If no changes are found db.ErrRecordNotFound is returned. No options are
+// currently supported.
+func (r *Repository) ResourceHistory(ctx context.Context, resourceId string, _ ...Option) ([]*Change, error) {
+	if resourceId == "" {
+		return nil, fmt.Errorf("resource history: missing resource id: %w", db.ErrInvalidParameter)
+	}
+	entries, err := r.ListEntries(ctx, WithResourceId(resourceId), WithLimit(-1))
+	if err != nil {
+		return nil, fmt.Errorf("resource history: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("resource history: %s: %w", resourceId, db.ErrRecordNotFound)
+	}
+
+	// state holds the last known field values of each item, keyed by its type
+	// and private or public id.
+	state := map[string]map[string]interface{}{}
+	changes := make([]*Change, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.DecodeError != nil {
+			return nil, fmt.Errorf("resource history: entry %d: %w", e.Id, e.DecodeError)
+		}
+		for _, m := range e.Messages {
+			c, err := newChange(e, m, state)
+			if err != nil {
+				return nil, fmt.Errorf("resource history: entry %d: %w", e.Id, err)
+			}
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+func newChange(e *Entry, m oplog.Message, state map[string]map[string]interface{}) (*Change, error) {
+	c := &Change{
+		EntryId:    e.Id,
+		CreateTime: e.CreateTime,
+		OpType:     m.OpType,
+		TypeName:   m.TypeName,
+	}
+	if ids := e.Metadata[oplog.PrincipalUserIdKey]; len(ids) > 0 {
+		c.UserId = ids[0]
+	}
+	if ids := e.Metadata[oplog.PrincipalAuthTokenIdKey]; len(ids) > 0 {
+		c.AuthTokenId = ids[0]
+	}
+
+	values, err := messageValues(m.Message)
+	if err != nil {
+		return nil, err
+	}
+	key := m.TypeName + "/" + itemKey(m.Message, values)
+	prev := state[key]
+
+	switch m.OpType {
+	case oplog.OpType_OP_TYPE_CREATE:
+		c.After = values
+		state[key] = copyValues(values)
+
+	case oplog.OpType_OP_TYPE_UPDATE:
+		c.ChangedFields = fieldNames(m.Message, m.FieldMaskPaths)
+		c.NulledFields = fieldNames(m.Message, m.SetToNullPaths)
+		c.After = make(map[string]interface{}, len(c.ChangedFields)+len(c.NulledFields))
+		for _, f := range c.ChangedFields {
+			c.After[f] = values[f]
+		}
+		for _, f := range c.NulledFields {
+			c.After[f] = nil
+		}
+		if prev != nil {
+			c.Before = make(map[string]interface{}, len(c.After))
+			for f := range c.After {
+				c.Before[f] = prev[f]
+			}
+			for f, v := range c.After {
+				prev[f] = v
+			}
+		}
+
+	case oplog.OpType_OP_TYPE_DELETE:
+		// Deletes often only record the id of the item, so prefer the values
+		// known from earlier changes.
+		c.Before = values
+		if prev != nil {
+			c.Before = prev
+		}
+		delete(state, key)
+	}
+	return c, nil
+}
+
+// messageValues returns the populated fields of the message keyed by field
+// name, in their JSON representation.
+func messageValues(m proto.Message) (map[string]interface{}, error) {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal %s: %w", m.ProtoReflect().Descriptor().FullName(), err)
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %s: %w", m.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return values, nil
+}
+
+// itemKey returns the id identifying the item stored by the message. Items
+// with a public id are identified by it; associations, which have none, by
+// the values of their fields other than timestamps and versions.
+func itemKey(m proto.Message, values map[string]interface{}) string {
+	for _, f := range []string{"private_id", "public_id"} {
+		if id, ok := values[f].(string); ok && id != "" {
+			return id
+		}
+	}
+	var parts []string
+	fields := m.ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		name := string(fields.Get(i).Name())
+		switch name {
+		case "create_time", "update_time", "version":
+			continue
+		}
+		if v, ok := values[name]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%v", name, v))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// fieldNames maps the paths of an oplog field mask, which name struct
+// fields in any case, e.g. "GrantScopeId" or "name", to the message's field
+// names, e.g. "grant_scope_id". Unknown paths are returned unchanged.
+func fieldNames(m proto.Message, paths []string) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+	byNormalized := map[string]string{}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		name := string(fields.Get(i).Name())
+		byNormalized[normalizeFieldName(name)] = name
+	}
+	names := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if name, ok := byNormalized[normalizeFieldName(p)]; ok {
+			names = append(names, name)
+			continue
+		}
+		names = append(names, p)
+	}
+	return names
+}
+
+func normalizeFieldName(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		c[k] = v
+	}
+	return c
+}
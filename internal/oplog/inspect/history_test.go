@@ -0,0 +1,118 @@
+package inspect_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/hashicorp/boundary/internal/target"
+	targetstore "github.com/hashicorp/boundary/internal/target/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ResourceHistory(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	user := iam.TestUser(t, iamRepo, org.PublicId, iam.WithName("alice"), iam.WithDescription("first"))
+
+	ctx := oplog.NewPrincipalContext(context.Background())
+	oplog.SetPrincipal(ctx, oplog.Principal{UserId: "u_1234567890", AuthTokenId: "at_1234567890"})
+	user.Name = "bob"
+	user.Description = ""
+	_, _, _, err := iamRepo.UpdateUser(ctx, user, user.Version, []string{"Name", "Description"})
+	require.NoError(err)
+	_, err = iamRepo.DeleteUser(ctx, user.PublicId)
+	require.NoError(err)
+
+	repo, err := inspect.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+
+	_, err = repo.ResourceHistory(ctx, "u_doesnotexist")
+	assert.True(errors.Is(err, db.ErrRecordNotFound))
+
+	changes, err := repo.ResourceHistory(ctx, user.PublicId)
+	require.NoError(err)
+	require.Len(changes, 3)
+
+	create := changes[0]
+	assert.Equal(oplog.OpType_OP_TYPE_CREATE, create.OpType)
+	assert.Equal("iam_user", create.TypeName)
+	assert.Nil(create.Before)
+	assert.Equal("alice", create.After["name"])
+	assert.Empty(create.UserId)
+
+	update := changes[1]
+	assert.Equal(oplog.OpType_OP_TYPE_UPDATE, update.OpType)
+	assert.Equal([]string{"name"}, update.ChangedFields)
+	assert.Equal([]string{"description"}, update.NulledFields)
+	assert.Equal(map[string]interface{}{"name": "alice", "description": "first"}, update.Before)
+	assert.Equal(map[string]interface{}{"name": "bob", "description": nil}, update.After)
+	assert.Equal("u_1234567890", update.UserId)
+	assert.Equal("at_1234567890", update.AuthTokenId)
+	assert.True(update.EntryId > create.EntryId)
+
+	del := changes[2]
+	assert.Equal(oplog.OpType_OP_TYPE_DELETE, del.OpType)
+	assert.Equal("bob", del.Before["name"])
+	assert.Nil(del.After)
+}
+
+func TestRepository_ResourceHistory_udpTarget(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	_, proj := iam.TestScopes(t, iamRepo)
+
+	targetRepo, err := target.NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	udp, err := target.NewUdpTarget(proj.PublicId, target.WithName("dns"), target.WithDefaultPort(53))
+	require.NoError(err)
+	created, _, err := targetRepo.CreateUdpTarget(ctx, udp)
+	require.NoError(err)
+	updated := created.(*target.UdpTarget).Clone().(*target.UdpTarget)
+	updated.Name = "resolver"
+	_, _, _, err = targetRepo.UpdateUdpTarget(ctx, updated, created.GetVersion(), []string{"Name"})
+	require.NoError(err)
+
+	repo, err := inspect.NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	changes, err := repo.ResourceHistory(ctx, created.GetPublicId())
+	require.NoError(err)
+	require.Len(changes, 2)
+
+	assert.Equal(oplog.OpType_OP_TYPE_CREATE, changes[0].OpType)
+	assert.Equal(target.DefaultUdpTableName, changes[0].TypeName)
+	assert.Equal("dns", changes[0].After["name"])
+
+	assert.Equal(oplog.OpType_OP_TYPE_UPDATE, changes[1].OpType)
+	assert.Equal("dns", changes[1].Before["name"])
+	assert.Equal("resolver", changes[1].After["name"])
+
+	entries, err := repo.ListEntries(ctx, inspect.WithResourceId(created.GetPublicId()))
+	require.NoError(err)
+	for _, e := range entries {
+		require.NoError(e.DecodeError)
+		for _, m := range e.Messages {
+			if m.TypeName == target.DefaultUdpTableName {
+				_, ok := m.Message.(*targetstore.UdpTarget)
+				assert.True(ok)
+			}
+		}
+	}
+}
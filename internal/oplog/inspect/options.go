@@ -0,0 +1,85 @@
+package inspect
+
+import "time"
+
+// getOpts - iterate the inbound Options and return a struct
+func getOpts(opt ...Option) options {
+	opts := getDefaultOptions()
+	for _, o := range opt {
+		o(&opts)
+	}
+	return opts
+}
+
+// Option - how Options are passed as arguments
+type Option func(*options)
+
+// options = how options are represented
+type options struct {
+	withLimit         int
+	withScopeId       string
+	withAggregateName string
+	withResourceId    string
+	withStartTime     time.Time
+	withEndTime       time.Time
+	withTicketName    string
+}
+
+func getDefaultOptions() options {
+	return options{}
+}
+
+// WithLimit provides an option to provide a limit. Intentionally allowing
+// negative integers. If WithLimit < 0, then unlimited results are returned. If
+// WithLimit == 0, then default limits are used for results.
+func WithLimit(limit int) Option {
+	return func(o *options) {
+		o.withLimit = limit
+	}
+}
+
+// WithScopeId restricts results to entries recorded for resources in the
+// scope.
+func WithScopeId(scopeId string) Option {
+	return func(o *options) {
+		o.withScopeId = scopeId
+	}
+}
+
+// WithAggregateName restricts results to entries with the aggregate name,
+// which is the table of the aggregate root modified.
+func WithAggregateName(name string) Option {
+	return func(o *options) {
+		o.withAggregateName = name
+	}
+}
+
+// WithResourceId restricts results to entries recorded for the resource with
+// the public id.
+func WithResourceId(id string) Option {
+	return func(o *options) {
+		o.withResourceId = id
+	}
+}
+
+// WithStartTime restricts results to entries created at or after t.
+func WithStartTime(t time.Time) Option {
+	return func(o *options) {
+		o.withStartTime = t
+	}
+}
+
+// WithEndTime restricts results to entries created before t.
+func WithEndTime(t time.Time) Option {
+	return func(o *options) {
+		o.withEndTime = t
+	}
+}
+
+// WithTicketName restricts chain verification to the chain of entries written
+// with the named ticket.
+func WithTicketName(name string) Option {
+	return func(o *options) {
+		o.withTicketName = name
+	}
+}
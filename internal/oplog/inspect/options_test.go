@@ -0,0 +1,70 @@
+package inspect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_GetOpts provides unit tests for GetOpts and all the options
+func Test_GetOpts(t *testing.T) {
+	t.Parallel()
+	t.Run("WithLimit", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default of 0
+		opts := getOpts()
+		testOpts := getDefaultOptions()
+		testOpts.withLimit = 0
+		assert.Equal(opts, testOpts)
+
+		opts = getOpts(WithLimit(-1))
+		testOpts = getDefaultOptions()
+		testOpts.withLimit = -1
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithScopeId", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithScopeId("o_1234"))
+		testOpts := getDefaultOptions()
+		testOpts.withScopeId = "o_1234"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithAggregateName", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithAggregateName("iam_user"))
+		testOpts := getDefaultOptions()
+		testOpts.withAggregateName = "iam_user"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithResourceId", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithResourceId("u_1234"))
+		testOpts := getDefaultOptions()
+		testOpts.withResourceId = "u_1234"
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithStartTime", func(t *testing.T) {
+		assert := assert.New(t)
+		now := time.Now()
+		opts := getOpts(WithStartTime(now))
+		testOpts := getDefaultOptions()
+		testOpts.withStartTime = now
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithEndTime", func(t *testing.T) {
+		assert := assert.New(t)
+		now := time.Now()
+		opts := getOpts(WithEndTime(now))
+		testOpts := getDefaultOptions()
+		testOpts.withEndTime = now
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithTicketName", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithTicketName("iam_user"))
+		testOpts := getDefaultOptions()
+		testOpts.withTicketName = "iam_user"
+		assert.Equal(opts, testOpts)
+	})
+}
@@ -0,0 +1,54 @@
+package inspect
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+)
+
+// Repository reads and decodes oplog entries and verifies their hash chains.
+// Entries are decrypted with the oplog key version of the scope which wrote
+// them.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+	kms    *kms.Kms
+	types  *oplog.TypeCatalog
+
+	// defaultLimit provides a default for limiting the number of results returned from the repo
+	defaultLimit int
+}
+
+// NewRepository creates a new oplog inspection Repository. Supports the
+// options: WithLimit which sets a default limit on results returned by repo
+// operations.
+func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repository, error) {
+	if r == nil {
+		return nil, errors.New("error creating db repository with nil reader")
+	}
+	if w == nil {
+		return nil, errors.New("error creating db repository with nil writer")
+	}
+	if kms == nil {
+		return nil, errors.New("error creating db repository with nil kms")
+	}
+	types, err := NewTypeCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("error creating db repository: %w", err)
+	}
+	opts := getOpts(opt...)
+	if opts.withLimit == 0 {
+		// zero signals the boundary defaults should be used.
+		opts.withLimit = db.DefaultLimit
+	}
+	return &Repository{
+		reader:       r,
+		writer:       w,
+		kms:          kms,
+		types:        types,
+		defaultLimit: opts.withLimit,
+	}, nil
+}
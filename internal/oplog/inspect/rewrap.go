@@ -0,0 +1,598 @@
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultRewrapBatchSize = 100
+
+// setRewrapQuery allows the columns changed by rewrapping to be updated for
+// the rest of the transaction; see oplog_rewrap_columns.
+const setRewrapQuery = "set local boundary.oplog_rewrap = 'true';"
+
+// staleOplogKeyVersionsQuery returns every oplog key version which is not the
+// current version of its key, with the scope of the key.
+const staleOplogKeyVersionsQuery = `
+select v.private_id, rk.scope_id
+  from kms_oplog_key_version v
+  join kms_oplog_key k on k.private_id = v.oplog_key_id
+  join kms_root_key rk on rk.private_id = k.root_key_id
+ where v.version < (
+   select max(cv.version)
+     from kms_oplog_key_version cv
+    where cv.oplog_key_id = v.oplog_key_id
+ );
+`
+
+const (
+	nullKeyIdEntriesQuery = `
+select id, data
+  from oplog_entry
+ where key_id is null
+   and id > $1
+ order by id
+ limit %d;
+`
+	setKeyIdQuery = `
+update oplog_entry set key_id = $1 where id = $2 and key_id is null;
+`
+)
+
+// restoredCondition matches entries restored from an archive, which are left
+// as they are in the archive file until the archive is released.
+const restoredCondition = `exists (
+     select 1 from oplog_archive a
+      where a.restore_time is not null
+        and a.aggregate_name = e.aggregate_name
+        and e.id between a.first_entry_id and a.last_entry_id
+   )`
+
+const (
+	staleUnchainedEntriesQuery = `
+select e.id, e.data, e.key_id
+  from oplog_entry e
+ where e.ticket_name is null
+   and e.key_id = any($1)
+   and e.id > $2
+   and not %s
+ order by e.id
+ limit %d;
+`
+	rewrapUnchainedEntryQuery = `
+update oplog_entry set data = $1, key_id = $2 where id = $3 and key_id = $4;
+`
+)
+
+const (
+	staleChainsQuery = `
+select ticket_name from oplog_entry where ticket_name is not null and key_id = any($1)
+union
+select ticket_name from oplog_chain_head where key_id = any($1)
+order by 1;
+`
+	lockTicketQuery = `
+select 1 from oplog_ticket where name = $1 for update;
+`
+	restoredChainEntriesQuery = `
+select count(*)
+  from oplog_entry e
+ where e.ticket_name = $1
+   and e.ticket_version >= $2
+   and %s;
+`
+	firstStaleChainEntryQuery = `
+select min(ticket_version) from oplog_entry where ticket_name = $1 and key_id = any($2);
+`
+	chainEntriesFromQuery = `
+select e.id, e.ticket_version, e.version, e.aggregate_name, e.data, e.prev_hash, e.hash, e.key_id,
+       (select json_agg(json_build_array(m.key, m.value) order by m.id)
+          from oplog_metadata m
+         where m.entry_id = e.id) as metadata
+  from oplog_entry e
+ where e.ticket_name = $1
+   and (e.ticket_version, e.id) > ($2, $3)
+ order by e.ticket_version, e.id
+ limit %d;
+`
+	rewrapChainEntryQuery = `
+update oplog_entry set data = $1, key_id = $2, prev_hash = $3, hash = $4 where id = $5 and hash = $6;
+`
+	chainHeadsOfTicketQuery = `
+select ticket_name, ticket_version, hash, key_id, signature
+  from oplog_chain_head
+ where ticket_name = $1;
+`
+	resignChainHeadQuery = `
+update oplog_chain_head set hash = $1, key_id = $2, signature = $3 where ticket_name = $4 and ticket_version = $5;
+`
+)
+
+// RewrapEntries re-encrypts the data of oplog entries which was encrypted with
+// a previous version of the oplog key of its scope so that it uses the current
+// version, and re-signs the chain heads which were signed with a previous
+// version of the global scope's oplog key, after which the previous versions
+// may be destroyed. It returns the number of entries rewrapped. Entries are
+// read in batches whose size can be set with WithLimit.
+//
+// The hash of an entry covers its encrypted data, so rewrapping an entry
+// changes its hash and the hashes of every entry chained after it. Each chain
+// is rewritten in a single transaction which holds the chain's ticket, so
+// oplog writes with the ticket wait until it commits. The hashes of the
+// entries and the signatures of the chain heads are verified before they are
+// replaced, and a chain with a problem is left unchanged so that rewrapping
+// never hides it. Chains with entries restored from an archive are left
+// unchanged until the archive is released. Archive files are never changed,
+// so archived entries stay encrypted with the versions they were written with.
+//
+// The key id of entries written before key ids were recorded is set from
+// their data first.
+func (r *Repository) RewrapEntries(ctx context.Context, opt ...Option) (int, error) {
+	opts := getOpts(opt...)
+	batchSize := opts.withLimit
+	if batchSize <= 0 {
+		batchSize = defaultRewrapBatchSize
+	}
+
+	if err := r.setEntryKeyIds(ctx, batchSize); err != nil {
+		return 0, fmt.Errorf("rewrap entries: %w", err)
+	}
+	stale, err := r.staleKeyVersions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("rewrap entries: %w", err)
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+	staleIds := make([]string, 0, len(stale))
+	for id := range stale {
+		staleIds = append(staleIds, id)
+	}
+
+	er := &entryRewrapper{repo: r, stale: stale, wrappers: map[string]wrapping.Wrapper{}}
+
+	total, err := r.rewrapUnchained(ctx, er, staleIds, batchSize)
+	if err != nil {
+		return total, fmt.Errorf("rewrap entries: %w", err)
+	}
+
+	names, err := r.queryStrings(ctx, staleChainsQuery, pq.Array(staleIds))
+	if err != nil {
+		return total, fmt.Errorf("rewrap entries: %w", err)
+	}
+	// A problem with one chain doesn't stop the others from being rewrapped.
+	var firstErr error
+	for _, name := range names {
+		n, err := r.rewrapChain(ctx, er, name, staleIds, batchSize)
+		total += n
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("rewrap entries: chain %s: %w", name, err)
+		}
+	}
+	return total, firstErr
+}
+
+// entryRewrapper re-encrypts entry data and re-signs chain heads with the
+// current oplog key versions.
+type entryRewrapper struct {
+	repo *Repository
+	// stale maps the ids of the oplog key versions to rewrap to their scopes
+	stale map[string]string
+	// wrappers are the oplog wrappers by scope, loaded fresh from the database
+	wrappers map[string]wrapping.Wrapper
+}
+
+// wrapper returns the oplog wrapper of the scope. Wrappers always start from
+// the versions in the database: rewrapping with a stale cached wrapper would
+// move entries back to a previous version.
+func (er *entryRewrapper) wrapper(ctx context.Context, scopeId string) (wrapping.Wrapper, error) {
+	if w, ok := er.wrappers[scopeId]; ok {
+		return w, nil
+	}
+	er.repo.kms.GetScopePurposeCache().Delete(scopeId + kms.KeyPurposeOplog.String())
+	w, err := er.repo.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get oplog wrapper for scope %s: %w", scopeId, err)
+	}
+	er.wrappers[scopeId] = w
+	return w, nil
+}
+
+// rewrapData re-encrypts data encrypted with the stale key version, returning
+// the new data and the id of the key version used.
+func (er *entryRewrapper) rewrapData(ctx context.Context, ct []byte, keyId string) ([]byte, string, error) {
+	w, err := er.wrapper(ctx, er.stale[keyId])
+	if err != nil {
+		return nil, "", err
+	}
+	e := &oplog.Entry{Entry: &store.Entry{CtData: ct}, Cipherer: w}
+	if err := e.DecryptData(ctx); err != nil {
+		return nil, "", err
+	}
+	if err := e.EncryptData(ctx); err != nil {
+		return nil, "", err
+	}
+	return e.CtData, e.KeyId, nil
+}
+
+// setEntryKeyIds sets the key id of entries which have none from the key info
+// of their encrypted data. Entries whose data can't be parsed get an empty key
+// id, as no key version can decrypt them.
+func (r *Repository) setEntryKeyIds(ctx context.Context, batchSize int) error {
+	var lastId uint32
+	for {
+		rows, err := r.reader.Query(ctx, fmt.Sprintf(nullKeyIdEntriesQuery, batchSize), []interface{}{lastId})
+		if err != nil {
+			return err
+		}
+		type keyIdRow struct {
+			id    uint32
+			keyId string
+		}
+		var batch []keyIdRow
+		for rows.Next() {
+			var row keyIdRow
+			var ct []byte
+			if err := rows.Scan(&row.id, &ct); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan row failed: %w", err)
+			}
+			blob := new(wrapping.EncryptedBlobInfo)
+			if err := proto.Unmarshal(ct, blob); err == nil {
+				row.keyId = blob.GetKeyInfo().GetKeyID()
+			}
+			batch = append(batch, row)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		_, err = r.writer.DoTx(
+			ctx,
+			db.StdRetryCnt,
+			db.ExpBackoff{},
+			func(_ db.Reader, w db.Writer) error {
+				if _, err := w.Exec(ctx, setRewrapQuery, nil); err != nil {
+					return err
+				}
+				for _, row := range batch {
+					if _, err := w.Exec(ctx, setKeyIdQuery, []interface{}{row.keyId, row.id}); err != nil {
+						return fmt.Errorf("unable to set key id of entry %d: %w", row.id, err)
+					}
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		lastId = batch[len(batch)-1].id
+	}
+}
+
+// staleKeyVersions returns the ids of the oplog key versions which are not
+// current, mapped to the scopes of their keys.
+func (r *Repository) staleKeyVersions(ctx context.Context) (map[string]string, error) {
+	rows, err := r.reader.Query(ctx, staleOplogKeyVersionsQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stale := map[string]string{}
+	for rows.Next() {
+		var id, scopeId string
+		if err := rows.Scan(&id, &scopeId); err != nil {
+			return nil, fmt.Errorf("scan row failed: %w", err)
+		}
+		stale[id] = scopeId
+	}
+	return stale, rows.Err()
+}
+
+// rewrapUnchained rewraps the entries written before hash chaining, which can
+// be rewrapped independently of each other.
+func (r *Repository) rewrapUnchained(ctx context.Context, er *entryRewrapper, staleIds []string, batchSize int) (int, error) {
+	type unchainedRow struct {
+		id    uint32
+		ct    []byte
+		keyId string
+	}
+	var total int
+	var lastId uint32
+	for {
+		q := fmt.Sprintf(staleUnchainedEntriesQuery, restoredCondition, batchSize)
+		rows, err := r.reader.Query(ctx, q, []interface{}{pq.Array(staleIds), lastId})
+		if err != nil {
+			return total, err
+		}
+		var batch []unchainedRow
+		for rows.Next() {
+			var row unchainedRow
+			if err := rows.Scan(&row.id, &row.ct, &row.keyId); err != nil {
+				rows.Close()
+				return total, fmt.Errorf("scan row failed: %w", err)
+			}
+			batch = append(batch, row)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return total, err
+		}
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		var rewrapped int
+		_, err = r.writer.DoTx(
+			ctx,
+			db.StdRetryCnt,
+			db.ExpBackoff{},
+			func(_ db.Reader, w db.Writer) error {
+				rewrapped = 0
+				if _, err := w.Exec(ctx, setRewrapQuery, nil); err != nil {
+					return err
+				}
+				for _, row := range batch {
+					ct, keyId, err := er.rewrapData(ctx, row.ct, row.keyId)
+					if err != nil {
+						return fmt.Errorf("entry %d: %w", row.id, err)
+					}
+					n, err := w.Exec(ctx, rewrapUnchainedEntryQuery, []interface{}{ct, keyId, row.id, row.keyId})
+					if err != nil {
+						return fmt.Errorf("unable to update entry %d: %w", row.id, err)
+					}
+					rewrapped += n
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return total, err
+		}
+		total += rewrapped
+		if len(batch) < batchSize {
+			return total, nil
+		}
+		lastId = batch[len(batch)-1].id
+	}
+}
+
+// chainLink is the hash of a chain entry before and after rewrapping.
+type chainLink struct {
+	ticketVersion uint32
+	oldHash       []byte
+	newHash       []byte
+}
+
+// rewrapChain rewraps the stale entries of the chain, relinks and rehashes
+// the entries after them and re-signs the chain's heads, returning the number
+// of entries rewrapped.
+func (r *Repository) rewrapChain(ctx context.Context, er *entryRewrapper, ticketName string, staleIds []string, batchSize int) (int, error) {
+	var rewrapped int
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			rewrapped = 0
+			if _, err := w.Exec(ctx, setRewrapQuery, nil); err != nil {
+				return err
+			}
+			// Holding the ticket makes writers wait to chain new entries
+			// until the rewritten chain is committed.
+			if _, err := w.Exec(ctx, lockTicketQuery, []interface{}{ticketName}); err != nil {
+				return fmt.Errorf("unable to lock ticket: %w", err)
+			}
+			heads, err := queryChainHeads(ctx, reader, ticketName)
+			if err != nil {
+				return err
+			}
+
+			links := map[uint32]*chainLink{}
+			first, err := queryNullInt(ctx, reader, firstStaleChainEntryQuery, ticketName, pq.Array(staleIds))
+			if err != nil {
+				return err
+			}
+			if first.Valid {
+				restored, err := queryNullInt(ctx, reader, fmt.Sprintf(restoredChainEntriesQuery, restoredCondition), ticketName, first.Int64)
+				if err != nil {
+					return err
+				}
+				if restored.Int64 > 0 {
+					// Leave the chain for a run after the archive is released.
+					return nil
+				}
+				headVersions := map[uint32]bool{}
+				for _, h := range heads {
+					headVersions[h.ticketVersion] = true
+				}
+				if rewrapped, err = r.rewriteChainEntries(ctx, er, reader, w, ticketName, uint32(first.Int64), headVersions, links, batchSize); err != nil {
+					return err
+				}
+			}
+			return r.resignChainHeads(ctx, er, w, heads, links)
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return rewrapped, nil
+}
+
+// rewriteChainEntries rewraps and rehashes the entries of the chain from the
+// ticket version on, recording the links of the entries with the versions of
+// heads so the heads can be re-signed.
+func (r *Repository) rewriteChainEntries(ctx context.Context, er *entryRewrapper, reader db.Reader, w db.Writer, ticketName string, from uint32, headVersions map[uint32]bool, links map[uint32]*chainLink, batchSize int) (int, error) {
+	var rewrapped int
+	var prev *chainLink
+	afterVersion, afterId := int64(from)-1, int64(0)
+	for {
+		rows, err := reader.Query(ctx, fmt.Sprintf(chainEntriesFromQuery, batchSize), []interface{}{ticketName, afterVersion, afterId})
+		if err != nil {
+			return rewrapped, err
+		}
+		var batch []*oplog.Entry
+		for rows.Next() {
+			e := &oplog.Entry{Entry: &store.Entry{TicketName: ticketName}}
+			var keyId sql.NullString
+			var md []byte
+			if err := rows.Scan(&e.Id, &e.TicketVersion, &e.Version, &e.AggregateName, &e.CtData, &e.PrevHash, &e.Hash, &keyId, &md); err != nil {
+				rows.Close()
+				return rewrapped, fmt.Errorf("scan row failed: %w", err)
+			}
+			e.KeyId = keyId.String
+			if e.Metadata, err = parseMetadataPairs(md); err != nil {
+				rows.Close()
+				return rewrapped, fmt.Errorf("entry %d: %w", e.Id, err)
+			}
+			batch = append(batch, e)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return rewrapped, err
+		}
+
+		for _, e := range batch {
+			if err := e.VerifyHash(); err != nil {
+				return rewrapped, fmt.Errorf("entry %d: %w", e.Id, err)
+			}
+			link := &chainLink{ticketVersion: e.TicketVersion, oldHash: e.Hash}
+			oldKeyId := e.KeyId
+			if _, ok := er.stale[e.KeyId]; ok {
+				if e.CtData, e.KeyId, err = er.rewrapData(ctx, e.CtData, e.KeyId); err != nil {
+					return rewrapped, fmt.Errorf("entry %d: %w", e.Id, err)
+				}
+				rewrapped++
+			}
+			// Only links which were intact are relinked, so a broken link
+			// stays visible.
+			if prev != nil && e.TicketVersion == prev.ticketVersion+1 && bytes.Equal(e.PrevHash, prev.oldHash) {
+				e.PrevHash = prev.newHash
+			}
+			e.Hash = e.ComputeHash()
+			link.newHash = e.Hash
+			if !bytes.Equal(link.oldHash, link.newHash) || e.KeyId != oldKeyId {
+				n, err := w.Exec(ctx, rewrapChainEntryQuery, []interface{}{e.CtData, e.KeyId, e.PrevHash, e.Hash, e.Id, link.oldHash})
+				if err != nil {
+					return rewrapped, fmt.Errorf("unable to update entry %d: %w", e.Id, err)
+				}
+				if n != 1 {
+					return rewrapped, fmt.Errorf("entry %d changed while being rewrapped", e.Id)
+				}
+			}
+			if headVersions[e.TicketVersion] {
+				links[e.TicketVersion] = link
+			}
+			prev = link
+		}
+		if len(batch) < batchSize {
+			return rewrapped, nil
+		}
+		last := batch[len(batch)-1]
+		afterVersion, afterId = int64(last.TicketVersion), int64(last.Id)
+	}
+}
+
+// resignChainHeads updates the hashes of heads whose entries were rehashed
+// and signs them again with the current oplog key of the global scope, as
+// well as heads signed with a stale key version.
+func (r *Repository) resignChainHeads(ctx context.Context, er *entryRewrapper, w db.Writer, heads []*chainHead, links map[uint32]*chainLink) error {
+	for _, h := range heads {
+		hash := h.hash
+		if l, ok := links[h.ticketVersion]; ok && bytes.Equal(h.hash, l.oldHash) {
+			hash = l.newHash
+		}
+		_, staleKey := er.stale[h.keyId]
+		if bytes.Equal(hash, h.hash) && !staleKey {
+			continue
+		}
+
+		old, err := r.kms.GetWrapper(ctx, scope.Global.String(), kms.KeyPurposeOplog, kms.WithKeyId(h.keyId))
+		if err != nil {
+			return fmt.Errorf("unable to get oplog wrapper for key %s: %w", h.keyId, err)
+		}
+		if !verifyChainHeadSignature(ctx, old, h) {
+			return fmt.Errorf("chain head %d signature is not valid", h.ticketVersion)
+		}
+		current, err := er.wrapper(ctx, scope.Global.String())
+		if err != nil {
+			return err
+		}
+		h.hash = hash
+		keyId, sig, err := signChainHead(ctx, current, h)
+		if err != nil {
+			return fmt.Errorf("chain head %d: %w", h.ticketVersion, err)
+		}
+		if _, err := w.Exec(ctx, resignChainHeadQuery, []interface{}{h.hash, keyId, sig, h.ticketName, h.ticketVersion}); err != nil {
+			return fmt.Errorf("unable to update chain head %d: %w", h.ticketVersion, err)
+		}
+	}
+	return nil
+}
+
+func queryChainHeads(ctx context.Context, reader db.Reader, ticketName string) ([]*chainHead, error) {
+	rows, err := reader.Query(ctx, chainHeadsOfTicketQuery, []interface{}{ticketName})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var heads []*chainHead
+	for rows.Next() {
+		h := &chainHead{}
+		if err := rows.Scan(&h.ticketName, &h.ticketVersion, &h.hash, &h.keyId, &h.signature); err != nil {
+			return nil, fmt.Errorf("scan row failed: %w", err)
+		}
+		heads = append(heads, h)
+	}
+	return heads, rows.Err()
+}
+
+func queryNullInt(ctx context.Context, reader db.Reader, q string, args ...interface{}) (sql.NullInt64, error) {
+	var n sql.NullInt64
+	rows, err := reader.Query(ctx, q, args)
+	if err != nil {
+		return n, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		if err := rows.Scan(&n); err != nil {
+			return n, fmt.Errorf("scan row failed: %w", err)
+		}
+	}
+	return n, rows.Err()
+}
+
+func (r *Repository) queryStrings(ctx context.Context, q string, args ...interface{}) ([]string, error) {
+	rows, err := r.reader.Query(ctx, q, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("scan row failed: %w", err)
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
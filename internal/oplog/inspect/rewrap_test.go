@@ -0,0 +1,155 @@
+package inspect_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepository_RewrapEntries is not parallel as it changes
+// kms.KeyVersionRetention.
+func TestRepository_RewrapEntries(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	var users []*iam.User
+	for i := 0; i < 3; i++ {
+		users = append(users, iam.TestUser(t, iamRepo, org.PublicId))
+	}
+
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	repo, err := inspect.NewRepository(rw, rw, kmsCache)
+	require.NoError(err)
+	ticketName := new(iam.User).TableName()
+	_, err = repo.SignChainHeads(ctx)
+	require.NoError(err)
+
+	scopeIds := []string{scope.Global.String(), org.PublicId}
+	oldVersions := map[string]string{}
+	for _, scopeId := range scopeIds {
+		w, err := kmsCache.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+		require.NoError(err)
+		oldVersions[scopeId] = w.KeyID()
+		_, err = kmsCache.RotateKeys(ctx, scopeId)
+		require.NoError(err)
+	}
+
+	userEntryKeyIds := func(r *inspect.Repository) []string {
+		t.Helper()
+		var keyIds []string
+		for _, u := range users {
+			entries, err := r.ListEntries(ctx, inspect.WithResourceId(u.PublicId))
+			require.NoError(err)
+			require.Len(entries, 1)
+			require.NoError(entries[0].DecodeError)
+			keyIds = append(keyIds, entries[0].KeyId)
+		}
+		return keyIds
+	}
+	for _, keyId := range userEntryKeyIds(repo) {
+		assert.Equal(oldVersions[org.PublicId], keyId)
+	}
+	version := func(scopeId, id string) *kms.KeyVersionStatus {
+		t.Helper()
+		versions, err := kmsCache.ListKeyVersionStatus(ctx, scopeId)
+		require.NoError(err)
+		for _, v := range versions {
+			if v.Id == id {
+				return v
+			}
+		}
+		require.FailNow("key version not found", id)
+		return nil
+	}
+	for scopeId, id := range oldVersions {
+		assert.Greater(version(scopeId, id).References, 0, scopeId)
+	}
+
+	n, err := repo.RewrapEntries(ctx, inspect.WithLimit(2))
+	require.NoError(err)
+	assert.GreaterOrEqual(n, len(users))
+	n, err = repo.RewrapEntries(ctx)
+	require.NoError(err)
+	assert.Zero(n)
+
+	// the rewritten chains are intact and their heads are signed again
+	v, err := repo.VerifyChains(ctx)
+	require.NoError(err)
+	assert.True(v.Valid())
+	for _, c := range v.Chains {
+		if c.TicketName == ticketName {
+			assert.Equal(c.LastVersion, c.SignedVersion)
+		}
+	}
+
+	defer func(retention time.Duration) { kms.KeyVersionRetention = retention }(kms.KeyVersionRetention)
+	kms.KeyVersionRetention = 0
+
+	// With the oplog entries rewrapped, every previous version of the org's
+	// keys can be destroyed, the root key version last.
+	versions, err := kmsCache.ListKeyVersionStatus(ctx, org.PublicId)
+	require.NoError(err)
+	var oldRoot string
+	for _, v := range versions {
+		switch {
+		case v.Current:
+		case v.Purpose == kms.KeyVersionPurposeRoot:
+			oldRoot = v.Id
+		default:
+			assert.Equal(0, v.References, v.Id)
+			require.NoError(kmsCache.DestroyKeyVersion(ctx, org.PublicId, v.Id), v.NotDestroyableReason)
+		}
+	}
+	require.NotEmpty(oldRoot)
+	require.NoError(kmsCache.DestroyKeyVersion(ctx, org.PublicId, oldRoot))
+	require.NoError(kmsCache.DestroyKeyVersion(ctx, scope.Global.String(), oldVersions[scope.Global.String()]))
+
+	// entries and heads are verified without the destroyed versions
+	freshRepo, err := inspect.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+	newOrgWrapper, err := kmsCache.GetWrapper(ctx, org.PublicId, kms.KeyPurposeOplog)
+	require.NoError(err)
+	for _, keyId := range userEntryKeyIds(freshRepo) {
+		assert.Equal(newOrgWrapper.KeyID(), keyId)
+	}
+	v, err = freshRepo.VerifyChains(ctx)
+	require.NoError(err)
+	assert.True(v.Valid())
+
+	// A chain with a problem is not rewritten, so the problem is still
+	// reported.
+	_, err = kmsCache.RotateKeys(ctx, org.PublicId)
+	require.NoError(err)
+	entries, err := repo.ListEntries(ctx, inspect.WithResourceId(users[1].PublicId))
+	require.NoError(err)
+	require.Len(entries, 1)
+	_, err = rw.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{}, func(_ db.Reader, w db.Writer) error {
+		if _, err := w.Exec(ctx, "set local boundary.oplog_rewrap = 'true';", nil); err != nil {
+			return err
+		}
+		_, err := w.Exec(ctx, "update oplog_entry set hash = $1 where id = $2", []interface{}{[]byte("tampered"), entries[0].Id})
+		return err
+	})
+	require.NoError(err)
+
+	_, err = repo.RewrapEntries(ctx)
+	require.Error(err)
+	for _, keyId := range userEntryKeyIds(repo) {
+		assert.Equal(newOrgWrapper.KeyID(), keyId)
+	}
+	v, err = repo.VerifyChains(ctx, inspect.WithTicketName(ticketName))
+	require.NoError(err)
+	assert.False(v.Valid())
+}
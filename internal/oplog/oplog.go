@@ -143,10 +143,8 @@ func (e *Entry) WriteEntryWith(ctx context.Context, tx Writer, ticket *store.Tic
 			return fmt.Errorf("error encrypting entry: %w", err)
 		}
 	}
-	if err := tx.Create(e); err != nil {
-		return fmt.Errorf("error writing data to storage: %w", err)
-	}
-	return e.Ticketer.Redeem(ticket)
+	e.addPrincipal(ctx)
+	return e.redeemAndCreate(tx, ticket)
 }
 
 // Write the entry as is with whatever it has for e.Data marshaled into a FIFO QueueBuffer
@@ -163,18 +161,36 @@ func (e *Entry) Write(ctx context.Context, tx Writer, ticket *store.Ticket) erro
 			return fmt.Errorf("error encrypting entry: %w", err)
 		}
 	}
+	e.addPrincipal(ctx)
+	return e.redeemAndCreate(tx, ticket)
+}
+
+// redeemAndCreate redeems the ticket, then chains the entry and writes it.
+// Redeeming first locks the ticket until the transaction ends, so the hash of
+// the previous entry is read after any transaction which holds the ticket,
+// such as one rewrapping the chain, has committed.
+func (e *Entry) redeemAndCreate(tx Writer, ticket *store.Ticket) error {
+	ticketName, ticketVersion := ticket.GetName(), ticket.GetVersion()
+	if err := e.Ticketer.Redeem(ticket); err != nil {
+		return err
+	}
+	if err := e.chain(tx, ticketName, ticketVersion); err != nil {
+		return fmt.Errorf("error chaining entry: %w", err)
+	}
 	if err := tx.Create(e); err != nil {
 		return fmt.Errorf("error writing data to storage: %w", err)
 	}
-	return e.Ticketer.Redeem(ticket)
+	return nil
 }
 
-// EncryptData the entry's data using its Cipherer (wrapping.Wrapper)
+// EncryptData the entry's data using its Cipherer (wrapping.Wrapper) and sets
+// its KeyId to the id of the key used
 func (e *Entry) EncryptData(ctx context.Context) error {
 	// structwrapping doesn't support embedding, so we'll pass in the store.Entry directly
 	if err := structwrapping.WrapStruct(ctx, e.Cipherer, e.Entry, nil); err != nil {
 		return fmt.Errorf("error encrypting entry: %w", err)
 	}
+	e.KeyId = e.Cipherer.KeyID()
 	return nil
 }
 
@@ -0,0 +1,69 @@
+package oplog
+
+import (
+	"context"
+
+	"github.com/hashicorp/boundary/internal/oplog/store"
+)
+
+const (
+	// PrincipalUserIdKey is the metadata key holding the id of the user whose
+	// request wrote an entry.
+	PrincipalUserIdKey = "principal-user-id"
+
+	// PrincipalAuthTokenIdKey is the metadata key holding the id of the auth
+	// token used by the request which wrote an entry.
+	PrincipalAuthTokenIdKey = "principal-auth-token-id"
+)
+
+// Principal identifies who made the request which wrote an entry.
+type Principal struct {
+	UserId      string
+	AuthTokenId string
+}
+
+type principalKey struct{}
+
+// NewPrincipalContext returns a context which can carry the principal of a
+// request. The principal is usually only known once the request has been
+// authenticated, well after the context was created, so it is set on the
+// returned context with SetPrincipal.
+func NewPrincipalContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, principalKey{}, new(Principal))
+}
+
+// SetPrincipal sets the principal carried by a context created with
+// NewPrincipalContext. It does nothing for any other context.
+func SetPrincipal(ctx context.Context, p Principal) {
+	if holder, ok := ctx.Value(principalKey{}).(*Principal); ok {
+		*holder = p
+	}
+}
+
+// PrincipalFromContext returns the principal carried by the context, if one
+// was set.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	holder, ok := ctx.Value(principalKey{}).(*Principal)
+	if !ok || holder.UserId == "" {
+		return Principal{}, false
+	}
+	return *holder, true
+}
+
+// addPrincipal adds the principal carried by the context, if any, to the
+// entry's metadata.
+func (e *Entry) addPrincipal(ctx context.Context) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return
+	}
+	for _, m := range e.Metadata {
+		if m.Key == PrincipalUserIdKey {
+			return
+		}
+	}
+	e.Metadata = append(e.Metadata, &store.Metadata{Key: PrincipalUserIdKey, Value: p.UserId})
+	if p.AuthTokenId != "" {
+		e.Metadata = append(e.Metadata, &store.Metadata{Key: PrincipalAuthTokenIdKey, Value: p.AuthTokenId})
+	}
+}
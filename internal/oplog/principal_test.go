@@ -0,0 +1,47 @@
+package oplog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Principal(t *testing.T) {
+	t.Run("not-set", func(t *testing.T) {
+		assert := assert.New(t)
+		ctx := NewPrincipalContext(context.Background())
+		_, ok := PrincipalFromContext(ctx)
+		assert.False(ok)
+
+		e := &Entry{Entry: &store.Entry{}}
+		e.addPrincipal(ctx)
+		assert.Empty(e.Metadata)
+	})
+	t.Run("no-holder", func(t *testing.T) {
+		assert := assert.New(t)
+		ctx := context.Background()
+		SetPrincipal(ctx, Principal{UserId: "u_1234567890"})
+		_, ok := PrincipalFromContext(ctx)
+		assert.False(ok)
+	})
+	t.Run("set", func(t *testing.T) {
+		assert := assert.New(t)
+		ctx := NewPrincipalContext(context.Background())
+		want := Principal{UserId: "u_1234567890", AuthTokenId: "at_1234567890"}
+		SetPrincipal(ctx, want)
+		got, ok := PrincipalFromContext(ctx)
+		assert.True(ok)
+		assert.Equal(want, got)
+
+		e := &Entry{Entry: &store.Entry{Metadata: []*store.Metadata{{Key: "scope-id", Value: "o_1234567890"}}}}
+		e.addPrincipal(ctx)
+		e.addPrincipal(ctx)
+		assert.Equal([]*store.Metadata{
+			{Key: "scope-id", Value: "o_1234567890"},
+			{Key: PrincipalUserIdKey, Value: "u_1234567890"},
+			{Key: PrincipalAuthTokenIdKey, Value: "at_1234567890"},
+		}, e.Metadata)
+	})
+}
@@ -0,0 +1,199 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+)
+
+// archiveFormat identifies the format of archive files. An archive is a gzip
+// compressed file of JSON lines: a header followed by one line per entry. The
+// data of entries is written exactly as stored, so it remains encrypted with
+// the oplog key version of the scope which wrote it.
+const archiveFormat = "boundary-oplog-archive-v1"
+
+// archiveHeader is the first line of an archive file.
+type archiveHeader struct {
+	Format        string    `json:"format"`
+	AggregateName string    `json:"aggregate_name"`
+	FirstEntryId  uint32    `json:"first_entry_id"`
+	LastEntryId   uint32    `json:"last_entry_id"`
+	EntryCount    int       `json:"entry_count"`
+	CreateTime    time.Time `json:"create_time"`
+}
+
+// archivedEntry is an oplog entry as written to an archive file. Entries
+// written before hash chaining have no ticket or hashes, and entries archived
+// before key ids were recorded have no key id.
+type archivedEntry struct {
+	Id            uint32              `json:"id"`
+	CreateTime    time.Time           `json:"create_time"`
+	UpdateTime    time.Time           `json:"update_time"`
+	Version       string              `json:"version"`
+	AggregateName string              `json:"aggregate_name"`
+	Data          []byte              `json:"data"`
+	TicketName    *string             `json:"ticket_name,omitempty"`
+	TicketVersion *uint32             `json:"ticket_version,omitempty"`
+	PrevHash      []byte              `json:"prev_hash,omitempty"`
+	Hash          []byte              `json:"hash,omitempty"`
+	KeyId         *string             `json:"key_id,omitempty"`
+	Metadata      []*archivedMetadata `json:"metadata,omitempty"`
+}
+
+type archivedMetadata struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value,omitempty"`
+}
+
+// verifyHash returns an error if the entry is chained and its hash doesn't
+// match its contents.
+func (e *archivedEntry) verifyHash() error {
+	if e.TicketName == nil || e.TicketVersion == nil {
+		return nil
+	}
+	se := &store.Entry{
+		Version:       e.Version,
+		AggregateName: e.AggregateName,
+		CtData:        e.Data,
+		TicketName:    *e.TicketName,
+		TicketVersion: *e.TicketVersion,
+		PrevHash:      e.PrevHash,
+		Hash:          e.Hash,
+	}
+	for _, m := range e.Metadata {
+		sm := &store.Metadata{Key: m.Key}
+		if m.Value != nil {
+			sm.Value = *m.Value
+		}
+		se.Metadata = append(se.Metadata, sm)
+	}
+	if err := (&oplog.Entry{Entry: se}).VerifyHash(); err != nil {
+		return fmt.Errorf("entry %d: %w", e.Id, err)
+	}
+	return nil
+}
+
+// writeArchive writes the entries, which must all be of the aggregate and
+// ordered by id, as an archive.
+func writeArchive(w io.Writer, aggregateName string, entries []*archivedEntry) (*archiveHeader, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("no entries to archive")
+	}
+	h := &archiveHeader{
+		Format:        archiveFormat,
+		AggregateName: aggregateName,
+		FirstEntryId:  entries[0].Id,
+		LastEntryId:   entries[len(entries)-1].Id,
+		EntryCount:    len(entries),
+		CreateTime:    time.Now().UTC(),
+	}
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(h); err != nil {
+		return nil, fmt.Errorf("unable to write archive header: %w", err)
+	}
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return nil, fmt.Errorf("unable to write entry %d: %w", e.Id, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("unable to compress archive: %w", err)
+	}
+	return h, nil
+}
+
+// readArchive reads an archive written by writeArchive, verifying the hash of
+// every chained entry.
+func readArchive(r io.Reader) (*archiveHeader, []*archivedEntry, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to decompress archive: %w", err)
+	}
+	defer zr.Close()
+	dec := json.NewDecoder(bufio.NewReader(zr))
+
+	h := &archiveHeader{}
+	if err := dec.Decode(h); err != nil {
+		return nil, nil, fmt.Errorf("unable to read archive header: %w", err)
+	}
+	if h.Format != archiveFormat {
+		return nil, nil, fmt.Errorf("unsupported archive format %q", h.Format)
+	}
+	entries := make([]*archivedEntry, 0, h.EntryCount)
+	for {
+		e := &archivedEntry{}
+		err := dec.Decode(e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read entry: %w", err)
+		}
+		if e.AggregateName != h.AggregateName {
+			return nil, nil, fmt.Errorf("entry %d has aggregate %q, expected %q", e.Id, e.AggregateName, h.AggregateName)
+		}
+		if err := e.verifyHash(); err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != h.EntryCount {
+		return nil, nil, fmt.Errorf("archive has %d entries, expected %d", len(entries), h.EntryCount)
+	}
+	return h, entries, nil
+}
+
+func readArchiveFile(path string) (*archiveHeader, []*archivedEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return readArchive(f)
+}
+
+// archiveFileName returns the name of the archive file for the entries of the
+// aggregate with ids from first to last.
+func archiveFileName(aggregateName string, first, last uint32) string {
+	return fmt.Sprintf("oplog-%s-%d-%d.jsonl.gz", aggregateName, first, last)
+}
+
+// writeArchiveFile writes the entries as an archive in dir. The file is synced
+// and only given its final name once complete, so an archive file is never
+// partially written.
+func writeArchiveFile(dir, aggregateName string, entries []*archivedEntry) (string, *archiveHeader, error) {
+	tmp, err := ioutil.TempFile(dir, ".oplog-archive-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to create archive file: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+	h, err := writeArchive(tmp, aggregateName, entries)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", nil, fmt.Errorf("unable to sync archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", nil, fmt.Errorf("unable to close archive file: %w", err)
+	}
+	name := archiveFileName(aggregateName, h.FirstEntryId, h.LastEntryId)
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return "", nil, fmt.Errorf("unable to rename archive file: %w", err)
+	}
+	return name, h, nil
+}
@@ -0,0 +1,196 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/lib/pq"
+)
+
+// Archive is an archive file of oplog entries written by Prune.
+type Archive struct {
+	FileName      string
+	AggregateName string
+	FirstEntryId  uint32
+	LastEntryId   uint32
+	EntryCount    int
+	CreateTime    time.Time
+	// RestoreTime is when the entries of the archive were restored, or the
+	// zero time if they are not restored
+	RestoreTime time.Time
+}
+
+const listArchivesQuery = `
+select file_name, aggregate_name, first_entry_id, last_entry_id, entry_count, create_time, restore_time
+  from oplog_archive
+ where true%s
+ order by id desc
+%s;
+`
+
+// ListArchives returns the archives written by Prune, newest first. Supports
+// the options WithAggregateName and WithLimit.
+func (r *Repository) ListArchives(ctx context.Context, opt ...Option) ([]*Archive, error) {
+	opts := getOpts(opt...)
+	var where string
+	var args []interface{}
+	if opts.withAggregateName != "" {
+		where = "\n   and aggregate_name = $1"
+		args = append(args, opts.withAggregateName)
+	}
+	var limit string
+	switch {
+	case opts.withLimit < 0: // any negative number signals unlimited results
+	case opts.withLimit == 0: // zero signals the default value and default limits
+		limit = fmt.Sprintf("limit %d", r.defaultLimit)
+	default:
+		// non-zero signals an override of the default limit for the repo.
+		limit = fmt.Sprintf("limit %d", opts.withLimit)
+	}
+
+	rows, err := r.reader.Query(ctx, fmt.Sprintf(listArchivesQuery, where, limit), args)
+	if err != nil {
+		return nil, fmt.Errorf("list archives: %w", err)
+	}
+	defer rows.Close()
+	var archives []*Archive
+	for rows.Next() {
+		a := &Archive{}
+		var restoreTime sql.NullTime
+		if err := rows.Scan(&a.FileName, &a.AggregateName, &a.FirstEntryId, &a.LastEntryId, &a.EntryCount, &a.CreateTime, &restoreTime); err != nil {
+			return nil, fmt.Errorf("list archives: scan row failed: %w", err)
+		}
+		a.RestoreTime = restoreTime.Time
+		archives = append(archives, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list archives: %w", err)
+	}
+	return archives, nil
+}
+
+const (
+	restoreEntryQuery = `
+insert into oplog_entry (id, create_time, update_time, version, aggregate_name, data, ticket_name, ticket_version, prev_hash, hash, key_id)
+overriding system value
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+on conflict (id) do nothing;
+`
+	restoreMetadataQuery = `
+insert into oplog_metadata (entry_id, key, value)
+values ($1, $2, $3);
+`
+	markRestoredQuery = `
+insert into oplog_archive (file_name, aggregate_name, first_entry_id, last_entry_id, entry_count, restore_time)
+values ($1, $2, $3, $4, $5, now())
+on conflict (file_name) do update set restore_time = now();
+`
+	releaseQuery = `
+update oplog_archive set restore_time = null where file_name = $1;
+`
+)
+
+// RestoreArchive inserts the entries of the archive file back into the oplog
+// with their original ids and create times, so they can be inspected like any
+// other entry. The hash of every chained entry is verified first. Restored
+// entries are not pruned again until the archive is released with
+// ReleaseArchive. Entries which are already present are left unchanged. The
+// restored archive is returned.
+func (r *Repository) RestoreArchive(ctx context.Context, path string) (*Archive, error) {
+	if path == "" {
+		return nil, fmt.Errorf("restore archive: missing path: %w", db.ErrInvalidParameter)
+	}
+	h, entries, err := readArchiveFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("restore archive: %w", err)
+	}
+	name := filepath.Base(path)
+
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			// Allows inserting entries with their original create times; see
+			// oplog_entry_default_create_time.
+			if _, err := w.Exec(ctx, "set local boundary.oplog_restore = 'true';", nil); err != nil {
+				return err
+			}
+			for _, e := range entries {
+				n, err := w.Exec(ctx, restoreEntryQuery, []interface{}{
+					e.Id, e.CreateTime, e.UpdateTime, e.Version, e.AggregateName, e.Data,
+					e.TicketName, e.TicketVersion, e.PrevHash, e.Hash, e.KeyId,
+				})
+				if err != nil {
+					return fmt.Errorf("unable to restore entry %d: %w", e.Id, err)
+				}
+				if n == 0 {
+					continue
+				}
+				for _, m := range e.Metadata {
+					if _, err := w.Exec(ctx, restoreMetadataQuery, []interface{}{e.Id, m.Key, m.Value}); err != nil {
+						return fmt.Errorf("unable to restore metadata of entry %d: %w", e.Id, err)
+					}
+				}
+			}
+			if _, err := w.Exec(ctx, markRestoredQuery, []interface{}{name, h.AggregateName, h.FirstEntryId, h.LastEntryId, h.EntryCount}); err != nil {
+				return fmt.Errorf("unable to record restore: %w", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("restore archive: %s: %w", name, err)
+	}
+	return &Archive{
+		FileName:      name,
+		AggregateName: h.AggregateName,
+		FirstEntryId:  h.FirstEntryId,
+		LastEntryId:   h.LastEntryId,
+		EntryCount:    h.EntryCount,
+		CreateTime:    h.CreateTime,
+		RestoreTime:   time.Now(),
+	}, nil
+}
+
+// ReleaseArchive deletes the entries restored from the archive file, which
+// remains the record of them, returning the number of entries deleted.
+func (r *Repository) ReleaseArchive(ctx context.Context, path string) (int, error) {
+	if path == "" {
+		return 0, fmt.Errorf("release archive: missing path: %w", db.ErrInvalidParameter)
+	}
+	h, entries, err := readArchiveFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("release archive: %w", err)
+	}
+	name := filepath.Base(path)
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, int64(e.Id))
+	}
+
+	var deleted int
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			deleted, err = w.Exec(ctx, "delete from oplog_entry where id = any($1) and aggregate_name = $2;", []interface{}{pq.Array(ids), h.AggregateName})
+			if err != nil {
+				return fmt.Errorf("unable to delete entries: %w", err)
+			}
+			if _, err := w.Exec(ctx, releaseQuery, []interface{}{name}); err != nil {
+				return fmt.Errorf("unable to record release: %w", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("release archive: %s: %w", name, err)
+	}
+	return deleted, nil
+}
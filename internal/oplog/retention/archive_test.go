@@ -0,0 +1,122 @@
+package retention
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testArchivedEntries(t *testing.T) []*archivedEntry {
+	t.Helper()
+	ticketName, scopeId := "iam_user", "o_1234567890"
+	var entries []*archivedEntry
+	var prevHash []byte
+	for i := uint32(1); i <= 3; i++ {
+		ticketVersion := i
+		e := &archivedEntry{
+			Id:            10 + i,
+			CreateTime:    time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond),
+			UpdateTime:    time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond),
+			Version:       "v1",
+			AggregateName: "iam_user",
+			Data:          []byte("ciphertext"),
+			TicketName:    &ticketName,
+			TicketVersion: &ticketVersion,
+			PrevHash:      prevHash,
+			Metadata: []*archivedMetadata{
+				{Key: "scope-id", Value: &scopeId},
+				{Key: "key-only"},
+			},
+		}
+		se := &store.Entry{
+			Version:       e.Version,
+			AggregateName: e.AggregateName,
+			CtData:        e.Data,
+			TicketName:    ticketName,
+			TicketVersion: ticketVersion,
+			PrevHash:      prevHash,
+			Metadata: []*store.Metadata{
+				{Key: "scope-id", Value: scopeId},
+				{Key: "key-only"},
+			},
+		}
+		e.Hash = (&oplog.Entry{Entry: se}).ComputeHash()
+		prevHash = e.Hash
+		entries = append(entries, e)
+	}
+	// an entry written before hash chaining
+	entries = append(entries, &archivedEntry{
+		Id:            20,
+		Version:       "v1",
+		AggregateName: "iam_user",
+		Data:          []byte("legacy"),
+	})
+	return entries
+}
+
+func Test_writeReadArchive(t *testing.T) {
+	t.Parallel()
+	t.Run("round-trip", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		entries := testArchivedEntries(t)
+		var buf bytes.Buffer
+		h, err := writeArchive(&buf, "iam_user", entries)
+		require.NoError(err)
+		assert.Equal(uint32(11), h.FirstEntryId)
+		assert.Equal(uint32(20), h.LastEntryId)
+		assert.Equal(4, h.EntryCount)
+
+		gotHeader, gotEntries, err := readArchive(&buf)
+		require.NoError(err)
+		assert.Equal(h.AggregateName, gotHeader.AggregateName)
+		assert.Equal(h.EntryCount, gotHeader.EntryCount)
+		assert.Equal(entries, gotEntries)
+	})
+	t.Run("tampered", func(t *testing.T) {
+		require := require.New(t)
+		entries := testArchivedEntries(t)
+		entries[1].Data = []byte("tampered")
+		var buf bytes.Buffer
+		_, err := writeArchive(&buf, "iam_user", entries)
+		require.NoError(err)
+		_, _, err = readArchive(&buf)
+		require.Error(err)
+	})
+	t.Run("double-compressed", func(t *testing.T) {
+		require := require.New(t)
+		entries := testArchivedEntries(t)
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := writeArchive(zw, "iam_user", entries)
+		require.NoError(err)
+		require.NoError(zw.Close())
+		_, _, err = readArchive(&buf)
+		require.Error(err)
+	})
+	t.Run("no-entries", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := writeArchive(&buf, "iam_user", nil)
+		require.Error(t, err)
+	})
+}
+
+func TestPolicy_Period(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	p := Policy{
+		Default: time.Hour,
+		Aggregates: map[string]time.Duration{
+			"iam_user":   2 * time.Hour,
+			"target_tcp": 0,
+		},
+	}
+	assert.Equal(2*time.Hour, p.Period("iam_user"))
+	assert.Equal(time.Duration(0), p.Period("target_tcp"))
+	assert.Equal(time.Hour, p.Period("iam_scope"))
+}
@@ -0,0 +1,26 @@
+package retention
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_GetOpts provides unit tests for GetOpts and all the options
+func Test_GetOpts(t *testing.T) {
+	t.Parallel()
+	t.Run("WithLimit", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithLimit(5))
+		testOpts := getDefaultOptions()
+		testOpts.withLimit = 5
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithAggregateName", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithAggregateName("iam_user"))
+		testOpts := getDefaultOptions()
+		testOpts.withAggregateName = "iam_user"
+		assert.Equal(opts, testOpts)
+	})
+}
@@ -0,0 +1,270 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/lib/pq"
+)
+
+// Policy sets how long the entries of each aggregate are retained.
+type Policy struct {
+	// Default is the retention period of aggregates not in Aggregates. Zero
+	// retains entries forever.
+	Default time.Duration
+	// Aggregates are the retention periods of aggregates by name. Zero
+	// retains entries of the aggregate forever.
+	Aggregates map[string]time.Duration
+}
+
+// Period returns the retention period of the aggregate.
+func (p Policy) Period(aggregateName string) time.Duration {
+	if d, ok := p.Aggregates[aggregateName]; ok {
+		return d
+	}
+	return p.Default
+}
+
+const defaultPruneBatchSize = 1000
+
+// Each ticket is named for the aggregate it serializes writes to, so the
+// ticket names are the names of every aggregate which has entries.
+const aggregateNamesQuery = `
+select name from oplog_ticket order by name;
+`
+
+// The newest entry of each hash chain is never pruned, so a chain can still be
+// checked against its signed head. Entries of restored archives are kept
+// until the archive is released.
+const pruneCandidatesQuery = `
+select e.id
+  from oplog_entry e
+ where e.aggregate_name = $1
+   and e.create_time < $2
+   and (e.ticket_name is null
+        or exists (
+          select 1 from oplog_entry n
+           where n.ticket_name = e.ticket_name
+             and n.ticket_version > e.ticket_version
+        ))
+   and not exists (
+     select 1 from oplog_archive a
+      where a.restore_time is not null
+        and a.aggregate_name = e.aggregate_name
+        and e.id between a.first_entry_id and a.last_entry_id
+   )
+ order by e.id
+ limit %d
+   for update of e skip locked;
+`
+
+const archiveEntriesQuery = `
+select e.id, e.create_time, e.update_time, e.version, e.aggregate_name, e.data,
+       e.ticket_name, e.ticket_version, e.prev_hash, e.hash, e.key_id,
+       (select json_agg(json_build_array(m.key, m.value) order by m.id)
+          from oplog_metadata m
+         where m.entry_id = e.id) as metadata
+  from oplog_entry e
+ where e.id = any($1)
+ order by e.id;
+`
+
+const insertArchiveQuery = `
+insert into oplog_archive (file_name, aggregate_name, first_entry_id, last_entry_id, entry_count)
+values ($1, $2, $3, $4, $5);
+`
+
+// Prune archives and then deletes the entries of every aggregate which are
+// older than the aggregate's retention period. Archive files are written to
+// archiveDir, each holding up to the batch size of entries of one aggregate,
+// and are recorded in the database. The archives written are returned. The
+// batch size can be set with WithLimit.
+//
+// Prune is safe to run concurrently from multiple controllers: each entry is
+// archived by exactly one of them.
+func (r *Repository) Prune(ctx context.Context, policy Policy, archiveDir string, opt ...Option) ([]*Archive, error) {
+	if archiveDir == "" {
+		return nil, fmt.Errorf("prune: missing archive directory: %w", db.ErrInvalidParameter)
+	}
+	if err := os.MkdirAll(archiveDir, 0o700); err != nil {
+		return nil, fmt.Errorf("prune: unable to create archive directory: %w", err)
+	}
+	opts := getOpts(opt...)
+	batchSize := opts.withLimit
+	if batchSize <= 0 {
+		batchSize = defaultPruneBatchSize
+	}
+
+	names, err := r.aggregateNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("prune: %w", err)
+	}
+	var archives []*Archive
+	for _, name := range names {
+		period := policy.Period(name)
+		if period <= 0 {
+			continue
+		}
+		cutoff := time.Now().Add(-period)
+		for {
+			a, err := r.pruneBatch(ctx, name, cutoff, archiveDir, batchSize)
+			if err != nil {
+				return archives, fmt.Errorf("prune: %s: %w", name, err)
+			}
+			if a == nil {
+				break
+			}
+			archives = append(archives, a)
+			if a.EntryCount < batchSize {
+				break
+			}
+		}
+	}
+	return archives, nil
+}
+
+func (r *Repository) aggregateNames(ctx context.Context) ([]string, error) {
+	rows, err := r.reader.Query(ctx, aggregateNamesQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// pruneBatch archives and deletes up to batchSize entries of the aggregate
+// created before cutoff, returning the archive written or nil if there were
+// no entries to prune.
+func (r *Repository) pruneBatch(ctx context.Context, aggregateName string, cutoff time.Time, archiveDir string, batchSize int) (*Archive, error) {
+	var archive *Archive
+	var archivePath string
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			archive = nil
+			ids, err := queryIds(ctx, reader, fmt.Sprintf(pruneCandidatesQuery, batchSize), aggregateName, cutoff)
+			if err != nil {
+				return fmt.Errorf("unable to select entries: %w", err)
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			entries, err := queryArchivedEntries(ctx, reader, ids)
+			if err != nil {
+				return fmt.Errorf("unable to read entries: %w", err)
+			}
+
+			name, h, err := writeArchiveFile(archiveDir, aggregateName, entries)
+			if err != nil {
+				return err
+			}
+			archivePath = filepath.Join(archiveDir, name)
+
+			deleted, err := w.Exec(ctx, "delete from oplog_entry where id = any($1);", []interface{}{pq.Array(ids)})
+			if err != nil {
+				return fmt.Errorf("unable to delete entries: %w", err)
+			}
+			if deleted != len(ids) {
+				return fmt.Errorf("deleted %d entries, expected %d", deleted, len(ids))
+			}
+			if _, err := w.Exec(ctx, insertArchiveQuery, []interface{}{name, aggregateName, h.FirstEntryId, h.LastEntryId, h.EntryCount}); err != nil {
+				return fmt.Errorf("unable to record archive: %w", err)
+			}
+			archive = &Archive{
+				FileName:      name,
+				AggregateName: aggregateName,
+				FirstEntryId:  h.FirstEntryId,
+				LastEntryId:   h.LastEntryId,
+				EntryCount:    h.EntryCount,
+				CreateTime:    h.CreateTime,
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		// The entries were not deleted, so the archive would only duplicate
+		// them.
+		if archivePath != "" {
+			os.Remove(archivePath)
+		}
+		return nil, err
+	}
+	return archive, nil
+}
+
+func queryIds(ctx context.Context, r db.Reader, q string, args ...interface{}) ([]int64, error) {
+	rows, err := r.Query(ctx, q, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func queryArchivedEntries(ctx context.Context, r db.Reader, ids []int64) ([]*archivedEntry, error) {
+	rows, err := r.Query(ctx, archiveEntriesQuery, []interface{}{pq.Array(ids)})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []*archivedEntry
+	for rows.Next() {
+		e := &archivedEntry{}
+		var ticketName sql.NullString
+		var ticketVersion sql.NullInt64
+		var keyId sql.NullString
+		var md []byte
+		if err := rows.Scan(&e.Id, &e.CreateTime, &e.UpdateTime, &e.Version, &e.AggregateName, &e.Data,
+			&ticketName, &ticketVersion, &e.PrevHash, &e.Hash, &keyId, &md); err != nil {
+			return nil, fmt.Errorf("scan row failed: %w", err)
+		}
+		if ticketName.Valid {
+			e.TicketName = &ticketName.String
+		}
+		if ticketVersion.Valid {
+			v := uint32(ticketVersion.Int64)
+			e.TicketVersion = &v
+		}
+		if keyId.Valid {
+			e.KeyId = &keyId.String
+		}
+		if len(md) > 0 {
+			var pairs [][2]*string
+			if err := json.Unmarshal(md, &pairs); err != nil {
+				return nil, fmt.Errorf("entry %d: unable to parse metadata: %w", e.Id, err)
+			}
+			for _, p := range pairs {
+				if p[0] == nil {
+					return nil, fmt.Errorf("entry %d: metadata with null key", e.Id)
+				}
+				e.Metadata = append(e.Metadata, &archivedMetadata{Key: *p[0], Value: p[1]})
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
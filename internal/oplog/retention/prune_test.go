@@ -0,0 +1,101 @@
+package retention_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/hashicorp/boundary/internal/oplog/retention"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Prune(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	org, _ := iam.TestScopes(t, iamRepo)
+	var users []*iam.User
+	for i := 0; i < 3; i++ {
+		users = append(users, iam.TestUser(t, iamRepo, org.PublicId))
+	}
+	aggregateName := new(iam.User).TableName()
+
+	repo, err := retention.NewRepository(rw, rw)
+	require.NoError(err)
+	inspectRepo, err := inspect.NewRepository(rw, rw, kms.TestKms(t, conn, wrapper))
+	require.NoError(err)
+	countEntries := func() int {
+		t.Helper()
+		entries, err := inspectRepo.ListEntries(ctx, inspect.WithAggregateName(aggregateName), inspect.WithLimit(-1))
+		require.NoError(err)
+		return len(entries)
+	}
+	before := countEntries()
+	require.GreaterOrEqual(before, 3)
+	lastUser, err := inspectRepo.ListEntries(ctx, inspect.WithResourceId(users[2].PublicId))
+	require.NoError(err)
+	require.Len(lastUser, 1)
+
+	dir, err := ioutil.TempDir("", "oplog-archive")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	policy := retention.Policy{Aggregates: map[string]time.Duration{aggregateName: time.Nanosecond}}
+	archives, err := repo.Prune(ctx, policy, dir, retention.WithLimit(2))
+	require.NoError(err)
+	require.NotEmpty(archives)
+	var archived int
+	for _, a := range archives {
+		assert.Equal(aggregateName, a.AggregateName)
+		assert.FileExists(filepath.Join(dir, a.FileName))
+		archived += a.EntryCount
+	}
+	// the newest entry of the chain is kept
+	assert.Equal(before-1, archived)
+	assert.Equal(1, countEntries())
+	kept, err := inspectRepo.ListEntries(ctx, inspect.WithAggregateName(aggregateName))
+	require.NoError(err)
+	assert.Equal(lastUser[0].Id, kept[0].Id)
+
+	v, err := inspectRepo.VerifyChains(ctx, inspect.WithTicketName(aggregateName))
+	require.NoError(err)
+	assert.True(v.Valid())
+
+	listed, err := repo.ListArchives(ctx, retention.WithAggregateName(aggregateName))
+	require.NoError(err)
+	assert.Len(listed, len(archives))
+
+	// restore the archive of the first user
+	path := filepath.Join(dir, archives[0].FileName)
+	restored, err := repo.RestoreArchive(ctx, path)
+	require.NoError(err)
+	assert.False(restored.RestoreTime.IsZero())
+	assert.Equal(1+archives[0].EntryCount, countEntries())
+	entries, err := inspectRepo.ListEntries(ctx, inspect.WithResourceId(users[0].PublicId))
+	require.NoError(err)
+	require.Len(entries, 1)
+	assert.True(entries[0].CreateTime.Before(time.Now().Add(-time.Millisecond)))
+	require.Len(entries[0].Messages, 1)
+
+	// restored entries are kept until released
+	archives, err = repo.Prune(ctx, policy, dir)
+	require.NoError(err)
+	assert.Empty(archives)
+
+	deleted, err := repo.ReleaseArchive(ctx, path)
+	require.NoError(err)
+	assert.Equal(restored.EntryCount, deleted)
+	assert.Equal(1, countEntries())
+}
@@ -0,0 +1,39 @@
+package retention
+
+import (
+	"errors"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// Repository archives oplog entries which are past their retention period to
+// files, deletes them, and restores archived entries for forensic queries.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+
+	// defaultLimit provides a default for limiting the number of results returned from the repo
+	defaultLimit int
+}
+
+// NewRepository creates a new oplog retention Repository. Supports the
+// options: WithLimit which sets a default limit on results returned by repo
+// operations.
+func NewRepository(r db.Reader, w db.Writer, opt ...Option) (*Repository, error) {
+	if r == nil {
+		return nil, errors.New("error creating db repository with nil reader")
+	}
+	if w == nil {
+		return nil, errors.New("error creating db repository with nil writer")
+	}
+	opts := getOpts(opt...)
+	if opts.withLimit == 0 {
+		// zero signals the boundary defaults should be used.
+		opts.withLimit = db.DefaultLimit
+	}
+	return &Repository{
+		reader:       r,
+		writer:       w,
+		defaultLimit: opts.withLimit,
+	}, nil
+}
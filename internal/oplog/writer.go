@@ -32,6 +32,10 @@ type Writer interface {
 
 	// DropTableIfExists will drop the table if it exists
 	dropTableIfExists(tableName string) error
+
+	// entryHash returns the hash of the entry written with the ticket
+	// version, or nil if there is no such entry
+	entryHash(ticketName string, ticketVersion uint32) ([]byte, error)
 }
 
 // GormWriter uses a gorm DB connection for writing
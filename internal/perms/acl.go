@@ -129,11 +129,15 @@ func topLevelType(typ resource.Type) bool {
 		resource.AuthToken,
 		resource.Group,
 		resource.HostCatalog,
+		resource.Job,
+		resource.Key,
+		resource.Report,
 		resource.Role,
 		resource.Scope,
 		resource.Session,
 		resource.Target,
-		resource.User:
+		resource.User,
+		resource.Worker:
 		return true
 	}
 	return false
@@ -336,7 +336,11 @@ func (g Grant) validateType() error {
 		resource.HostSet,
 		resource.Host,
 		resource.Target,
-		resource.Session:
+		resource.Session,
+		resource.Report,
+		resource.Key,
+		resource.Worker,
+		resource.Job:
 		return nil
 	}
 	return fmt.Errorf("unknown type specifier %q", g.typ)
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/hashicorp/yamux"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxStreamResultSize bounds the size of a stream result read from a stream.
+const maxStreamResultSize = 4096
+
+// ErrStreamUnauthorized is returned by ReadStreamResult when the worker
+// closed the stream without a result because it did not authorize a
+// connection for it.
+var ErrStreamUnauthorized = errors.New("stream closed before its result")
+
+// MuxConfig returns the configuration of both ends of the yamux session of
+// the multiplexed TCP proxy protocol. After the handshake of the session's
+// websocket, the client opens a stream for each proxied connection; the
+// worker authorizes a connection for the stream and writes its result to
+// the stream before proxying it to the endpoint. The handshake result of the
+// websocket itself accounts for no connection, so its connections_left is
+// not set.
+func MuxConfig() *yamux.Config {
+	conf := yamux.DefaultConfig()
+	conf.LogOutput = ioutil.Discard
+	conf.ConnectionWriteTimeout = 30 * time.Second
+	return conf
+}
+
+// WriteStreamResult writes the result of authorizing the connection of a
+// stream as a big-endian length-prefixed message.
+func WriteStreamResult(w io.Writer, result *HandshakeResult) error {
+	msg, err := proto.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("error marshaling stream result: %w", err)
+	}
+	buf := make([]byte, 4+len(msg))
+	binary.BigEndian.PutUint32(buf, uint32(len(msg)))
+	copy(buf[4:], msg)
+	if _, err := w.Write(buf); err != nil {
+		return fmt.Errorf("error writing stream result: %w", err)
+	}
+	return nil
+}
+
+// ReadStreamResult reads the result written by WriteStreamResult. It
+// returns ErrStreamUnauthorized if the stream is closed before the result.
+func ReadStreamResult(r io.Reader) (*HandshakeResult, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrStreamUnauthorized
+		}
+		return nil, fmt.Errorf("error reading stream result: %w", err)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxStreamResultSize {
+		return nil, fmt.Errorf("stream result of %d bytes is too large", n)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, fmt.Errorf("error reading stream result: %w", err)
+	}
+	result := new(HandshakeResult)
+	if err := proto.Unmarshal(msg, result); err != nil {
+		return nil, fmt.Errorf("error unmarshaling stream result: %w", err)
+	}
+	return result, nil
+}
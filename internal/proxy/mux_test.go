@@ -0,0 +1,40 @@
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestStreamResult(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	want := &HandshakeResult{
+		Expiration:      timestamppb.Now(),
+		ConnectionLimit: 10,
+		ConnectionsLeft: 7,
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(WriteStreamResult(buf, want))
+	buf.WriteString("proxied data")
+
+	got, err := ReadStreamResult(buf)
+	require.NoError(err)
+	assert.True(proto.Equal(want, got))
+	assert.Equal("proxied data", buf.String(), "only the result is read")
+
+	_, err = ReadStreamResult(new(bytes.Buffer))
+	assert.True(errors.Is(err, ErrStreamUnauthorized))
+
+	_, err = ReadStreamResult(bytes.NewReader([]byte{0, 0, 0, 5, 1}))
+	assert.Error(err)
+	assert.False(errors.Is(err, ErrStreamUnauthorized))
+
+	_, err = ReadStreamResult(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff}))
+	assert.Error(err)
+}
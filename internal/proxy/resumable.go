@@ -0,0 +1,445 @@
+package proxy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// ResumeGracePeriod is how long a resumable stream waits for its
+	// transport to be resumed after it failed before the stream is aborted.
+	ResumeGracePeriod = time.Minute
+
+	// MaxResumablePayloadSize is the largest amount of data sent in a single
+	// frame of a resumable stream, keeping frames below the default read
+	// limit of a websocket.
+	MaxResumablePayloadSize = 16 * 1024
+
+	// DefaultResumableBufferSize is the default amount of data written to a
+	// resumable stream that can be waiting for the peer to read it.
+	DefaultResumableBufferSize = 1024 * 1024
+
+	resumableFrameHeaderSize = 9
+
+	resumableFrameData byte = 1
+	resumableFrameAck  byte = 2
+	resumableFrameFin  byte = 3
+)
+
+var (
+	// ErrCannotResume is returned when a resumable stream cannot be resumed,
+	// for instance because the offset the peer received up to is no longer
+	// buffered or the worker no longer knows the stream.
+	ErrCannotResume = errors.New("cannot resume stream")
+
+	// ErrResumableClosed is returned when writing to a resumable stream that
+	// was closed.
+	ErrResumableClosed = errors.New("resumable stream closed")
+
+	// ErrInvalidResumableFrame is returned when the peer of a resumable
+	// stream sends a frame that does not fit the state of the stream.
+	ErrInvalidResumableFrame = errors.New("invalid resumable stream frame")
+)
+
+// ResumableStream is a bidirectional byte stream carried over a transport
+// that can fail and be replaced without losing data. Data is sent in frames
+// numbered by the offset of their first byte in the stream. Written data is
+// kept until the peer acknowledges having read it, so that whatever the peer
+// did not receive is sent again when the transport is resumed from the
+// offset it received up to. The amount of data kept doubles as flow control:
+// writes block while the peer has not read enough of what was sent.
+//
+// Closing the stream sends a FIN frame once all written data was sent, after
+// which the peer reads io.EOF. The stream is done once both ends have closed
+// and read each other's FIN.
+type ResumableStream struct {
+	bufferSize int
+
+	// attachL serializes replacing the transport
+	attachL sync.Mutex
+
+	l    sync.Mutex
+	cond *sync.Cond
+
+	// unacked holds the written data from offset acked on that the peer has
+	// not acknowledged reading yet
+	unacked  []byte
+	acked    uint64
+	closing  bool
+	finAcked bool
+
+	// received holds the data received from offset readOffset on that was
+	// not read yet
+	received    []byte
+	readOffset  uint64
+	finReceived bool
+	finOffset   uint64
+	eofRead     bool
+
+	err        error
+	attachment *resumableAttachment
+
+	finished     chan struct{}
+	finishedOnce sync.Once
+}
+
+type resumableAttachment struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewResumableStream returns a resumable stream buffering up to bufferSize
+// bytes of written data, or DefaultResumableBufferSize if it is not
+// positive. It has no transport until Attach is called.
+func NewResumableStream(bufferSize int) *ResumableStream {
+	if bufferSize <= 0 {
+		bufferSize = DefaultResumableBufferSize
+	}
+	s := &ResumableStream{
+		bufferSize: bufferSize,
+		finished:   make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.l)
+	return s
+}
+
+// written returns the offset of the end of the written data. The lock must
+// be held.
+func (s *ResumableStream) written() uint64 {
+	return s.acked + uint64(len(s.unacked))
+}
+
+// readAck returns the offset to acknowledge to the peer, which counts its
+// FIN once io.EOF was read. The lock must be held.
+func (s *ResumableStream) readAck() uint64 {
+	if s.eofRead {
+		return s.finOffset + 1
+	}
+	return s.readOffset
+}
+
+// done returns whether both ends have closed and read each other's FIN. The
+// lock must be held.
+func (s *ResumableStream) done() bool {
+	return s.finAcked && s.eofRead
+}
+
+// Received returns the offset the stream received data up to, which the
+// peer resumes sending from.
+func (s *ResumableStream) Received() uint64 {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.readOffset + uint64(len(s.received))
+}
+
+// Read reads data received from the peer. It returns io.EOF once the peer
+// closed the stream and all of its data was read.
+func (s *ResumableStream) Read(p []byte) (int, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	for len(s.received) == 0 && !s.finReceived && s.err == nil {
+		s.cond.Wait()
+	}
+	switch {
+	case len(s.received) > 0:
+		n := copy(p, s.received)
+		s.received = s.received[n:]
+		s.readOffset += uint64(n)
+		s.cond.Broadcast()
+		return n, nil
+	case s.err != nil:
+		return 0, s.err
+	default:
+		if !s.eofRead {
+			s.eofRead = true
+			s.cond.Broadcast()
+		}
+		return 0, io.EOF
+	}
+}
+
+// Write writes data to the stream, blocking while the buffer of data the
+// peer has not read is full.
+func (s *ResumableStream) Write(p []byte) (int, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	var n int
+	for n < len(p) {
+		for len(s.unacked) >= s.bufferSize && s.err == nil && !s.closing {
+			s.cond.Wait()
+		}
+		switch {
+		case s.err != nil:
+			return n, s.err
+		case s.closing:
+			return n, ErrResumableClosed
+		}
+		chunk := p[n:]
+		if room := s.bufferSize - len(s.unacked); len(chunk) > room {
+			chunk = chunk[:room]
+		}
+		s.unacked = append(s.unacked, chunk...)
+		n += len(chunk)
+		s.cond.Broadcast()
+	}
+	return n, nil
+}
+
+// Close closes the stream for writing; the peer reads io.EOF after all data
+// written before. Reading is unaffected until the peer closes as well.
+func (s *ResumableStream) Close() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.closing = true
+	s.cond.Broadcast()
+	return nil
+}
+
+// Abort terminates the stream: reading and writing return err and the
+// current transport is detached.
+func (s *ResumableStream) Abort(err error) {
+	s.l.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	att := s.attachment
+	s.cond.Broadcast()
+	s.l.Unlock()
+	if att != nil {
+		att.cancel()
+	}
+	s.finish()
+}
+
+// Done returns a channel that is closed once Attach returned with the stream
+// done, or the stream was aborted.
+func (s *ResumableStream) Done() <-chan struct{} {
+	return s.finished
+}
+
+func (s *ResumableStream) finish() {
+	s.finishedOnce.Do(func() { close(s.finished) })
+}
+
+// Err returns the error the stream was aborted with, if any.
+func (s *ResumableStream) Err() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.err
+}
+
+// Attach carries the stream over a transport of frames, replacing the
+// current one if any, until the transport fails, ctx is done or the stream
+// is done, in which case it returns nil. peerReceived is the offset the peer
+// received data up to, as returned by its Received.
+func (s *ResumableStream) Attach(ctx context.Context, peerReceived uint64, read func(context.Context) ([]byte, error), write func(context.Context, []byte) error) error {
+	s.attachL.Lock()
+	s.l.Lock()
+	prev := s.attachment
+	s.l.Unlock()
+	if prev != nil {
+		prev.cancel()
+		<-prev.done
+	}
+
+	s.l.Lock()
+	switch {
+	case s.err != nil:
+		s.l.Unlock()
+		s.attachL.Unlock()
+		return s.err
+	case peerReceived < s.acked || peerReceived > s.written():
+		s.l.Unlock()
+		s.attachL.Unlock()
+		return fmt.Errorf("%w: peer received up to offset %d, buffered %d to %d", ErrCannotResume, peerReceived, s.acked, s.written())
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	att := &resumableAttachment{cancel: cancel, done: make(chan struct{})}
+	s.attachment = att
+	s.l.Unlock()
+	s.attachL.Unlock()
+
+	defer func() {
+		cancel()
+		s.l.Lock()
+		if s.attachment == att {
+			s.attachment = nil
+		}
+		s.l.Unlock()
+		close(att.done)
+	}()
+
+	go func() {
+		<-ctx.Done()
+		s.l.Lock()
+		s.cond.Broadcast()
+		s.l.Unlock()
+	}()
+
+	readErr := make(chan error, 1)
+	go func() {
+		err := s.receive(ctx, read)
+		readErr <- err
+		cancel()
+	}()
+	err := s.send(ctx, peerReceived, write)
+	cancel()
+	rerr := <-readErr
+
+	// Once done, the peer closing the transport is expected rather than a
+	// failure to resume from
+	s.l.Lock()
+	done := s.done()
+	s.l.Unlock()
+	if done {
+		s.finish()
+		return nil
+	}
+	if (err == nil || errors.Is(err, context.Canceled)) && rerr != nil {
+		err = rerr
+	}
+	return err
+}
+
+// send sends data, acknowledgments and the FIN to the peer until ctx is
+// done or the stream is done.
+func (s *ResumableStream) send(ctx context.Context, sent uint64, write func(context.Context, []byte) error) error {
+	var ackSent uint64
+	var finSent bool
+	for {
+		s.l.Lock()
+		var frame []byte
+		for frame == nil {
+			if s.err != nil {
+				s.l.Unlock()
+				return s.err
+			}
+			if ctx.Err() != nil {
+				s.l.Unlock()
+				return ctx.Err()
+			}
+			if sent < s.acked {
+				sent = s.acked
+			}
+			ack := s.readAck()
+			written := s.written()
+			switch {
+			// Acknowledge once a quarter of the peer's buffer is read, or all
+			// of it, so that it can write more
+			case ack > ackSent && (ack-ackSent >= uint64(s.bufferSize/4) || len(s.received) == 0):
+				frame = encodeResumableFrame(resumableFrameAck, ack, nil)
+				ackSent = ack
+			case sent < written:
+				chunk := s.unacked[sent-s.acked:]
+				if len(chunk) > MaxResumablePayloadSize {
+					chunk = chunk[:MaxResumablePayloadSize]
+				}
+				frame = encodeResumableFrame(resumableFrameData, sent, chunk)
+				sent += uint64(len(chunk))
+			case s.closing && !finSent && !s.finAcked:
+				frame = encodeResumableFrame(resumableFrameFin, written, nil)
+				finSent = true
+			case s.done() && ackSent >= ack:
+				s.l.Unlock()
+				return nil
+			default:
+				s.cond.Wait()
+			}
+		}
+		s.l.Unlock()
+		if err := write(ctx, frame); err != nil {
+			return err
+		}
+	}
+}
+
+// receive applies the frames from the peer until reading fails.
+func (s *ResumableStream) receive(ctx context.Context, read func(context.Context) ([]byte, error)) error {
+	for {
+		frame, err := read(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.apply(frame); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *ResumableStream) apply(frame []byte) error {
+	if len(frame) < resumableFrameHeaderSize {
+		return ErrInvalidResumableFrame
+	}
+	typ := frame[0]
+	offset := binary.BigEndian.Uint64(frame[1:resumableFrameHeaderSize])
+	payload := frame[resumableFrameHeaderSize:]
+
+	s.l.Lock()
+	defer s.l.Unlock()
+	switch typ {
+	case resumableFrameData:
+		received := s.readOffset + uint64(len(s.received))
+		end := offset + uint64(len(payload))
+		switch {
+		case offset > received, s.finReceived && end > s.finOffset:
+			return fmt.Errorf("%w: data at offset %d, received up to %d", ErrInvalidResumableFrame, offset, received)
+		case end <= received:
+			// Sent again on resuming; already received
+			return nil
+		}
+		s.received = append(s.received, payload[received-offset:]...)
+		if len(s.received) > s.bufferSize {
+			return fmt.Errorf("%w: peer exceeded the buffer size", ErrInvalidResumableFrame)
+		}
+
+	case resumableFrameAck:
+		written := s.written()
+		switch {
+		case offset > written+1, offset == written+1 && !s.closing:
+			return fmt.Errorf("%w: acknowledgment of offset %d, written up to %d", ErrInvalidResumableFrame, offset, written)
+		case offset == written+1:
+			s.finAcked = true
+			offset = written
+		}
+		if offset > s.acked {
+			s.unacked = s.unacked[offset-s.acked:]
+			s.acked = offset
+		}
+
+	case resumableFrameFin:
+		if s.finReceived {
+			if offset != s.finOffset {
+				return fmt.Errorf("%w: FIN at offset %d after FIN at %d", ErrInvalidResumableFrame, offset, s.finOffset)
+			}
+			return nil
+		}
+		if offset < s.readOffset+uint64(len(s.received)) {
+			return fmt.Errorf("%w: FIN at offset %d before received data", ErrInvalidResumableFrame, offset)
+		}
+		// Data sent before the FIN may still be missing if the FIN arrives
+		// first; it is only acted upon once all of it is received
+		if offset != s.readOffset+uint64(len(s.received)) {
+			return nil
+		}
+		s.finReceived = true
+		s.finOffset = offset
+
+	default:
+		return fmt.Errorf("%w: unknown frame type %d", ErrInvalidResumableFrame, typ)
+	}
+	s.cond.Broadcast()
+	return nil
+}
+
+func encodeResumableFrame(typ byte, offset uint64, payload []byte) []byte {
+	frame := make([]byte, resumableFrameHeaderSize+len(payload))
+	frame[0] = typ
+	binary.BigEndian.PutUint64(frame[1:], offset)
+	copy(frame[resumableFrameHeaderSize:], payload)
+	return frame
+}
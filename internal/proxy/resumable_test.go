@@ -0,0 +1,199 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testResumableTransport is an in-memory transport of frames between two
+// streams that fails both ways when cut.
+type testResumableTransport struct {
+	ctx  context.Context
+	cut  context.CancelFunc
+	aToB chan []byte
+	bToA chan []byte
+}
+
+func newTestResumableTransport() *testResumableTransport {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &testResumableTransport{
+		ctx:  ctx,
+		cut:  cancel,
+		aToB: make(chan []byte, 16),
+		bToA: make(chan []byte, 16),
+	}
+}
+
+func (tr *testResumableTransport) funcs(in, out chan []byte) (func(context.Context) ([]byte, error), func(context.Context, []byte) error) {
+	read := func(ctx context.Context) ([]byte, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-tr.ctx.Done():
+			return nil, errors.New("transport cut")
+		case frame := <-in:
+			return frame, nil
+		}
+	}
+	write := func(ctx context.Context, frame []byte) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tr.ctx.Done():
+			return errors.New("transport cut")
+		case out <- frame:
+			return nil
+		}
+	}
+	return read, write
+}
+
+// testAttach attaches both streams to a new transport, returning it and a
+// channel receiving the result of each Attach.
+func testAttach(a, b *ResumableStream) (*testResumableTransport, chan error) {
+	tr := newTestResumableTransport()
+	results := make(chan error, 2)
+	aReceived, bReceived := a.Received(), b.Received()
+	readA, writeA := tr.funcs(tr.bToA, tr.aToB)
+	readB, writeB := tr.funcs(tr.aToB, tr.bToA)
+	go func() { results <- a.Attach(context.Background(), bReceived, readA, writeA) }()
+	go func() { results <- b.Attach(context.Background(), aReceived, readB, writeB) }()
+	return tr, results
+}
+
+func testRandomBytes(t *testing.T, n int) []byte {
+	t.Helper()
+	buf := make([]byte, n)
+	_, err := rand.New(rand.NewSource(int64(n))).Read(buf)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestResumableStream(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	a, b := NewResumableStream(64*1024), NewResumableStream(64*1024)
+	aData, bData := testRandomBytes(t, 1000*1000), testRandomBytes(t, 300*1000)
+
+	// Both directions are written and read in full, with the transport cut
+	// and resumed repeatedly meanwhile
+	copyDone := make(chan struct{})
+	var aGot, bGot []byte
+	go func() {
+		defer close(copyDone)
+		go func() {
+			a.Write(aData)
+			a.Close()
+		}()
+		go func() {
+			b.Write(bData)
+			b.Close()
+		}()
+		got := make(chan []byte)
+		go func() {
+			data, _ := ioutil.ReadAll(b)
+			got <- data
+		}()
+		aGot, _ = ioutil.ReadAll(a)
+		bGot = <-got
+	}()
+
+	for i := 0; ; i++ {
+		tr, results := testAttach(a, b)
+		select {
+		case <-copyDone:
+		case <-time.After(time.Duration(rand.Intn(20)) * time.Millisecond):
+		}
+		select {
+		case <-copyDone:
+			// Once done, both attachments end without error
+			for j := 0; j < 2; j++ {
+				select {
+				case err := <-results:
+					require.NoError(err)
+				case <-time.After(5 * time.Second):
+					require.FailNow("attachment did not end once the stream was done")
+				}
+			}
+			assert.True(bytes.Equal(aData, bGot), "data from a differs")
+			assert.True(bytes.Equal(bData, aGot), "data from b differs")
+			return
+		default:
+		}
+		tr.cut()
+		for j := 0; j < 2; j++ {
+			<-results
+		}
+		require.Less(i, 10000, "stream did not complete")
+	}
+}
+
+func TestResumableStream_FlowControl(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	a, b := NewResumableStream(1024), NewResumableStream(1024)
+	_, results := testAttach(a, b)
+
+	written := make(chan error)
+	go func() {
+		_, err := a.Write(make([]byte, 4096))
+		written <- err
+	}()
+	select {
+	case <-written:
+		require.FailNow("write did not block while the peer was not reading")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	n, err := io.ReadFull(b, make([]byte, 4096))
+	require.NoError(err)
+	assert.Equal(4096, n)
+	require.NoError(<-written)
+
+	a.Abort(errors.New("aborted"))
+	_, err = a.Read(make([]byte, 1))
+	assert.EqualError(err, "aborted")
+	_, err = a.Write([]byte("x"))
+	assert.EqualError(err, "aborted")
+	assert.EqualError(<-results, "aborted")
+	b.Abort(errors.New("aborted"))
+	<-results
+}
+
+func TestResumableStream_CannotResume(t *testing.T) {
+	assert := assert.New(t)
+	s := NewResumableStream(0)
+	_, err := s.Write([]byte("hello"))
+	assert.NoError(err)
+
+	tr := newTestResumableTransport()
+	read, write := tr.funcs(tr.aToB, tr.bToA)
+	err = s.Attach(context.Background(), 6, read, write)
+	assert.True(errors.Is(err, ErrCannotResume))
+}
+
+func TestResumableStream_InvalidFrames(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+	}{
+		{name: "short", frame: []byte{resumableFrameData, 0}},
+		{name: "unknown type", frame: encodeResumableFrame(9, 0, nil)},
+		{name: "data gap", frame: encodeResumableFrame(resumableFrameData, 10, []byte("x"))},
+		{name: "ack beyond written", frame: encodeResumableFrame(resumableFrameAck, 1, nil)},
+		{name: "data beyond buffer", frame: encodeResumableFrame(resumableFrameData, 0, make([]byte, 100))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewResumableStream(10)
+			assert.True(t, errors.Is(s.apply(tt.frame), ErrInvalidResumableFrame))
+		})
+	}
+}
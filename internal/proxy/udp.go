@@ -0,0 +1,48 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxUdpDatagramSize is the largest UDP payload that can be proxied.
+	MaxUdpDatagramSize = 65507
+
+	// UdpFrameHeaderSize is the size of the header preceding the datagram in
+	// a frame.
+	UdpFrameHeaderSize = 4
+
+	// MaxUdpFrameSize is the largest frame sent over a connection of the UDP
+	// proxy protocol.
+	MaxUdpFrameSize = UdpFrameHeaderSize + MaxUdpDatagramSize
+)
+
+// ErrInvalidUdpFrame is returned when decoding a frame that is too short or
+// too long to hold a datagram.
+var ErrInvalidUdpFrame = errors.New("invalid udp frame")
+
+// EncodeUdpFrame frames a datagram of a flow. Each frame of the UDP proxy
+// protocol is sent as one binary websocket message made of the big-endian
+// flow id followed by the datagram. A flow is the traffic between one client
+// address and the endpoint; the client picks the flow ids and the worker
+// keeps a socket to the endpoint for each flow.
+func EncodeUdpFrame(flowId uint32, datagram []byte) []byte {
+	frame := make([]byte, UdpFrameHeaderSize+len(datagram))
+	binary.BigEndian.PutUint32(frame, flowId)
+	copy(frame[UdpFrameHeaderSize:], datagram)
+	return frame
+}
+
+// DecodeUdpFrame returns the flow id and the datagram of a frame. The
+// datagram shares its memory with the frame.
+func DecodeUdpFrame(frame []byte) (uint32, []byte, error) {
+	if len(frame) < UdpFrameHeaderSize {
+		return 0, nil, fmt.Errorf("frame of %d bytes has no header: %w", len(frame), ErrInvalidUdpFrame)
+	}
+	if len(frame) > MaxUdpFrameSize {
+		return 0, nil, fmt.Errorf("frame of %d bytes exceeds maximum size: %w", len(frame), ErrInvalidUdpFrame)
+	}
+	return binary.BigEndian.Uint32(frame), frame[UdpFrameHeaderSize:], nil
+}
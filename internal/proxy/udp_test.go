@@ -0,0 +1,39 @@
+package proxy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUdpFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		flowId   uint32
+		datagram []byte
+	}{
+		{name: "empty", flowId: 1, datagram: []byte{}},
+		{name: "datagram", flowId: 0xdeadbeef, datagram: []byte("query")},
+		{name: "max size", flowId: 7, datagram: make([]byte, MaxUdpDatagramSize)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			frame := EncodeUdpFrame(tt.flowId, tt.datagram)
+			assert.Len(frame, UdpFrameHeaderSize+len(tt.datagram))
+			flowId, datagram, err := DecodeUdpFrame(frame)
+			require.NoError(err)
+			assert.Equal(tt.flowId, flowId)
+			assert.Equal(tt.datagram, datagram)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := DecodeUdpFrame([]byte{0, 1})
+		assert.True(t, errors.Is(err, ErrInvalidUdpFrame))
+		_, _, err = DecodeUdpFrame(make([]byte, MaxUdpFrameSize+1))
+		assert.True(t, errors.Is(err, ErrInvalidUdpFrame))
+	})
+}
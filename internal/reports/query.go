@@ -0,0 +1,44 @@
+package reports
+
+const (
+	// usageQuery aggregates the session and connection accumulating facts over
+	// a dimension. A connection is considered failed if it was closed without
+	// ever reaching the connected state. The grouping key and name columns,
+	// the where clause and the limit are substituted in order.
+	usageQuery = `
+with
+connection_usage (session_id, connection_count, failed_connection_count, connected_seconds, bytes_up, bytes_down) as (
+  select session_id,
+         sum(connection_count),
+         count(*) filter (
+           where connection_connected_time = 'infinity'::timestamptz
+             and connection_closed_time <> 'infinity'::timestamptz
+         ),
+         coalesce(sum(extract(epoch from connection_closed_time - connection_connected_time)) filter (
+           where connection_connected_time <> 'infinity'::timestamptz
+             and connection_closed_time <> 'infinity'::timestamptz
+         ), 0),
+         coalesce(sum(bytes_up), 0),
+         coalesce(sum(bytes_down), 0)
+    from wh_session_connection_accumulating_fact
+   group by session_id
+)
+select %s                                            as report_key,
+       max(%s)                                       as report_name,
+       count(s.session_id)                                 as session_count,
+       coalesce(sum(c.connection_count), 0)::bigint        as connection_count,
+       coalesce(sum(c.failed_connection_count), 0)::bigint as failed_connection_count,
+       coalesce(sum(c.connected_seconds), 0)::bigint       as connected_seconds,
+       coalesce(sum(c.bytes_up), 0)::bigint                as bytes_up,
+       coalesce(sum(c.bytes_down), 0)::bigint              as bytes_down
+  from wh_session_accumulating_fact as s
+  join wh_host_dimension            as h on h.id = s.host_id
+  join wh_user_dimension            as u on u.id = s.user_id
+  join wh_date_dimension            as d on d.id = s.session_pending_date_id
+  left join connection_usage        as c on c.session_id = s.session_id
+ where true %s
+ group by report_key
+ order by session_count desc, report_key
+ %s;
+`
+)
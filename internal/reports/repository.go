@@ -0,0 +1,35 @@
+package reports
+
+import (
+	"errors"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+// Repository is the reports database repository. It only reads from the
+// session data warehouse tables, which are populated by triggers on the
+// operational session tables.
+type Repository struct {
+	reader db.Reader
+
+	// defaultLimit provides a default for limiting the number of results returned from the repo
+	defaultLimit int
+}
+
+// NewRepository creates a new reports Repository. Supports the options:
+// WithLimit which sets a default limit on results returned by repo
+// operations.
+func NewRepository(r db.Reader, opt ...Option) (*Repository, error) {
+	if r == nil {
+		return nil, errors.New("error creating db repository with nil reader")
+	}
+	opts := getOpts(opt...)
+	if opts.withLimit == 0 {
+		// zero signals the boundary defaults should be used.
+		opts.withLimit = db.DefaultLimit
+	}
+	return &Repository{
+		reader:       r,
+		defaultLimit: opts.withLimit,
+	}, nil
+}
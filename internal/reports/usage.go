@@ -0,0 +1,141 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// GroupBy defines the dimension session usage is aggregated over.
+type GroupBy string
+
+const (
+	GroupByUser   GroupBy = "user"
+	GroupByTarget GroupBy = "target"
+	GroupByHost   GroupBy = "host"
+	GroupByDay    GroupBy = "day"
+)
+
+// String representation of the grouping
+func (g GroupBy) String() string {
+	return string(g)
+}
+
+// groupColumns returns the key and name columns for the grouping.
+func (g GroupBy) groupColumns() (key, name string, err error) {
+	switch g {
+	case GroupByUser:
+		return "u.user_id", "u.user_name", nil
+	case GroupByTarget:
+		return "h.target_id", "h.target_name", nil
+	case GroupByHost:
+		return "h.host_id", "h.host_name", nil
+	case GroupByDay:
+		return "d.date::text", "d.day_of_week", nil
+	default:
+		return "", "", fmt.Errorf("unknown grouping %q", g)
+	}
+}
+
+// Usage is session usage aggregated over a single user, target, host or day.
+type Usage struct {
+	// Key is the user, target or host id, or the date formatted as YYYY-MM-DD.
+	Key string
+	// Name is the name of the user, target or host, or the day of the week.
+	Name                  string
+	SessionCount          uint64
+	ConnectionCount       uint64
+	FailedConnectionCount uint64
+	ConnectedSeconds      uint64
+	BytesUp               uint64
+	BytesDown             uint64
+}
+
+// FailedConnectionRate returns the fraction of connections which were closed
+// without ever being connected to the endpoint.
+func (u *Usage) FailedConnectionRate() float64 {
+	if u.ConnectionCount == 0 {
+		return 0
+	}
+	return float64(u.FailedConnectionCount) / float64(u.ConnectionCount)
+}
+
+// Usage returns session usage aggregated by the grouping, ordered by session
+// count with the highest first. Supports the options: WithScopeId,
+// WithStartTime, WithEndTime and WithLimit.
+func (r *Repository) Usage(ctx context.Context, groupBy GroupBy, opt ...Option) ([]*Usage, error) {
+	keyCol, nameCol, err := groupBy.groupColumns()
+	if err != nil {
+		return nil, fmt.Errorf("usage: %w", err)
+	}
+	opts := getOpts(opt...)
+
+	var where []string
+	var args []interface{}
+	switch {
+	case opts.withScopeId == "", opts.withScopeId == scope.Global.String():
+	case strings.HasPrefix(opts.withScopeId, scope.Org.Prefix()+"_"):
+		args = append(args, opts.withScopeId)
+		where = append(where, fmt.Sprintf("h.host_organization_id = $%d", len(args)))
+	case strings.HasPrefix(opts.withScopeId, scope.Project.Prefix()+"_"):
+		args = append(args, opts.withScopeId)
+		where = append(where, fmt.Sprintf("h.project_id = $%d", len(args)))
+	default:
+		return nil, errors.New("usage: invalid scope id")
+	}
+	if !opts.withStartTime.IsZero() {
+		args = append(args, opts.withStartTime)
+		where = append(where, fmt.Sprintf("s.session_pending_time >= $%d", len(args)))
+	}
+	if !opts.withEndTime.IsZero() {
+		args = append(args, opts.withEndTime)
+		where = append(where, fmt.Sprintf("s.session_pending_time < $%d", len(args)))
+	}
+
+	var limit string
+	switch {
+	case opts.withLimit < 0: // any negative number signals unlimited results
+	case opts.withLimit == 0: // zero signals the default value and default limits
+		limit = fmt.Sprintf("limit %d", r.defaultLimit)
+	default:
+		// non-zero signals an override of the default limit for the repo.
+		limit = fmt.Sprintf("limit %d", opts.withLimit)
+	}
+
+	var whereClause string
+	if len(where) > 0 {
+		whereClause = " and " + strings.Join(where, " and ")
+	}
+	query := fmt.Sprintf(usageQuery, keyCol, nameCol, whereClause, limit)
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("usage: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []*Usage
+	for rows.Next() {
+		var u Usage
+		if err := rows.Scan(
+			&u.Key,
+			&u.Name,
+			&u.SessionCount,
+			&u.ConnectionCount,
+			&u.FailedConnectionCount,
+			&u.ConnectedSeconds,
+			&u.BytesUp,
+			&u.BytesDown,
+		); err != nil {
+			return nil, fmt.Errorf("usage: scan row failed: %w", err)
+		}
+		usage = append(usage, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("usage: %w", err)
+	}
+	return usage, nil
+}
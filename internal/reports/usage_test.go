@@ -0,0 +1,47 @@
+package reports
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage_FailedConnectionRate(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	assert.Equal(0.0, (&Usage{}).FailedConnectionRate())
+	assert.Equal(0.25, (&Usage{ConnectionCount: 4, FailedConnectionCount: 1}).FailedConnectionRate())
+}
+
+func TestRepository_Usage(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	repo, err := NewRepository(rw)
+	require.NoError(t, err)
+
+	t.Run("invalid-group-by", func(t *testing.T) {
+		assert := assert.New(t)
+		got, err := repo.Usage(context.Background(), GroupBy("nope"))
+		assert.Error(err)
+		assert.Nil(got)
+	})
+	t.Run("invalid-scope", func(t *testing.T) {
+		assert := assert.New(t)
+		got, err := repo.Usage(context.Background(), GroupByUser, WithScopeId("s_1234567890"))
+		assert.Error(err)
+		assert.Nil(got)
+	})
+	for _, g := range []GroupBy{GroupByUser, GroupByTarget, GroupByHost, GroupByDay} {
+		g := g
+		t.Run(g.String(), func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.Usage(context.Background(), g, WithScopeId("global"), WithLimit(-1))
+			require.NoError(err)
+			assert.Empty(got)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Job is background work the controllers run periodically. Every controller
+// registers the same jobs with its scheduler, and each run of a job happens
+// on a single controller.
+type Job struct {
+	// Name uniquely identifies the job across controllers.
+	Name string
+	// Description is a short description of what the job does, for operators.
+	Description string
+	// Interval is the time between the starts of two runs of the job.
+	Interval time.Duration
+	// Timeout is the longest a run of the job may take, and how long the
+	// controller running it holds the lease on the job. If zero,
+	// defaultRunTimeout is used.
+	Timeout time.Duration
+	// Run does the work of the job. It must stop when ctx is done.
+	Run func(ctx context.Context) error
+}
+
+func (j *Job) validate() error {
+	switch {
+	case j == nil:
+		return errors.New("job is nil")
+	case j.Name == "":
+		return errors.New("job name is empty")
+	case j.Interval <= 0:
+		return errors.New("job interval must be positive")
+	case j.Timeout < 0:
+		return errors.New("job timeout must not be negative")
+	case j.Run == nil:
+		return errors.New("job run function is nil")
+	}
+	return nil
+}
+
+// RunStatus is the status of a run of a job.
+type RunStatus string
+
+const (
+	RunStatusRunning     RunStatus = "running"
+	RunStatusCompleted   RunStatus = "completed"
+	RunStatusFailed      RunStatus = "failed"
+	RunStatusInterrupted RunStatus = "interrupted"
+)
+
+// String representation of the status
+func (s RunStatus) String() string {
+	return string(s)
+}
+
+// Run is a run of a job on a controller.
+type Run struct {
+	PrivateId string
+	JobName   string
+	// ServerId is the private id of the controller which ran the job.
+	ServerId  string
+	Status    RunStatus
+	StartTime time.Time
+	// EndTime is zero while the job is running, or if the run was
+	// interrupted.
+	EndTime time.Time
+	// Error is the error the run failed with, if any.
+	Error string
+}
+
+// Duration returns how long the run took, or zero if it has not ended.
+func (r *Run) Duration() time.Duration {
+	if r.EndTime.IsZero() {
+		return 0
+	}
+	return r.EndTime.Sub(r.StartTime)
+}
+
+// JobStatus is a job as stored in the database, along with its latest run.
+type JobStatus struct {
+	Name             string
+	Description      string
+	NextScheduledRun time.Time
+	// LeaseOwner is the private id of the controller holding the lease on
+	// the job, i.e. running it, if any.
+	LeaseOwner string
+	// LastRun is the latest run of the job, or nil if it never ran.
+	LastRun *Run
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob_validate(t *testing.T) {
+	t.Parallel()
+	run := func(context.Context) error { return nil }
+	tests := []struct {
+		name    string
+		job     *Job
+		wantErr bool
+	}{
+		{name: "valid", job: &Job{Name: "job", Interval: time.Minute, Run: run}},
+		{name: "nil", wantErr: true},
+		{name: "missing-name", job: &Job{Interval: time.Minute, Run: run}, wantErr: true},
+		{name: "missing-interval", job: &Job{Name: "job", Run: run}, wantErr: true},
+		{name: "negative-timeout", job: &Job{Name: "job", Interval: time.Minute, Timeout: -time.Second, Run: run}, wantErr: true},
+		{name: "missing-run", job: &Job{Name: "job", Interval: time.Minute}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.job.validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRun_Duration(t *testing.T) {
+	t.Parallel()
+	start := time.Now()
+	assert.Equal(t, time.Duration(0), (&Run{StartTime: start}).Duration())
+	assert.Equal(t, time.Second, (&Run{StartTime: start, EndTime: start.Add(time.Second)}).Duration())
+}
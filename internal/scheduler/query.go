@@ -0,0 +1,116 @@
+package scheduler
+
+const (
+	upsertJobSql = `
+	insert into job
+		(name, description)
+	values
+		($1, $2)
+	on conflict (name)
+	do update set
+		description = $2;
+	`
+
+	// claimJobSql takes the lease on the job if it is due and no other
+	// controller holds an unexpired lease, and schedules its next run.
+	claimJobSql = `
+	update job
+	   set next_scheduled_run = current_timestamp + $3::double precision * interval '1 second',
+	       lease_owner = $2,
+	       lease_expiration = current_timestamp + $4::double precision * interval '1 second'
+	 where name = $1
+	   and next_scheduled_run <= current_timestamp
+	   and (lease_expiration is null or lease_expiration <= current_timestamp);
+	`
+
+	// interruptRunsSql marks the runs which were left running when their
+	// controller lost the lease on the job.
+	interruptRunsSql = `
+	update job_run
+	   set status = 'interrupted'
+	 where job_name = $1
+	   and status = 'running';
+	`
+
+	insertRunSql = `
+	insert into job_run
+		(private_id, job_name, server_id)
+	values
+		($1, $2, $3);
+	`
+
+	endRunSql = `
+	update job_run
+	   set status = $2,
+	       end_time = current_timestamp,
+	       error = nullif($3, '')
+	 where private_id = $1
+	   and status = 'running';
+	`
+
+	releaseJobSql = `
+	update job
+	   set lease_owner = null,
+	       lease_expiration = null
+	 where name = $1
+	   and lease_owner = $2;
+	`
+
+	// pruneRunsSql deletes the runs of the job beyond the latest ones kept.
+	pruneRunsSql = `
+	delete from job_run
+	 where job_name = $1
+	   and private_id not in (
+	     select private_id
+	       from job_run
+	      where job_name = $1
+	      order by start_time desc
+	      limit $2
+	   );
+	`
+
+	nextRunSql = `
+	select greatest(next_scheduled_run, coalesce(lease_expiration, next_scheduled_run))
+	  from job
+	 where name = $1;
+	`
+
+	// listJobsSql lists the jobs along with their latest run, if any. The
+	// where clause is substituted.
+	listJobsSql = `
+	select j.name,
+	       j.description,
+	       j.next_scheduled_run,
+	       coalesce(j.lease_owner, ''),
+	       coalesce(r.private_id, ''),
+	       coalesce(r.server_id, ''),
+	       coalesce(r.status, ''),
+	       r.start_time,
+	       r.end_time,
+	       coalesce(r.error, '')
+	  from job as j
+	  left join lateral (
+	    select *
+	      from job_run
+	     where job_name = j.name
+	     order by start_time desc
+	     limit 1
+	  ) as r on true
+	 %s
+	 order by j.name;
+	`
+
+	listRunsSql = `
+	select private_id,
+	       job_name,
+	       server_id,
+	       status,
+	       start_time,
+	       end_time,
+	       coalesce(error, '')
+	  from job_run
+	 where job_name = $1
+	 order by start_time desc
+	 %s;
+	`
+)
@@ -0,0 +1,284 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+)
+
+const (
+	// RunPrefix is the prefix of the private ids of job runs.
+	RunPrefix = "jobr"
+
+	// runsKept is the number of latest runs kept for each job.
+	runsKept = 100
+)
+
+// Repository is the scheduler database repository. It stores the jobs and
+// their leases, through which the controllers coordinate their runs, and the
+// history of the runs.
+type Repository struct {
+	reader db.Reader
+	writer db.Writer
+
+	// defaultLimit provides a default for limiting the number of results returned from the repo
+	defaultLimit int
+}
+
+// NewRepository creates a new scheduler Repository. Supports the options:
+// WithLimit which sets a default limit on results returned by repo
+// operations.
+func NewRepository(r db.Reader, w db.Writer, opt ...Option) (*Repository, error) {
+	if r == nil {
+		return nil, errors.New("error creating scheduler repository with nil reader")
+	}
+	if w == nil {
+		return nil, errors.New("error creating scheduler repository with nil writer")
+	}
+	opts := getOpts(opt...)
+	if opts.withLimit == 0 {
+		// zero signals the boundary defaults should be used.
+		opts.withLimit = db.DefaultLimit
+	}
+	return &Repository{
+		reader:       r,
+		writer:       w,
+		defaultLimit: opts.withLimit,
+	}, nil
+}
+
+// UpsertJob stores the job, or updates its description if it is already
+// stored. A new job is due immediately.
+func (r *Repository) UpsertJob(ctx context.Context, name, description string) error {
+	if name == "" {
+		return errors.New("upsert job: missing name")
+	}
+	if _, err := r.writer.Exec(ctx, upsertJobSql, []interface{}{name, description}); err != nil {
+		return fmt.Errorf("upsert job: %w", err)
+	}
+	return nil
+}
+
+// ClaimRun starts a run of the job on the controller if the job is due and no
+// other controller holds the lease on it. The lease is held for timeout, and
+// the next run of the job is scheduled interval from now. Runs of the job
+// left running by a controller which lost the lease are marked interrupted.
+//
+// If the run is not started, the returned run is nil and the returned time is
+// when the job can next be claimed.
+func (r *Repository) ClaimRun(ctx context.Context, name, serverId string, interval, timeout time.Duration) (*Run, time.Time, error) {
+	if name == "" {
+		return nil, time.Time{}, errors.New("claim run: missing name")
+	}
+	if serverId == "" {
+		return nil, time.Time{}, errors.New("claim run: missing server id")
+	}
+	runId, err := db.NewPrivateId(RunPrefix)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("claim run: %w", err)
+	}
+	var run *Run
+	var next time.Time
+	_, err = r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			run, next = nil, time.Time{}
+			claimed, err := w.Exec(ctx, claimJobSql, []interface{}{name, serverId, interval.Seconds(), timeout.Seconds()})
+			if err != nil {
+				return fmt.Errorf("unable to take lease: %w", err)
+			}
+			if claimed == 0 {
+				rows, err := reader.Query(ctx, nextRunSql, []interface{}{name})
+				if err != nil {
+					return fmt.Errorf("unable to look up next run: %w", err)
+				}
+				defer rows.Close()
+				if !rows.Next() {
+					if err := rows.Err(); err != nil {
+						return fmt.Errorf("unable to look up next run: %w", err)
+					}
+					return fmt.Errorf("job %q not found", name)
+				}
+				return rows.Scan(&next)
+			}
+			if _, err := w.Exec(ctx, interruptRunsSql, []interface{}{name}); err != nil {
+				return fmt.Errorf("unable to mark interrupted runs: %w", err)
+			}
+			if _, err := w.Exec(ctx, insertRunSql, []interface{}{runId, name, serverId}); err != nil {
+				return fmt.Errorf("unable to insert run: %w", err)
+			}
+			run = &Run{
+				PrivateId: runId,
+				JobName:   name,
+				ServerId:  serverId,
+				Status:    RunStatusRunning,
+				StartTime: time.Now(),
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("claim run: %w", err)
+	}
+	return run, next, nil
+}
+
+// EndRun records the end of the run, as failed if runErr is not nil, releases
+// the lease on its job and deletes the runs of the job beyond the latest ones.
+func (r *Repository) EndRun(ctx context.Context, run *Run, runErr error) error {
+	if run == nil {
+		return errors.New("end run: run is nil")
+	}
+	status, errMsg := RunStatusCompleted, ""
+	if runErr != nil {
+		status, errMsg = RunStatusFailed, runErr.Error()
+	}
+	_, err := r.writer.DoTx(
+		ctx,
+		db.StdRetryCnt,
+		db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			if _, err := w.Exec(ctx, endRunSql, []interface{}{run.PrivateId, status.String(), errMsg}); err != nil {
+				return fmt.Errorf("unable to update run: %w", err)
+			}
+			if _, err := w.Exec(ctx, releaseJobSql, []interface{}{run.JobName, run.ServerId}); err != nil {
+				return fmt.Errorf("unable to release lease: %w", err)
+			}
+			if _, err := w.Exec(ctx, pruneRunsSql, []interface{}{run.JobName, runsKept}); err != nil {
+				return fmt.Errorf("unable to delete old runs: %w", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("end run: %w", err)
+	}
+	return nil
+}
+
+// ListJobs returns the stored jobs along with their latest run, ordered by
+// name.
+func (r *Repository) ListJobs(ctx context.Context) ([]*JobStatus, error) {
+	jobs, err := r.listJobs(ctx, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// LookupJob returns the job with the name along with its latest run, or nil
+// if there is none.
+func (r *Repository) LookupJob(ctx context.Context, name string) (*JobStatus, error) {
+	if name == "" {
+		return nil, errors.New("lookup job: missing name")
+	}
+	jobs, err := r.listJobs(ctx, "where j.name = $1", []interface{}{name})
+	if err != nil {
+		return nil, fmt.Errorf("lookup job: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return jobs[0], nil
+}
+
+func (r *Repository) listJobs(ctx context.Context, where string, args []interface{}) ([]*JobStatus, error) {
+	rows, err := r.reader.Query(ctx, fmt.Sprintf(listJobsSql, where), args)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*JobStatus
+	for rows.Next() {
+		var j JobStatus
+		var run Run
+		var startTime, endTime *time.Time
+		if err := rows.Scan(
+			&j.Name,
+			&j.Description,
+			&j.NextScheduledRun,
+			&j.LeaseOwner,
+			&run.PrivateId,
+			&run.ServerId,
+			&run.Status,
+			&startTime,
+			&endTime,
+			&run.Error,
+		); err != nil {
+			return nil, fmt.Errorf("scan row failed: %w", err)
+		}
+		if run.PrivateId != "" {
+			run.JobName = j.Name
+			setRunTimes(&run, startTime, endTime)
+			j.LastRun = &run
+		}
+		jobs = append(jobs, &j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ListRuns returns the runs of the job, latest first. Supports the options:
+// WithLimit.
+func (r *Repository) ListRuns(ctx context.Context, name string, opt ...Option) ([]*Run, error) {
+	if name == "" {
+		return nil, errors.New("list runs: missing name")
+	}
+	opts := getOpts(opt...)
+	var limit string
+	switch {
+	case opts.withLimit < 0: // any negative number signals unlimited results
+	case opts.withLimit == 0: // zero signals the default value and default limits
+		limit = fmt.Sprintf("limit %d", r.defaultLimit)
+	default:
+		// non-zero signals an override of the default limit for the repo.
+		limit = fmt.Sprintf("limit %d", opts.withLimit)
+	}
+
+	rows, err := r.reader.Query(ctx, fmt.Sprintf(listRunsSql, limit), []interface{}{name})
+	if err != nil {
+		return nil, fmt.Errorf("list runs: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*Run
+	for rows.Next() {
+		var run Run
+		var startTime, endTime *time.Time
+		if err := rows.Scan(
+			&run.PrivateId,
+			&run.JobName,
+			&run.ServerId,
+			&run.Status,
+			&startTime,
+			&endTime,
+			&run.Error,
+		); err != nil {
+			return nil, fmt.Errorf("list runs: scan row failed: %w", err)
+		}
+		setRunTimes(&run, startTime, endTime)
+		runs = append(runs, &run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+	return runs, nil
+}
+
+// setRunTimes sets the times of the run from the nullable columns.
+func setRunTimes(run *Run, startTime, endTime *time.Time) {
+	if startTime != nil {
+		run.StartTime = *startTime
+	}
+	if endTime != nil {
+		run.EndTime = *endTime
+	}
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ClaimRun(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(err)
+	ctx := context.Background()
+
+	_, _, err = repo.ClaimRun(ctx, "unknown", "c1", time.Hour, time.Minute)
+	assert.Error(err)
+
+	require.NoError(repo.UpsertJob(ctx, "test-job", "A test job"))
+
+	// A new job is due, and only one controller can claim it
+	run, _, err := repo.ClaimRun(ctx, "test-job", "c1", time.Hour, time.Minute)
+	require.NoError(err)
+	require.NotNil(run)
+	assert.Equal(RunStatusRunning, run.Status)
+	other, next, err := repo.ClaimRun(ctx, "test-job", "c2", time.Hour, time.Minute)
+	require.NoError(err)
+	assert.Nil(other)
+	assert.True(next.After(time.Now().Add(50 * time.Minute)))
+
+	job, err := repo.LookupJob(ctx, "test-job")
+	require.NoError(err)
+	require.NotNil(job)
+	assert.Equal("c1", job.LeaseOwner)
+	require.NotNil(job.LastRun)
+	assert.Equal(run.PrivateId, job.LastRun.PrivateId)
+	assert.Equal(RunStatusRunning, job.LastRun.Status)
+
+	// Ending the run releases the lease, but the job is not due again until
+	// its interval passes
+	require.NoError(repo.EndRun(ctx, run, errors.New("test failure")))
+	other, _, err = repo.ClaimRun(ctx, "test-job", "c2", time.Hour, time.Minute)
+	require.NoError(err)
+	assert.Nil(other)
+
+	runs, err := repo.ListRuns(ctx, "test-job")
+	require.NoError(err)
+	require.Len(runs, 1)
+	assert.Equal(RunStatusFailed, runs[0].Status)
+	assert.Equal("test failure", runs[0].Error)
+	assert.False(runs[0].EndTime.IsZero())
+
+	jobs, err := repo.ListJobs(ctx)
+	require.NoError(err)
+	require.Len(jobs, 1)
+	assert.Empty(jobs[0].LeaseOwner)
+	assert.Equal("A test job", jobs[0].Description)
+}
+
+func TestRepository_ClaimRun_expiredLease(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	repo, err := NewRepository(rw, rw)
+	require.NoError(err)
+	ctx := context.Background()
+
+	require.NoError(repo.UpsertJob(ctx, "test-job", "A test job"))
+	first, _, err := repo.ClaimRun(ctx, "test-job", "c1", time.Millisecond, time.Millisecond)
+	require.NoError(err)
+	require.NotNil(first)
+	time.Sleep(10 * time.Millisecond)
+
+	// The lease of the first controller expired without it ending its run
+	second, _, err := repo.ClaimRun(ctx, "test-job", "c2", time.Hour, time.Minute)
+	require.NoError(err)
+	require.NotNil(second)
+	require.NoError(repo.EndRun(ctx, second, nil))
+
+	runs, err := repo.ListRuns(ctx, "test-job")
+	require.NoError(err)
+	require.Len(runs, 2)
+	assert.Equal(second.PrivateId, runs[0].PrivateId)
+	assert.Equal(RunStatusCompleted, runs[0].Status)
+	assert.Equal(first.PrivateId, runs[1].PrivateId)
+	assert.Equal(RunStatusInterrupted, runs[1].Status)
+
+	// The first controller ending its run late changes nothing
+	require.NoError(repo.EndRun(ctx, first, nil))
+	runs, err = repo.ListRuns(ctx, "test-job")
+	require.NoError(err)
+	assert.Equal(RunStatusInterrupted, runs[1].Status)
+}
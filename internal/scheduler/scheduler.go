@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	defaultRunTimeout = 5 * time.Minute
+
+	// maxWait is the longest the scheduler waits before trying to claim a
+	// job again, so that it notices when another controller stops running
+	// it.
+	maxWait = time.Minute
+)
+
+// Scheduler runs the registered jobs at their interval, coordinating with the
+// schedulers of the other controllers through the database so that each run
+// of a job happens on a single controller.
+type Scheduler struct {
+	serverId string
+	repoFn   func() (*Repository, error)
+	logger   hclog.Logger
+
+	l       sync.Mutex
+	jobs    map[string]*Job
+	started bool
+}
+
+// New returns a scheduler for the controller with the server id.
+func New(serverId string, repoFn func() (*Repository, error), logger hclog.Logger) (*Scheduler, error) {
+	if serverId == "" {
+		return nil, errors.New("error creating scheduler with empty server id")
+	}
+	if repoFn == nil {
+		return nil, errors.New("error creating scheduler with nil repository")
+	}
+	if logger == nil {
+		return nil, errors.New("error creating scheduler with nil logger")
+	}
+	return &Scheduler{
+		serverId: serverId,
+		repoFn:   repoFn,
+		logger:   logger,
+		jobs:     make(map[string]*Job),
+	}, nil
+}
+
+// RegisterJob adds the job to the ones run by the scheduler. Jobs must be
+// registered before the scheduler is started.
+func (s *Scheduler) RegisterJob(job *Job) error {
+	if err := job.validate(); err != nil {
+		return fmt.Errorf("register job: %w", err)
+	}
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.started {
+		return fmt.Errorf("register job %q: scheduler already started", job.Name)
+	}
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("register job %q: job already registered", job.Name)
+	}
+	s.jobs[job.Name] = job
+	return nil
+}
+
+// Start stores the registered jobs and starts running them until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.started {
+		return errors.New("scheduler already started")
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return fmt.Errorf("error fetching scheduler repository: %w", err)
+	}
+	for _, job := range s.jobs {
+		if err := repo.UpsertJob(ctx, job.Name, job.Description); err != nil {
+			return fmt.Errorf("error storing job %q: %w", job.Name, err)
+		}
+	}
+	for _, job := range s.jobs {
+		go s.schedule(ctx, job)
+	}
+	s.started = true
+	return nil
+}
+
+// schedule runs the job whenever this controller manages to claim it, until
+// ctx is done.
+func (s *Scheduler) schedule(ctx context.Context, job *Job) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	timer := time.NewTimer(0)
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.logger.Info("job scheduling shutting down", "job", job.Name)
+			return
+
+		case <-timer.C:
+			wait := s.runIfDue(ctx, job)
+			if wait > maxWait {
+				wait = maxWait
+			}
+			// desynchronize the claims of the controllers, as only one of
+			// them can succeed.
+			wait += time.Duration(r.Int63n(int64(time.Second)))
+			timer.Reset(wait)
+		}
+	}
+}
+
+// runIfDue claims the job and runs it, and returns how long to wait before
+// trying to claim it again.
+func (s *Scheduler) runIfDue(ctx context.Context, job *Job) time.Duration {
+	timeout := job.Timeout
+	if timeout == 0 {
+		timeout = defaultRunTimeout
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		s.logger.Error("error fetching repository for job", "job", job.Name, "error", err)
+		return job.Interval
+	}
+	run, next, err := repo.ClaimRun(ctx, job.Name, s.serverId, job.Interval, timeout)
+	if err != nil {
+		s.logger.Error("error claiming job", "job", job.Name, "error", err)
+		return job.Interval
+	}
+	if run == nil {
+		s.logger.Trace("job not due or running on another controller", "job", job.Name, "next_run", next)
+		return time.Until(next)
+	}
+
+	s.logger.Trace("running job", "job", job.Name, "run_id", run.PrivateId)
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	runErr := job.Run(runCtx)
+	cancel()
+	if runErr != nil {
+		s.logger.Error("error running job", "job", job.Name, "run_id", run.PrivateId, "error", runErr)
+	}
+	if ctx.Err() != nil {
+		// The run is marked interrupted once the lease expires.
+		return 0
+	}
+	if err := repo.EndRun(ctx, run, runErr); err != nil {
+		s.logger.Error("error recording end of job run", "job", job.Name, "run_id", run.PrivateId, "error", err)
+	}
+	return time.Until(run.StartTime.Add(job.Interval))
+}
@@ -5,6 +5,10 @@ import (
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/hashicorp/boundary/internal/oplog/retention"
+	"github.com/hashicorp/boundary/internal/reports"
+	"github.com/hashicorp/boundary/internal/scheduler"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/boundary/internal/target"
@@ -13,7 +17,11 @@ import (
 type (
 	AuthTokenRepoFactory    func() (*authtoken.Repository, error)
 	IamRepoFactory          func() (*iam.Repository, error)
+	OplogRepoFactory        func() (*inspect.Repository, error)
+	OplogRetentionFactory   func() (*retention.Repository, error)
 	PasswordAuthRepoFactory func() (*password.Repository, error)
+	ReportsRepoFactory      func() (*reports.Repository, error)
+	SchedulerRepoFactory    func() (*scheduler.Repository, error)
 	ServersRepoFactory      func() (*servers.Repository, error)
 	StaticRepoFactory       func() (*static.Repository, error)
 	SessionRepoFactory      func() (*session.Repository, error)
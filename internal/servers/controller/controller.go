@@ -8,11 +8,16 @@ import (
 
 	"github.com/hashicorp/boundary/internal/auth/password"
 	"github.com/hashicorp/boundary/internal/authtoken"
+	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/cmd/config"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/host/static"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/hashicorp/boundary/internal/oplog/retention"
+	"github.com/hashicorp/boundary/internal/reports"
+	"github.com/hashicorp/boundary/internal/scheduler"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/session"
@@ -37,10 +42,18 @@ type Controller struct {
 	// Used for testing
 	workerStatusUpdateTimes *sync.Map
 
+	// Shared by every session repository so that watchers observe all
+	// session and connection state changes made by this controller
+	sessionEvents *session.EventBroker
+
 	// Repo factory methods
 	AuthTokenRepoFn    common.AuthTokenRepoFactory
 	IamRepoFn          common.IamRepoFactory
+	OplogRepoFn        common.OplogRepoFactory
+	OplogRetentionFn   common.OplogRetentionFactory
 	PasswordAuthRepoFn common.PasswordAuthRepoFactory
+	ReportsRepoFn      common.ReportsRepoFactory
+	SchedulerRepoFn    common.SchedulerRepoFactory
 	ServersRepoFn      common.ServersRepoFactory
 	SessionRepoFn      common.SessionRepoFactory
 	StaticHostRepoFn   common.StaticRepoFactory
@@ -49,6 +62,9 @@ type Controller struct {
 	kms *kms.Kms
 
 	clusterAddress string
+
+	// apiListeners holds the reloadable state of the API listeners.
+	apiListeners map[*base.ServerListener]*apiListener
 }
 
 func New(conf *Config) (*Controller, error) {
@@ -56,6 +72,7 @@ func New(conf *Config) (*Controller, error) {
 		conf:                    conf,
 		logger:                  conf.Logger.Named("controller"),
 		workerStatusUpdateTimes: new(sync.Map),
+		sessionEvents:           session.NewEventBroker(),
 	}
 
 	c.started.Store(false)
@@ -91,7 +108,7 @@ func New(conf *Config) (*Controller, error) {
 	}
 
 	// Set up repo stuff
-	dbase := db.New(c.conf.Database)
+	dbase := db.New(c.conf.Database, c.conf.DatabaseReplicas...)
 	kmsRepo, err := kms.NewRepository(dbase, dbase)
 	if err != nil {
 		return nil, fmt.Errorf("error creating kms repository: %w", err)
@@ -126,7 +143,19 @@ func New(conf *Config) (*Controller, error) {
 		return target.NewRepository(dbase, dbase, c.kms)
 	}
 	c.SessionRepoFn = func() (*session.Repository, error) {
-		return session.NewRepository(dbase, dbase, c.kms)
+		return session.NewRepository(dbase, dbase, c.kms, session.WithEventBroker(c.sessionEvents))
+	}
+	c.ReportsRepoFn = func() (*reports.Repository, error) {
+		return reports.NewRepository(dbase)
+	}
+	c.OplogRepoFn = func() (*inspect.Repository, error) {
+		return inspect.NewRepository(dbase, dbase, c.kms)
+	}
+	c.OplogRetentionFn = func() (*retention.Repository, error) {
+		return retention.NewRepository(dbase, dbase)
+	}
+	c.SchedulerRepoFn = func() (*scheduler.Repository, error) {
+		return scheduler.NewRepository(dbase, dbase)
 	}
 
 	c.workerAuthCache = cache.New(0, 0)
@@ -145,9 +174,14 @@ func (c *Controller) Start() error {
 		return fmt.Errorf("error starting controller listeners: %w", err)
 	}
 
-	c.startStatusTicking(c.baseContext)
-	c.startRecoveryNonceCleanupTicking(c.baseContext)
-	c.startTerminateCompletedSessionsTicking(c.baseContext)
+	// Background tasks coordinate with other controllers through the
+	// database, so they always read from the primary. Requests are served
+	// with the base context itself.
+	tickerCtx := db.WithPrimary(c.baseContext)
+	c.startStatusTicking(tickerCtx)
+	if err := c.startScheduler(tickerCtx); err != nil {
+		return fmt.Errorf("error starting job scheduler: %w", err)
+	}
 	c.started.Store(true)
 
 	return nil
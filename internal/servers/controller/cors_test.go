@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/hashicorp/boundary/internal/cmd/config"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/shared-secure-libs/configutil"
 )
 
 const corsTestConfig = `
@@ -251,3 +253,35 @@ func TestHandler_CORS(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_CORSReload(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	cors := newCorsSettings(&configutil.Listener{
+		CorsEnabled:        true,
+		CorsAllowedOrigins: []string{"https://first.example.com"},
+	})
+	h := wrapHandlerWithCors(ok, HandlerProperties{cors: cors})
+
+	status := func(origin string) int {
+		req := httptest.NewRequest(http.MethodGet, "/v1/scopes", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, status("https://first.example.com"))
+	assert.Equal(t, http.StatusForbidden, status("https://second.example.com"))
+
+	cors.store(&configutil.Listener{
+		CorsEnabled:        true,
+		CorsAllowedOrigins: []string{"https://second.example.com"},
+	})
+	assert.Equal(t, http.StatusForbidden, status("https://first.example.com"))
+	assert.Equal(t, http.StatusOK, status("https://second.example.com"))
+
+	cors.store(&configutil.Listener{})
+	assert.Equal(t, http.StatusOK, status("https://first.example.com"))
+}
@@ -7,18 +7,27 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/metrics"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/accounts"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/authmethods"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/history"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/host_sets"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/jobs"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/keys"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/reports"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/sessions"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/targets"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/workers"
+	"github.com/hashicorp/boundary/internal/tracing"
 	"github.com/hashicorp/boundary/sdk/strutil"
 	"github.com/hashicorp/shared-secure-libs/configutil"
 
@@ -36,6 +45,44 @@ import (
 type HandlerProperties struct {
 	ListenerConfig *configutil.Listener
 	CancelCtx      context.Context
+
+	// cors holds the CORS settings of the listener, which are replaced when
+	// the config is reloaded. If nil they are read from ListenerConfig.
+	cors *corsSettings
+}
+
+// corsSettings holds the CORS settings of an API listener.
+type corsSettings struct {
+	v atomic.Value
+}
+
+type corsConfig struct {
+	enabled        bool
+	allowedOrigins []string
+	allowedHeaders []string
+}
+
+func newCorsSettings(l *configutil.Listener) *corsSettings {
+	c := new(corsSettings)
+	c.store(l)
+	return c
+}
+
+// store replaces the settings with those of the listener config.
+func (c *corsSettings) store(l *configutil.Listener) {
+	c.v.Store(&corsConfig{
+		enabled:        l.CorsEnabled,
+		allowedOrigins: l.CorsAllowedOrigins,
+		allowedHeaders: append([]string{
+			"Content-Type",
+			"X-Requested-With",
+			"Authorization",
+		}, l.CorsAllowedHeaders...),
+	})
+}
+
+func (c *corsSettings) load() *corsConfig {
+	return c.v.Load().(*corsConfig)
 }
 
 // Handler returns an http.Handler for the services. This can be used on
@@ -53,8 +100,10 @@ func (c *Controller) handler(props HandlerProperties) (http.Handler, error) {
 
 	corsWrappedHandler := wrapHandlerWithCors(mux, props)
 	commonWrappedHandler := wrapHandlerWithCommonFuncs(corsWrappedHandler, c, props)
+	metricsWrappedHandler := metrics.InstrumentApiHandler(commonWrappedHandler)
+	tracingWrappedHandler := tracing.HttpHandler(metricsWrappedHandler, "controller.api")
 
-	return commonWrappedHandler, nil
+	return tracingWrappedHandler, nil
 }
 
 func handleGrpcGateway(c *Controller, props HandlerProperties) (http.Handler, error) {
@@ -162,13 +211,53 @@ func handleGrpcGateway(c *Controller, props HandlerProperties) (http.Handler, er
 	if err := services.RegisterRoleServiceHandlerServer(ctx, mux, rs); err != nil {
 		return nil, fmt.Errorf("failed to register role service handler: %w", err)
 	}
-	ss, err := sessions.NewService(c.SessionRepoFn, c.IamRepoFn)
+	ss, err := sessions.NewService(c.SessionRepoFn, c.IamRepoFn, c.sessionEvents)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session handler service: %w", err)
 	}
 	if err := services.RegisterSessionServiceHandlerServer(ctx, mux, ss); err != nil {
 		return nil, fmt.Errorf("failed to register session service handler: %w", err)
 	}
+	if err := sessions.RegisterWatchHandler(mux, ss); err != nil {
+		return nil, fmt.Errorf("failed to register session watch handler: %w", err)
+	}
+	reps, err := reports.NewService(c.ReportsRepoFn, c.IamRepoFn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report handler service: %w", err)
+	}
+	if err := services.RegisterReportServiceHandlerServer(ctx, mux, reps); err != nil {
+		return nil, fmt.Errorf("failed to register report service handler: %w", err)
+	}
+	ks, err := keys.NewService(c.kms, c.IamRepoFn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key handler service: %w", err)
+	}
+	if err := services.RegisterKeyServiceHandlerServer(ctx, mux, ks); err != nil {
+		return nil, fmt.Errorf("failed to register key service handler: %w", err)
+	}
+	ws, err := workers.NewService(c.ServersRepoFn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worker handler service: %w", err)
+	}
+	if err := services.RegisterWorkerServiceHandlerServer(ctx, mux, ws); err != nil {
+		return nil, fmt.Errorf("failed to register worker service handler: %w", err)
+	}
+	js, err := jobs.NewService(c.SchedulerRepoFn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job handler service: %w", err)
+	}
+	if err := services.RegisterJobServiceHandlerServer(ctx, mux, js); err != nil {
+		return nil, fmt.Errorf("failed to register job service handler: %w", err)
+	}
+	// The history service is registered last so its custom methods, e.g.
+	// "/v1/roles/{id}:history", are matched before the resources' Get methods.
+	hist, err := history.NewService(c.IamRepoFn, c.StaticHostRepoFn, c.TargetRepoFn, c.OplogRepoFn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create history handler service: %w", err)
+	}
+	if err := services.RegisterHistoryServiceHandlerServer(ctx, mux, hist); err != nil {
+		return nil, fmt.Errorf("failed to register history service handler: %w", err)
+	}
 
 	return mux, nil
 }
@@ -203,8 +292,15 @@ func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProp
 		// Set the Cache-Control header for all responses returned
 		w.Header().Set("Cache-Control", "no-store")
 
-		// Start with the request context and our timeout
-		ctx, cancelFunc := context.WithTimeout(r.Context(), maxRequestDuration)
+		// Start with the request context and our timeout. Streaming requests
+		// are long lived and are only bound by the client's connection.
+		var ctx context.Context
+		var cancelFunc context.CancelFunc
+		if r.URL.Path == sessions.WatchPath {
+			ctx, cancelFunc = context.WithCancel(r.Context())
+		} else {
+			ctx, cancelFunc = context.WithTimeout(r.Context(), maxRequestDuration)
+		}
 		defer cancelFunc()
 
 		// Add a size limiter if desired
@@ -212,6 +308,10 @@ func wrapHandlerWithCommonFuncs(h http.Handler, c *Controller, props HandlerProp
 			ctx = context.WithValue(ctx, globals.ContextMaxRequestSizeTypeKey, maxRequestSize)
 		}
 
+		// Reads made after a write in the request see the write, even when
+		// read replicas are used
+		ctx = db.NewReadYourWritesContext(ctx)
+
 		// Add values for authn/authz checking
 		requestInfo := auth.RequestInfo{
 			Path:                 r.URL.Path,
@@ -238,19 +338,18 @@ func wrapHandlerWithCors(h http.Handler, props HandlerProperties) http.Handler {
 		http.MethodPatch,
 	}
 
-	allowedOrigins := props.ListenerConfig.CorsAllowedOrigins
-
-	allowedHeaders := append([]string{
-		"Content-Type",
-		"X-Requested-With",
-		"Authorization",
-	}, props.ListenerConfig.CorsAllowedHeaders...)
+	settings := props.cors
+	if settings == nil {
+		settings = newCorsSettings(props.ListenerConfig)
+	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		if !props.ListenerConfig.CorsEnabled {
+		cors := settings.load()
+		if !cors.enabled {
 			h.ServeHTTP(w, req)
 			return
 		}
+		allowedOrigins := cors.allowedOrigins
 
 		origin := req.Header.Get("Origin")
 
@@ -298,7 +397,7 @@ func wrapHandlerWithCors(h http.Handler, props HandlerProperties) http.Handler {
 		// Apply headers for preflight requests
 		if req.Method == http.MethodOptions {
 			w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowedMethods, ", "))
-			w.Header().Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(cors.allowedHeaders, ", "))
 			w.Header().Set("Access-Control-Max-Age", "300")
 			w.WriteHeader(http.StatusNoContent)
 			return
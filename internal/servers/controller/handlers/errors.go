@@ -148,6 +148,26 @@ func backendErrorToApiError(inErr error) error {
 	return nil
 }
 
+// ToApiError converts the provided error into the error representation
+// presented to an end user over the API. Errors which are not known are
+// presented as internal errors.
+func ToApiError(inErr error) *pb.Error {
+	var apiErr *apiError
+	if !errors.As(inErr, &apiErr) {
+		if err := backendErrorToApiError(inErr); err != nil {
+			errors.As(err, &apiErr)
+		}
+	}
+	if apiErr == nil {
+		errId, err := internalErrorId()
+		if err != nil {
+			errId = "failed_to_generate_error_id"
+		}
+		apiErr = getInternalError(errId)
+	}
+	return apiErr.inner
+}
+
 func getInternalError(id string) *apiError {
 	return &apiError{&pb.Error{
 		Status:  http.StatusInternalServerError,
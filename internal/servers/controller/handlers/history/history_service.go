@@ -0,0 +1,262 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/db"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/history"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Service handles request as described by the pbs.HistoryServiceServer interface.
+type Service struct {
+	iamRepoFn    common.IamRepoFactory
+	staticRepoFn common.StaticRepoFactory
+	targetRepoFn common.TargetRepoFactory
+	oplogRepoFn  common.OplogRepoFactory
+}
+
+// NewService returns a history service which handles requests for the change
+// history of resources to boundary.
+func NewService(iamRepoFn common.IamRepoFactory, staticRepoFn common.StaticRepoFactory, targetRepoFn common.TargetRepoFactory, oplogRepoFn common.OplogRepoFactory) (Service, error) {
+	if iamRepoFn == nil {
+		return Service{}, fmt.Errorf("nil iam repository provided")
+	}
+	if staticRepoFn == nil {
+		return Service{}, fmt.Errorf("nil static repository provided")
+	}
+	if targetRepoFn == nil {
+		return Service{}, fmt.Errorf("nil target repository provided")
+	}
+	if oplogRepoFn == nil {
+		return Service{}, fmt.Errorf("nil oplog repository provided")
+	}
+	return Service{iamRepoFn: iamRepoFn, staticRepoFn: staticRepoFn, targetRepoFn: targetRepoFn, oplogRepoFn: oplogRepoFn}, nil
+}
+
+var _ pbs.HistoryServiceServer = Service{}
+
+// GetRoleHistory implements the interface pbs.HistoryServiceServer.
+func (s Service) GetRoleHistory(ctx context.Context, req *pbs.GetRoleHistoryRequest) (*pbs.GetRoleHistoryResponse, error) {
+	if err := handlers.ValidateGetRequest(iam.RolePrefix, req, handlers.NoopValidatorFn); err != nil {
+		return nil, err
+	}
+	if authResults := s.roleAuthResult(ctx, req.GetId()); authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	items, err := s.historyFromRepo(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetRoleHistoryResponse{Items: items}, nil
+}
+
+// GetTargetHistory implements the interface pbs.HistoryServiceServer.
+func (s Service) GetTargetHistory(ctx context.Context, req *pbs.GetTargetHistoryRequest) (*pbs.GetTargetHistoryResponse, error) {
+	prefix := target.TcpTargetPrefix
+	if target.SubtypeFromId(req.GetId()) == target.UdpSubType {
+		prefix = target.UdpTargetPrefix
+	}
+	if err := handlers.ValidateGetRequest(prefix, req, handlers.NoopValidatorFn); err != nil {
+		return nil, err
+	}
+	if authResults := s.targetAuthResult(ctx, req.GetId()); authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	items, err := s.historyFromRepo(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetTargetHistoryResponse{Items: items}, nil
+}
+
+// GetHostSetHistory implements the interface pbs.HistoryServiceServer.
+func (s Service) GetHostSetHistory(ctx context.Context, req *pbs.GetHostSetHistoryRequest) (*pbs.GetHostSetHistoryResponse, error) {
+	if err := handlers.ValidateGetRequest(static.HostSetPrefix, req, handlers.NoopValidatorFn); err != nil {
+		return nil, err
+	}
+	if authResults := s.hostSetAuthResult(ctx, req.GetId()); authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	items, err := s.historyFromRepo(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetHostSetHistoryResponse{Items: items}, nil
+}
+
+// GetUserHistory implements the interface pbs.HistoryServiceServer.
+func (s Service) GetUserHistory(ctx context.Context, req *pbs.GetUserHistoryRequest) (*pbs.GetUserHistoryResponse, error) {
+	if err := handlers.ValidateGetRequest(iam.UserPrefix, req, handlers.NoopValidatorFn); err != nil {
+		return nil, err
+	}
+	if authResults := s.userAuthResult(ctx, req.GetId()); authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	items, err := s.historyFromRepo(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetUserHistoryResponse{Items: items}, nil
+}
+
+func (s Service) historyFromRepo(ctx context.Context, id string) ([]*pb.Change, error) {
+	repo, err := s.oplogRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := repo.ResourceHistory(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// The resource exists but its history was pruned from the oplog.
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := make([]*pb.Change, 0, len(changes))
+	for _, c := range changes {
+		item, err := toProto(c)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (s Service) roleAuthResult(ctx context.Context, id string) auth.VerifyResults {
+	res := auth.VerifyResults{}
+	repo, err := s.iamRepoFn()
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	r, _, _, err := repo.LookupRole(ctx, id)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	if r == nil {
+		res.Error = handlers.NotFoundError()
+		return res
+	}
+	return auth.Verify(ctx, auth.WithType(resource.Role), auth.WithAction(action.History), auth.WithId(id), auth.WithScopeId(r.GetScopeId()))
+}
+
+func (s Service) userAuthResult(ctx context.Context, id string) auth.VerifyResults {
+	res := auth.VerifyResults{}
+	repo, err := s.iamRepoFn()
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	u, _, err := repo.LookupUser(ctx, id)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	if u == nil {
+		res.Error = handlers.NotFoundError()
+		return res
+	}
+	return auth.Verify(ctx, auth.WithType(resource.User), auth.WithAction(action.History), auth.WithId(id), auth.WithScopeId(u.GetScopeId()))
+}
+
+func (s Service) targetAuthResult(ctx context.Context, id string) auth.VerifyResults {
+	res := auth.VerifyResults{}
+	repo, err := s.targetRepoFn()
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	t, _, err := repo.LookupTarget(ctx, id)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	if t == nil {
+		res.Error = handlers.NotFoundError()
+		return res
+	}
+	return auth.Verify(ctx, auth.WithType(resource.Target), auth.WithAction(action.History), auth.WithId(id), auth.WithScopeId(t.GetScopeId()))
+}
+
+func (s Service) hostSetAuthResult(ctx context.Context, id string) auth.VerifyResults {
+	res := auth.VerifyResults{}
+	repo, err := s.staticRepoFn()
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	set, _, err := repo.LookupSet(ctx, id)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	if set == nil {
+		res.Error = handlers.NotFoundError()
+		return res
+	}
+	cat, err := repo.LookupCatalog(ctx, set.GetCatalogId())
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	if cat == nil {
+		res.Error = handlers.NotFoundError()
+		return res
+	}
+	return auth.Verify(ctx, auth.WithType(resource.HostSet), auth.WithAction(action.History), auth.WithId(id),
+		auth.WithScopeId(cat.GetScopeId()), auth.WithPin(cat.GetPublicId()))
+}
+
+func toProto(in *inspect.Change) (*pb.Change, error) {
+	out := &pb.Change{
+		EntryId:       in.EntryId,
+		CreatedTime:   timestamppb.New(in.CreateTime),
+		Operation:     operation(in.OpType),
+		TypeName:      in.TypeName,
+		ChangedFields: in.ChangedFields,
+		NulledFields:  in.NulledFields,
+		UserId:        in.UserId,
+		AuthTokenId:   in.AuthTokenId,
+	}
+	var err error
+	if in.Before != nil {
+		if out.Before, err = structpb.NewStruct(in.Before); err != nil {
+			return nil, fmt.Errorf("unable to convert previous values of entry %d: %w", in.EntryId, err)
+		}
+	}
+	if in.After != nil {
+		if out.After, err = structpb.NewStruct(in.After); err != nil {
+			return nil, fmt.Errorf("unable to convert values of entry %d: %w", in.EntryId, err)
+		}
+	}
+	return out, nil
+}
+
+func operation(t oplog.OpType) string {
+	switch t {
+	case oplog.OpType_OP_TYPE_CREATE:
+		return "create"
+	case oplog.OpType_OP_TYPE_UPDATE:
+		return "update"
+	case oplog.OpType_OP_TYPE_DELETE:
+		return "delete"
+	}
+	return "unknown"
+}
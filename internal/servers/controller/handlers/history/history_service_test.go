@@ -0,0 +1,85 @@
+package history_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/db"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog/inspect"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers/history"
+	"github.com/hashicorp/boundary/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGetUserHistory(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	wrap := db.TestWrapper(t)
+	rw := db.New(conn)
+	kmsCache := kms.TestKms(t, conn, wrap)
+	iamRepo := iam.TestRepo(t, conn, wrap)
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iamRepo, nil
+	}
+	staticRepoFn := func() (*static.Repository, error) {
+		return static.NewRepository(rw, rw, kmsCache)
+	}
+	targetRepoFn := func() (*target.Repository, error) {
+		return target.NewRepository(rw, rw, kmsCache)
+	}
+	oplogRepoFn := func() (*inspect.Repository, error) {
+		return inspect.NewRepository(rw, rw, kmsCache)
+	}
+	s, err := history.NewService(iamRepoFn, staticRepoFn, targetRepoFn, oplogRepoFn)
+	require.NoError(t, err)
+
+	o, _ := iam.TestScopes(t, iamRepo)
+	u := iam.TestUser(t, iamRepo, o.GetPublicId(), iam.WithName("default"))
+
+	cases := []struct {
+		name    string
+		id      string
+		wantLen int
+		err     error
+	}{
+		{
+			name:    "Existing User",
+			id:      u.GetPublicId(),
+			wantLen: 1,
+		},
+		{
+			name: "Non existant User",
+			id:   iam.UserPrefix + "_DoesntExis",
+			err:  handlers.ApiErrorWithCode(codes.NotFound),
+		},
+		{
+			name: "Wrong id prefix",
+			id:   "j_1234567890",
+			err:  handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, gErr := s.GetUserHistory(auth.DisabledAuthTestContext(auth.WithScopeId(o.GetPublicId())), &pbs.GetUserHistoryRequest{Id: tc.id})
+			if tc.err != nil {
+				require.Error(gErr)
+				assert.True(errors.Is(gErr, tc.err), "GetUserHistory(%q) got error %v, wanted %v", tc.id, gErr, tc.err)
+				return
+			}
+			require.NoError(gErr)
+			require.Len(got.GetItems(), tc.wantLen)
+			item := got.GetItems()[0]
+			assert.Equal("create", item.GetOperation())
+			assert.Equal("iam_user", item.GetTypeName())
+			assert.Equal("default", item.GetAfter().GetFields()["name"].GetStringValue())
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/jobs"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// runsListed is the number of latest runs returned when reading a job.
+const runsListed = 20
+
+// Service handles request as described by the pbs.JobServiceServer interface.
+type Service struct {
+	repoFn common.SchedulerRepoFactory
+}
+
+// NewService returns a job service which handles job related requests to boundary.
+func NewService(repoFn common.SchedulerRepoFactory) (Service, error) {
+	if repoFn == nil {
+		return Service{}, fmt.Errorf("nil scheduler repository provided")
+	}
+	return Service{repoFn: repoFn}, nil
+}
+
+var _ pbs.JobServiceServer = Service{}
+
+// ListJobs implements the interface pbs.JobServiceServer.
+func (s Service) ListJobs(ctx context.Context, req *pbs.ListJobsRequest) (*pbs.ListJobsResponse, error) {
+	if err := validateListRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, "", action.List)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	js, err := repo.ListJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var items []*pb.Job
+	for _, j := range js {
+		item := toProto(j)
+		item.Scope = authResults.Scope
+		items = append(items, item)
+	}
+	return &pbs.ListJobsResponse{Items: items}, nil
+}
+
+// GetJob implements the interface pbs.JobServiceServer.
+func (s Service) GetJob(ctx context.Context, req *pbs.GetJobRequest) (*pbs.GetJobResponse, error) {
+	if err := validateGetRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	j, err := repo.LookupJob(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if j == nil {
+		return nil, handlers.NotFoundErrorf("Job %q not found.", req.GetId())
+	}
+	runs, err := repo.ListRuns(ctx, req.GetId(), scheduler.WithLimit(runsListed))
+	if err != nil {
+		return nil, err
+	}
+	item := toProto(j)
+	item.Scope = authResults.Scope
+	for _, r := range runs {
+		item.Runs = append(item.Runs, runToProto(r))
+	}
+	return &pbs.GetJobResponse{Item: item}, nil
+}
+
+func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.VerifyResults {
+	res := auth.VerifyResults{}
+	opts := []auth.Option{auth.WithType(resource.Job), auth.WithAction(a), auth.WithScopeId(scope.Global.String())}
+	if a != action.List {
+		repo, err := s.repoFn()
+		if err != nil {
+			res.Error = err
+			return res
+		}
+		j, err := repo.LookupJob(ctx, id)
+		if err != nil {
+			res.Error = err
+			return res
+		}
+		if j == nil {
+			res.Error = handlers.NotFoundError()
+			return res
+		}
+		opts = append(opts, auth.WithId(id))
+	}
+	return auth.Verify(ctx, opts...)
+}
+
+func toProto(in *scheduler.JobStatus) *pb.Job {
+	out := &pb.Job{
+		Id:                  in.Name,
+		Description:         in.Description,
+		NextScheduledRun:    timestamppb.New(in.NextScheduledRun),
+		RunningControllerId: in.LeaseOwner,
+	}
+	if in.LastRun != nil {
+		out.LastRun = runToProto(in.LastRun)
+	}
+	return out
+}
+
+func runToProto(in *scheduler.Run) *pb.JobRun {
+	out := &pb.JobRun{
+		Id:           in.PrivateId,
+		ControllerId: in.ServerId,
+		Status:       in.Status.String(),
+		StartTime:    timestamppb.New(in.StartTime),
+		Error:        in.Error,
+	}
+	if !in.EndTime.IsZero() {
+		out.EndTime = timestamppb.New(in.EndTime)
+		out.DurationMilliseconds = uint64(in.Duration().Milliseconds())
+	}
+	return out
+}
+
+// A validateX method should exist for each method above.  These methods do not make calls to any backing service but enforce
+// requirements on the structure of the request.  They verify that:
+//  * The path passed in is correctly formatted
+//  * All required parameters are set
+//  * There are no conflicting parameters provided
+func validateGetRequest(req *pbs.GetJobRequest) error {
+	if req.GetId() == "" {
+		return handlers.InvalidArgumentErrorf("Invalid fields provided in request.",
+			map[string]string{"id": "This field is required."})
+	}
+	return nil
+}
+
+func validateListRequest(req *pbs.ListJobsRequest) error {
+	if req.GetScopeId() != "" && req.GetScopeId() != scope.Global.String() {
+		return handlers.InvalidArgumentErrorf("Invalid fields provided in request.",
+			map[string]string{"scope_id": "Jobs are only in the global scope."})
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package keys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	"github.com/hashicorp/boundary/internal/db"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/keys"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Service handles request as described by the pbs.KeyServiceServer interface.
+type Service struct {
+	kms       *kms.Kms
+	iamRepoFn common.IamRepoFactory
+}
+
+// NewService returns a key service which handles key related requests to boundary.
+func NewService(kms *kms.Kms, iamRepoFn common.IamRepoFactory) (Service, error) {
+	if kms == nil {
+		return Service{}, fmt.Errorf("nil kms provided")
+	}
+	if iamRepoFn == nil {
+		return Service{}, fmt.Errorf("nil iam repository provided")
+	}
+	return Service{kms: kms, iamRepoFn: iamRepoFn}, nil
+}
+
+var _ pbs.KeyServiceServer = Service{}
+
+// ListKeyVersions implements the interface pbs.KeyServiceServer.
+func (s Service) ListKeyVersions(ctx context.Context, req *pbs.ListKeyVersionsRequest) (*pbs.ListKeyVersionsResponse, error) {
+	if err := validateScopeId(req.GetScopeId()); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	items, err := s.listFromKms(ctx, req.GetScopeId())
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.Scope = authResults.Scope
+	}
+	return &pbs.ListKeyVersionsResponse{Items: items}, nil
+}
+
+// RotateKeys implements the interface pbs.KeyServiceServer.
+func (s Service) RotateKeys(ctx context.Context, req *pbs.RotateKeysRequest) (*pbs.RotateKeysResponse, error) {
+	if err := validateScopeId(req.GetScopeId()); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetScopeId(), action.Rotate)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	if _, err := s.kms.RotateKeys(ctx, req.GetScopeId()); err != nil {
+		return nil, fmt.Errorf("unable to rotate keys: %w", err)
+	}
+	items, err := s.listFromKms(ctx, req.GetScopeId())
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.Scope = authResults.Scope
+	}
+	return &pbs.RotateKeysResponse{Items: items}, nil
+}
+
+// DeleteKeyVersion implements the interface pbs.KeyServiceServer.
+func (s Service) DeleteKeyVersion(ctx context.Context, req *pbs.DeleteKeyVersionRequest) (*pbs.DeleteKeyVersionResponse, error) {
+	if err := validateDeleteRequest(req); err != nil {
+		return nil, err
+	}
+	scopeId, err := s.kms.LookupKeyVersionScope(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, handlers.NotFoundErrorf("Key version %q not found.", req.GetId())
+		}
+		return nil, err
+	}
+	authResults := s.authResult(ctx, scopeId, action.Delete)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	if err := s.kms.DestroyKeyVersion(ctx, scopeId, req.GetId()); err != nil {
+		if errors.Is(err, kms.ErrKeyVersionInUse) {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.FailedPrecondition, "Key version %q is still in use: %s.", req.GetId(), inUseReason(err))
+		}
+		return nil, fmt.Errorf("unable to destroy key version: %w", err)
+	}
+	return &pbs.DeleteKeyVersionResponse{}, nil
+}
+
+func (s Service) listFromKms(ctx context.Context, scopeId string) ([]*pb.KeyVersion, error) {
+	versions, err := s.kms.ListKeyVersionStatus(ctx, scopeId)
+	if err != nil {
+		return nil, err
+	}
+	var out []*pb.KeyVersion
+	for _, v := range versions {
+		out = append(out, toProto(v))
+	}
+	return out, nil
+}
+
+func (s Service) authResult(ctx context.Context, scopeId string, a action.Type) auth.VerifyResults {
+	res := auth.VerifyResults{}
+	iamRepo, err := s.iamRepoFn()
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	scp, err := iamRepo.LookupScope(ctx, scopeId)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	if scp == nil {
+		res.Error = handlers.NotFoundError()
+		return res
+	}
+	return auth.Verify(ctx, auth.WithType(resource.Key), auth.WithAction(a), auth.WithScopeId(scopeId))
+}
+
+// inUseReason strips the operation prefixes from an error returned by
+// DestroyKeyVersion, leaving the reason the version is in use.
+func inUseReason(err error) string {
+	msg := strings.TrimSuffix(err.Error(), ": "+kms.ErrKeyVersionInUse.Error())
+	if i := strings.LastIndex(msg, ": "); i >= 0 {
+		msg = msg[i+2:]
+	}
+	return msg
+}
+
+func toProto(in *kms.KeyVersionStatus) *pb.KeyVersion {
+	return &pb.KeyVersion{
+		Id:          in.Id,
+		Purpose:     in.Purpose,
+		Version:     in.Version,
+		CreatedTime: timestamppb.New(in.CreateTime),
+		Current:     in.Current,
+		References:  uint32(in.References),
+		Destroyable: in.Destroyable,
+
+		NotDestroyableReason: in.NotDestroyableReason,
+	}
+}
+
+// A validateX method should exist for each method above.  These methods do not make calls to any backing service but enforce
+// requirements on the structure of the request.  They verify that:
+//  * The path passed in is correctly formatted
+//  * All required parameters are set
+//  * There are no conflicting parameters provided
+func validateScopeId(id string) error {
+	if id != scope.Global.String() &&
+		!handlers.ValidId(scope.Org.Prefix(), id) &&
+		!handlers.ValidId(scope.Project.Prefix(), id) {
+		return handlers.InvalidArgumentErrorf("Invalid fields provided in request.",
+			map[string]string{"scope_id": "This field is required to have a properly formatted scope id."})
+	}
+	return nil
+}
+
+func validateDeleteRequest(req *pbs.DeleteKeyVersionRequest) error {
+	if req.GetId() == "" {
+		return handlers.InvalidArgumentErrorf("Invalid fields provided in request.",
+			map[string]string{"id": "This field is required."})
+	}
+	return nil
+}
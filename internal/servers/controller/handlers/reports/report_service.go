@@ -0,0 +1,144 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/reports"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/reports"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/internal/types/scope"
+)
+
+// Service handles request as described by the pbs.ReportServiceServer interface.
+type Service struct {
+	repoFn    common.ReportsRepoFactory
+	iamRepoFn common.IamRepoFactory
+}
+
+// NewService returns a report service which handles report related requests to boundary.
+func NewService(repoFn common.ReportsRepoFactory, iamRepoFn common.IamRepoFactory) (Service, error) {
+	if repoFn == nil {
+		return Service{}, fmt.Errorf("nil reports repository provided")
+	}
+	if iamRepoFn == nil {
+		return Service{}, fmt.Errorf("nil iam repository provided")
+	}
+	return Service{repoFn: repoFn, iamRepoFn: iamRepoFn}, nil
+}
+
+var _ pbs.ReportServiceServer = Service{}
+
+// GetUsageReport implements the interface pbs.ReportServiceServer.
+func (s Service) GetUsageReport(ctx context.Context, req *pbs.GetUsageReportRequest) (*pbs.GetUsageReportResponse, error) {
+	if err := validateGetUsageReportRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetScopeId())
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	items, err := s.usageFromRepo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pbs.GetUsageReportResponse{Items: items}, nil
+}
+
+func (s Service) usageFromRepo(ctx context.Context, req *pbs.GetUsageReportRequest) ([]*pb.Usage, error) {
+	repo, err := s.repoFn()
+	if err != nil {
+		return nil, err
+	}
+	opts := []reports.Option{reports.WithScopeId(req.GetScopeId())}
+	if req.GetStartTime() != nil {
+		opts = append(opts, reports.WithStartTime(req.GetStartTime().AsTime()))
+	}
+	if req.GetEndTime() != nil {
+		opts = append(opts, reports.WithEndTime(req.GetEndTime().AsTime()))
+	}
+	if req.GetLimit() > 0 {
+		opts = append(opts, reports.WithLimit(int(req.GetLimit())))
+	}
+	usage, err := repo.Usage(ctx, reports.GroupBy(req.GetGroupBy()), opts...)
+	if err != nil {
+		return nil, err
+	}
+	var out []*pb.Usage
+	for _, u := range usage {
+		out = append(out, toProto(u))
+	}
+	return out, nil
+}
+
+func (s Service) authResult(ctx context.Context, scopeId string) auth.VerifyResults {
+	res := auth.VerifyResults{}
+	iamRepo, err := s.iamRepoFn()
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	scp, err := iamRepo.LookupScope(ctx, scopeId)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	if scp == nil {
+		res.Error = handlers.NotFoundError()
+		return res
+	}
+	return auth.Verify(ctx, auth.WithType(resource.Report), auth.WithAction(action.Read), auth.WithScopeId(scopeId))
+}
+
+func toProto(in *reports.Usage) *pb.Usage {
+	return &pb.Usage{
+		Key:                   in.Key,
+		Name:                  in.Name,
+		SessionCount:          in.SessionCount,
+		ConnectionCount:       in.ConnectionCount,
+		FailedConnectionCount: in.FailedConnectionCount,
+		FailedConnectionRate:  in.FailedConnectionRate(),
+		ConnectedSeconds:      in.ConnectedSeconds,
+		BytesUp:               in.BytesUp,
+		BytesDown:             in.BytesDown,
+	}
+}
+
+// A validateX method should exist for each method above.  These methods do not make calls to any backing service but enforce
+// requirements on the structure of the request.  They verify that:
+//  * The path passed in is correctly formatted
+//  * All required parameters are set
+//  * There are no conflicting parameters provided
+func validateGetUsageReportRequest(req *pbs.GetUsageReportRequest) error {
+	badFields := map[string]string{}
+	id := req.GetScopeId()
+	if id != scope.Global.String() &&
+		!handlers.ValidId(scope.Org.Prefix(), id) &&
+		!handlers.ValidId(scope.Project.Prefix(), id) {
+		badFields["scope_id"] = "This field is required to have a properly formatted scope id."
+	}
+	switch reports.GroupBy(req.GetGroupBy()) {
+	case reports.GroupByUser, reports.GroupByTarget, reports.GroupByHost, reports.GroupByDay:
+	default:
+		badFields["group_by"] = `Must be one of "user", "target", "host" or "day".`
+	}
+	if req.GetStartTime() != nil && !req.GetStartTime().IsValid() {
+		badFields["start_time"] = "Invalid timestamp."
+	}
+	if req.GetEndTime() != nil && !req.GetEndTime().IsValid() {
+		badFields["end_time"] = "Invalid timestamp."
+	}
+	if req.GetStartTime() != nil && req.GetEndTime() != nil &&
+		!req.GetStartTime().AsTime().Before(req.GetEndTime().AsTime()) {
+		badFields["end_time"] = "Must be after the start time."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Invalid fields provided in request.", badFields)
+	}
+	return nil
+}
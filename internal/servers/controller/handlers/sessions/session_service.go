@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/boundary/internal/db"
 	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/sessions"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/session"
@@ -16,23 +17,29 @@ import (
 	"github.com/hashicorp/boundary/internal/types/action"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Service handles request as described by the pbs.SessionServiceServer interface.
 type Service struct {
 	repoFn    common.SessionRepoFactory
 	iamRepoFn common.IamRepoFactory
+	events    *session.EventBroker
 }
 
 // NewService returns a session service which handles session related requests to boundary.
-func NewService(repoFn common.SessionRepoFactory, iamRepoFn common.IamRepoFactory) (Service, error) {
+func NewService(repoFn common.SessionRepoFactory, iamRepoFn common.IamRepoFactory, events *session.EventBroker) (Service, error) {
 	if repoFn == nil {
 		return Service{}, fmt.Errorf("nil session repository provided")
 	}
 	if iamRepoFn == nil {
 		return Service{}, fmt.Errorf("nil iam repository provided")
 	}
-	return Service{repoFn: repoFn, iamRepoFn: iamRepoFn}, nil
+	if events == nil {
+		return Service{}, fmt.Errorf("nil session event broker provided")
+	}
+	return Service{repoFn: repoFn, iamRepoFn: iamRepoFn, events: events}, nil
 }
 
 var _ pbs.SessionServiceServer = Service{}
@@ -90,6 +97,39 @@ func (s Service) CancelSession(ctx context.Context, req *pbs.CancelSessionReques
 	return &pbs.CancelSessionResponse{Item: ses}, nil
 }
 
+// WatchSessions implements the interface pbs.SessionServiceServer.
+func (s Service) WatchSessions(req *pbs.WatchSessionsRequest, stream pbs.SessionService_WatchSessionsServer) error {
+	if err := validateWatchRequest(req); err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	authResults := s.authResult(ctx, req.GetScopeId(), action.List)
+	if authResults.Error != nil {
+		return authResults.Error
+	}
+	events := s.events.Subscribe(ctx, session.EventFilter{
+		ScopeId:  authResults.Scope.GetId(),
+		UserId:   req.GetUserId(),
+		TargetId: req.GetTargetId(),
+	})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return handlers.ApiErrorWithCodeAndMessage(codes.Aborted, "Watch fell too far behind the session state changes; list sessions and watch again.")
+			}
+			if err := stream.Send(toWatchProto(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 func (s Service) getFromRepo(ctx context.Context, id string) (*pb.Session, error) {
 	repo, err := s.repoFn()
 	if err != nil {
@@ -221,6 +261,19 @@ func toProto(in *session.Session) *pb.Session {
 	return &out
 }
 
+func toWatchProto(in *session.Event) *pbs.WatchSessionsResponse {
+	return &pbs.WatchSessionsResponse{
+		Type:         in.Type.String(),
+		SessionId:    in.SessionId,
+		ConnectionId: in.ConnectionId,
+		ScopeId:      in.ScopeId,
+		UserId:       in.UserId,
+		TargetId:     in.TargetId,
+		Status:       in.Status,
+		Time:         timestamppb.New(in.Time),
+	}
+}
+
 // A validateX method should exist for each method above.  These methods do not make calls to any backing service but enforce
 // requirements on the structure of the request.  They verify that:
 //  * The path passed in is correctly formatted
@@ -254,3 +307,20 @@ func validateCancelRequest(req *pbs.CancelSessionRequest) error {
 	}
 	return nil
 }
+
+func validateWatchRequest(req *pbs.WatchSessionsRequest) error {
+	badFields := map[string]string{}
+	if !handlers.ValidId(scope.Project.Prefix(), req.GetScopeId()) {
+		badFields["scope_id"] = "This field is required to have a properly formatted project scope id."
+	}
+	if req.GetUserId() != "" && !handlers.ValidId(iam.UserPrefix, req.GetUserId()) {
+		badFields["user_id"] = "Improperly formatted identifier."
+	}
+	if req.GetTargetId() != "" && !handlers.ValidId(target.TcpTargetPrefix, req.GetTargetId()) && !handlers.ValidId(target.UdpTargetPrefix, req.GetTargetId()) {
+		badFields["target_id"] = "Improperly formatted identifier."
+	}
+	if len(badFields) > 0 {
+		return handlers.InvalidArgumentErrorf("Improperly formatted identifier.", badFields)
+	}
+	return nil
+}
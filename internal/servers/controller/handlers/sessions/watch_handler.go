@@ -0,0 +1,178 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// WatchPath is the HTTP path WatchSessions is served on.
+	WatchPath = "/v1/sessions:watch"
+
+	// watchKeepaliveInterval is how often an SSE comment is written to keep
+	// intermediaries from closing an idle stream.
+	watchKeepaliveInterval = 30 * time.Second
+)
+
+// RegisterWatchHandler registers an HTTP handler on the gateway mux which
+// serves WatchSessions as Server-Sent Events. The gateway's in-process
+// transport does not support streaming calls so this replaces the generated
+// handler for the route.
+func RegisterWatchHandler(mux *runtime.ServeMux, s Service) error {
+	return mux.HandlePath(http.MethodGet, WatchPath, func(w http.ResponseWriter, req *http.Request, _ map[string]string) {
+		ctx := req.Context()
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, handlers.ApiErrorWithCodeAndMessage(codes.Unimplemented, "Streaming is not supported by this listener."))
+			return
+		}
+		if err := req.ParseForm(); err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, handlers.InvalidArgumentErrorf("Unable to parse query parameters.", nil))
+			return
+		}
+		var protoReq pbs.WatchSessionsRequest
+		if err := runtime.PopulateQueryParameters(&protoReq, req.Form, utilities.NewDoubleArray(nil)); err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, handlers.InvalidArgumentErrorf("Unable to parse query parameters.", nil))
+			return
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		stream := &sseStream{
+			ctx:       ctx,
+			w:         w,
+			flusher:   flusher,
+			marshaler: outboundMarshaler,
+		}
+		keepaliveDone := make(chan struct{})
+		go func() {
+			defer close(keepaliveDone)
+			stream.keepalive()
+		}()
+		// The response writer must not be used once the handler returns.
+		defer func() {
+			cancel()
+			<-keepaliveDone
+		}()
+
+		err := s.WatchSessions(&protoReq, stream)
+		if err == nil {
+			return
+		}
+		if !stream.started() {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		stream.writeError(err)
+	})
+}
+
+// sseStream adapts an http.ResponseWriter to the
+// pbs.SessionService_WatchSessionsServer interface, writing each message as a
+// Server-Sent Event.
+type sseStream struct {
+	ctx       context.Context
+	w         http.ResponseWriter
+	flusher   http.Flusher
+	marshaler runtime.Marshaler
+
+	l           sync.Mutex
+	wroteHeader bool
+}
+
+var _ pbs.SessionService_WatchSessionsServer = (*sseStream)(nil)
+
+func (s *sseStream) Send(m *pbs.WatchSessionsResponse) error {
+	return s.SendMsg(m)
+}
+
+func (s *sseStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", m)
+	}
+	b, err := s.marshaler.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.write("message", b)
+}
+
+func (s *sseStream) RecvMsg(interface{}) error {
+	return fmt.Errorf("receiving is not supported on a server-sent event stream")
+}
+
+func (s *sseStream) Context() context.Context     { return s.ctx }
+func (s *sseStream) SetHeader(metadata.MD) error  { return nil }
+func (s *sseStream) SendHeader(metadata.MD) error { return nil }
+func (s *sseStream) SetTrailer(metadata.MD)       {}
+
+func (s *sseStream) started() bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.wroteHeader
+}
+
+func (s *sseStream) writeHeaderLocked() {
+	if s.wroteHeader {
+		return
+	}
+	s.w.Header().Set("Content-Type", "text/event-stream")
+	s.w.Header().Set("Connection", "keep-alive")
+	s.w.WriteHeader(http.StatusOK)
+	s.wroteHeader = true
+}
+
+func (s *sseStream) write(event string, data []byte) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.writeHeaderLocked()
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sseStream) writeError(err error) {
+	apiErr := handlers.ToApiError(err)
+	b, mErr := s.marshaler.Marshal(apiErr)
+	if mErr != nil {
+		return
+	}
+	_ = s.write("error", b)
+}
+
+func (s *sseStream) keepalive() {
+	t := time.NewTicker(watchKeepaliveInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-t.C:
+			s.l.Lock()
+			s.writeHeaderLocked()
+			_, err := fmt.Fprint(s.w, ": keepalive\n\n")
+			if err == nil {
+				s.flusher.Flush()
+			}
+			s.l.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
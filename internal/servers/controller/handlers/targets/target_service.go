@@ -29,12 +29,14 @@ import (
 	"github.com/mr-tron/base58"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 var (
-	maskManager handlers.MaskManager
+	maskManager    handlers.MaskManager
+	udpMaskManager handlers.MaskManager
 )
 
 func init() {
@@ -42,6 +44,9 @@ func init() {
 	if maskManager, err = handlers.NewMaskManager(&store.TcpTarget{}, &pb.Target{}, &pb.TcpTargetAttributes{}); err != nil {
 		panic(err)
 	}
+	if udpMaskManager, err = handlers.NewMaskManager(&store.UdpTarget{}, &pb.Target{}, &pb.UdpTargetAttributes{}); err != nil {
+		panic(err)
+	}
 }
 
 // Service handles request as described by the pbs.TargetServiceServer interface.
@@ -352,6 +357,19 @@ HostSetIterationLoop:
 		endpointUrl.Host = endpointHost
 	}
 
+	// Draining and disabled workers are not given new sessions
+	var workers []*pb.WorkerInfo
+	servers, err := serversRepo.ListServers(ctx, servers.ServerTypeWorker, servers.WithOperationalState(servers.OperationalStateActive))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range servers {
+		workers = append(workers, &pb.WorkerInfo{Address: v.Address})
+	}
+	if len(workers) == 0 {
+		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Unavailable, "No active workers are available to handle the session.")
+	}
+
 	expTime := timestamppb.Now()
 	expTime.Seconds += int64(t.GetSessionMaxSeconds())
 	sessionComposition := session.ComposedOf{
@@ -364,6 +382,11 @@ HostSetIterationLoop:
 		Endpoint:        endpointUrl.String(),
 		ExpirationTime:  &timestamp.Timestamp{Timestamp: expTime},
 		ConnectionLimit: t.GetSessionConnectionLimit(),
+
+		ConnectionUpstreamBytesPerSecond:   t.GetConnectionUpstreamBytesPerSecond(),
+		ConnectionDownstreamBytesPerSecond: t.GetConnectionDownstreamBytesPerSecond(),
+		SessionUpstreamBytesPerSecond:      t.GetSessionUpstreamBytesPerSecond(),
+		SessionDownstreamBytesPerSecond:    t.GetSessionDownstreamBytesPerSecond(),
 	}
 
 	sess, err := session.New(sessionComposition)
@@ -379,15 +402,6 @@ HostSetIterationLoop:
 		return nil, err
 	}
 
-	var workers []*pb.WorkerInfo
-	servers, err := serversRepo.ListServers(ctx, servers.ServerTypeWorker)
-	if err != nil {
-		return nil, err
-	}
-	for _, v := range servers {
-		workers = append(workers, &pb.WorkerInfo{Address: v.Address})
-	}
-
 	sad := &pb.SessionAuthorizationData{
 		SessionId:       sess.PublicId,
 		TargetId:        t.GetPublicId(),
@@ -449,22 +463,36 @@ func (s Service) createInRepo(ctx context.Context, item *pb.Target) (*pb.Target,
 	if item.GetSessionConnectionLimit() != nil {
 		opts = append(opts, target.WithSessionConnectionLimit(item.GetSessionConnectionLimit().GetValue()))
 	}
-	tcpAttrs := &pb.TcpTargetAttributes{}
-	if err := handlers.StructToProto(item.GetAttributes(), tcpAttrs); err != nil {
-		return nil, handlers.ApiErrorWithCodeAndMessage(codes.InvalidArgument, "Provided attributes don't match expected format.")
-	}
-	if tcpAttrs.GetDefaultPort().GetValue() != 0 {
-		opts = append(opts, target.WithDefaultPort(tcpAttrs.GetDefaultPort().GetValue()))
-	}
-	u, err := target.NewTcpTarget(item.GetScopeId(), opts...)
+	opts = append(opts, bandwidthLimitOptions(item)...)
+	defaultPort, err := defaultPortAttribute(target.SubtypeFromType(item.GetType()), item.GetAttributes())
 	if err != nil {
-		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build target for creation: %v.", err)
+		return nil, err
+	}
+	if defaultPort != 0 {
+		opts = append(opts, target.WithDefaultPort(defaultPort))
 	}
 	repo, err := s.repoFn()
 	if err != nil {
 		return nil, err
 	}
-	out, m, err := repo.CreateTcpTarget(ctx, u)
+	var out target.Target
+	var m []*target.TargetSet
+	switch target.SubtypeFromType(item.GetType()) {
+	case target.UdpSubType:
+		var u *target.UdpTarget
+		u, err = target.NewUdpTarget(item.GetScopeId(), opts...)
+		if err != nil {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build target for creation: %v.", err)
+		}
+		out, m, err = repo.CreateUdpTarget(ctx, u)
+	default:
+		var u *target.TcpTarget
+		u, err = target.NewTcpTarget(item.GetScopeId(), opts...)
+		if err != nil {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build target for creation: %v.", err)
+		}
+		out, m, err = repo.CreateTcpTarget(ctx, u)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to create target: %w", err)
 	}
@@ -488,20 +516,21 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 	if item.GetSessionConnectionLimit() != nil {
 		opts = append(opts, target.WithSessionConnectionLimit(item.GetSessionConnectionLimit().GetValue()))
 	}
-	tcpAttrs := &pb.TcpTargetAttributes{}
-	if err := handlers.StructToProto(item.GetAttributes(), tcpAttrs); err != nil {
-		return nil, handlers.ApiErrorWithCodeAndMessage(codes.InvalidArgument, "Provided attributes don't match expected format.")
+	opts = append(opts, bandwidthLimitOptions(item)...)
+	subtype := target.SubtypeFromId(id)
+	defaultPort, err := defaultPortAttribute(subtype, item.GetAttributes())
+	if err != nil {
+		return nil, err
 	}
-	if tcpAttrs.GetDefaultPort().GetValue() != 0 {
-		opts = append(opts, target.WithDefaultPort(tcpAttrs.GetDefaultPort().GetValue()))
+	if defaultPort != 0 {
+		opts = append(opts, target.WithDefaultPort(defaultPort))
 	}
 	version := item.GetVersion()
-	u, err := target.NewTcpTarget(scopeId, opts...)
-	if err != nil {
-		return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build target for update: %v.", err)
+	mm := maskManager
+	if subtype == target.UdpSubType {
+		mm = udpMaskManager
 	}
-	u.PublicId = id
-	dbMask := maskManager.Translate(mask)
+	dbMask := mm.Translate(mask)
 	if len(dbMask) == 0 {
 		return nil, handlers.InvalidArgumentErrorf("No valid fields included in the update mask.", map[string]string{"update_mask": "No valid paths provided in the update mask."})
 	}
@@ -509,7 +538,27 @@ func (s Service) updateInRepo(ctx context.Context, scopeId, id string, mask []st
 	if err != nil {
 		return nil, err
 	}
-	out, m, rowsUpdated, err := repo.UpdateTcpTarget(ctx, u, version, dbMask)
+	var out target.Target
+	var m []*target.TargetSet
+	var rowsUpdated int
+	switch subtype {
+	case target.UdpSubType:
+		var u *target.UdpTarget
+		u, err = target.NewUdpTarget(scopeId, opts...)
+		if err != nil {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build target for update: %v.", err)
+		}
+		u.PublicId = id
+		out, m, rowsUpdated, err = repo.UpdateUdpTarget(ctx, u, version, dbMask)
+	default:
+		var u *target.TcpTarget
+		u, err = target.NewTcpTarget(scopeId, opts...)
+		if err != nil {
+			return nil, handlers.ApiErrorWithCodeAndMessage(codes.Internal, "Unable to build target for update: %v.", err)
+		}
+		u.PublicId = id
+		out, m, rowsUpdated, err = repo.UpdateTcpTarget(ctx, u, version, dbMask)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to update target: %w", err)
 	}
@@ -662,7 +711,7 @@ func toProto(in target.Target, m []*target.TargetSet) (*pb.Target, error) {
 		CreatedTime:            in.GetCreateTime().GetTimestamp(),
 		UpdatedTime:            in.GetUpdateTime().GetTimestamp(),
 		Version:                in.GetVersion(),
-		Type:                   target.TcpTargetType.String(),
+		Type:                   in.GetType(),
 		SessionMaxSeconds:      wrapperspb.UInt32(in.GetSessionMaxSeconds()),
 		SessionConnectionLimit: wrapperspb.Int32(in.GetSessionConnectionLimit()),
 	}
@@ -672,9 +721,32 @@ func toProto(in target.Target, m []*target.TargetSet) (*pb.Target, error) {
 	if in.GetName() != "" {
 		out.Name = wrapperspb.String(in.GetName())
 	}
-	attrs := &pb.TcpTargetAttributes{}
-	if in.GetDefaultPort() > 0 {
-		attrs.DefaultPort = &wrappers.UInt32Value{Value: in.GetDefaultPort()}
+	if in.GetConnectionUpstreamBytesPerSecond() > 0 {
+		out.ConnectionUpstreamBytesPerSecond = wrapperspb.UInt32(in.GetConnectionUpstreamBytesPerSecond())
+	}
+	if in.GetConnectionDownstreamBytesPerSecond() > 0 {
+		out.ConnectionDownstreamBytesPerSecond = wrapperspb.UInt32(in.GetConnectionDownstreamBytesPerSecond())
+	}
+	if in.GetSessionUpstreamBytesPerSecond() > 0 {
+		out.SessionUpstreamBytesPerSecond = wrapperspb.UInt32(in.GetSessionUpstreamBytesPerSecond())
+	}
+	if in.GetSessionDownstreamBytesPerSecond() > 0 {
+		out.SessionDownstreamBytesPerSecond = wrapperspb.UInt32(in.GetSessionDownstreamBytesPerSecond())
+	}
+	var attrs proto.Message
+	switch in.GetType() {
+	case target.UdpTargetType.String():
+		udpAttrs := &pb.UdpTargetAttributes{}
+		if in.GetDefaultPort() > 0 {
+			udpAttrs.DefaultPort = &wrappers.UInt32Value{Value: in.GetDefaultPort()}
+		}
+		attrs = udpAttrs
+	default:
+		tcpAttrs := &pb.TcpTargetAttributes{}
+		if in.GetDefaultPort() > 0 {
+			tcpAttrs.DefaultPort = &wrappers.UInt32Value{Value: in.GetDefaultPort()}
+		}
+		attrs = tcpAttrs
 	}
 	st, err := handlers.ProtoToStruct(attrs)
 	if err != nil {
@@ -691,13 +763,61 @@ func toProto(in target.Target, m []*target.TargetSet) (*pb.Target, error) {
 	return &out, nil
 }
 
+// bandwidthLimitOptions returns the options setting the bandwidth limits of
+// the item; unset limits are left at their default of no limit.
+func bandwidthLimitOptions(item *pb.Target) []target.Option {
+	var opts []target.Option
+	if item.GetConnectionUpstreamBytesPerSecond() != nil {
+		opts = append(opts, target.WithConnectionUpstreamBytesPerSecond(item.GetConnectionUpstreamBytesPerSecond().GetValue()))
+	}
+	if item.GetConnectionDownstreamBytesPerSecond() != nil {
+		opts = append(opts, target.WithConnectionDownstreamBytesPerSecond(item.GetConnectionDownstreamBytesPerSecond().GetValue()))
+	}
+	if item.GetSessionUpstreamBytesPerSecond() != nil {
+		opts = append(opts, target.WithSessionUpstreamBytesPerSecond(item.GetSessionUpstreamBytesPerSecond().GetValue()))
+	}
+	if item.GetSessionDownstreamBytesPerSecond() != nil {
+		opts = append(opts, target.WithSessionDownstreamBytesPerSecond(item.GetSessionDownstreamBytesPerSecond().GetValue()))
+	}
+	return opts
+}
+
+// defaultPortAttribute returns the default port set in the attributes of a
+// target of the subtype.
+func defaultPortAttribute(subtype target.SubType, attributes *structpb.Struct) (uint32, error) {
+	var attrs interface {
+		proto.Message
+		GetDefaultPort() *wrappers.UInt32Value
+	}
+	switch subtype {
+	case target.UdpSubType:
+		attrs = &pb.UdpTargetAttributes{}
+	default:
+		attrs = &pb.TcpTargetAttributes{}
+	}
+	if err := handlers.StructToProto(attributes, attrs); err != nil {
+		return 0, handlers.ApiErrorWithCodeAndMessage(codes.InvalidArgument, "Provided attributes don't match expected format.")
+	}
+	return attrs.GetDefaultPort().GetValue(), nil
+}
+
+// targetPrefix returns the prefix of the target subtype of id. The tcp target
+// prefix is returned for ids of unknown subtypes so that they fail validation.
+func targetPrefix(id string) string {
+	switch target.SubtypeFromId(id) {
+	case target.UdpSubType:
+		return target.UdpTargetPrefix
+	}
+	return target.TcpTargetPrefix
+}
+
 // A validateX method should exist for each method above.  These methods do not make calls to any backing service but enforce
 // requirements on the structure of the request.  They verify that:
 //  * The path passed in is correctly formatted
 //  * All required parameters are set
 //  * There are no conflicting parameters provided
 func validateGetRequest(req *pbs.GetTargetRequest) error {
-	return handlers.ValidateGetRequest(target.TcpTargetPrefix, req, handlers.NoopValidatorFn)
+	return handlers.ValidateGetRequest(targetPrefix(req.GetId()), req, handlers.NoopValidatorFn)
 }
 
 func validateCreateRequest(req *pbs.CreateTargetRequest) error {
@@ -730,9 +850,17 @@ func validateCreateRequest(req *pbs.CreateTargetRequest) error {
 			if tcpAttrs.GetDefaultPort() != nil && tcpAttrs.GetDefaultPort().GetValue() == 0 {
 				badFields["attributes.default_port"] = "This optional field cannot be set to 0."
 			}
+		case target.UdpSubType:
+			udpAttrs := &pb.UdpTargetAttributes{}
+			if err := handlers.StructToProto(req.GetItem().GetAttributes(), udpAttrs); err != nil {
+				badFields["attributes"] = "Attribute fields do not match the expected format."
+			}
+			if udpAttrs.GetDefaultPort() != nil && udpAttrs.GetDefaultPort().GetValue() == 0 {
+				badFields["attributes.default_port"] = "This optional field cannot be set to 0."
+			}
 		}
 		switch req.GetItem().GetType() {
-		case target.TcpTargetType.String():
+		case target.TcpTargetType.String(), target.UdpTargetType.String():
 		case "":
 			badFields["type"] = "This is a required field."
 		default:
@@ -743,7 +871,7 @@ func validateCreateRequest(req *pbs.CreateTargetRequest) error {
 }
 
 func validateUpdateRequest(req *pbs.UpdateTargetRequest) error {
-	return handlers.ValidateUpdateRequest(target.TcpTargetPrefix, req, req.GetItem(), func() map[string]string {
+	return handlers.ValidateUpdateRequest(targetPrefix(req.GetId()), req, req.GetItem(), func() map[string]string {
 		badFields := map[string]string{}
 		if handlers.MaskContains(req.GetUpdateMask().GetPaths(), "name") && req.GetItem().GetName().GetValue() == "" {
 			badFields["name"] = "This field cannot be set to empty."
@@ -760,7 +888,7 @@ func validateUpdateRequest(req *pbs.UpdateTargetRequest) error {
 		if req.GetItem().GetSessionMaxSeconds() != nil && req.GetItem().GetSessionMaxSeconds().GetValue() == 0 {
 			badFields["session_max_seconds"] = "This must be greater than zero."
 		}
-		switch target.SubtypeFromId(req.GetItem().GetType()) {
+		switch target.SubtypeFromId(req.GetId()) {
 		case target.TcpSubType:
 			if req.GetItem().GetType() != "" && target.SubtypeFromType(req.GetItem().GetType()) != target.TcpSubType {
 				badFields["type"] = "Cannot modify the resource type."
@@ -772,13 +900,24 @@ func validateUpdateRequest(req *pbs.UpdateTargetRequest) error {
 			if tcpAttrs.GetDefaultPort() != nil && tcpAttrs.GetDefaultPort().GetValue() == 0 {
 				badFields["attributes.default_port"] = "This optional field cannot be set to 0."
 			}
+		case target.UdpSubType:
+			if req.GetItem().GetType() != "" && target.SubtypeFromType(req.GetItem().GetType()) != target.UdpSubType {
+				badFields["type"] = "Cannot modify the resource type."
+			}
+			udpAttrs := &pb.UdpTargetAttributes{}
+			if err := handlers.StructToProto(req.GetItem().GetAttributes(), udpAttrs); err != nil {
+				badFields["attributes"] = "Attribute fields do not match the expected format."
+			}
+			if udpAttrs.GetDefaultPort() != nil && udpAttrs.GetDefaultPort().GetValue() == 0 {
+				badFields["attributes.default_port"] = "This optional field cannot be set to 0."
+			}
 		}
 		return badFields
 	})
 }
 
 func validateDeleteRequest(req *pbs.DeleteTargetRequest) error {
-	return handlers.ValidateDeleteRequest(target.TcpTargetPrefix, req, handlers.NoopValidatorFn)
+	return handlers.ValidateDeleteRequest(targetPrefix(req.GetId()), req, handlers.NoopValidatorFn)
 }
 
 func validateListRequest(req *pbs.ListTargetsRequest) error {
@@ -794,7 +933,7 @@ func validateListRequest(req *pbs.ListTargetsRequest) error {
 
 func validateAddRequest(req *pbs.AddTargetHostSetsRequest) error {
 	badFields := map[string]string{}
-	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+	if !handlers.ValidId(targetPrefix(req.GetId()), req.GetId()) {
 		badFields["id"] = "Incorrectly formatted identifier."
 	}
 	if req.GetVersion() == 0 {
@@ -817,7 +956,7 @@ func validateAddRequest(req *pbs.AddTargetHostSetsRequest) error {
 
 func validateSetRequest(req *pbs.SetTargetHostSetsRequest) error {
 	badFields := map[string]string{}
-	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+	if !handlers.ValidId(targetPrefix(req.GetId()), req.GetId()) {
 		badFields["id"] = "Incorrectly formatted identifier."
 	}
 	if req.GetVersion() == 0 {
@@ -837,7 +976,7 @@ func validateSetRequest(req *pbs.SetTargetHostSetsRequest) error {
 
 func validateRemoveRequest(req *pbs.RemoveTargetHostSetsRequest) error {
 	badFields := map[string]string{}
-	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+	if !handlers.ValidId(targetPrefix(req.GetId()), req.GetId()) {
 		badFields["id"] = "Incorrectly formatted identifier."
 	}
 	if req.GetVersion() == 0 {
@@ -860,7 +999,7 @@ func validateRemoveRequest(req *pbs.RemoveTargetHostSetsRequest) error {
 
 func validateAuthorizeSessionRequest(req *pbs.AuthorizeSessionRequest) error {
 	badFields := map[string]string{}
-	if !handlers.ValidId(target.TcpTargetPrefix, req.GetId()) {
+	if !handlers.ValidId(targetPrefix(req.GetId()), req.GetId()) {
 		badFields["id"] = "Incorrectly formatted identifier."
 	}
 	if req.GetHostId() != "" {
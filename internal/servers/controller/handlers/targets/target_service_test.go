@@ -321,6 +321,31 @@ func TestCreate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Create a valid udp target",
+			req: &pbs.CreateTargetRequest{Item: &pb.Target{
+				ScopeId: proj.GetPublicId(),
+				Name:    wrapperspb.String("udp name"),
+				Type:    target.UdpTargetType.String(),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port": structpb.NewNumberValue(53),
+				}},
+			}},
+			res: &pbs.CreateTargetResponse{
+				Uri: fmt.Sprintf("targets/%s_", target.UdpTargetPrefix),
+				Item: &pb.Target{
+					ScopeId: proj.GetPublicId(),
+					Scope:   &scopes.ScopeInfo{Id: proj.GetPublicId(), Type: scope.Project.String()},
+					Name:    wrapperspb.String("udp name"),
+					Type:    target.UdpTargetType.String(),
+					Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+						"default_port": structpb.NewNumberValue(53),
+					}},
+					SessionMaxSeconds:      wrapperspb.UInt32(28800),
+					SessionConnectionLimit: wrapperspb.Int32(1),
+				},
+			},
+		},
 		{
 			name: "Create with default port 0",
 			req: &pbs.CreateTargetRequest{Item: &pb.Target{
@@ -389,7 +414,7 @@ func TestCreate(t *testing.T) {
 			}
 			if got != nil {
 				assert.Contains(got.GetUri(), tc.res.GetUri())
-				assert.True(strings.HasPrefix(got.GetItem().GetId(), target.TcpTargetPrefix), got.GetItem().GetId())
+				assert.True(strings.HasPrefix(got.GetItem().GetId(), strings.TrimPrefix(tc.res.GetUri(), "targets/")), got.GetItem().GetId())
 
 				// Clear all values which are hard to compare against.
 				got.Uri, tc.res.Uri = "", ""
@@ -798,6 +823,74 @@ func TestUpdate_BadVersion(t *testing.T) {
 	assert.True(t, errors.Is(err, handlers.NotFoundError()), "Got %v, wanted not found error.", err)
 }
 
+func TestCreateUpdate_DuplicateName(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	tested, err := testService(t, conn, kms, wrapper)
+	require.NoError(t, err, "Failed to create a new target service.")
+
+	isUniquenessError := func(t *testing.T, err error) {
+		t.Helper()
+		require.Error(t, err)
+		apiErr := handlers.ToApiError(err)
+		assert.Equal(t, codes.InvalidArgument.String(), apiErr.GetCode(), "Got %v, wanted a uniqueness error.", err)
+		assert.Contains(t, apiErr.GetMessage(), "same field value that must be unique")
+	}
+
+	cases := []struct {
+		name       string
+		targetType target.TargetType
+		existing   func(name string) target.Target
+	}{
+		{
+			name:       "tcp",
+			targetType: target.TcpTargetType,
+			existing: func(name string) target.Target {
+				return target.TestTcpTarget(t, conn, proj.GetPublicId(), name)
+			},
+		},
+		{
+			name:       "udp",
+			targetType: target.UdpTargetType,
+			existing: func(name string) target.Target {
+				return target.TestUdpTarget(t, conn, proj.GetPublicId(), name)
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := auth.DisabledAuthTestContext(auth.WithScopeId(proj.GetPublicId()))
+			dupName := tc.name + "-taken"
+			tc.existing(dupName)
+			other := tc.existing(tc.name + "-other")
+
+			_, err := tested.CreateTarget(ctx, &pbs.CreateTargetRequest{Item: &pb.Target{
+				ScopeId: proj.GetPublicId(),
+				Name:    wrapperspb.String(dupName),
+				Type:    tc.targetType.String(),
+				Attributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"default_port": structpb.NewNumberValue(2),
+				}},
+			}})
+			isUniquenessError(t, err)
+
+			_, err = tested.UpdateTarget(ctx, &pbs.UpdateTargetRequest{
+				Id: other.GetPublicId(),
+				Item: &pb.Target{
+					Name:    wrapperspb.String(dupName),
+					Version: other.GetVersion(),
+				},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"name"}},
+			})
+			isUniquenessError(t, err)
+		})
+	}
+}
+
 func TestAddTargetHostSets(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	wrapper := db.TestWrapper(t)
@@ -0,0 +1,231 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/auth"
+	pb "github.com/hashicorp/boundary/internal/gen/controller/api/resources/workers"
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/api/services"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/servers/controller/common"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/action"
+	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Service handles request as described by the pbs.WorkerServiceServer
+// interface. It serves the workers API to clients, unlike the
+// workerServiceServer which serves the workers themselves.
+type Service struct {
+	serversRepoFn common.ServersRepoFactory
+}
+
+// NewService returns a worker service which handles worker related requests to boundary.
+func NewService(serversRepoFn common.ServersRepoFactory) (Service, error) {
+	if serversRepoFn == nil {
+		return Service{}, fmt.Errorf("nil servers repository provided")
+	}
+	return Service{serversRepoFn: serversRepoFn}, nil
+}
+
+var _ pbs.WorkerServiceServer = Service{}
+
+// ListWorkers implements the interface pbs.WorkerServiceServer.
+func (s Service) ListWorkers(ctx context.Context, req *pbs.ListWorkersRequest) (*pbs.ListWorkersResponse, error) {
+	if err := validateListRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, "", action.List)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.serversRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	ws, err := repo.ListServers(ctx, servers.ServerTypeWorker, servers.WithLiveness(-1))
+	if err != nil {
+		return nil, err
+	}
+	tags, err := repo.ListTags(ctx, servers.ServerTypeWorker)
+	if err != nil {
+		return nil, err
+	}
+	var items []*pb.Worker
+	for _, w := range ws {
+		w.Tags = tags[w.GetPrivateId()]
+		item := toProto(w)
+		item.Scope = authResults.Scope
+		items = append(items, item)
+	}
+	return &pbs.ListWorkersResponse{Items: items}, nil
+}
+
+// GetWorker implements the interface pbs.WorkerServiceServer.
+func (s Service) GetWorker(ctx context.Context, req *pbs.GetWorkerRequest) (*pbs.GetWorkerResponse, error) {
+	if err := validateGetRequest(req); err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.Read)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	w, err := s.lookupWorker(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	item := toProto(w)
+	item.Scope = authResults.Scope
+	return &pbs.GetWorkerResponse{Item: item}, nil
+}
+
+// SetWorkerOperationalState implements the interface pbs.WorkerServiceServer.
+func (s Service) SetWorkerOperationalState(ctx context.Context, req *pbs.SetWorkerOperationalStateRequest) (*pbs.SetWorkerOperationalStateResponse, error) {
+	state, err := validateSetOperationalStateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	authResults := s.authResult(ctx, req.GetId(), action.SetOperationalState)
+	if authResults.Error != nil {
+		return nil, authResults.Error
+	}
+	repo, err := s.serversRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	var drainDeadline time.Time
+	if state == servers.OperationalStateDraining && req.GetDrainTimeoutSeconds() > 0 {
+		drainDeadline = time.Now().Add(time.Duration(req.GetDrainTimeoutSeconds()) * time.Second)
+	}
+	w, err := repo.SetOperationalState(ctx, req.GetId(), state, drainDeadline)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set worker operational state: %w", err)
+	}
+	if w == nil {
+		return nil, handlers.NotFoundErrorf("Worker %q not found.", req.GetId())
+	}
+	if w, err = s.lookupWorker(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	item := toProto(w)
+	item.Scope = authResults.Scope
+	return &pbs.SetWorkerOperationalStateResponse{Item: item}, nil
+}
+
+// lookupWorker returns the worker with the ID along with its tags.
+func (s Service) lookupWorker(ctx context.Context, id string) (*servers.Server, error) {
+	repo, err := s.serversRepoFn()
+	if err != nil {
+		return nil, err
+	}
+	w, err := repo.LookupServer(ctx, servers.ServerTypeWorker, id)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, handlers.NotFoundErrorf("Worker %q not found.", id)
+	}
+	tags, err := repo.ListTags(ctx, servers.ServerTypeWorker)
+	if err != nil {
+		return nil, err
+	}
+	w.Tags = tags[w.GetPrivateId()]
+	return w, nil
+}
+
+func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.VerifyResults {
+	res := auth.VerifyResults{}
+	opts := []auth.Option{auth.WithType(resource.Worker), auth.WithAction(a), auth.WithScopeId(scope.Global.String())}
+	if a != action.List {
+		repo, err := s.serversRepoFn()
+		if err != nil {
+			res.Error = err
+			return res
+		}
+		w, err := repo.LookupServer(ctx, servers.ServerTypeWorker, id)
+		if err != nil {
+			res.Error = err
+			return res
+		}
+		if w == nil {
+			res.Error = handlers.NotFoundError()
+			return res
+		}
+		opts = append(opts, auth.WithId(id))
+	}
+	return auth.Verify(ctx, opts...)
+}
+
+func toProto(in *servers.Server) *pb.Worker {
+	out := &pb.Worker{
+		Id:                    in.GetPrivateId(),
+		Name:                  in.GetName(),
+		Description:           in.GetDescription(),
+		Address:               in.GetAddress(),
+		CreatedTime:           in.GetCreateTime().GetTimestamp(),
+		UpdatedTime:           in.GetUpdateTime().GetTimestamp(),
+		OperationalState:      in.GetOperationalState(),
+		ActiveSessionCount:    in.GetActiveSessionCount(),
+		ActiveConnectionCount: in.GetActiveConnectionCount(),
+		ReleaseVersion:        in.GetReleaseVersion(),
+	}
+	if in.GetDrainDeadline() != nil {
+		out.DrainDeadline = in.GetDrainDeadline().GetTimestamp()
+	}
+	if len(in.GetTags()) > 0 {
+		out.Tags = &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+		for _, t := range in.GetTags() {
+			v, ok := out.Tags.Fields[t.GetKey()]
+			if !ok {
+				v = structpb.NewListValue(&structpb.ListValue{})
+				out.Tags.Fields[t.GetKey()] = v
+			}
+			list := v.GetListValue()
+			list.Values = append(list.Values, structpb.NewStringValue(t.GetValue()))
+		}
+	}
+	return out
+}
+
+// A validateX method should exist for each method above.  These methods do not make calls to any backing service but enforce
+// requirements on the structure of the request.  They verify that:
+//  * The path passed in is correctly formatted
+//  * All required parameters are set
+//  * There are no conflicting parameters provided
+func validateGetRequest(req *pbs.GetWorkerRequest) error {
+	if req.GetId() == "" {
+		return handlers.InvalidArgumentErrorf("Invalid fields provided in request.",
+			map[string]string{"id": "This field is required."})
+	}
+	return nil
+}
+
+func validateListRequest(req *pbs.ListWorkersRequest) error {
+	if req.GetScopeId() != "" && req.GetScopeId() != scope.Global.String() {
+		return handlers.InvalidArgumentErrorf("Invalid fields provided in request.",
+			map[string]string{"scope_id": "Workers are only in the global scope."})
+	}
+	return nil
+}
+
+func validateSetOperationalStateRequest(req *pbs.SetWorkerOperationalStateRequest) (servers.OperationalState, error) {
+	badFields := map[string]string{}
+	if req.GetId() == "" {
+		badFields["id"] = "This field is required."
+	}
+	state, err := servers.ParseOperationalState(req.GetOperationalState())
+	if err != nil {
+		badFields["operational_state"] = `This field must be one of "active", "draining" or "disabled".`
+	}
+	if req.GetDrainTimeoutSeconds() > 0 && state != servers.OperationalStateDraining {
+		badFields["drain_timeout_seconds"] = "This field can only be set when draining."
+	}
+	if len(badFields) > 0 {
+		return "", handlers.InvalidArgumentErrorf("Invalid fields provided in request.", badFields)
+	}
+	return state, nil
+}
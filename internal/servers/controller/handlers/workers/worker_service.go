@@ -2,16 +2,22 @@ package workers
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/gen/controller/api/resources/targets"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/common"
 	"github.com/hashicorp/boundary/internal/session"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-kms-wrapping/wrappers/multiwrapper"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -51,13 +57,30 @@ func (ws *workerServiceServer) Status(ctx context.Context, req *pbs.StatusReques
 		return &pbs.StatusResponse{}, status.Errorf(codes.Internal, "Error aqcuiring repo to store worker status: %v", err)
 	}
 	req.Worker.Type = resource.Worker.String()
-	controllers, _, err := repo.UpsertServer(ctx, req.Worker)
+	controllers, _, err := repo.UpsertServer(ctx, req.Worker, servers.WithUpdateTags(req.GetUpdateTags()))
 	if err != nil {
 		ws.logger.Error("error storing worker status", "error", err)
 		return &pbs.StatusResponse{}, status.Errorf(codes.Internal, "Error storing worker status: %v", err)
 	}
+	if requested := req.GetRequestedOperationalState(); requested != "" {
+		state, err := servers.ParseOperationalState(requested)
+		if err != nil {
+			return &pbs.StatusResponse{}, status.Errorf(codes.InvalidArgument, "Invalid requested operational state: %v", err)
+		}
+		if _, err := repo.SetOperationalState(ctx, req.Worker.PrivateId, state, time.Time{}); err != nil {
+			ws.logger.Error("error storing worker operational state", "error", err)
+			return &pbs.StatusResponse{}, status.Errorf(codes.Internal, "Error storing worker operational state: %v", err)
+		}
+		ws.logger.Info("worker changed operational state", "name", req.Worker.Name, "operational_state", state)
+	}
+	worker, err := repo.LookupServer(ctx, servers.ServerTypeWorker, req.Worker.PrivateId)
+	if err != nil {
+		ws.logger.Error("error looking up worker", "error", err)
+		return &pbs.StatusResponse{}, status.Errorf(codes.Internal, "Error looking up worker: %v", err)
+	}
 	ret := &pbs.StatusResponse{
 		Controllers: controllers,
+		Worker:      worker,
 	}
 
 	// Happy path
@@ -160,19 +183,19 @@ func (ws *workerServiceServer) LookupSession(ctx context.Context, req *pbs.Looku
 		HostSetId:       sessionInfo.HostSetId,
 		TargetId:        sessionInfo.TargetId,
 		UserId:          sessionInfo.UserId,
+
+		ConnectionUpstreamBytesPerSecond:   sessionInfo.ConnectionUpstreamBytesPerSecond,
+		ConnectionDownstreamBytesPerSecond: sessionInfo.ConnectionDownstreamBytesPerSecond,
+		SessionUpstreamBytesPerSecond:      sessionInfo.SessionUpstreamBytesPerSecond,
+		SessionDownstreamBytesPerSecond:    sessionInfo.SessionDownstreamBytesPerSecond,
 	}
 	if resp.ConnectionsLeft != -1 {
 		resp.ConnectionsLeft -= int32(authzSummary.CurrentConnectionCount)
 	}
 
-	wrapper, err := ws.kms.GetWrapper(ctx, sessionInfo.ScopeId, kms.KeyPurposeSessions)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Error getting sessions wrapper: %v", err)
-	}
-
 	// Derive the private key, which should match. Deriving on both ends allows
 	// us to not store it in the DB.
-	_, resp.Authorization.PrivateKey, err = session.DeriveED25519Key(wrapper, sessionInfo.UserId, sessionInfo.GetPublicId())
+	resp.Authorization.PrivateKey, err = ws.deriveSessionKey(ctx, sessionInfo)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Error deriving session key: %v", err)
 	}
@@ -180,6 +203,63 @@ func (ws *workerServiceServer) LookupSession(ctx context.Context, req *pbs.Looku
 	return resp, nil
 }
 
+// deriveSessionKey derives the private key of the session's certificate. The
+// key is derived from the session DEK version which was current when the
+// session was created, so if the scope's keys have been rotated since then
+// the previous versions are tried.
+func (ws *workerServiceServer) deriveSessionKey(ctx context.Context, sessionInfo *session.Session) (ed25519.PrivateKey, error) {
+	cert, err := x509.ParseCertificate(sessionInfo.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing session certificate: %w", err)
+	}
+	certKey, ok := cert.PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("session certificate does not hold an ed25519 key")
+	}
+
+	wrapper, err := ws.kms.GetWrapper(ctx, sessionInfo.ScopeId, kms.KeyPurposeSessions)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sessions wrapper: %w", err)
+	}
+	pubKey, privKey, err := session.DeriveED25519Key(wrapper, sessionInfo.UserId, sessionInfo.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+	if pubKey.Equal(certKey) {
+		return privKey, nil
+	}
+
+	versions, err := ws.kms.ListKeyVersionStatus(ctx, sessionInfo.ScopeId)
+	if err != nil {
+		return nil, fmt.Errorf("error listing session key versions: %w", err)
+	}
+	for _, v := range versions {
+		if v.Purpose != kms.KeyPurposeSessions.String() || v.Current {
+			continue
+		}
+		wrapper, err := ws.kms.GetWrapper(ctx, sessionInfo.ScopeId, kms.KeyPurposeSessions, kms.WithKeyId(v.Id))
+		if err != nil {
+			return nil, fmt.Errorf("error getting sessions wrapper: %w", err)
+		}
+		mw, ok := wrapper.(*multiwrapper.MultiWrapper)
+		if !ok {
+			break
+		}
+		versionWrapper := mw.WrapperForKeyID(v.Id)
+		if versionWrapper == nil {
+			continue
+		}
+		pubKey, privKey, err := session.DeriveED25519Key(versionWrapper, sessionInfo.UserId, sessionInfo.GetPublicId())
+		if err != nil {
+			return nil, err
+		}
+		if pubKey.Equal(certKey) {
+			return privKey, nil
+		}
+	}
+	return nil, errors.New("no session key version matches the session certificate")
+}
+
 func (ws *workerServiceServer) ActivateSession(ctx context.Context, req *pbs.ActivateSessionRequest) (*pbs.ActivateSessionResponse, error) {
 	ws.logger.Trace("got activate session request from worker", "session_id", req.GetSessionId())
 
@@ -286,7 +366,7 @@ func (ws *workerServiceServer) ConnectConnection(ctx context.Context, req *pbs.C
 		"client_tcp_port", req.ClientTcpPort,
 	}
 	switch req.GetType() {
-	case "tcp":
+	case "tcp", "udp":
 		loggerPairs = append(loggerPairs,
 			"endpoint_tcp_address", connectionInfo.EndpointTcpAddress,
 			"endpoint_tcp_port", connectionInfo.EndpointTcpPort,
@@ -314,6 +394,9 @@ func (ws *workerServiceServer) CloseConnection(ctx context.Context, req *pbs.Clo
 			BytesUp:      v.GetBytesUp(),
 			BytesDown:    v.GetBytesDown(),
 			ClosedReason: session.ClosedReason(v.GetReason()),
+
+			ThrottledUpMs:   v.GetThrottledUpMs(),
+			ThrottledDownMs: v.GetThrottledDownMs(),
 		})
 	}
 	ws.logger.Trace("got connection close information from worker", "connection_ids", closeIds)
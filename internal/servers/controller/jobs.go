@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/oplog/retention"
+	"github.com/hashicorp/boundary/internal/scheduler"
+)
+
+// In the future we could make these configurable
+const (
+	terminationInterval = 1 * time.Minute
+	keyRewrapInterval   = 5 * time.Minute
+
+	defaultOplogRetentionInterval = 1 * time.Hour
+)
+
+// This is exported so it can be tweaked in tests
+var RecoveryNonceCleanupInterval = 2 * time.Minute
+
+// startScheduler starts running the jobs which coordinate with the other
+// controllers so that each run happens on a single controller.
+func (c *Controller) startScheduler(cancelCtx context.Context) error {
+	s, err := scheduler.New(c.conf.RawConfig.Controller.Name, c.SchedulerRepoFn, c.logger.Named("scheduler"))
+	if err != nil {
+		return err
+	}
+	jobs := []*scheduler.Job{
+		{
+			Name:        "recovery-nonce-cleanup",
+			Description: "Deletes the nonces of recovery tokens which are no longer valid.",
+			Interval:    RecoveryNonceCleanupInterval,
+			Run:         c.cleanupRecoveryNonces,
+		},
+		{
+			Name:        "terminate-completed-sessions",
+			Description: "Terminates the sessions which are expired, canceling or out of connections once all of their connections are closed.",
+			Interval:    terminationInterval,
+			Run:         c.terminateCompletedSessions,
+		},
+		{
+			Name:        "key-rewrap",
+			Description: "Re-encrypts the values and oplog entries encrypted with previous key versions with the current versions.",
+			Interval:    keyRewrapInterval,
+			Run:         c.rewrapKeys,
+		},
+	}
+	if oplog := c.conf.RawConfig.Controller.Oplog; oplog != nil && oplog.ChainSigningIntervalDuration > 0 {
+		jobs = append(jobs, &scheduler.Job{
+			Name:        "oplog-chain-signing",
+			Description: "Signs the heads of the oplog hash chains with the global scope's oplog key.",
+			Interval:    oplog.ChainSigningIntervalDuration,
+			Run:         c.signOplogChainHeads,
+		})
+	}
+	if oplog := c.conf.RawConfig.Controller.Oplog; oplog.RetentionEnabled() {
+		interval := oplog.RetentionIntervalDuration
+		if interval <= 0 {
+			interval = defaultOplogRetentionInterval
+		}
+		policy := retention.Policy{
+			Default:    oplog.RetentionPeriodDuration,
+			Aggregates: oplog.AggregateRetentionPeriodDurations,
+		}
+		jobs = append(jobs, &scheduler.Job{
+			Name:        "oplog-retention",
+			Description: "Archives and deletes the oplog entries past their retention period.",
+			Interval:    interval,
+			// Archiving a backlog of entries can take a while, so a run may
+			// take up to the interval
+			Timeout: interval,
+			Run: func(ctx context.Context) error {
+				return c.pruneOplog(ctx, policy, oplog.ArchivePath)
+			},
+		})
+	}
+	for _, job := range jobs {
+		if err := s.RegisterJob(job); err != nil {
+			return err
+		}
+	}
+	return s.Start(cancelCtx)
+}
+
+func (c *Controller) cleanupRecoveryNonces(ctx context.Context) error {
+	repo, err := c.ServersRepoFn()
+	if err != nil {
+		return fmt.Errorf("error fetching repository for recovery nonce cleanup: %w", err)
+	}
+	nonceCount, err := repo.CleanupNonces(ctx)
+	if err != nil {
+		return fmt.Errorf("error performing recovery nonce cleanup: %w", err)
+	}
+	if nonceCount > 0 {
+		c.logger.Info("recovery nonce cleanup successful", "nonces_cleaned", nonceCount)
+	}
+	return nil
+}
+
+func (c *Controller) terminateCompletedSessions(ctx context.Context) error {
+	repo, err := c.SessionRepoFn()
+	if err != nil {
+		return fmt.Errorf("error fetching repository for terminating completed sessions: %w", err)
+	}
+	terminationCount, err := repo.TerminateCompletedSessions(ctx)
+	if err != nil {
+		return fmt.Errorf("error performing termination of completed sessions: %w", err)
+	}
+	if terminationCount > 0 {
+		c.logger.Info("terminating completed sessions successful", "sessions_terminated", terminationCount)
+	}
+	return nil
+}
+
+func (c *Controller) rewrapKeys(ctx context.Context) error {
+	rewrapCount, err := c.kms.RewrapAll(ctx)
+	if rewrapCount > 0 {
+		c.logger.Info("rewrapping values encrypted with previous key versions successful", "values_rewrapped", rewrapCount)
+	}
+	if err != nil {
+		return fmt.Errorf("error rewrapping values encrypted with previous key versions: %w", err)
+	}
+
+	repo, err := c.OplogRepoFn()
+	if err != nil {
+		return fmt.Errorf("error fetching oplog repository for rewrapping: %w", err)
+	}
+	entryCount, err := repo.RewrapEntries(ctx)
+	if entryCount > 0 {
+		c.logger.Info("rewrapping oplog entries encrypted with previous key versions successful", "entries_rewrapped", entryCount)
+	}
+	if err != nil {
+		return fmt.Errorf("error rewrapping oplog entries encrypted with previous key versions: %w", err)
+	}
+	return nil
+}
+
+func (c *Controller) signOplogChainHeads(ctx context.Context) error {
+	repo, err := c.OplogRepoFn()
+	if err != nil {
+		return fmt.Errorf("error fetching oplog repository for chain signing: %w", err)
+	}
+	signedCount, err := repo.SignChainHeads(ctx)
+	if signedCount > 0 {
+		c.logger.Info("signing oplog chain heads successful", "heads_signed", signedCount)
+	}
+	if err != nil {
+		return fmt.Errorf("error signing oplog chain heads: %w", err)
+	}
+	return nil
+}
+
+func (c *Controller) pruneOplog(ctx context.Context, policy retention.Policy, archivePath string) error {
+	repo, err := c.OplogRetentionFn()
+	if err != nil {
+		return fmt.Errorf("error fetching oplog retention repository: %w", err)
+	}
+	archives, err := repo.Prune(ctx, policy, archivePath)
+	var archived int
+	for _, a := range archives {
+		archived += a.EntryCount
+	}
+	if archived > 0 {
+		c.logger.Info("pruning oplog entries successful", "entries_archived", archived, "archives_written", len(archives))
+	}
+	if err != nil {
+		return fmt.Errorf("error pruning oplog entries: %w", err)
+	}
+	return nil
+}
@@ -6,15 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"net"
-	"net/http"
 	"sync"
-	"time"
 
 	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/boundary/internal/db"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 	"github.com/hashicorp/boundary/internal/libs/alpnmux"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers/workers"
+	"github.com/hashicorp/boundary/internal/tracing"
 	"github.com/hashicorp/go-multierror"
 	"google.golang.org/grpc"
 )
@@ -22,9 +21,17 @@ import (
 func (c *Controller) startListeners() error {
 	servers := make([]func(), 0, len(c.conf.Listeners))
 
+	c.apiListeners = make(map[*base.ServerListener]*apiListener)
+
 	configureForAPI := func(ln *base.ServerListener) error {
+		cors := newCorsSettings(ln.Config)
+		c.apiListeners[ln] = &apiListener{
+			cors:   cors,
+			config: ln.Config,
+		}
 		handler, err := c.handler(HandlerProperties{
 			ListenerConfig: ln.Config,
+			cors:           cors,
 		})
 		if err != nil {
 			return err
@@ -48,59 +55,11 @@ func (c *Controller) startListeners() error {
 
 		// Resolve it here to avoid race conditions if the base context is
 		// replaced
-		cancelCtx := c.baseContext
-
-		server := &http.Server{
-			Handler:           handler,
-			ReadHeaderTimeout: 10 * time.Second,
-			ReadTimeout:       30 * time.Second,
-			IdleTimeout:       5 * time.Minute,
-			ErrorLog:          c.logger.StandardLogger(nil),
-			BaseContext: func(net.Listener) context.Context {
-				return cancelCtx
-			},
-		}
-		ln.HTTPServer = server
-
-		if ln.Config.HTTPReadHeaderTimeout > 0 {
-			server.ReadHeaderTimeout = ln.Config.HTTPReadHeaderTimeout
-		}
-		if ln.Config.HTTPReadTimeout > 0 {
-			server.ReadTimeout = ln.Config.HTTPReadTimeout
-		}
-		if ln.Config.HTTPWriteTimeout > 0 {
-			server.WriteTimeout = ln.Config.HTTPWriteTimeout
-		}
-		if ln.Config.HTTPIdleTimeout > 0 {
-			server.IdleTimeout = ln.Config.HTTPIdleTimeout
-		}
-
-		switch ln.Config.TLSDisable {
-		case true:
-			l, err := ln.Mux.RegisterProto(alpnmux.NoProto, nil)
-			if err != nil {
-				return fmt.Errorf("error getting non-tls listener: %w", err)
-			}
-			if l == nil {
-				return errors.New("could not get non-tls listener")
-			}
-			servers = append(servers, func() {
-				go server.Serve(l)
-			})
-
-		default:
-			protos := []string{"", "http/1.1", "h2"}
-			for _, v := range protos {
-				l := ln.Mux.GetListener(v)
-				if l == nil {
-					return fmt.Errorf("could not get tls proto %q listener", v)
-				}
-				servers = append(servers, func() {
-					go server.Serve(l)
-				})
-			}
+		apiServers, err := base.ConfigureHTTPServer(ln, handler, c.baseContext, c.logger)
+		if err != nil {
+			return err
 		}
-
+		servers = append(servers, apiServers...)
 		return nil
 	}
 
@@ -119,6 +78,10 @@ func (c *Controller) startListeners() error {
 		workerServer := grpc.NewServer(
 			grpc.MaxRecvMsgSize(math.MaxInt32),
 			grpc.MaxSendMsgSize(math.MaxInt32),
+			grpc.ChainUnaryInterceptor(
+				tracing.UnaryServerInterceptor(),
+				readYourWritesInterceptor,
+			),
 		)
 		workerService := workers.NewWorkerServiceServer(c.logger.Named("worker-handler"), c.ServersRepoFn, c.SessionRepoFn, c.workerStatusUpdateTimes, c.kms)
 		pbs.RegisterServerCoordinationServiceServer(workerServer, workerService)
@@ -134,12 +97,23 @@ func (c *Controller) startListeners() error {
 		return nil
 	}
 
+	configureForMetrics := func(ln *base.ServerListener) error {
+		metricsServers, err := base.ConfigureMetricsListener(ln, c.baseContext, c.logger.Named("metrics"))
+		if err != nil {
+			return err
+		}
+		servers = append(servers, metricsServers...)
+		return nil
+	}
+
 	for _, ln := range c.conf.Listeners {
 		var err error
 		for _, purpose := range ln.Config.Purpose {
 			switch purpose {
 			case "api":
 				err = configureForAPI(ln)
+			case "metrics":
+				err = configureForMetrics(ln)
 			case "cluster":
 				if c.clusterAddress != "" {
 					err = errors.New("more than one cluster listener found")
@@ -200,3 +174,9 @@ func (c *Controller) stopListeners(serversOnly bool) error {
 	}
 	return retErr.ErrorOrNil()
 }
+
+// readYourWritesInterceptor makes reads made after a write in a worker's call
+// see the write, even when read replicas are used.
+func readYourWritesInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(db.NewReadYourWritesContext(ctx), req)
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/cmd/base"
+	"github.com/hashicorp/shared-secure-libs/configutil"
+	"github.com/mitchellh/cli"
+)
+
+// apiListener is the reloadable state of an API listener.
+type apiListener struct {
+	cors *corsSettings
+	// config is the listener config last applied, to find which settings
+	// changed in a reloaded config.
+	config *configutil.Listener
+}
+
+// ReloadListener applies the reloadable settings of newConf, the reloaded
+// config of the listener ln. For API listeners these are the CORS settings
+// and the TLS configuration; the listener's protos are registered again with
+// a changed TLS configuration without dropping open connections. Other
+// listeners have no reloadable settings.
+func (c *Controller) ReloadListener(ln *base.ServerListener, newConf *configutil.Listener, ui cli.Ui) error {
+	api, ok := c.apiListeners[ln]
+	if !ok {
+		return nil
+	}
+	api.cors.store(newConf)
+	if base.TLSConfigChanged(api.config, newConf) {
+		listeners, err := ln.ReloadTLS(newConf, ui)
+		if err != nil {
+			return fmt.Errorf("error reloading api listener %s: %w", ln.Config.Address, err)
+		}
+		for _, l := range listeners {
+			go ln.HTTPServer.Serve(l)
+		}
+		c.logger.Info("reloaded api listener tls configuration", "address", ln.Config.Address)
+	}
+	api.config = newConf
+	return nil
+}
@@ -3,3 +3,7 @@ package servers
 func (s *Server) TableName() string {
 	return "server"
 }
+
+func (t *ServerTag) TableName() string {
+	return "server_tag"
+}
@@ -0,0 +1,32 @@
+package servers
+
+import "fmt"
+
+// OperationalState is the state of a worker which decides whether it is given
+// new sessions and connections.
+type OperationalState string
+
+const (
+	// OperationalStateActive workers are given new sessions.
+	OperationalStateActive OperationalState = "active"
+	// OperationalStateDraining workers are not given new sessions, and let the
+	// connections of the sessions they proxy finish, until their drain
+	// deadline if they have one.
+	OperationalStateDraining OperationalState = "draining"
+	// OperationalStateDisabled workers are not given new sessions and proxy no
+	// connections.
+	OperationalStateDisabled OperationalState = "disabled"
+)
+
+func (s OperationalState) String() string {
+	return string(s)
+}
+
+// ParseOperationalState returns the operational state named s.
+func ParseOperationalState(s string) (OperationalState, error) {
+	switch OperationalState(s) {
+	case OperationalStateActive, OperationalStateDraining, OperationalStateDisabled:
+		return OperationalState(s), nil
+	}
+	return "", fmt.Errorf("unknown operational state %q", s)
+}
@@ -16,14 +16,18 @@ type Option func(*options)
 
 // options = how options are represented
 type options struct {
-	withLimit    int
-	withLiveness time.Duration
+	withLimit            int
+	withLiveness         time.Duration
+	withOperationalState OperationalState
+	withUpdateTags       bool
 }
 
 func getDefaultOptions() options {
 	return options{
-		withLimit:    0,
-		withLiveness: 0,
+		withLimit:            0,
+		withLiveness:         0,
+		withOperationalState: "",
+		withUpdateTags:       false,
 	}
 }
 
@@ -36,10 +40,26 @@ func WithLimit(limit int) Option {
 	}
 }
 
-// WithSkipVetForWrite provides an option to allow skipping vet checks to allow
-// testing lower-level SQL triggers and constraints
+// WithLiveness provides an option to only list servers which reported their
+// status within the duration. If negative, all servers are listed.
 func WithLiveness(liveness time.Duration) Option {
 	return func(o *options) {
 		o.withLiveness = liveness
 	}
 }
+
+// WithOperationalState provides an option to only list servers in the
+// operational state.
+func WithOperationalState(state OperationalState) Option {
+	return func(o *options) {
+		o.withOperationalState = state
+	}
+}
+
+// WithUpdateTags provides an option to replace the stored tags of a server
+// with the ones it is upserted with.
+func WithUpdateTags(updateTags bool) Option {
+	return func(o *options) {
+		o.withUpdateTags = updateTags
+	}
+}
@@ -54,20 +54,32 @@ func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms) (*Repository, error)
 }
 
 // list will return a listing of resources and honor the WithLimit option or the
-// repo defaultLimit
+// repo defaultLimit. Only servers which reported their status within the
+// liveness set with WithLiveness are listed, or all of them if it is negative.
+// WithOperationalState only lists the servers in that state.
 func (r *Repository) ListServers(ctx context.Context, serverType ServerType, opt ...Option) ([]*Server, error) {
 	opts := getOpts(opt...)
 	liveness := opts.withLiveness
 	if liveness == 0 {
 		liveness = defaultLiveness
 	}
-	updateTime := time.Now().Add(-1 * liveness)
+	where := "type = $1"
+	args := []interface{}{serverType}
+	if liveness > 0 {
+		updateTime := time.Now().Add(-1 * liveness)
+		args = append(args, updateTime.Format(time.RFC3339))
+		where += fmt.Sprintf(" and update_time > $%d", len(args))
+	}
+	if opts.withOperationalState != "" {
+		args = append(args, opts.withOperationalState.String())
+		where += fmt.Sprintf(" and operational_state = $%d", len(args))
+	}
 	var servers []*Server
 	if err := r.reader.SearchWhere(
 		ctx,
 		&servers,
-		"type = $1 and update_time > $2",
-		[]interface{}{serverType, updateTime.Format(time.RFC3339)},
+		where,
+		args,
 		db.WithLimit(-1),
 	); err != nil {
 		return nil, fmt.Errorf("error listing servers: %w", err)
@@ -75,34 +87,67 @@ func (r *Repository) ListServers(ctx context.Context, serverType ServerType, opt
 	return servers, nil
 }
 
-// UpsertServer adds or updates a server in the DB
+// UpsertServer adds or updates a server in the DB. Supports the option
+// WithUpdateTags, which replaces the stored tags of the server with its Tags.
 func (r *Repository) UpsertServer(ctx context.Context, server *Server, opt ...Option) ([]*Server, int, error) {
 	if server == nil {
 		return nil, db.NoRowsAffected, errors.New("cannot update server that is nil")
 	}
+	opts := getOpts(opt...)
 	// Ensure, for now at least, the private ID is always equivalent to the name
 	server.PrivateId = server.Name
 	// Build query
 	q := `
 	insert into server
-		(private_id, type, name, description, address, update_time)
+		(private_id, type, name, description, address, update_time, active_session_count, active_connection_count, release_version)
 	values
-		($1, $2, $3, $4, $5, $6)
+		($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	on conflict on constraint server_pkey
 	do update set
 		name = $3,
 		description = $4,
 		address = $5,
-		update_time = $6;
+		update_time = $6,
+		active_session_count = $7,
+		active_connection_count = $8,
+		release_version = $9;
 	`
+	args := []interface{}{server.PrivateId,
+		server.Type,
+		server.Name,
+		server.Description,
+		server.Address,
+		time.Now().Format(time.RFC3339),
+		server.ActiveSessionCount,
+		server.ActiveConnectionCount,
+		server.ReleaseVersion}
 
-	rowsAffected, err := r.writer.Exec(ctx, q,
-		[]interface{}{server.PrivateId,
-			server.Type,
-			server.Name,
-			server.Description,
-			server.Address,
-			time.Now().Format(time.RFC3339)})
+	var rowsAffected int
+	var err error
+	if !opts.withUpdateTags {
+		rowsAffected, err = r.writer.Exec(ctx, q, args)
+	} else {
+		_, err = r.writer.DoTx(
+			ctx,
+			db.StdRetryCnt,
+			db.ExpBackoff{},
+			func(_ db.Reader, w db.Writer) error {
+				var err error
+				if rowsAffected, err = w.Exec(ctx, q, args); err != nil {
+					return err
+				}
+				if _, err := w.Exec(ctx, deleteTagsSql, []interface{}{server.PrivateId, server.Type}); err != nil {
+					return fmt.Errorf("error deleting tags: %w", err)
+				}
+				for _, t := range server.Tags {
+					if _, err := w.Exec(ctx, insertTagSql, []interface{}{server.PrivateId, server.Type, t.GetKey(), t.GetValue()}); err != nil {
+						return fmt.Errorf("error inserting tag: %w", err)
+					}
+				}
+				return nil
+			},
+		)
+	}
 	if err != nil {
 		return nil, db.NoRowsAffected, fmt.Errorf("error performing status upsert: %w", err)
 	}
@@ -115,6 +160,75 @@ func (r *Repository) UpsertServer(ctx context.Context, server *Server, opt ...Op
 	return controllers, len(controllers), err
 }
 
+// LookupServer returns the server of the type with the private ID, or nil if
+// there is none.
+func (r *Repository) LookupServer(ctx context.Context, serverType ServerType, privateId string, opt ...Option) (*Server, error) {
+	if privateId == "" {
+		return nil, errors.New("missing server private id")
+	}
+	var servers []*Server
+	if err := r.reader.SearchWhere(
+		ctx,
+		&servers,
+		"type = $1 and private_id = $2",
+		[]interface{}{serverType, privateId},
+		db.WithLimit(1),
+	); err != nil {
+		return nil, fmt.Errorf("error looking up server: %w", err)
+	}
+	if len(servers) == 0 {
+		return nil, nil
+	}
+	return servers[0], nil
+}
+
+// SetOperationalState changes the operational state of the worker with the
+// private ID and returns it, or nil if there is no such worker. A zero
+// drainDeadline means that a draining worker waits for its connections to
+// finish however long they take; it is ignored for the other states.
+func (r *Repository) SetOperationalState(ctx context.Context, privateId string, state OperationalState, drainDeadline time.Time, opt ...Option) (*Server, error) {
+	if privateId == "" {
+		return nil, errors.New("missing server private id")
+	}
+	if _, err := ParseOperationalState(state.String()); err != nil {
+		return nil, err
+	}
+	var deadline interface{}
+	if state == OperationalStateDraining && !drainDeadline.IsZero() {
+		deadline = drainDeadline.Format(time.RFC3339)
+	}
+	rowsAffected, err := r.writer.Exec(ctx,
+		"update server set operational_state = $1, drain_deadline = $2 where type = $3 and private_id = $4",
+		[]interface{}{state.String(), deadline, ServerTypeWorker, privateId})
+	if err != nil {
+		return nil, fmt.Errorf("error setting server operational state: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, nil
+	}
+	return r.LookupServer(ctx, ServerTypeWorker, privateId)
+}
+
+// ListTags returns the tags of the servers of the type, by server private ID.
+func (r *Repository) ListTags(ctx context.Context, serverType ServerType, opt ...Option) (map[string][]*ServerTag, error) {
+	var tags []*ServerTag
+	if err := r.reader.SearchWhere(
+		ctx,
+		&tags,
+		"server_type = $1",
+		[]interface{}{serverType},
+		db.WithLimit(-1),
+		db.WithOrder("server_id, key, value"),
+	); err != nil {
+		return nil, fmt.Errorf("error listing server tags: %w", err)
+	}
+	ret := make(map[string][]*ServerTag)
+	for _, t := range tags {
+		ret[t.ServerId] = append(ret[t.ServerId], t)
+	}
+	return ret, nil
+}
+
 type RecoveryNonce struct {
 	Nonce string
 }
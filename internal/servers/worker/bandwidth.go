@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/metrics"
+	ua "go.uber.org/atomic"
+	"golang.org/x/time/rate"
+)
+
+// throttleChunkSize is the most data passed on at once by a throttled reader,
+// so that limited traffic flows smoothly rather than in large bursts.
+const throttleChunkSize = 32 * 1024
+
+// bandwidthLimiters are the token buckets limiting the upstream and downstream
+// traffic of a connection, a session or the worker. A nil limiter does not
+// limit.
+type bandwidthLimiters struct {
+	upstream   *rate.Limiter
+	downstream *rate.Limiter
+}
+
+// newBandwidthLimiters returns limiters for the limits in bytes per second,
+// where 0 means no limit.
+func newBandwidthLimiters(upstream, downstream uint64) *bandwidthLimiters {
+	return &bandwidthLimiters{
+		upstream:   newBandwidthLimiter(upstream),
+		downstream: newBandwidthLimiter(downstream),
+	}
+}
+
+// newBandwidthLimiter returns a limiter for the limit in bytes per second, or
+// nil if it is 0.
+func newBandwidthLimiter(bytesPerSecond uint64) *rate.Limiter {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+	l := rate.NewLimiter(rate.Inf, 0)
+	setBandwidthLimit(l, bytesPerSecond)
+	return l
+}
+
+// setBandwidthLimit changes the limit of the limiter to bytesPerSecond, where
+// 0 means no limit. The bucket holds a tenth of a second of traffic, which is
+// the most that is let through at once after the traffic was idle.
+func setBandwidthLimit(l *rate.Limiter, bytesPerSecond uint64) {
+	if bytesPerSecond == 0 {
+		l.SetLimit(rate.Inf)
+		return
+	}
+	burst := bytesPerSecond / 10
+	if burst == 0 {
+		burst = 1
+	}
+	l.SetLimit(rate.Limit(bytesPerSecond))
+	l.SetBurst(int(burst))
+}
+
+// ReloadBandwidth changes the limits of the traffic of all connections the
+// worker proxies; nil removes them.
+func (w *Worker) ReloadBandwidth(bandwidth *config.WorkerBandwidth) {
+	var upstream, downstream int64
+	if bandwidth != nil {
+		upstream, downstream = bandwidth.UpstreamBytesPerSecond, bandwidth.DownstreamBytesPerSecond
+	}
+	setBandwidthLimit(w.bandwidth.upstream, uint64(upstream))
+	setBandwidthLimit(w.bandwidth.downstream, uint64(downstream))
+	w.logger.Info("reloaded bandwidth limits", "upstream_bytes_per_second", upstream, "downstream_bytes_per_second", downstream)
+}
+
+// connectionThrottles returns the throttles of the upstream and downstream
+// traffic of a new connection of the session, which keep to the limits of the
+// connection, of all connections of the session and of the worker.
+func (w *Worker) connectionThrottles(si *sessionInfo) (upstream, downstream *throttle) {
+	si.Lock()
+	resp := si.lookupSessionResponse
+	if si.bandwidth == nil {
+		si.bandwidth = newBandwidthLimiters(uint64(resp.GetSessionUpstreamBytesPerSecond()), uint64(resp.GetSessionDownstreamBytesPerSecond()))
+	}
+	session := si.bandwidth
+	si.Unlock()
+	conn := newBandwidthLimiters(uint64(resp.GetConnectionUpstreamBytesPerSecond()), uint64(resp.GetConnectionDownstreamBytesPerSecond()))
+
+	upstream = newThrottle(metrics.DirectionUpstream, conn.upstream, session.upstream, w.bandwidth.upstream)
+	downstream = newThrottle(metrics.DirectionDownstream, conn.downstream, session.downstream, w.bandwidth.downstream)
+	return upstream, downstream
+}
+
+// throttle holds back one direction of the traffic of a connection to keep to
+// the limits of all its limiters, and keeps track of how long it held it back.
+type throttle struct {
+	direction string
+	limiters  []*rate.Limiter
+	throttled ua.Int64
+}
+
+// newThrottle returns a throttle keeping to the limits of the non-nil
+// limiters.
+func newThrottle(direction string, limiters ...*rate.Limiter) *throttle {
+	t := &throttle{direction: direction}
+	for _, l := range limiters {
+		if l != nil {
+			t.limiters = append(t.limiters, l)
+		}
+	}
+	return t
+}
+
+// chunkSize returns the most data to pass on at once, which is at most what
+// each limiter lets through at once.
+func (t *throttle) chunkSize() int {
+	size := throttleChunkSize
+	for _, l := range t.limiters {
+		if l.Limit() != rate.Inf && l.Burst() < size {
+			size = l.Burst()
+		}
+	}
+	return size
+}
+
+// wait waits until n bytes may be passed on. Data larger than what a limiter
+// lets through at once is accounted for in multiple parts.
+func (t *throttle) wait(ctx context.Context, n int) error {
+	now := time.Now()
+	var delay time.Duration
+	var reservations []*rate.Reservation
+	for _, l := range t.limiters {
+		if l.Limit() == rate.Inf {
+			continue
+		}
+		for left := n; left > 0; {
+			part := left
+			if burst := l.Burst(); burst > 0 && part > burst {
+				part = burst
+			}
+			r := l.ReserveN(now, part)
+			if !r.OK() {
+				// The limit changed meanwhile; let the data through rather
+				// than failing the connection
+				break
+			}
+			reservations = append(reservations, r)
+			if d := r.DelayFrom(now); d > delay {
+				delay = d
+			}
+			left -= part
+		}
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		t.record(delay)
+		return nil
+	case <-ctx.Done():
+		for _, r := range reservations {
+			r.Cancel()
+		}
+		t.record(time.Since(now))
+		return ctx.Err()
+	}
+}
+
+func (t *throttle) record(d time.Duration) {
+	t.throttled.Add(int64(d))
+	metrics.AddProxyThrottled(t.direction, d)
+}
+
+// throttledTime returns how long the traffic was held back for.
+func (t *throttle) throttledTime() time.Duration {
+	return time.Duration(t.throttled.Load())
+}
+
+// reader returns a reader passing on the data read from r as fast as the
+// throttle allows, until ctx is done.
+func (t *throttle) reader(ctx context.Context, r io.Reader) io.Reader {
+	return &throttledReader{ctx: ctx, r: r, t: t}
+}
+
+type throttledReader struct {
+	ctx context.Context
+	r   io.Reader
+	t   *throttle
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if size := r.t.chunkSize(); len(p) > size {
+		p = p[:size]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.t.wait(r.ctx, n); werr != nil {
+			return 0, werr
+		}
+	}
+	return n, err
+}
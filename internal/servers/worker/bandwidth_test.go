@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestThrottleReader(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	// The slowest limiter sets the rate
+	fast, slow := newBandwidthLimiter(1000*1000), newBandwidthLimiter(20*1000)
+	th := newThrottle("upstream", fast, nil, slow)
+	assert.Equal(2000, th.chunkSize())
+
+	data := make([]byte, 10*1000)
+	start := time.Now()
+	got, err := ioutil.ReadAll(th.reader(context.Background(), bytes.NewReader(data)))
+	elapsed := time.Since(start)
+	require.NoError(err)
+	assert.Equal(data, got)
+
+	// The first 2000 bytes pass right away and the rest at 20000 per second
+	assert.True(elapsed >= 350*time.Millisecond, "took %s", elapsed)
+	assert.True(elapsed < 2*time.Second, "took %s", elapsed)
+	assert.True(th.throttledTime() >= 350*time.Millisecond, "throttled %s", th.throttledTime())
+}
+
+func TestThrottleWait(t *testing.T) {
+	assert := assert.New(t)
+
+	// Without limits nothing is held back
+	th := newThrottle("downstream", rate.NewLimiter(rate.Inf, 0))
+	assert.Equal(throttleChunkSize, th.chunkSize())
+	assert.NoError(th.wait(context.Background(), 1000*1000))
+	assert.Zero(th.throttledTime())
+
+	// Datagrams larger than the bucket are let through, taking as long as
+	// their size requires
+	th = newThrottle("downstream", newBandwidthLimiter(10*1000))
+	start := time.Now()
+	assert.NoError(th.wait(context.Background(), 3000))
+	assert.True(time.Since(start) >= 150*time.Millisecond, "took %s", time.Since(start))
+
+	// Waiting ends when the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(th.wait(ctx, 100*1000))
+}
+
+func TestReloadBandwidth(t *testing.T) {
+	assert := assert.New(t)
+	w := &Worker{
+		logger: hclog.NewNullLogger(),
+		bandwidth: &bandwidthLimiters{
+			upstream:   rate.NewLimiter(rate.Inf, 0),
+			downstream: rate.NewLimiter(rate.Inf, 0),
+		},
+	}
+	w.ReloadBandwidth(&config.WorkerBandwidth{UpstreamBytesPerSecond: 5000})
+	assert.Equal(rate.Limit(5000), w.bandwidth.upstream.Limit())
+	assert.Equal(500, w.bandwidth.upstream.Burst())
+	assert.Equal(rate.Inf, w.bandwidth.downstream.Limit())
+
+	w.ReloadBandwidth(nil)
+	assert.Equal(rate.Inf, w.bandwidth.upstream.Limit())
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/tracing"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/resolver"
@@ -26,17 +27,9 @@ import (
 )
 
 func (w *Worker) startControllerConnections() error {
-	initialAddrs := make([]resolver.Address, 0, len(w.conf.RawConfig.Worker.Controllers))
-	for _, addr := range w.conf.RawConfig.Worker.Controllers {
-		host, port, err := net.SplitHostPort(addr)
-		if err != nil && strings.Contains(err.Error(), "missing port in address") {
-			w.logger.Trace("missing port in controller address, using port 9201", "address", addr)
-			host, port, err = net.SplitHostPort(fmt.Sprintf("%s:%s", addr, "9201"))
-		}
-		if err != nil {
-			return fmt.Errorf("error parsing controller address: %w", err)
-		}
-		initialAddrs = append(initialAddrs, resolver.Address{Addr: fmt.Sprintf("%s:%s", host, port)})
+	initialAddrs, err := w.controllerAddresses(w.conf.RawConfig.Worker.Controllers)
+	if err != nil {
+		return err
 	}
 
 	if len(initialAddrs) == 0 {
@@ -53,6 +46,41 @@ func (w *Worker) startControllerConnections() error {
 	return nil
 }
 
+// ReloadControllers replaces the controller addresses the worker connects to
+// with the ones given, e.g. when the worker's controllers list is changed in a
+// reloaded config. The worker still replaces them with the controllers
+// reported by the controller in its next status update.
+func (w *Worker) ReloadControllers(controllers []string) error {
+	addrs, err := w.controllerAddresses(controllers)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return errors.New("no controller addresses found")
+	}
+	w.Resolver().UpdateState(resolver.State{Addresses: addrs})
+	w.logger.Info("reloaded controller addresses", "addrs", controllers)
+	return nil
+}
+
+// controllerAddresses parses the controller addresses, using port 9201 for
+// the addresses without a port.
+func (w *Worker) controllerAddresses(controllers []string) ([]resolver.Address, error) {
+	addrs := make([]resolver.Address, 0, len(controllers))
+	for _, addr := range controllers {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil && strings.Contains(err.Error(), "missing port in address") {
+			w.logger.Trace("missing port in controller address, using port 9201", "address", addr)
+			host, port, err = net.SplitHostPort(fmt.Sprintf("%s:%s", addr, "9201"))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing controller address: %w", err)
+		}
+		addrs = append(addrs, resolver.Address{Addr: fmt.Sprintf("%s:%s", host, port)})
+	}
+	return addrs, nil
+}
+
 func (w Worker) controllerDialerFunc() func(context.Context, string) (net.Conn, error) {
 	return func(ctx context.Context, addr string) (net.Conn, error) {
 		tlsConf, authInfo, err := w.workerAuthTLSConfig()
@@ -102,6 +130,7 @@ func (w *Worker) createClientConn(addr string) error {
 		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(math.MaxInt32)),
 		grpc.WithContextDialer(w.controllerDialerFunc()),
 		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(tracing.UnaryClientInterceptor()),
 		grpc.WithDefaultServiceConfig(defServiceConfig),
 		// Don't have the resolver reach out for a service config from the
 		// resolver, use the one specified as default
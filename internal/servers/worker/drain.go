@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/session"
+)
+
+var (
+	errWorkerDraining = errors.New("worker is draining")
+	errWorkerDisabled = errors.New("worker is disabled")
+)
+
+// operationalState is the operational state of the worker, along with the
+// time a drain must be finished by, if any.
+type operationalState struct {
+	state         servers.OperationalState
+	drainDeadline time.Time
+}
+
+// OperationalState returns the operational state of the worker and the time a
+// drain must be finished by, which is zero if there is none.
+func (w *Worker) OperationalState() (servers.OperationalState, time.Time) {
+	s := w.operationalState.Load().(*operationalState)
+	return s.state, s.drainDeadline
+}
+
+// RequestOperationalState changes the operational state of the worker without
+// a drain deadline, as when the worker is signaled. The state is sent to the
+// controller with each status until the controller returns the worker in that
+// state.
+func (w *Worker) RequestOperationalState(state servers.OperationalState) {
+	w.requestedOperationalState.Store(state)
+	w.setOperationalState(state, time.Time{})
+}
+
+func (w *Worker) setOperationalState(state servers.OperationalState, drainDeadline time.Time) {
+	prev := w.operationalState.Load().(*operationalState)
+	if prev.state == state && prev.drainDeadline.Equal(drainDeadline) {
+		return
+	}
+	w.operationalState.Store(&operationalState{state: state, drainDeadline: drainDeadline})
+	if drainDeadline.IsZero() {
+		w.logger.Info("operational state changed", "operational_state", state)
+		return
+	}
+	w.logger.Info("operational state changed", "operational_state", state, "drain_deadline", drainDeadline)
+}
+
+// applyOperationalState changes the operational state of the worker to the
+// one of the worker returned by the controller, unless the worker was
+// requested to change to another state which the controller does not know
+// about yet.
+func (w *Worker) applyOperationalState(worker *servers.Server) {
+	if worker.GetOperationalState() == "" {
+		return
+	}
+	state, err := servers.ParseOperationalState(worker.GetOperationalState())
+	if err != nil {
+		w.logger.Warn("got invalid operational state from controller", "error", err)
+		return
+	}
+	if requested := w.requestedOperationalState.Load().(servers.OperationalState); requested != "" {
+		if requested != state {
+			return
+		}
+		w.requestedOperationalState.Store(servers.OperationalState(""))
+	}
+	var drainDeadline time.Time
+	if worker.GetDrainDeadline() != nil {
+		drainDeadline = worker.GetDrainDeadline().GetTimestamp().AsTime()
+	}
+	w.setOperationalState(state, drainDeadline)
+}
+
+// checkOperationalState returns an error if the worker does not accept new
+// connections of the session in its operational state: a draining worker only
+// accepts those of sessions it already proxied connections of, and a disabled
+// worker none.
+func (w *Worker) checkOperationalState(si *sessionInfo) error {
+	state, _ := w.OperationalState()
+	switch state {
+	case servers.OperationalStateDraining:
+		si.RLock()
+		known := len(si.connInfoMap) > 0
+		si.RUnlock()
+		if !known {
+			return errWorkerDraining
+		}
+	case servers.OperationalStateDisabled:
+		return errWorkerDisabled
+	}
+	return nil
+}
+
+// closeDrainedConnections cancels the open connections of a disabled worker,
+// or of a draining worker whose drain deadline has passed, and adds them to
+// closeInfo to be marked closed with the worker drained reason.
+func (w *Worker) closeDrainedConnections(closeInfo map[string]string) {
+	state, drainDeadline := w.OperationalState()
+	switch {
+	case state == servers.OperationalStateDisabled:
+	case state == servers.OperationalStateDraining && !drainDeadline.IsZero() && time.Now().After(drainDeadline):
+	default:
+		return
+	}
+	w.sessionInfoMap.Range(func(key, value interface{}) bool {
+		si := value.(*sessionInfo)
+		si.Lock()
+		for k, v := range si.connInfoMap {
+			if v.closeTime.IsZero() && v.connCancel != nil {
+				v.closeReason = session.ConnectionWorkerDrained
+				v.connCancel()
+				w.logger.Info("terminated connection due to drain", "session_id", si.id, "connection_id", k, "operational_state", state)
+				closeInfo[k] = si.id
+			}
+		}
+		si.Unlock()
+		return true
+	})
+}
+
+// reportDrainProgress logs the number of open connections of a draining
+// worker whenever it changes.
+func (w *Worker) reportDrainProgress(activeConnections int) {
+	state, _ := w.OperationalState()
+	if state != servers.OperationalStateDraining {
+		w.drainConnectionsLeft.Store(-1)
+		return
+	}
+	if w.drainConnectionsLeft.Swap(int64(activeConnections)) == int64(activeConnections) {
+		return
+	}
+	if activeConnections == 0 {
+		w.logger.Info("worker drained")
+		return
+	}
+	w.logger.Info("worker draining", "connections_left", activeConnections)
+}
@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/servers"
+	"github.com/hashicorp/boundary/internal/session"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testDrainWorker() *Worker {
+	w := &Worker{
+		logger:                    hclog.NewNullLogger(),
+		sessionInfoMap:            new(sync.Map),
+		operationalState:          new(atomic.Value),
+		requestedOperationalState: new(atomic.Value),
+	}
+	w.operationalState.Store(&operationalState{state: servers.OperationalStateActive})
+	w.requestedOperationalState.Store(servers.OperationalState(""))
+	return w
+}
+
+func TestApplyOperationalState(t *testing.T) {
+	assert := assert.New(t)
+	w := testDrainWorker()
+
+	deadline := time.Now().Add(time.Hour).Truncate(time.Second)
+	w.applyOperationalState(&servers.Server{
+		OperationalState: servers.OperationalStateDraining.String(),
+		DrainDeadline:    &timestamp.Timestamp{Timestamp: timestamppb.New(deadline)},
+	})
+	state, drainDeadline := w.OperationalState()
+	assert.Equal(servers.OperationalStateDraining, state)
+	assert.True(deadline.Equal(drainDeadline))
+
+	// Controllers not knowing about operational states change nothing
+	w.applyOperationalState(&servers.Server{})
+	state, _ = w.OperationalState()
+	assert.Equal(servers.OperationalStateDraining, state)
+
+	// A requested state takes precedence until the controller returns it
+	w.RequestOperationalState(servers.OperationalStateDisabled)
+	w.applyOperationalState(&servers.Server{OperationalState: servers.OperationalStateActive.String()})
+	state, drainDeadline = w.OperationalState()
+	assert.Equal(servers.OperationalStateDisabled, state)
+	assert.True(drainDeadline.IsZero())
+	w.applyOperationalState(&servers.Server{OperationalState: servers.OperationalStateDisabled.String()})
+	assert.Equal(servers.OperationalState(""), w.requestedOperationalState.Load())
+	w.applyOperationalState(&servers.Server{OperationalState: servers.OperationalStateActive.String()})
+	state, _ = w.OperationalState()
+	assert.Equal(servers.OperationalStateActive, state)
+}
+
+func TestCheckOperationalState(t *testing.T) {
+	assert := assert.New(t)
+	w := testDrainWorker()
+	known := &sessionInfo{connInfoMap: map[string]*connInfo{"sc_1": {id: "sc_1"}}}
+	unknown := &sessionInfo{connInfoMap: map[string]*connInfo{}}
+
+	assert.NoError(w.checkOperationalState(known))
+	assert.NoError(w.checkOperationalState(unknown))
+
+	w.setOperationalState(servers.OperationalStateDraining, time.Time{})
+	assert.NoError(w.checkOperationalState(known))
+	assert.Equal(errWorkerDraining, w.checkOperationalState(unknown))
+
+	w.setOperationalState(servers.OperationalStateDisabled, time.Time{})
+	assert.Equal(errWorkerDisabled, w.checkOperationalState(known))
+	assert.Equal(errWorkerDisabled, w.checkOperationalState(unknown))
+}
+
+func TestCloseDrainedConnections(t *testing.T) {
+	assert := assert.New(t)
+	w := testDrainWorker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	connCtx, connCancel := context.WithCancel(ctx)
+	ci := &connInfo{id: "sc_1", connCtx: connCtx, connCancel: connCancel}
+	w.sessionInfoMap.Store("s_1", &sessionInfo{id: "s_1", connInfoMap: map[string]*connInfo{ci.id: ci}})
+
+	// Draining without a deadline, or before it, lets connections run
+	closeInfo := make(map[string]string)
+	w.setOperationalState(servers.OperationalStateDraining, time.Time{})
+	w.closeDrainedConnections(closeInfo)
+	w.setOperationalState(servers.OperationalStateDraining, time.Now().Add(time.Hour))
+	w.closeDrainedConnections(closeInfo)
+	assert.Empty(closeInfo)
+	assert.NoError(connCtx.Err())
+
+	w.setOperationalState(servers.OperationalStateDraining, time.Now().Add(-time.Second))
+	w.closeDrainedConnections(closeInfo)
+	assert.Equal(map[string]string{"sc_1": "s_1"}, closeInfo)
+	assert.Error(connCtx.Err())
+	assert.Equal(session.ConnectionWorkerDrained, ci.closeReason)
+}
@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/session"
+)
+
+// errEgressDenied is returned for endpoints the worker's egress configuration
+// does not allow connecting to.
+var errEgressDenied = errors.New("endpoint not allowed by egress configuration")
+
+// ReloadEgress replaces the egress configuration restricting the endpoints
+// the worker connects to; nil allows any endpoint.
+func (w *Worker) ReloadEgress(egress *config.WorkerEgress) {
+	w.egress.Store(egress)
+	w.logger.Info("reloaded egress configuration")
+}
+
+// egressAddress returns the address to dial for the host and port of an
+// endpoint if the egress configuration allows it, or an error wrapping
+// errEgressDenied if it does not. A host name that is only allowed because
+// of the addresses it resolves to is resolved once and the checked address
+// is returned, so that it cannot resolve to another one when dialed.
+func (w *Worker) egressAddress(ctx context.Context, hostPort string) (string, error) {
+	egress := w.egress.Load().(*config.WorkerEgress)
+	if egress == nil {
+		return hostPort, nil
+	}
+	host, portStr, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return "", fmt.Errorf("error parsing endpoint address: %w", err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", fmt.Errorf("error parsing endpoint port: %w", err)
+	}
+	if !egress.AllowsPort(uint16(port)) {
+		return "", fmt.Errorf("%w: port %d", errEgressDenied, port)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if !egress.AllowsIp(ip) {
+			return "", fmt.Errorf("%w: address %s", errEgressDenied, ip)
+		}
+		return hostPort, nil
+	}
+	if egress.AllowsDnsName(host) {
+		return hostPort, nil
+	}
+	if len(egress.AllowedCidrNets) == 0 {
+		return "", fmt.Errorf("%w: host %s", errEgressDenied, host)
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", fmt.Errorf("error resolving endpoint: %w", err)
+	}
+	for _, addr := range addrs {
+		if !egress.AllowsIp(addr.IP) {
+			return "", fmt.Errorf("%w: host %s resolves to %s", errEgressDenied, host, addr.IP)
+		}
+	}
+	return net.JoinHostPort(addrs[0].IP.String(), portStr), nil
+}
+
+// checkEgress returns the address to dial for the host and port of the
+// connection's endpoint. If the egress configuration does not allow the
+// endpoint, the connection is recorded as closed for it and the returned
+// error is the reason to give the client.
+func (w *Worker) checkEgress(ctx context.Context, si *sessionInfo, connectionId, hostPort string) (string, error) {
+	addr, err := w.egressAddress(ctx, hostPort)
+	switch {
+	case err == nil:
+		return addr, nil
+	case errors.Is(err, errEgressDenied):
+		w.logger.Warn("refusing to connect to endpoint", "error", err, "connection_id", connectionId)
+		si.Lock()
+		if ci, ok := si.connInfoMap[connectionId]; ok {
+			ci.closeReason = session.ConnectionEgressDenied
+		}
+		si.Unlock()
+		return "", errors.New("endpoint denied by egress configuration")
+	default:
+		w.logger.Error("error checking endpoint egress", "error", err, "connection_id", connectionId)
+		return "", errors.New("endpoint resolution failed")
+	}
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressAddress(t *testing.T) {
+	conf, err := config.Parse(`
+worker {
+	egress {
+		allowed_cidrs = ["127.0.0.0/8", "::1/128", "10.0.0.0/8"]
+		allowed_ports = ["22", "8000-8100"]
+		allowed_dns_suffixes = ["internal.example.com"]
+	}
+}
+`)
+	require.NoError(t, err)
+	w := &Worker{logger: hclog.NewNullLogger(), egress: new(atomic.Value)}
+
+	tests := []struct {
+		name     string
+		hostPort string
+		want     string
+		denied   bool
+	}{
+		{name: "allowed ip", hostPort: "10.1.2.3:22", want: "10.1.2.3:22"},
+		{name: "denied ip", hostPort: "192.168.1.1:22", denied: true},
+		{name: "metadata ip", hostPort: "169.254.169.254:8080", denied: true},
+		{name: "denied port", hostPort: "10.1.2.3:23", denied: true},
+		{name: "allowed suffix", hostPort: "db.internal.example.com:8050", want: "db.internal.example.com:8050"},
+		{name: "denied suffix port", hostPort: "db.internal.example.com:80", denied: true},
+		{name: "resolved into cidrs", hostPort: "localhost:8000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			w.egress.Store(conf.Worker.Egress)
+			got, err := w.egressAddress(context.Background(), tt.hostPort)
+			if tt.denied {
+				assert.True(errors.Is(err, errEgressDenied), "got %v", err)
+				return
+			}
+			require.NoError(err)
+			if tt.want == "" {
+				// Resolved host names are replaced by the checked address
+				host, port, err := net.SplitHostPort(got)
+				require.NoError(err)
+				assert.True(net.ParseIP(host).IsLoopback(), "got %s", got)
+				assert.Equal("8000", port)
+			} else {
+				assert.Equal(tt.want, got)
+			}
+
+			// Without egress configuration, any endpoint is dialed as is
+			w.ReloadEgress(nil)
+			got, err = w.egressAddress(context.Background(), tt.hostPort)
+			require.NoError(err)
+			assert.Equal(tt.hostPort, got)
+		})
+	}
+}
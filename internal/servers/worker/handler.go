@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/boundary/globals"
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
 	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/internal/tracing"
 	"github.com/hashicorp/shared-secure-libs/configutil"
 	"nhooyr.io/websocket"
 	"nhooyr.io/websocket/wspb"
@@ -40,6 +41,12 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		}
 		sessionId := r.TLS.ServerName
 
+		ctx, span := tracing.Start(r.Context(), "worker.proxy",
+			tracing.SessionIdKey.String(sessionId),
+			tracing.WorkerKey.String(w.conf.RawConfig.Worker.Name),
+		)
+		defer span.End()
+
 		clientIp, clientPort, err := net.SplitHostPort(r.RemoteAddr)
 		if err != nil {
 			w.logger.Error("unable to understand remote address", "error", err)
@@ -78,7 +85,7 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		w.logger.Trace("found session in session info map")
 
 		opts := &websocket.AcceptOptions{
-			Subprotocols: []string{globals.TcpProxyV1},
+			Subprotocols: []string{globals.TcpProxyV1, globals.TcpProxyMuxV1, globals.TcpProxyResumableV1, globals.UdpProxyV1},
 		}
 		conn, err := websocket.Accept(wr, r, opts)
 		if err != nil {
@@ -91,7 +98,7 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 
 		w.logger.Trace("websocket upgrade done")
 
-		connCtx, connCancel := context.WithDeadline(r.Context(), expiration.AsTime())
+		connCtx, connCancel := context.WithDeadline(ctx, expiration.AsTime())
 		defer connCancel()
 
 		var handshake proxy.ClientHandshake
@@ -108,6 +115,12 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 
 		w.logger.Trace("proxy handshake finished")
 
+		if err := w.checkOperationalState(si); err != nil {
+			w.logger.Info("refusing connection", "session_id", sessionId, "reason", err)
+			conn.Close(websocket.StatusTryAgainLater, err.Error())
+			return
+		}
+
 		if tofuToken != "" {
 			if tofuToken != handshake.GetTofuToken() {
 				w.logger.Error("WARNING: mismatched tofu token", "session_id", sessionId)
@@ -121,18 +134,51 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 				return
 			}
 			w.logger.Trace("activating session")
-			sessStatus, err = w.activateSession(r.Context(), sessionId, handshake.GetTofuToken(), version)
+			sessStatus, err = w.activateSession(ctx, sessionId, handshake.GetTofuToken(), version)
 			if err != nil {
+				tracing.RecordError(ctx, err)
 				w.logger.Error("unable to validate session", "error", err)
 				conn.Close(websocket.StatusInternalError, "unable to activate session")
 				return
 			}
 		}
 
+		if conn.Subprotocol() == globals.TcpProxyResumableV1 {
+			// The connection outlives the websocket, which may be replaced
+			// when it is resumed
+			si.Lock()
+			si.status = sessStatus
+			si.Unlock()
+			w.handleTcpProxyResumableV1(connCtx, clientAddr, conn, si, &handshake, endpoint)
+			return
+		}
+
+		if conn.Subprotocol() == globals.TcpProxyMuxV1 {
+			// Connections are authorized for each stream of the session
+			// rather than for the websocket
+			si.Lock()
+			si.status = sessStatus
+			connectionLimit := si.lookupSessionResponse.GetConnectionLimit()
+			si.Unlock()
+
+			handshakeResult := &proxy.HandshakeResult{
+				Expiration:      expiration,
+				ConnectionLimit: connectionLimit,
+			}
+			if err := wspb.Write(connCtx, conn, handshakeResult); err != nil {
+				w.logger.Error("error sending handshake result to client", "error", err)
+				conn.Close(websocket.StatusProtocolError, "unable to send handshake result")
+				return
+			}
+			w.handleTcpProxyMuxV1(connCtx, clientAddr, conn, si, endpoint)
+			return
+		}
+
 		var ci *connInfo
 		var connsLeft int32
-		ci, connsLeft, err = w.authorizeConnection(r.Context(), sessionId)
+		ci, connsLeft, err = w.authorizeConnection(ctx, sessionId)
 		if err != nil {
+			tracing.RecordError(ctx, err)
 			w.logger.Error("unable to authorize connection", "error", err)
 			conn.Close(websocket.StatusInternalError, "unable to authorize connection")
 			return
@@ -140,7 +186,7 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 
 		defer func() {
 			connectionId := ci.id
-			if err := w.closeConnections(r.Context(), map[string]string{
+			if err := w.closeConnections(ctx, map[string]string{
 				connectionId: si.id,
 			}); err != nil {
 				w.logger.Error("error marking connection closed", "error", err, "connection_id", connectionId)
@@ -156,6 +202,7 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		si.Unlock()
 
 		w.logger.Trace("authorized connection", "connection_id", ci.id)
+		span.SetAttributes(tracing.ConnectionIdKey.String(ci.id))
 
 		handshakeResult := &proxy.HandshakeResult{
 			Expiration:      expiration,
@@ -171,6 +218,8 @@ func (w *Worker) handleProxy() http.HandlerFunc {
 		switch conn.Subprotocol() {
 		case globals.TcpProxyV1:
 			w.handleTcpProxyV1(connCtx, clientAddr, conn, si, ci.id, endpoint)
+		case globals.UdpProxyV1:
+			w.handleUdpProxyV1(connCtx, clientAddr, conn, si, ci.id, endpoint)
 		default:
 			conn.Close(websocket.StatusProtocolError, "unsupported-protocol")
 			return
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/boundary/internal/libs/alpnmux"
 	"github.com/hashicorp/go-multierror"
 )
@@ -24,6 +25,19 @@ func (w *Worker) startListeners() error {
 				// We may have this in dev mode; ignore
 				continue
 
+			case "metrics":
+				// When running combined with a controller, the controller
+				// serves metrics for the whole process
+				if w.conf.RawConfig.Controller != nil {
+					continue
+				}
+				metricsServers, err := base.ConfigureMetricsListener(ln, w.baseContext, w.logger.Named("metrics"))
+				if err != nil {
+					return err
+				}
+				servers = append(servers, metricsServers...)
+				continue
+
 			case "proxy":
 				// Do nothing; handle below
 
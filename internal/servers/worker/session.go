@@ -25,6 +25,19 @@ type connInfo struct {
 	connCancel context.CancelFunc
 	status     pbs.CONNECTIONSTATUS
 	closeTime  time.Time
+	// closeReason is reported to the controller when the connection is
+	// closed, if set
+	closeReason session.ClosedReason
+	// stats are reported to the controller when the connection is closed
+	stats connStats
+}
+
+// connStats are the traffic statistics of a proxied connection.
+type connStats struct {
+	bytesUp       uint64
+	bytesDown     uint64
+	throttledUp   time.Duration
+	throttledDown time.Duration
 }
 
 type sessionInfo struct {
@@ -34,6 +47,9 @@ type sessionInfo struct {
 	status                pbs.SESSIONSTATUS
 	lookupSessionResponse *pbs.LookupSessionResponse
 	connInfoMap           map[string]*connInfo
+	// bandwidth limits the traffic of all connections of the session; it is
+	// created with the first connection
+	bandwidth *bandwidthLimiters
 }
 
 func (w *Worker) getSessionTls(hello *tls.ClientHelloInfo) (*tls.Config, error) {
@@ -153,6 +169,12 @@ func (w *Worker) activateSession(ctx context.Context, sessionId, tofuToken strin
 }
 
 func (w *Worker) authorizeConnection(ctx context.Context, sessionId string) (*connInfo, int32, error) {
+	if siRaw, ok := w.sessionInfoMap.Load(sessionId); ok {
+		if err := w.checkOperationalState(siRaw.(*sessionInfo)); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	rawConn := w.controllerSessionConn.Load()
 	if rawConn == nil {
 		return nil, 0, errors.New("could not get a controller client")
@@ -227,15 +249,51 @@ func (w *Worker) closeConnection(ctx context.Context, req *pbs.CloseConnectionRe
 	return resp, nil
 }
 
+// connectionCloseData returns the information to report to the controller
+// when closing the connection: the reason recorded for closing it, or the
+// unknown reason if none was, and its traffic statistics.
+func (w *Worker) connectionCloseData(sessionId, connectionId string) *pbs.CloseConnectionRequestData {
+	data := &pbs.CloseConnectionRequestData{
+		ConnectionId: connectionId,
+		Reason:       session.UnknownReason.String(),
+	}
+	siRaw, ok := w.sessionInfoMap.Load(sessionId)
+	if !ok {
+		return data
+	}
+	si := siRaw.(*sessionInfo)
+	si.RLock()
+	defer si.RUnlock()
+	ci, ok := si.connInfoMap[connectionId]
+	if !ok {
+		return data
+	}
+	if ci.closeReason != "" {
+		data.Reason = ci.closeReason.String()
+	}
+	data.BytesUp = ci.stats.bytesUp
+	data.BytesDown = ci.stats.bytesDown
+	data.ThrottledUpMs = uint64(ci.stats.throttledUp.Milliseconds())
+	data.ThrottledDownMs = uint64(ci.stats.throttledDown.Milliseconds())
+	return data
+}
+
+// recordConnectionStats records the traffic statistics of the connection, to
+// be reported when it is closed.
+func (w *Worker) recordConnectionStats(si *sessionInfo, connectionId string, stats connStats) {
+	si.Lock()
+	defer si.Unlock()
+	if ci, ok := si.connInfoMap[connectionId]; ok {
+		ci.stats = stats
+	}
+}
+
 func (w *Worker) closeConnections(ctx context.Context, closeMap map[string]string) error {
 	w.logger.Trace("marking connections as closed", "session_and_connection_ids", fmt.Sprintf("%#v", closeMap))
 
 	closeData := make([]*pbs.CloseConnectionRequestData, 0, len(closeMap))
-	for connId := range closeMap {
-		closeData = append(closeData, &pbs.CloseConnectionRequestData{
-			ConnectionId: connId,
-			Reason:       session.UnknownReason.String(),
-		})
+	for connId, sessId := range closeMap {
+		closeData = append(closeData, w.connectionCloseData(sessId, connId))
 	}
 	closeInfo := &pbs.CloseConnectionRequest{
 		CloseRequestData: closeData,
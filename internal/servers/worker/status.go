@@ -3,11 +3,14 @@ package worker
 import (
 	"context"
 	"math/rand"
+	"sort"
 	"time"
 
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/metrics"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/types/resource"
+	"github.com/hashicorp/boundary/version"
 	"google.golang.org/grpc/resolver"
 )
 
@@ -48,6 +51,7 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 				// First send info as-is. We'll perform cleanup duties after we
 				// get cancel/job change info back.
 				var activeJobs []*pbs.JobStatus
+				var activeSessions, activeConnections int
 				w.sessionInfoMap.Range(func(key, value interface{}) bool {
 					var jobInfo pbs.SessionJobInfo
 					sessionId := key.(string)
@@ -55,13 +59,21 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					si.RLock()
 					status := si.status
 					connections := make([]*pbs.Connection, 0, len(si.connInfoMap))
+					var open int
 					for k, v := range si.connInfoMap {
 						connections = append(connections, &pbs.Connection{
 							ConnectionId: k,
 							Status:       v.status,
 						})
+						if v.closeTime.IsZero() {
+							open++
+						}
 					}
 					si.RUnlock()
+					if open > 0 {
+						activeSessions++
+						activeConnections += open
+					}
 					jobInfo.SessionId = sessionId
 					activeJobs = append(activeJobs, &pbs.JobStatus{
 						Job: &pbs.Job{
@@ -77,21 +89,35 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					})
 					return true
 				})
+				metrics.SetWorkerActive(activeSessions, activeConnections)
+				w.reportDrainProgress(activeConnections)
 				client := w.controllerStatusConn.Load().(pbs.ServerCoordinationServiceClient)
+				statusStart := time.Now()
+				// Tags are only sent until they have been stored, as they
+				// don't change while the worker runs
+				updateTags := !w.tagsUpdated.Load()
 				result, err := client.Status(cancelCtx, &pbs.StatusRequest{
 					Jobs: activeJobs,
 					Worker: &servers.Server{
-						PrivateId:   w.conf.RawConfig.Worker.Name,
-						Name:        w.conf.RawConfig.Worker.Name,
-						Type:        resource.Worker.String(),
-						Description: w.conf.RawConfig.Worker.Description,
-						Address:     w.conf.RawConfig.Worker.PublicAddr,
+						PrivateId:             w.conf.RawConfig.Worker.Name,
+						Name:                  w.conf.RawConfig.Worker.Name,
+						Type:                  resource.Worker.String(),
+						Description:           w.conf.RawConfig.Worker.Description,
+						Address:               w.conf.RawConfig.Worker.PublicAddr,
+						ActiveSessionCount:    uint32(activeSessions),
+						ActiveConnectionCount: uint32(activeConnections),
+						ReleaseVersion:        version.Get().FullVersionNumber(false),
+						Tags:                  w.tags(updateTags),
 					},
+					RequestedOperationalState: w.requestedOperationalState.Load().(servers.OperationalState).String(),
+					UpdateTags:                updateTags,
 				})
+				metrics.ObserveWorkerStatus(statusStart, err)
 				if err != nil {
 					w.logger.Error("error making status request to controller", "error", err)
 				} else {
 					w.logger.Trace("successfully sent status to controller")
+					w.tagsUpdated.Store(true)
 					addrs := make([]resolver.Address, 0, len(result.Controllers))
 					strAddrs := make([]string, 0, len(result.Controllers))
 					for _, v := range result.Controllers {
@@ -106,6 +132,7 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 						w.Resolver().UpdateState(resolver.State{Addresses: addrs})
 					}
 					w.lastStatusSuccess.Store(&LastStatusInformation{StatusResponse: result, StatusTime: time.Now()})
+					w.applyOperationalState(result.GetWorker())
 
 					for _, request := range result.GetJobsRequests() {
 						switch request.GetRequestType() {
@@ -162,6 +189,8 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 					return true
 				})
 
+				w.closeDrainedConnections(closeInfo)
+
 				// Note that we won't clean these from the info map until the
 				// next time we run this function
 				if len(closeInfo) > 0 {
@@ -182,6 +211,26 @@ func (w *Worker) startStatusTicking(cancelCtx context.Context) {
 	}()
 }
 
+// tags returns the tags of the worker's configuration to send with its
+// status, or nil if they are not to be sent.
+func (w *Worker) tags(updateTags bool) []*servers.ServerTag {
+	if !updateTags {
+		return nil
+	}
+	keys := make([]string, 0, len(w.conf.RawConfig.Worker.Tags))
+	for k := range w.conf.RawConfig.Worker.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var tags []*servers.ServerTag
+	for _, k := range keys {
+		for _, v := range w.conf.RawConfig.Worker.Tags[k] {
+			tags = append(tags, &servers.ServerTag{Key: k, Value: v})
+		}
+	}
+	return tags
+}
+
 func (w *Worker) LastStatusSuccess() *LastStatusInformation {
 	return w.lastStatusSuccess.Load().(*LastStatusInformation)
 }
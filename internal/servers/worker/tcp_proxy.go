@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net"
 	"net/url"
@@ -10,29 +11,55 @@ import (
 	"nhooyr.io/websocket"
 
 	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/metrics"
+	"github.com/hashicorp/boundary/internal/tracing"
+	"go.opentelemetry.io/otel/label"
 )
 
 func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, connectionId, endpoint string) {
+	// Get a wrapped net.Conn so we can use io.Copy
+	netConn := websocket.NetConn(connCtx, conn, websocket.MessageBinary)
+	if err := w.proxyTcp(connCtx, clientAddr, netConn, si, connectionId, endpoint); err != nil {
+		conn.Close(websocket.StatusInternalError, err.Error())
+	}
+}
+
+// proxyTcp dials the endpoint for the connection and copies data between it
+// and the client until either side is done. The returned error is the
+// reason to give the client when the endpoint could not be proxied to.
+func (w *Worker) proxyTcp(connCtx context.Context, clientAddr *net.TCPAddr, clientConn io.ReadWriteCloser, si *sessionInfo, connectionId, endpoint string) error {
 	si.RLock()
 	sessionId := si.lookupSessionResponse.GetAuthorization().GetSessionId()
 	si.RUnlock()
 
+	connCtx, span := tracing.Start(connCtx, "worker.tcp_proxy",
+		tracing.SessionIdKey.String(sessionId),
+		tracing.ConnectionIdKey.String(connectionId),
+		tracing.EndpointKey.String(endpoint),
+	)
+	defer span.End()
+
 	sessionUrl, err := url.Parse(endpoint)
 	if err != nil {
 		w.logger.Error("error parsing endpoint information", "error", err, "session_id", sessionId, "endpoint", endpoint)
-		conn.Close(websocket.StatusInternalError, "cannot parse endpoint url")
-		return
+		return errors.New("cannot parse endpoint url")
 	}
 	if sessionUrl.Scheme != "tcp" {
 		w.logger.Error("invalid scheme for tcp proxy", "error", err, "session_id", sessionId, "endpoint", endpoint)
-		conn.Close(websocket.StatusInternalError, "invalid scheme for type")
-		return
+		return errors.New("invalid scheme for type")
 	}
-	remoteConn, err := net.Dial("tcp", sessionUrl.Host)
+	dialAddr, err := w.checkEgress(connCtx, si, connectionId, sessionUrl.Host)
 	if err != nil {
+		tracing.RecordError(connCtx, err)
+		return err
+	}
+	_, dialSpan := tracing.Start(connCtx, "worker.dial_endpoint", tracing.EndpointKey.String(sessionUrl.Host))
+	remoteConn, err := net.Dial("tcp", dialAddr)
+	tracing.End(connCtx, dialSpan, err)
+	if err != nil {
+		tracing.RecordError(connCtx, err)
 		w.logger.Error("error dialing endpoint", "error", err, "endpoint", endpoint)
-		conn.Close(websocket.StatusInternalError, "endpoint dialing failed")
-		return
+		return errors.New("endpoint dialing failed")
 	}
 	// Assert this for better Go 1.11 splice support
 	tcpRemoteConn := remoteConn.(*net.TCPConn)
@@ -49,29 +76,47 @@ func (w *Worker) handleTcpProxyV1(connCtx context.Context, clientAddr *net.TCPAd
 
 	connStatus, err := w.connectConnection(connCtx, connectionInfo)
 	if err != nil {
+		tcpRemoteConn.Close()
+		tracing.RecordError(connCtx, err)
 		w.logger.Error("error marking connection as connected", "error", err)
-		conn.Close(websocket.StatusInternalError, "failed to mark connection as connected")
-		return
+		return errors.New("failed to mark connection as connected")
 	}
 	si.Lock()
 	si.connInfoMap[connectionId].status = connStatus
 	si.Unlock()
 
-	// Get a wrapped net.Conn so we can use io.Copy
-	netConn := websocket.NetConn(connCtx, conn, websocket.MessageBinary)
-
+	// Either side being done ends the connection, which also unblocks the
+	// copy in the other direction. Each direction is throttled to the
+	// bandwidth limits of the connection, the session and the worker, and
+	// counted in the proxied bytes metric as it is written.
+	upstream, downstream := w.connectionThrottles(si)
+	var bytesUp, bytesDown int64
 	connWg := new(sync.WaitGroup)
 	connWg.Add(2)
 	go func() {
 		defer connWg.Done()
-		_, err := io.Copy(netConn, tcpRemoteConn)
+		n, err := io.Copy(metrics.ProxyBytesWriter(metrics.DirectionDownstream, clientConn), downstream.reader(connCtx, tcpRemoteConn))
+		bytesDown = n
+		span.SetAttributes(label.Int64("boundary.bytes_down", n))
 		w.logger.Debug("copy from client to endpoint done", "error", err)
+		clientConn.Close()
+		tcpRemoteConn.Close()
 	}()
 	go func() {
 		defer connWg.Done()
-		_, err := io.Copy(tcpRemoteConn, netConn)
+		n, err := io.Copy(metrics.ProxyBytesWriter(metrics.DirectionUpstream, tcpRemoteConn), upstream.reader(connCtx, clientConn))
+		bytesUp = n
+		span.SetAttributes(label.Int64("boundary.bytes_up", n))
 		w.logger.Debug("copy from endpoint to client done", "error", err)
+		clientConn.Close()
+		tcpRemoteConn.Close()
 	}()
 	connWg.Wait()
-
+	w.recordConnectionStats(si, connectionId, connStats{
+		bytesUp:       uint64(bytesUp),
+		bytesDown:     uint64(bytesDown),
+		throttledUp:   upstream.throttledTime(),
+		throttledDown: downstream.throttledTime(),
+	})
+	return nil
 }
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/internal/tracing"
+	"github.com/hashicorp/yamux"
+	"nhooyr.io/websocket"
+)
+
+// handleTcpProxyMuxV1 proxies the streams the client opens over the yamux
+// session of the websocket to the endpoint. Each stream is a connection of
+// its own, authorized and accounted for like a connection of the TCP proxy
+// protocol, which spares the client a TLS and websocket handshake for each
+// of its connections.
+func (w *Worker) handleTcpProxyMuxV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, endpoint string) {
+	// The websocket is closed when connCtx is done, which closes the
+	// session and all of its streams
+	netConn := websocket.NetConn(connCtx, conn, websocket.MessageBinary)
+	session, err := yamux.Server(netConn, proxy.MuxConfig())
+	if err != nil {
+		w.logger.Error("error starting multiplexed session", "error", err)
+		conn.Close(websocket.StatusInternalError, "unable to start multiplexed session")
+		return
+	}
+	defer session.Close()
+
+	streamWg := new(sync.WaitGroup)
+	defer streamWg.Wait()
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			w.logger.Debug("multiplexed session done", "error", err, "session_id", si.id)
+			return
+		}
+		streamWg.Add(1)
+		go func() {
+			defer streamWg.Done()
+			defer stream.Close()
+			w.handleTcpProxyMuxStream(connCtx, clientAddr, stream, si, endpoint)
+		}()
+	}
+}
+
+// handleTcpProxyMuxStream authorizes a connection for the stream, writes the
+// result to it and proxies it to the endpoint. The stream is closed without
+// a result if no connection can be authorized.
+func (w *Worker) handleTcpProxyMuxStream(connCtx context.Context, clientAddr *net.TCPAddr, stream *yamux.Stream, si *sessionInfo, endpoint string) {
+	si.RLock()
+	sessionId := si.id
+	expiration := si.lookupSessionResponse.GetExpiration()
+	connectionLimit := si.lookupSessionResponse.GetConnectionLimit()
+	si.RUnlock()
+
+	ci, connsLeft, err := w.authorizeConnection(connCtx, sessionId)
+	if err != nil {
+		tracing.RecordError(connCtx, err)
+		w.logger.Error("unable to authorize connection", "error", err, "session_id", sessionId)
+		return
+	}
+
+	// The stream's context is canceled when the connection is closed
+	streamCtx, streamCancel := context.WithCancel(connCtx)
+	defer streamCancel()
+	go func() {
+		<-streamCtx.Done()
+		stream.Close()
+	}()
+
+	// The connection is marked closed on the worker's context, as connCtx
+	// has passed its deadline when the session expires
+	defer func() {
+		if err := w.closeConnections(w.baseContext, map[string]string{
+			ci.id: si.id,
+		}); err != nil {
+			w.logger.Error("error marking connection closed", "error", err, "connection_id", ci.id)
+		}
+	}()
+
+	si.Lock()
+	ci.connCtx = streamCtx
+	ci.connCancel = streamCancel
+	si.connInfoMap[ci.id] = ci
+	si.Unlock()
+
+	w.logger.Trace("authorized multiplexed connection", "connection_id", ci.id)
+
+	if err := proxy.WriteStreamResult(stream, &proxy.HandshakeResult{
+		Expiration:      expiration,
+		ConnectionLimit: connectionLimit,
+		ConnectionsLeft: connsLeft,
+	}); err != nil {
+		w.logger.Error("error sending stream result to client", "error", err, "connection_id", ci.id)
+		return
+	}
+
+	if err := w.proxyTcp(streamCtx, clientAddr, stream, si, ci.id, endpoint); err != nil {
+		w.logger.Debug("stream not proxied", "reason", err, "connection_id", ci.id)
+	}
+}
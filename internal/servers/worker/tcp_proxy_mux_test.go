@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/yamux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// testSessionClient answers the connection calls of the worker like a
+// controller would, and reports the result of each close call.
+type testSessionClient struct {
+	pbs.SessionServiceClient
+	closeErrs chan error
+}
+
+func (c *testSessionClient) AuthorizeConnection(context.Context, *pbs.AuthorizeConnectionRequest, ...grpc.CallOption) (*pbs.AuthorizeConnectionResponse, error) {
+	return &pbs.AuthorizeConnectionResponse{
+		ConnectionId:    "tc_1234567890",
+		Status:          pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_AUTHORIZED,
+		ConnectionsLeft: -1,
+	}, nil
+}
+
+func (c *testSessionClient) ConnectConnection(context.Context, *pbs.ConnectConnectionRequest, ...grpc.CallOption) (*pbs.ConnectConnectionResponse, error) {
+	return &pbs.ConnectConnectionResponse{Status: pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CONNECTED}, nil
+}
+
+func (c *testSessionClient) CloseConnection(ctx context.Context, req *pbs.CloseConnectionRequest, _ ...grpc.CallOption) (*pbs.CloseConnectionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		c.closeErrs <- err
+		return nil, err
+	}
+	c.closeErrs <- nil
+	resp := new(pbs.CloseConnectionResponse)
+	for _, d := range req.GetCloseRequestData() {
+		resp.CloseResponseData = append(resp.CloseResponseData, &pbs.CloseConnectionResponseData{
+			ConnectionId: d.GetConnectionId(),
+			Status:       pbs.CONNECTIONSTATUS_CONNECTIONSTATUS_CLOSED,
+		})
+	}
+	return resp, nil
+}
+
+func TestHandleTcpProxyMuxStream_sessionExpired(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	// The endpoint holds its connections open until the worker closes them
+	endpoint, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer endpoint.Close()
+	go func() {
+		for {
+			conn, err := endpoint.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(ioutil.Discard, conn)
+			}()
+		}
+	}()
+
+	client := &testSessionClient{closeErrs: make(chan error, 1)}
+	w := &Worker{
+		logger:                hclog.NewNullLogger(),
+		baseContext:           context.Background(),
+		sessionInfoMap:        new(sync.Map),
+		controllerSessionConn: new(atomic.Value),
+		egress:                new(atomic.Value),
+		bandwidth:             newBandwidthLimiters(0, 0),
+	}
+	w.controllerSessionConn.Store(pbs.SessionServiceClient(client))
+	w.ReloadEgress(nil)
+
+	serverConn, clientConn := net.Pipe()
+	serverSession, err := yamux.Server(serverConn, nil)
+	require.NoError(err)
+	defer serverSession.Close()
+	clientSession, err := yamux.Client(clientConn, nil)
+	require.NoError(err)
+	defer clientSession.Close()
+	clientStream, err := clientSession.OpenStream()
+	require.NoError(err)
+	defer clientStream.Close()
+	stream, err := serverSession.AcceptStream()
+	require.NoError(err)
+
+	si := &sessionInfo{
+		id:                    "s_1234567890",
+		lookupSessionResponse: new(pbs.LookupSessionResponse),
+		connInfoMap:           make(map[string]*connInfo),
+	}
+	clientAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 10000}
+
+	// The websocket's context has the session expiration as its deadline
+	connCtx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.handleTcpProxyMuxStream(connCtx, clientAddr, stream, si, "tcp://"+endpoint.Addr().String())
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("stream was not closed after the session expired")
+	}
+	require.Error(connCtx.Err())
+	select {
+	case err := <-client.closeErrs:
+		assert.NoError(err)
+	default:
+		t.Fatal("connection was not marked closed")
+	}
+}
@@ -0,0 +1,187 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/internal/tracing"
+	"github.com/hashicorp/vault/sdk/helper/base62"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wspb"
+)
+
+var (
+	errResumeGraceExpired = errors.New("connection was not resumed within the grace period")
+	errResumableConnDone  = errors.New("connection closed")
+)
+
+// resumableConn is a connection of the resumable TCP proxy protocol. Its
+// stream to the client outlives the websocket it was opened over; when the
+// websocket fails, the endpoint connection is kept open for the grace period
+// for the client to resume the stream over a new websocket.
+type resumableConn struct {
+	sessionId string
+	stream    *proxy.ResumableStream
+
+	l sync.Mutex
+	// generation counts the websockets the stream was attached to, so that
+	// a replaced websocket failing does not start the grace period
+	generation uint64
+	graceTimer *time.Timer
+}
+
+// handleTcpProxyResumableV1 opens a resumable connection, or resumes the
+// one whose resume token is in the client's handshake, and carries its
+// stream over the websocket until the websocket fails or the connection is
+// done.
+func (w *Worker) handleTcpProxyResumableV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, handshake *proxy.ClientHandshake, endpoint string) {
+	si.RLock()
+	sessionId := si.id
+	expiration := si.lookupSessionResponse.GetExpiration()
+	connectionLimit := si.lookupSessionResponse.GetConnectionLimit()
+	si.RUnlock()
+
+	if token := handshake.GetResumeToken(); token != "" {
+		raw, ok := w.resumableConns.Load(token)
+		if !ok || raw.(*resumableConn).sessionId != sessionId {
+			w.logger.Error("unknown resumable connection", "session_id", sessionId)
+			conn.Close(websocket.StatusPolicyViolation, "unable to resume connection")
+			return
+		}
+		rc := raw.(*resumableConn)
+		handshakeResult := &proxy.HandshakeResult{
+			Expiration:      expiration,
+			ConnectionLimit: connectionLimit,
+			ResumeToken:     token,
+			ResumeReceived:  rc.stream.Received(),
+		}
+		if err := wspb.Write(connCtx, conn, handshakeResult); err != nil {
+			w.logger.Error("error sending handshake result to client", "error", err)
+			conn.Close(websocket.StatusProtocolError, "unable to send handshake result")
+			return
+		}
+		w.logger.Trace("resuming connection", "session_id", sessionId)
+		w.attachResumableConn(connCtx, conn, rc, handshake.GetResumeReceived())
+		return
+	}
+
+	ci, connsLeft, err := w.authorizeConnection(connCtx, sessionId)
+	if err != nil {
+		tracing.RecordError(connCtx, err)
+		w.logger.Error("unable to authorize connection", "error", err)
+		conn.Close(websocket.StatusInternalError, "unable to authorize connection")
+		return
+	}
+	token, err := base62.Random(32)
+	if err != nil {
+		w.logger.Error("error generating resume token", "error", err)
+		conn.Close(websocket.StatusInternalError, "unable to generate resume token")
+		if err := w.closeConnections(connCtx, map[string]string{ci.id: si.id}); err != nil {
+			w.logger.Error("error marking connection closed", "error", err, "connection_id", ci.id)
+		}
+		return
+	}
+
+	// The connection is bound to the worker and the session's expiration
+	// rather than to the websocket
+	streamCtx, streamCancel := context.WithDeadline(w.baseContext, expiration.AsTime())
+	rc := &resumableConn{
+		sessionId: sessionId,
+		stream:    proxy.NewResumableStream(0),
+	}
+	si.Lock()
+	ci.connCtx = streamCtx
+	ci.connCancel = streamCancel
+	si.connInfoMap[ci.id] = ci
+	si.Unlock()
+	w.resumableConns.Store(token, rc)
+
+	w.logger.Trace("authorized resumable connection", "connection_id", ci.id)
+
+	go func() {
+		// Closing the connection, for instance when the session is
+		// canceled, aborts the stream, which ends proxying
+		<-streamCtx.Done()
+		rc.stream.Abort(errResumableConnDone)
+	}()
+	go func() {
+		defer func() {
+			w.resumableConns.Delete(token)
+			streamCancel()
+			if err := w.closeConnections(w.baseContext, map[string]string{
+				ci.id: si.id,
+			}); err != nil {
+				w.logger.Error("error marking connection closed", "error", err, "connection_id", ci.id)
+			}
+		}()
+		if err := w.proxyTcp(streamCtx, clientAddr, rc.stream, si, ci.id, endpoint); err != nil {
+			rc.stream.Abort(err)
+			return
+		}
+		// Let the stream deliver what is left to the client
+		<-rc.stream.Done()
+	}()
+
+	handshakeResult := &proxy.HandshakeResult{
+		Expiration:      expiration,
+		ConnectionLimit: connectionLimit,
+		ConnectionsLeft: connsLeft,
+		ResumeToken:     token,
+	}
+	if err := wspb.Write(connCtx, conn, handshakeResult); err != nil {
+		w.logger.Error("error sending handshake result to client", "error", err)
+		rc.stream.Abort(errResumableConnDone)
+		conn.Close(websocket.StatusProtocolError, "unable to send handshake result")
+		return
+	}
+	w.attachResumableConn(connCtx, conn, rc, 0)
+}
+
+// attachResumableConn carries the connection's stream over the websocket.
+// If the websocket fails, the connection is aborted unless it is resumed
+// within the grace period.
+func (w *Worker) attachResumableConn(connCtx context.Context, conn *websocket.Conn, rc *resumableConn, peerReceived uint64) {
+	rc.l.Lock()
+	rc.generation++
+	generation := rc.generation
+	if rc.graceTimer != nil {
+		rc.graceTimer.Stop()
+		rc.graceTimer = nil
+	}
+	rc.l.Unlock()
+
+	err := rc.stream.Attach(connCtx, peerReceived, func(ctx context.Context) ([]byte, error) {
+		_, frame, err := conn.Read(ctx)
+		return frame, err
+	}, func(ctx context.Context, frame []byte) error {
+		return conn.Write(ctx, websocket.MessageBinary, frame)
+	})
+	switch {
+	case err == nil:
+		return
+	case rc.stream.Err() != nil:
+		w.logger.Debug("resumable connection aborted", "error", rc.stream.Err(), "session_id", rc.sessionId)
+		conn.Close(websocket.StatusInternalError, "connection aborted")
+		return
+	case errors.Is(err, proxy.ErrCannotResume), errors.Is(err, proxy.ErrInvalidResumableFrame):
+		w.logger.Error("unable to resume connection", "error", err, "session_id", rc.sessionId)
+		rc.stream.Abort(err)
+		conn.Close(websocket.StatusPolicyViolation, "unable to resume connection")
+		return
+	}
+
+	rc.l.Lock()
+	defer rc.l.Unlock()
+	if rc.generation != generation {
+		// Replaced by a websocket the client resumed over
+		return
+	}
+	w.logger.Debug("websocket of resumable connection failed, waiting for it to be resumed", "error", err, "session_id", rc.sessionId)
+	rc.graceTimer = time.AfterFunc(proxy.ResumeGracePeriod, func() {
+		rc.stream.Abort(errResumeGraceExpired)
+	})
+}
@@ -0,0 +1,291 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+
+	pbs "github.com/hashicorp/boundary/internal/gen/controller/servers/services"
+	"github.com/hashicorp/boundary/internal/metrics"
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/boundary/internal/tracing"
+	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel/label"
+	"go.uber.org/atomic"
+)
+
+const (
+	// udpFlowIdleTimeout is how long a flow can go without datagrams in
+	// either direction before its socket to the endpoint is closed.
+	udpFlowIdleTimeout = 2 * time.Minute
+
+	// udpMaxFlows is the maximum number of flows of a proxied connection;
+	// datagrams of new flows are dropped while it is reached.
+	udpMaxFlows = 1024
+)
+
+var errTooManyUdpFlows = errors.New("too many udp flows")
+
+func (w *Worker) handleUdpProxyV1(connCtx context.Context, clientAddr *net.TCPAddr, conn *websocket.Conn, si *sessionInfo, connectionId, endpoint string) {
+	si.RLock()
+	sessionId := si.lookupSessionResponse.GetAuthorization().GetSessionId()
+	si.RUnlock()
+
+	connCtx, span := tracing.Start(connCtx, "worker.udp_proxy",
+		tracing.SessionIdKey.String(sessionId),
+		tracing.ConnectionIdKey.String(connectionId),
+		tracing.EndpointKey.String(endpoint),
+	)
+	defer span.End()
+
+	sessionUrl, err := url.Parse(endpoint)
+	if err != nil {
+		w.logger.Error("error parsing endpoint information", "error", err, "session_id", sessionId, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "cannot parse endpoint url")
+		return
+	}
+	if sessionUrl.Scheme != "udp" {
+		w.logger.Error("invalid scheme for udp proxy", "session_id", sessionId, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "invalid scheme for type")
+		return
+	}
+	dialAddr, err := w.checkEgress(connCtx, si, connectionId, sessionUrl.Host)
+	if err != nil {
+		tracing.RecordError(connCtx, err)
+		conn.Close(websocket.StatusInternalError, err.Error())
+		return
+	}
+	endpointAddr, err := net.ResolveUDPAddr("udp", dialAddr)
+	if err != nil {
+		tracing.RecordError(connCtx, err)
+		w.logger.Error("error resolving endpoint", "error", err, "endpoint", endpoint)
+		conn.Close(websocket.StatusInternalError, "endpoint resolution failed")
+		return
+	}
+
+	connectionInfo := &pbs.ConnectConnectionRequest{
+		ConnectionId:       connectionId,
+		ClientTcpAddress:   clientAddr.IP.String(),
+		ClientTcpPort:      uint32(clientAddr.Port),
+		EndpointTcpAddress: endpointAddr.IP.String(),
+		EndpointTcpPort:    uint32(endpointAddr.Port),
+		Type:               "udp",
+	}
+
+	connStatus, err := w.connectConnection(connCtx, connectionInfo)
+	if err != nil {
+		tracing.RecordError(connCtx, err)
+		w.logger.Error("error marking connection as connected", "error", err)
+		conn.Close(websocket.StatusInternalError, "failed to mark connection as connected")
+		return
+	}
+	si.Lock()
+	si.connInfoMap[connectionId].status = connStatus
+	si.Unlock()
+
+	conn.SetReadLimit(proxy.MaxUdpFrameSize)
+	upstream, downstream := w.connectionThrottles(si)
+	relay := newUdpRelay(endpointAddr, udpFlowIdleTimeout, upstream, downstream, func(ctx context.Context, frame []byte) error {
+		return conn.Write(ctx, websocket.MessageBinary, frame)
+	}, w.logger.Named("udp").With("connection_id", connectionId))
+
+	err = relay.run(connCtx, func(ctx context.Context) ([]byte, error) {
+		typ, frame, err := conn.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if typ != websocket.MessageBinary {
+			return nil, fmt.Errorf("unexpected websocket message type %v", typ)
+		}
+		return frame, nil
+	})
+	span.SetAttributes(
+		label.Int64("boundary.bytes_up", relay.bytesUp.Load()),
+		label.Int64("boundary.bytes_down", relay.bytesDown.Load()),
+	)
+	w.recordConnectionStats(si, connectionId, connStats{
+		bytesUp:       uint64(relay.bytesUp.Load()),
+		bytesDown:     uint64(relay.bytesDown.Load()),
+		throttledUp:   upstream.throttledTime(),
+		throttledDown: downstream.throttledTime(),
+	})
+	w.logger.Debug("udp relay done", "error", err, "connection_id", connectionId)
+}
+
+// udpFlow is the socket to the endpoint of a flow.
+type udpFlow struct {
+	conn *net.UDPConn
+
+	// lastActive is the unix time in nanoseconds a datagram of the flow was
+	// last relayed at
+	lastActive atomic.Int64
+}
+
+func (f *udpFlow) touch() {
+	f.lastActive.Store(time.Now().UnixNano())
+}
+
+// udpRelay relays the datagrams of the flows of a proxied connection to the
+// endpoint. Each flow gets its own socket, so that the endpoint's replies can
+// be relayed back to the flow they are for; flows idle for longer than the
+// idle timeout are expired. Datagrams are held back as needed to keep to the
+// bandwidth limits of the throttles.
+type udpRelay struct {
+	endpoint    *net.UDPAddr
+	idleTimeout time.Duration
+	upstream    *throttle
+	downstream  *throttle
+	send        func(context.Context, []byte) error
+	logger      hclog.Logger
+
+	bytesUp   atomic.Int64
+	bytesDown atomic.Int64
+
+	l     sync.Mutex
+	flows map[uint32]*udpFlow
+	wg    sync.WaitGroup
+}
+
+func newUdpRelay(endpoint *net.UDPAddr, idleTimeout time.Duration, upstream, downstream *throttle, send func(context.Context, []byte) error, logger hclog.Logger) *udpRelay {
+	return &udpRelay{
+		endpoint:    endpoint,
+		idleTimeout: idleTimeout,
+		upstream:    upstream,
+		downstream:  downstream,
+		send:        send,
+		logger:      logger,
+		flows:       make(map[uint32]*udpFlow),
+	}
+}
+
+// run relays the frames returned by read to the endpoint until read fails or
+// ctx is done. The sockets of all flows are closed when it returns.
+func (r *udpRelay) run(ctx context.Context, read func(context.Context) ([]byte, error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		r.l.Lock()
+		for id, f := range r.flows {
+			f.conn.Close()
+			delete(r.flows, id)
+		}
+		r.l.Unlock()
+		r.wg.Wait()
+	}()
+
+	r.wg.Add(1)
+	go r.expire(ctx)
+
+	for {
+		frame, err := read(ctx)
+		if err != nil {
+			return err
+		}
+		flowId, datagram, err := proxy.DecodeUdpFrame(frame)
+		if err != nil {
+			return err
+		}
+		f, err := r.flow(ctx, flowId)
+		if err != nil {
+			r.logger.Debug("dropping datagram", "flow_id", flowId, "error", err)
+			continue
+		}
+		f.touch()
+		if err := r.upstream.wait(ctx, len(datagram)); err != nil {
+			return err
+		}
+		n, err := f.conn.Write(datagram)
+		if err != nil {
+			r.logger.Debug("error writing datagram to endpoint", "flow_id", flowId, "error", err)
+			continue
+		}
+		r.bytesUp.Add(int64(n))
+		metrics.AddProxyBytes(metrics.DirectionUpstream, int64(n))
+	}
+}
+
+// flow returns the flow with the id, creating it if needed.
+func (r *udpRelay) flow(ctx context.Context, id uint32) (*udpFlow, error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	if f, ok := r.flows[id]; ok {
+		return f, nil
+	}
+	if len(r.flows) >= udpMaxFlows {
+		return nil, errTooManyUdpFlows
+	}
+	conn, err := net.DialUDP("udp", nil, r.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error dialing endpoint: %w", err)
+	}
+	f := &udpFlow{conn: conn}
+	f.touch()
+	r.flows[id] = f
+	r.wg.Add(1)
+	go r.receive(ctx, id, f)
+	return f, nil
+}
+
+// remove closes the socket of the flow and forgets it.
+func (r *udpRelay) remove(id uint32, f *udpFlow) {
+	r.l.Lock()
+	if r.flows[id] == f {
+		delete(r.flows, id)
+	}
+	r.l.Unlock()
+	f.conn.Close()
+}
+
+// receive relays the endpoint's replies to the flow until its socket is
+// closed or fails; a failed flow is forgotten, so that its next datagram
+// opens a new socket.
+func (r *udpRelay) receive(ctx context.Context, id uint32, f *udpFlow) {
+	defer r.wg.Done()
+	defer r.remove(id, f)
+	buf := make([]byte, proxy.MaxUdpDatagramSize)
+	for {
+		n, err := f.conn.Read(buf)
+		if err != nil {
+			return
+		}
+		f.touch()
+		if err := r.downstream.wait(ctx, n); err != nil {
+			return
+		}
+		if err := r.send(ctx, proxy.EncodeUdpFrame(id, buf[:n])); err != nil {
+			return
+		}
+		r.bytesDown.Add(int64(n))
+		metrics.AddProxyBytes(metrics.DirectionDownstream, int64(n))
+	}
+}
+
+// expire closes the flows idle for longer than the idle timeout.
+func (r *udpRelay) expire(ctx context.Context) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.idleTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cutoff := time.Now().Add(-r.idleTimeout).UnixNano()
+		r.l.Lock()
+		for id, f := range r.flows {
+			if f.lastActive.Load() < cutoff {
+				r.logger.Trace("expiring idle flow", "flow_id", id)
+				delete(r.flows, id)
+				f.conn.Close()
+			}
+		}
+		r.l.Unlock()
+	}
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/proxy"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUdpEcho starts an endpoint replying to each datagram with the address
+// it was received from.
+func testUdpEcho(t *testing.T) *net.UDPConn {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	go func() {
+		buf := make([]byte, proxy.MaxUdpDatagramSize)
+		for {
+			_, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteToUDP([]byte(addr.String()), addr)
+		}
+	}()
+	return conn
+}
+
+func TestUdpRelay(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	echo := testUdpEcho(t)
+	defer echo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan []byte)
+	out := make(chan []byte, 10)
+	relay := newUdpRelay(echo.LocalAddr().(*net.UDPAddr), 200*time.Millisecond, newThrottle("upstream"), newThrottle("downstream"), func(ctx context.Context, frame []byte) error {
+		out <- frame
+		return nil
+	}, hclog.NewNullLogger())
+	done := make(chan error)
+	go func() {
+		done <- relay.run(ctx, func(ctx context.Context) ([]byte, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case frame := <-in:
+				return frame, nil
+			}
+		})
+	}()
+
+	roundTrip := func(flowId uint32) string {
+		in <- proxy.EncodeUdpFrame(flowId, []byte("ping"))
+		select {
+		case frame := <-out:
+			gotId, datagram, err := proxy.DecodeUdpFrame(frame)
+			require.NoError(err)
+			assert.Equal(flowId, gotId)
+			return string(datagram)
+		case <-time.After(5 * time.Second):
+			require.FailNow("no reply from relay")
+		}
+		return ""
+	}
+
+	// Each flow is relayed from its own socket
+	first := roundTrip(1)
+	assert.Equal(first, roundTrip(1))
+	second := roundTrip(2)
+	assert.NotEqual(first, second)
+
+	// Idle flows are expired and get a new socket
+	require.Eventually(func() bool {
+		relay.l.Lock()
+		defer relay.l.Unlock()
+		return len(relay.flows) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NotEqual(first, roundTrip(1))
+	assert.Equal(int64(len("ping")*4), relay.bytesUp.Load())
+
+	in <- []byte{0}
+	select {
+	case err := <-done:
+		assert.Error(err)
+	case <-time.After(5 * time.Second):
+		require.FailNow("relay did not stop on an invalid frame")
+	}
+	assert.Empty(relay.flows)
+}
@@ -8,10 +8,12 @@ import (
 	"sync/atomic"
 
 	"github.com/hashicorp/boundary/internal/cmd/config"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/sdk/helper/base62"
 	"github.com/hashicorp/vault/sdk/helper/mlock"
 	ua "go.uber.org/atomic"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc/resolver"
 	"google.golang.org/grpc/resolver/manual"
 )
@@ -32,6 +34,31 @@ type Worker struct {
 
 	controllerSessionConn *atomic.Value
 	sessionInfoMap        *sync.Map
+
+	// resumableConns maps the resume tokens of resumable connections to the
+	// connections
+	resumableConns *sync.Map
+
+	// egress holds the *config.WorkerEgress restricting the endpoints the
+	// worker connects to
+	egress *atomic.Value
+
+	// bandwidth limits the traffic of all connections the worker proxies
+	bandwidth *bandwidthLimiters
+
+	// operationalState holds the *operationalState deciding which connections
+	// the worker accepts, and requestedOperationalState the
+	// servers.OperationalState the worker was signaled to change to, until
+	// the controller knows about it
+	operationalState          *atomic.Value
+	requestedOperationalState *atomic.Value
+	// drainConnectionsLeft is the number of open connections last logged
+	// while draining, or -1
+	drainConnectionsLeft ua.Int64
+
+	// tagsUpdated is set once the tags of the worker have been sent with a
+	// successful status
+	tagsUpdated ua.Bool
 }
 
 func New(conf *Config) (*Worker, error) {
@@ -44,10 +71,21 @@ func New(conf *Config) (*Worker, error) {
 		controllerResolverCleanup: new(atomic.Value),
 		controllerSessionConn:     new(atomic.Value),
 		sessionInfoMap:            new(sync.Map),
+		resumableConns:            new(sync.Map),
+		egress:                    new(atomic.Value),
+		bandwidth: &bandwidthLimiters{
+			upstream:   rate.NewLimiter(rate.Inf, 0),
+			downstream: rate.NewLimiter(rate.Inf, 0),
+		},
+		operationalState:          new(atomic.Value),
+		requestedOperationalState: new(atomic.Value),
 	}
 
 	w.lastStatusSuccess.Store((*LastStatusInformation)(nil))
 	w.started.Store(false)
+	w.operationalState.Store(&operationalState{state: servers.OperationalStateActive})
+	w.requestedOperationalState.Store(servers.OperationalState(""))
+	w.drainConnectionsLeft.Store(-1)
 	w.controllerResolver.Store((*manual.Resolver)(nil))
 	w.controllerResolverCleanup.Store(func() {})
 
@@ -59,6 +97,11 @@ func New(conf *Config) (*Worker, error) {
 	if conf.RawConfig.Worker == nil {
 		conf.RawConfig.Worker = new(config.Worker)
 	}
+	w.egress.Store(conf.RawConfig.Worker.Egress)
+	if bandwidth := conf.RawConfig.Worker.Bandwidth; bandwidth != nil {
+		setBandwidthLimit(w.bandwidth.upstream, uint64(bandwidth.UpstreamBytesPerSecond))
+		setBandwidthLimit(w.bandwidth.downstream, uint64(bandwidth.DownstreamBytesPerSecond))
+	}
 	if conf.RawConfig.Worker.Name == "" {
 		if conf.RawConfig.Worker.Name, err = base62.Random(10); err != nil {
 			return nil, fmt.Errorf("error auto-generating worker name: %w", err)
@@ -127,6 +170,9 @@ func (w *Worker) Shutdown(skipListeners bool) error {
 		}
 	}
 	w.started.Store(false)
+	w.operationalState.Store(&operationalState{state: servers.OperationalStateActive})
+	w.requestedOperationalState.Store(servers.OperationalState(""))
+	w.drainConnectionsLeft.Store(-1)
 	return nil
 }
 
@@ -31,6 +31,12 @@ type Connection struct {
 	BytesUp uint64 `json:"bytes_up,omitempty" gorm:"default:null"`
 	// BytesDown of the connection
 	BytesDown uint64 `json:"bytes_down,omitempty" gorm:"default:null"`
+	// ThrottledUpMs is the time in milliseconds the worker held back traffic
+	// to the endpoint to keep to the bandwidth limits
+	ThrottledUpMs uint64 `json:"throttled_up_ms,omitempty" gorm:"default:null"`
+	// ThrottledDownMs is the time in milliseconds the worker held back
+	// traffic to the client to keep to the bandwidth limits
+	ThrottledDownMs uint64 `json:"throttled_down_ms,omitempty" gorm:"default:null"`
 	// ClosedReason of the conneciont
 	ClosedReason string `json:"closed_reason,omitempty" gorm:"default:null"`
 	// CreateTime from the RDBMS
@@ -82,6 +88,8 @@ func (c *Connection) Clone() interface{} {
 		EndpointTcpPort:    c.EndpointTcpPort,
 		BytesUp:            c.BytesUp,
 		BytesDown:          c.BytesDown,
+		ThrottledUpMs:      c.ThrottledUpMs,
+		ThrottledDownMs:    c.ThrottledDownMs,
 		ClosedReason:       c.ClosedReason,
 		Version:            c.Version,
 	}
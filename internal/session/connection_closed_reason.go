@@ -10,12 +10,14 @@ import (
 type ClosedReason string
 
 const (
-	UnknownReason          ClosedReason = "unknown"
-	ConnectionTimedOut     ClosedReason = "timed out"
-	ConnectionClosedByUser ClosedReason = "closed by end-user"
-	ConnectionCanceled     ClosedReason = "canceled"
-	ConnectionNetworkError ClosedReason = "network error"
-	ConnectionSystemError  ClosedReason = "system error"
+	UnknownReason           ClosedReason = "unknown"
+	ConnectionTimedOut      ClosedReason = "timed out"
+	ConnectionClosedByUser  ClosedReason = "closed by end-user"
+	ConnectionCanceled      ClosedReason = "canceled"
+	ConnectionNetworkError  ClosedReason = "network error"
+	ConnectionSystemError   ClosedReason = "system error"
+	ConnectionEgressDenied  ClosedReason = "egress denied"
+	ConnectionWorkerDrained ClosedReason = "worker drained"
 )
 
 // String representation of the termination reason
@@ -36,6 +38,10 @@ func convertToClosedReason(s string) (ClosedReason, error) {
 		return ConnectionNetworkError, nil
 	case ConnectionSystemError.String():
 		return ConnectionSystemError, nil
+	case ConnectionEgressDenied.String():
+		return ConnectionEgressDenied, nil
+	case ConnectionWorkerDrained.String():
+		return ConnectionWorkerDrained, nil
 	default:
 		return "", fmt.Errorf("closed reason: %s is not a valid reason: %w", s, db.ErrInvalidParameter)
 	}
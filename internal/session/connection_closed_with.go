@@ -13,6 +13,10 @@ type CloseWith struct {
 	BytesUp      uint64
 	BytesDown    uint64
 	ClosedReason ClosedReason
+	// ThrottledUpMs and ThrottledDownMs are the milliseconds the traffic of
+	// the connection was held back to keep to its bandwidth limits
+	ThrottledUpMs   uint64
+	ThrottledDownMs uint64
 }
 
 func (c CloseWith) validate() error {
@@ -22,6 +26,6 @@ func (c CloseWith) validate() error {
 	if c.ClosedReason.String() == "" {
 		return fmt.Errorf("missing closed reason: %w", db.ErrInvalidParameter)
 	}
-	// 0 is valid for BytesUp and BytesDown
+	// 0 is valid for BytesUp, BytesDown, ThrottledUpMs and ThrottledDownMs
 	return nil
 }
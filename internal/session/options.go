@@ -26,6 +26,7 @@ type options struct {
 	withTestTofu       []byte
 	withListingConvert bool
 	withSessionIds     []string
+	withEventBroker    *EventBroker
 }
 
 func getDefaultOptions() options {
@@ -84,6 +85,14 @@ func WithSessionIds(ids ...string) Option {
 	}
 }
 
+// WithEventBroker allows specifying an EventBroker which is notified of
+// session and connection state changes made by the repository.
+func WithEventBroker(b *EventBroker) Option {
+	return func(o *options) {
+		o.withEventBroker = b
+	}
+}
+
 func withListingConvert(withListingConvert bool) Option {
 	return func(o *options) {
 		o.withListingConvert = withListingConvert
@@ -20,13 +20,15 @@ type Repository struct {
 	reader db.Reader
 	writer db.Writer
 	kms    *kms.Kms
+	events *EventBroker
 
 	// defaultLimit provides a default for limiting the number of results returned from the repo
 	defaultLimit int
 }
 
 // NewRepository creates a new session Repository. Supports the options: WithLimit
-// which sets a default limit on results returned by repo operations.
+// which sets a default limit on results returned by repo operations and
+// WithEventBroker which publishes state changes made by the repo.
 func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repository, error) {
 	if r == nil {
 		return nil, errors.New("error creating db repository with nil reader")
@@ -46,6 +48,7 @@ func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*Repo
 		reader:       r,
 		writer:       w,
 		kms:          kms,
+		events:       opts.withEventBroker,
 		defaultLimit: opts.withLimit,
 	}, nil
 }
@@ -104,7 +107,13 @@ func (r *Repository) convertToSessions(ctx context.Context, sessionsWithState []
 				Version:           sv.Version,
 				Endpoint:          sv.Endpoint,
 				ConnectionLimit:   sv.ConnectionLimit,
-				KeyId:             sv.KeyId}
+				KeyId:             sv.KeyId,
+
+				ConnectionUpstreamBytesPerSecond:   sv.ConnectionUpstreamBytesPerSecond,
+				ConnectionDownstreamBytesPerSecond: sv.ConnectionDownstreamBytesPerSecond,
+				SessionUpstreamBytesPerSecond:      sv.SessionUpstreamBytesPerSecond,
+				SessionDownstreamBytesPerSecond:    sv.SessionDownstreamBytesPerSecond,
+			}
 			if opts.withListingConvert {
 				workingSession.CtTofuToken = nil // CtTofuToken should not returned in lists
 				workingSession.TofuToken = nil   // TofuToken should not returned in lists
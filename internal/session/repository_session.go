@@ -328,6 +328,7 @@ func (r *Repository) TerminateSession(ctx context.Context, sessionId string, ses
 	if err != nil {
 		return nil, fmt.Errorf("terminate session: %w", err)
 	}
+	r.publishSessionState(&updatedSession, StatusTerminated)
 	return &updatedSession, nil
 }
 
@@ -402,6 +403,7 @@ func (r *Repository) AuthorizeConnection(ctx context.Context, sessionId string)
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	r.publishConnectionState(ctx, &connection, StatusAuthorized)
 	authzSummary, err := r.sessionAuthzSummary(ctx, connection.SessionId)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("authorize connection: %w", err)
@@ -485,6 +487,7 @@ func (r *Repository) ConnectConnection(ctx context.Context, c ConnectWith) (*Con
 	if err != nil {
 		return nil, nil, fmt.Errorf("connect session: %w", err)
 	}
+	r.publishConnectionState(ctx, &connection, StatusConnected)
 	return &connection, connectionStates, nil
 }
 
@@ -518,13 +521,15 @@ func (r *Repository) CloseConnections(ctx context.Context, closeWith []CloseWith
 				updateConnection.PublicId = cw.ConnectionId
 				updateConnection.BytesUp = cw.BytesUp
 				updateConnection.BytesDown = cw.BytesDown
+				updateConnection.ThrottledUpMs = cw.ThrottledUpMs
+				updateConnection.ThrottledDownMs = cw.ThrottledDownMs
 				updateConnection.ClosedReason = cw.ClosedReason.String()
 				// updating the ClosedReason will trigger an insert into the
 				// session_connection_state with a state of closed.
 				rowsUpdated, err := w.Update(
 					ctx,
 					&updateConnection,
-					[]string{"BytesUp", "BytesDown", "ClosedReason"},
+					[]string{"BytesUp", "BytesDown", "ThrottledUpMs", "ThrottledDownMs", "ClosedReason"},
 					nil,
 				)
 				if err != nil {
@@ -549,6 +554,9 @@ func (r *Repository) CloseConnections(ctx context.Context, closeWith []CloseWith
 	if err != nil {
 		return nil, fmt.Errorf("close connections: %w", err)
 	}
+	for _, cr := range resp {
+		r.publishConnectionState(ctx, cr.Connection, StatusClosed)
+	}
 	return resp, nil
 }
 
@@ -608,7 +616,7 @@ func (r *Repository) ActivateSession(ctx context.Context, sessionId string, sess
 			if err := updatedSession.encrypt(ctx, databaseWrapper); err != nil {
 				return err
 			}
-			rowsUpdated, err := w.Update(ctx, &updatedSession, []string{"CtTofuToken"}, nil)
+			rowsUpdated, err := w.Update(ctx, &updatedSession, []string{"CtTofuToken", "KeyId"}, nil)
 			if err != nil {
 				return err
 			}
@@ -627,6 +635,7 @@ func (r *Repository) ActivateSession(ctx context.Context, sessionId string, sess
 	if err != nil {
 		return nil, nil, fmt.Errorf("activate session: %w", err)
 	}
+	r.publishSessionState(&updatedSession, StatusActive)
 	return &updatedSession, returnedStates, nil
 }
 
@@ -698,6 +707,7 @@ func (r *Repository) updateState(ctx context.Context, sessionId string, sessionV
 	if err != nil {
 		return nil, nil, fmt.Errorf("update session state: error creating new state: %w", err)
 	}
+	r.publishSessionState(&updatedSession, s)
 	return &updatedSession, returnedStates, nil
 }
 
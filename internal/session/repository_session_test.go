@@ -1357,8 +1357,8 @@ func TestRepository_ActivateSession(t *testing.T) {
 	rw := db.New(conn)
 	wrapper := db.TestWrapper(t)
 	iamRepo := iam.TestRepo(t, conn, wrapper)
-	kms := kms.TestKms(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kms)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, kmsCache)
 	require.NoError(t, err)
 	worker := TestWorker(t, conn, wrapper)
 
@@ -1457,6 +1457,14 @@ func TestRepository_ActivateSession(t *testing.T) {
 			assert.Equal(tofu, s.TofuToken)
 			assert.Equal(2, len(ss))
 			assert.Equal(StatusActive, ss[0].Status)
+
+			// the key version which encrypted the tofu token is stored
+			found := AllocSession()
+			found.PublicId = s.PublicId
+			require.NoError(rw.LookupById(context.Background(), &found))
+			databaseWrapper, err := kmsCache.GetWrapper(context.Background(), s.ScopeId, kms.KeyPurposeDatabase)
+			require.NoError(err)
+			assert.Equal(databaseWrapper.KeyID(), found.KeyId)
 		})
 		t.Run("already active", func(t *testing.T) {
 			assert, require := assert.New(t), require.New(t)
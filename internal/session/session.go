@@ -44,6 +44,14 @@ type ComposedOf struct {
 	ExpirationTime *timestamp.Timestamp
 	// Max connections for the session
 	ConnectionLimit int32
+	// Bandwidth limits of each connection and of all connections of the
+	// session, from the client to the endpoint (upstream) and from the
+	// endpoint to the client (downstream), in bytes per second. 0 means no
+	// limit.
+	ConnectionUpstreamBytesPerSecond   uint32
+	ConnectionDownstreamBytesPerSecond uint32
+	SessionUpstreamBytesPerSecond      uint32
+	SessionDownstreamBytesPerSecond    uint32
 }
 
 // Session contains information about a user's session with a target
@@ -88,6 +96,11 @@ type Session struct {
 	Endpoint string `json:"-" gorm:"default:null"`
 	// Maximum number of connections in a session
 	ConnectionLimit int32 `json:"connection_limit,omitempty" gorm:"default:null"`
+	// Bandwidth limits of the session, in bytes per second
+	ConnectionUpstreamBytesPerSecond   uint32 `json:"connection_upstream_bytes_per_second,omitempty" gorm:"default:null"`
+	ConnectionDownstreamBytesPerSecond uint32 `json:"connection_downstream_bytes_per_second,omitempty" gorm:"default:null"`
+	SessionUpstreamBytesPerSecond      uint32 `json:"session_upstream_bytes_per_second,omitempty" gorm:"default:null"`
+	SessionDownstreamBytesPerSecond    uint32 `json:"session_downstream_bytes_per_second,omitempty" gorm:"default:null"`
 
 	// key_id is the key ID that was used for the encryption operation. It can be
 	// used to identify a specific version of the key needed to decrypt the value,
@@ -120,6 +133,11 @@ func New(c ComposedOf, opt ...Option) (*Session, error) {
 		Endpoint:        c.Endpoint,
 		ExpirationTime:  c.ExpirationTime,
 		ConnectionLimit: c.ConnectionLimit,
+
+		ConnectionUpstreamBytesPerSecond:   c.ConnectionUpstreamBytesPerSecond,
+		ConnectionDownstreamBytesPerSecond: c.ConnectionDownstreamBytesPerSecond,
+		SessionUpstreamBytesPerSecond:      c.SessionUpstreamBytesPerSecond,
+		SessionDownstreamBytesPerSecond:    c.SessionDownstreamBytesPerSecond,
 	}
 	if err := s.validateNewSession("new session:"); err != nil {
 		return nil, err
@@ -148,6 +166,11 @@ func (s *Session) Clone() interface{} {
 		Version:           s.Version,
 		Endpoint:          s.Endpoint,
 		ConnectionLimit:   s.ConnectionLimit,
+
+		ConnectionUpstreamBytesPerSecond:   s.ConnectionUpstreamBytesPerSecond,
+		ConnectionDownstreamBytesPerSecond: s.ConnectionDownstreamBytesPerSecond,
+		SessionUpstreamBytesPerSecond:      s.SessionUpstreamBytesPerSecond,
+		SessionDownstreamBytesPerSecond:    s.SessionDownstreamBytesPerSecond,
 	}
 	if len(s.States) > 0 {
 		clone.States = make([]*State, 0, len(s.States))
@@ -236,6 +259,11 @@ func (s *Session) VetForWrite(ctx context.Context, r db.Reader, opType db.OpType
 			return fmt.Errorf("session vet for write: expiration time is immutable: %w", db.ErrInvalidParameter)
 		case contains(opts.WithFieldMaskPaths, "ConnectionLimit"):
 			return fmt.Errorf("session vet for write: connection limit is immutable: %w", db.ErrInvalidParameter)
+		case contains(opts.WithFieldMaskPaths, "ConnectionUpstreamBytesPerSecond"),
+			contains(opts.WithFieldMaskPaths, "ConnectionDownstreamBytesPerSecond"),
+			contains(opts.WithFieldMaskPaths, "SessionUpstreamBytesPerSecond"),
+			contains(opts.WithFieldMaskPaths, "SessionDownstreamBytesPerSecond"):
+			return fmt.Errorf("session vet for write: bandwidth limits are immutable: %w", db.ErrInvalidParameter)
 		case contains(opts.WithFieldMaskPaths, "TerminationReason"):
 			if _, err := convertToReason(s.TerminationReason); err != nil {
 				return fmt.Errorf("session vet for write: termination reason '%s' is invalid: %w", s.TerminationReason, db.ErrInvalidParameter)
@@ -386,6 +414,11 @@ type sessionView struct {
 	ConnectionLimit   int32                `json:"connection_limit,omitempty" gorm:"default:null"`
 	KeyId             string               `json:"key_id,omitempty" gorm:"not_null"`
 
+	ConnectionUpstreamBytesPerSecond   uint32 `json:"connection_upstream_bytes_per_second,omitempty" gorm:"default:null"`
+	ConnectionDownstreamBytesPerSecond uint32 `json:"connection_downstream_bytes_per_second,omitempty" gorm:"default:null"`
+	SessionUpstreamBytesPerSecond      uint32 `json:"session_upstream_bytes_per_second,omitempty" gorm:"default:null"`
+	SessionDownstreamBytesPerSecond    uint32 `json:"session_downstream_bytes_per_second,omitempty" gorm:"default:null"`
+
 	// State fields
 	Status          string               `json:"state,omitempty" gorm:"column:state"`
 	PreviousEndTime *timestamp.Timestamp `json:"previous_end_time,omitempty" gorm:"default:current_timestamp"`
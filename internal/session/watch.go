@@ -0,0 +1,173 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultEventBufferSize is the number of events buffered for each
+	// subscriber before it is considered to be lagging and is dropped.
+	defaultEventBufferSize = 256
+)
+
+// EventType defines the kind of state change described by an Event.
+type EventType string
+
+const (
+	SessionStateEvent    EventType = "session"
+	ConnectionStateEvent EventType = "connection"
+)
+
+// String representation of the event type
+func (t EventType) String() string {
+	return string(t)
+}
+
+// Event describes a state transition of a session or one of its connections.
+// Events are published by the Repository after the transaction performing the
+// transition has been committed.
+type Event struct {
+	Type         EventType
+	SessionId    string
+	ConnectionId string
+	ScopeId      string
+	UserId       string
+	TargetId     string
+	Status       string
+	Time         time.Time
+}
+
+// EventFilter restricts which events a subscriber receives. Empty fields match
+// every event.
+type EventFilter struct {
+	ScopeId  string
+	UserId   string
+	TargetId string
+}
+
+func (f EventFilter) matches(e *Event) bool {
+	switch {
+	case f.ScopeId != "" && f.ScopeId != e.ScopeId:
+		return false
+	case f.UserId != "" && f.UserId != e.UserId:
+		return false
+	case f.TargetId != "" && f.TargetId != e.TargetId:
+		return false
+	}
+	return true
+}
+
+type subscriber struct {
+	filter EventFilter
+	ch     chan *Event
+}
+
+// EventBroker fans out session and connection state changes to subscribers.
+// A single broker is meant to be shared by every Repository created by a
+// controller so that all transitions are observed regardless of which request
+// performed them.
+type EventBroker struct {
+	l          sync.Mutex
+	subs       map[*subscriber]struct{}
+	bufferSize int
+}
+
+// NewEventBroker creates a new EventBroker.
+func NewEventBroker() *EventBroker {
+	return &EventBroker{
+		subs:       make(map[*subscriber]struct{}),
+		bufferSize: defaultEventBufferSize,
+	}
+}
+
+// Subscribe returns a channel which receives all published events matching
+// the filter. The channel is closed when ctx is done or when the subscriber
+// falls too far behind, in which case the caller is expected to resynchronize
+// (e.g. via ListSessions) and subscribe again.
+func (b *EventBroker) Subscribe(ctx context.Context, f EventFilter) <-chan *Event {
+	s := &subscriber{
+		filter: f,
+		ch:     make(chan *Event, b.bufferSize),
+	}
+	b.l.Lock()
+	b.subs[s] = struct{}{}
+	b.l.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.unsubscribe(s)
+	}()
+	return s.ch
+}
+
+// Publish sends the event to every matching subscriber. It never blocks;
+// subscribers whose buffer is full are dropped.
+func (b *EventBroker) Publish(e *Event) {
+	if b == nil || e == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	b.l.Lock()
+	defer b.l.Unlock()
+	for s := range b.subs {
+		if !s.filter.matches(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			delete(b.subs, s)
+			close(s.ch)
+		}
+	}
+}
+
+func (b *EventBroker) unsubscribe(s *subscriber) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	if _, ok := b.subs[s]; !ok {
+		return
+	}
+	delete(b.subs, s)
+	close(s.ch)
+}
+
+func (r *Repository) publishSessionState(s *Session, st Status) {
+	if r.events == nil || s == nil {
+		return
+	}
+	r.events.Publish(&Event{
+		Type:      SessionStateEvent,
+		SessionId: s.PublicId,
+		ScopeId:   s.ScopeId,
+		UserId:    s.UserId,
+		TargetId:  s.TargetId,
+		Status:    st.String(),
+	})
+}
+
+func (r *Repository) publishConnectionState(ctx context.Context, c *Connection, st ConnectionStatus) {
+	if r.events == nil || c == nil {
+		return
+	}
+	s := AllocSession()
+	s.PublicId = c.SessionId
+	if err := r.reader.LookupById(ctx, &s); err != nil {
+		// The session is only needed to populate the fields used for
+		// filtering, so publish what we know.
+		s = AllocSession()
+	}
+	r.events.Publish(&Event{
+		Type:         ConnectionStateEvent,
+		SessionId:    c.SessionId,
+		ConnectionId: c.PublicId,
+		ScopeId:      s.ScopeId,
+		UserId:       s.UserId,
+		TargetId:     s.TargetId,
+		Status:       st.String(),
+	})
+}
@@ -0,0 +1,60 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBroker_Subscribe(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	b := NewEventBroker()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	all := b.Subscribe(ctx, EventFilter{})
+	scoped := b.Subscribe(ctx, EventFilter{ScopeId: "p_1234567890", UserId: "u_1234567890"})
+
+	b.Publish(&Event{Type: SessionStateEvent, SessionId: "s_1", ScopeId: "p_1234567890", UserId: "u_1234567890", Status: StatusCanceling.String()})
+	b.Publish(&Event{Type: ConnectionStateEvent, SessionId: "s_2", ScopeId: "p_0987654321", Status: StatusClosed.String()})
+
+	got := <-all
+	assert.Equal("s_1", got.SessionId)
+	assert.False(got.Time.IsZero())
+	got = <-all
+	assert.Equal("s_2", got.SessionId)
+	got = <-scoped
+	assert.Equal("s_1", got.SessionId)
+	select {
+	case e := <-scoped:
+		require.Failf("unexpected event", "%v", e)
+	default:
+	}
+
+	cancel()
+	_, ok := <-all
+	assert.False(ok)
+	_, ok = <-scoped
+	assert.False(ok)
+}
+
+func TestEventBroker_LaggingSubscriber(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	b := NewEventBroker()
+	b.bufferSize = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := b.Subscribe(ctx, EventFilter{})
+	b.Publish(&Event{SessionId: "s_1"})
+	b.Publish(&Event{SessionId: "s_2"})
+
+	got, ok := <-events
+	assert.True(ok)
+	assert.Equal("s_1", got.SessionId)
+	_, ok = <-events
+	assert.False(ok, "lagging subscriber should have been dropped")
+}
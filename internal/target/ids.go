@@ -8,6 +8,7 @@ import (
 
 const (
 	TcpTargetPrefix = "ttcp"
+	UdpTargetPrefix = "tudp"
 )
 
 func newTcpTargetId() (string, error) {
@@ -17,3 +18,11 @@ func newTcpTargetId() (string, error) {
 	}
 	return id, nil
 }
+
+func newUdpTargetId() (string, error) {
+	id, err := db.NewPublicId(UdpTargetPrefix)
+	if err != nil {
+		return "", fmt.Errorf("new udp target id: %w", err)
+	}
+	return id, nil
+}
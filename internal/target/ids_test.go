@@ -15,4 +15,9 @@ func Test_Ids(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, strings.HasPrefix(id, TcpTargetPrefix+"_"))
 	})
+	t.Run("udp", func(t *testing.T) {
+		id, err := newUdpTargetId()
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(id, UdpTargetPrefix+"_"))
+	})
 }
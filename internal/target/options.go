@@ -27,6 +27,11 @@ type options struct {
 	withSessionMaxSeconds      uint32
 	withSessionConnectionLimit int32
 	withPublicId               string
+
+	withConnectionUpstreamBytesPerSecond   uint32
+	withConnectionDownstreamBytesPerSecond uint32
+	withSessionUpstreamBytesPerSecond      uint32
+	withSessionDownstreamBytesPerSecond    uint32
 }
 
 func getDefaultOptions() options {
@@ -115,6 +120,42 @@ func WithSessionConnectionLimit(limit int32) Option {
 	}
 }
 
+// WithConnectionUpstreamBytesPerSecond provides an option to limit the
+// bandwidth from the client to the endpoint of each connection of a session.
+// 0 means no limit.
+func WithConnectionUpstreamBytesPerSecond(limit uint32) Option {
+	return func(o *options) {
+		o.withConnectionUpstreamBytesPerSecond = limit
+	}
+}
+
+// WithConnectionDownstreamBytesPerSecond provides an option to limit the
+// bandwidth from the endpoint to the client of each connection of a session.
+// 0 means no limit.
+func WithConnectionDownstreamBytesPerSecond(limit uint32) Option {
+	return func(o *options) {
+		o.withConnectionDownstreamBytesPerSecond = limit
+	}
+}
+
+// WithSessionUpstreamBytesPerSecond provides an option to limit the bandwidth
+// from the client to the endpoint of all connections of a session together.
+// 0 means no limit.
+func WithSessionUpstreamBytesPerSecond(limit uint32) Option {
+	return func(o *options) {
+		o.withSessionUpstreamBytesPerSecond = limit
+	}
+}
+
+// WithSessionDownstreamBytesPerSecond provides an option to limit the
+// bandwidth from the endpoint to the client of all connections of a session
+// together. 0 means no limit.
+func WithSessionDownstreamBytesPerSecond(limit uint32) Option {
+	return func(o *options) {
+		o.withSessionDownstreamBytesPerSecond = limit
+	}
+}
+
 // WithPublicId provides an optional public id
 func WithPublicId(id string) Option {
 	return func(o *options) {
@@ -127,6 +127,10 @@ func (r *Repository) UpdateTcpTarget(ctx context.Context, target *TcpTarget, ver
 		case strings.EqualFold("defaultport", f):
 		case strings.EqualFold("sessionmaxseconds", f):
 		case strings.EqualFold("sessionconnectionlimit", f):
+		case strings.EqualFold("connectionupstreambytespersecond", f):
+		case strings.EqualFold("connectiondownstreambytespersecond", f):
+		case strings.EqualFold("sessionupstreambytespersecond", f):
+		case strings.EqualFold("sessiondownstreambytespersecond", f):
 		default:
 			return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: field: %s: %w", f, db.ErrInvalidFieldMask)
 		}
@@ -134,14 +138,18 @@ func (r *Repository) UpdateTcpTarget(ctx context.Context, target *TcpTarget, ver
 	var dbMask, nullFields []string
 	dbMask, nullFields = dbcommon.BuildUpdatePaths(
 		map[string]interface{}{
-			"Name":                   target.Name,
-			"Description":            target.Description,
-			"DefaultPort":            target.DefaultPort,
-			"SessionMaxSeconds":      target.SessionMaxSeconds,
-			"SessionConnectionLimit": target.SessionConnectionLimit,
+			"Name":                               target.Name,
+			"Description":                        target.Description,
+			"DefaultPort":                        target.DefaultPort,
+			"SessionMaxSeconds":                  target.SessionMaxSeconds,
+			"SessionConnectionLimit":             target.SessionConnectionLimit,
+			"ConnectionUpstreamBytesPerSecond":   target.ConnectionUpstreamBytesPerSecond,
+			"ConnectionDownstreamBytesPerSecond": target.ConnectionDownstreamBytesPerSecond,
+			"SessionUpstreamBytesPerSecond":      target.SessionUpstreamBytesPerSecond,
+			"SessionDownstreamBytesPerSecond":    target.SessionDownstreamBytesPerSecond,
 		},
 		fieldMaskPaths,
-		[]string{"SessionMaxSeconds", "SessionConnectionLimit"},
+		[]string{"SessionMaxSeconds", "SessionConnectionLimit", "ConnectionUpstreamBytesPerSecond", "ConnectionDownstreamBytesPerSecond", "SessionUpstreamBytesPerSecond", "SessionDownstreamBytesPerSecond"},
 	)
 	if len(dbMask) == 0 && len(nullFields) == 0 {
 		return nil, nil, db.NoRowsAffected, fmt.Errorf("update tcp target: %w", db.ErrEmptyFieldMask)
@@ -0,0 +1,189 @@
+package target
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRepository_CreateUdpTarget(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(t, err)
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	cats := static.TestCatalogs(t, conn, proj.PublicId, 1)
+	hsets := static.TestSets(t, conn, cats[0].GetPublicId(), 2)
+	var sets []string
+	for _, s := range hsets {
+		sets = append(sets, s.PublicId)
+	}
+
+	type args struct {
+		target *UdpTarget
+		opt    []Option
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantHostSets []string
+		wantErr      bool
+		wantIsError  error
+	}{
+		{
+			name: "valid-org",
+			args: args{
+				target: func() *UdpTarget {
+					target, err := NewUdpTarget(proj.PublicId,
+						WithName("valid-org"),
+						WithDescription("valid-org"),
+						WithDefaultPort(uint32(53)))
+					require.NoError(t, err)
+					return target
+				}(),
+				opt: []Option{WithHostSets(sets)},
+			},
+			wantErr:      false,
+			wantHostSets: sets,
+		},
+		{
+			name: "nil-target",
+			args: args{
+				target: nil,
+			},
+			wantErr:     true,
+			wantIsError: db.ErrInvalidParameter,
+		},
+		{
+			name: "nil-target-store",
+			args: args{
+				target: func() *UdpTarget {
+					target := &UdpTarget{}
+					return target
+				}(),
+			},
+			wantErr:     true,
+			wantIsError: db.ErrInvalidParameter,
+		},
+		{
+			name: "public-id-not-empty",
+			args: args{
+				target: func() *UdpTarget {
+					target, err := NewUdpTarget(proj.PublicId, WithName("valid-org"), WithDescription("valid-org"), WithDefaultPort(uint32(53)))
+					require.NoError(t, err)
+					id, err := newUdpTargetId()
+					require.NoError(t, err)
+					target.PublicId = id
+					return target
+				}(),
+			},
+			wantErr:     true,
+			wantIsError: db.ErrInvalidParameter,
+		},
+		{
+			name: "empty-scope-id",
+			args: args{
+				target: func() *UdpTarget {
+					target := allocUdpTarget()
+					target.Name = "empty-scope-id"
+					require.NoError(t, err)
+					return &target
+				}(),
+			},
+			wantErr:     true,
+			wantIsError: db.ErrInvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			target, hostSets, err := repo.CreateUdpTarget(context.Background(), tt.args.target, tt.args.opt...)
+			if tt.wantErr {
+				assert.Error(err)
+				assert.Nil(target)
+				if tt.wantIsError != nil {
+					assert.True(errors.Is(err, tt.wantIsError))
+				}
+				return
+			}
+			require.NoError(err)
+			assert.NotNil(target.GetPublicId())
+			gotIds := make([]string, 0, len(hostSets))
+			for _, s := range hostSets {
+				gotIds = append(gotIds, s.PublicId)
+			}
+			assert.Equal(tt.wantHostSets, gotIds)
+
+			foundTarget, foundHostSets, err := repo.LookupTarget(context.Background(), target.GetPublicId())
+			assert.NoError(err)
+			assert.True(proto.Equal(target.(*UdpTarget), foundTarget.(*UdpTarget)))
+			assert.Equal(hostSets, foundHostSets)
+
+			err = db.TestVerifyOplog(t, rw, target.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second))
+			assert.NoError(err)
+
+		})
+	}
+}
+
+func TestRepository_UdpTargets(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	testKms := kms.TestKms(t, conn, wrapper)
+	repo, err := NewRepository(rw, rw, testKms)
+	require.NoError(err)
+	_, proj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	ctx := context.Background()
+
+	cats := static.TestCatalogs(t, conn, proj.PublicId, 1)
+	hsets := static.TestSets(t, conn, cats[0].GetPublicId(), 1)
+
+	_ = TestTcpTarget(t, conn, proj.PublicId, testTargetName(t, proj.PublicId))
+	udpTarget := TestUdpTarget(t, conn, proj.PublicId, testTargetName(t, proj.PublicId), WithDefaultPort(53))
+
+	got, err := repo.ListTargets(ctx, WithScopeId(proj.PublicId))
+	require.NoError(err)
+	assert.Len(got, 2)
+	got, err = repo.ListTargets(ctx, WithScopeId(proj.PublicId), WithTargetType(UdpTargetType))
+	require.NoError(err)
+	require.Len(got, 1)
+	assert.Equal(udpTarget.PublicId, got[0].GetPublicId())
+	assert.Equal("udp", got[0].GetType())
+	assert.Equal(uint32(53), got[0].GetDefaultPort())
+
+	updated, hostSets, err := repo.AddTargetHostSets(ctx, udpTarget.PublicId, udpTarget.Version, []string{hsets[0].PublicId})
+	require.NoError(err)
+	assert.Equal(udpTarget.Version+1, updated.GetVersion())
+	require.Len(hostSets, 1)
+	assert.Equal(hsets[0].PublicId, hostSets[0].PublicId)
+
+	updated.(*UdpTarget).Name = "updated"
+	updated, _, rowsUpdated, err := repo.UpdateUdpTarget(ctx, updated.(*UdpTarget), updated.GetVersion(), []string{"Name"})
+	require.NoError(err)
+	assert.Equal(1, rowsUpdated)
+	assert.Equal("updated", updated.GetName())
+
+	rowsDeleted, err := repo.DeleteTarget(ctx, udpTarget.PublicId)
+	require.NoError(err)
+	assert.Equal(1, rowsDeleted)
+	found, _, err := repo.LookupTarget(ctx, udpTarget.PublicId)
+	require.NoError(err)
+	assert.Nil(found)
+}
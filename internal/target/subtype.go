@@ -9,12 +9,15 @@ type SubType int
 const (
 	UnknownSubtype SubType = iota
 	TcpSubType
+	UdpSubType
 )
 
 func (t SubType) String() string {
 	switch t {
 	case TcpSubType:
 		return "tcp"
+	case UdpSubType:
+		return "udp"
 	}
 	return "unknown"
 }
@@ -25,6 +28,8 @@ func SubtypeFromType(t string) SubType {
 	switch {
 	case strings.EqualFold(strings.TrimSpace(t), TcpSubType.String()):
 		return TcpSubType
+	case strings.EqualFold(strings.TrimSpace(t), UdpSubType.String()):
+		return UdpSubType
 	}
 	return UnknownSubtype
 }
@@ -36,6 +41,8 @@ func SubtypeFromId(id string) SubType {
 	switch {
 	case strings.HasPrefix(strings.TrimSpace(id), TcpTargetPrefix):
 		return TcpSubType
+	case strings.HasPrefix(strings.TrimSpace(id), UdpTargetPrefix):
+		return UdpSubType
 	}
 	return UnknownSubtype
 }
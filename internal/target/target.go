@@ -21,6 +21,10 @@ type Target interface {
 	GetUpdateTime() *timestamp.Timestamp
 	GetSessionMaxSeconds() uint32
 	GetSessionConnectionLimit() int32
+	GetConnectionUpstreamBytesPerSecond() uint32
+	GetConnectionDownstreamBytesPerSecond() uint32
+	GetSessionUpstreamBytesPerSecond() uint32
+	GetSessionDownstreamBytesPerSecond() uint32
 	oplog(op oplog.OpType) oplog.Metadata
 }
 
@@ -30,6 +34,7 @@ type TargetType uint32
 const (
 	UnknownTargetType TargetType = 0
 	TcpTargetType     TargetType = 1
+	UdpTargetType     TargetType = 2
 )
 
 // String returns a string representation of the target type.
@@ -37,6 +42,7 @@ func (t TargetType) String() string {
 	return [...]string{
 		"unknown",
 		"tcp",
+		"udp",
 	}[t]
 }
 
@@ -92,7 +98,28 @@ func (t *targetView) targetSubType() (Target, error) {
 		tcpTarget.Version = t.Version
 		tcpTarget.SessionMaxSeconds = t.SessionMaxSeconds
 		tcpTarget.SessionConnectionLimit = t.SessionConnectionLimit
+		tcpTarget.ConnectionUpstreamBytesPerSecond = t.ConnectionUpstreamBytesPerSecond
+		tcpTarget.ConnectionDownstreamBytesPerSecond = t.ConnectionDownstreamBytesPerSecond
+		tcpTarget.SessionUpstreamBytesPerSecond = t.SessionUpstreamBytesPerSecond
+		tcpTarget.SessionDownstreamBytesPerSecond = t.SessionDownstreamBytesPerSecond
 		return &tcpTarget, nil
+	case UdpTargetType.String():
+		udpTarget := allocUdpTarget()
+		udpTarget.PublicId = t.PublicId
+		udpTarget.ScopeId = t.ScopeId
+		udpTarget.Name = t.Name
+		udpTarget.Description = t.Description
+		udpTarget.DefaultPort = t.DefaultPort
+		udpTarget.CreateTime = t.CreateTime
+		udpTarget.UpdateTime = t.UpdateTime
+		udpTarget.Version = t.Version
+		udpTarget.SessionMaxSeconds = t.SessionMaxSeconds
+		udpTarget.SessionConnectionLimit = t.SessionConnectionLimit
+		udpTarget.ConnectionUpstreamBytesPerSecond = t.ConnectionUpstreamBytesPerSecond
+		udpTarget.ConnectionDownstreamBytesPerSecond = t.ConnectionDownstreamBytesPerSecond
+		udpTarget.SessionUpstreamBytesPerSecond = t.SessionUpstreamBytesPerSecond
+		udpTarget.SessionDownstreamBytesPerSecond = t.SessionDownstreamBytesPerSecond
+		return &udpTarget, nil
 	}
 	return nil, fmt.Errorf("%s is an unknown target subtype of %s", t.PublicId, t.Type)
 }
@@ -32,12 +32,16 @@ func NewTcpTarget(scopeId string, opt ...Option) (*TcpTarget, error) {
 	}
 	t := &TcpTarget{
 		TcpTarget: &store.TcpTarget{
-			ScopeId:                scopeId,
-			Name:                   opts.withName,
-			Description:            opts.withDescription,
-			DefaultPort:            opts.withDefaultPort,
-			SessionConnectionLimit: opts.withSessionConnectionLimit,
-			SessionMaxSeconds:      opts.withSessionMaxSeconds,
+			ScopeId:                            scopeId,
+			Name:                               opts.withName,
+			Description:                        opts.withDescription,
+			DefaultPort:                        opts.withDefaultPort,
+			SessionConnectionLimit:             opts.withSessionConnectionLimit,
+			SessionMaxSeconds:                  opts.withSessionMaxSeconds,
+			ConnectionUpstreamBytesPerSecond:   opts.withConnectionUpstreamBytesPerSecond,
+			ConnectionDownstreamBytesPerSecond: opts.withConnectionDownstreamBytesPerSecond,
+			SessionUpstreamBytesPerSecond:      opts.withSessionUpstreamBytesPerSecond,
+			SessionDownstreamBytesPerSecond:    opts.withSessionDownstreamBytesPerSecond,
 		},
 	}
 	return t, nil
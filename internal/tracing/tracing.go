@@ -0,0 +1,80 @@
+// Package tracing provides helpers for instrumenting Boundary with
+// OpenTelemetry spans. Spans are created through the global tracer provider,
+// which is a no-op until it is configured by the server from the tracing
+// stanza of its configuration, so instrumented code pays very little when
+// tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/boundary/internal/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/label"
+	"google.golang.org/grpc"
+)
+
+const instrumentationName = "github.com/hashicorp/boundary"
+
+// Common span attribute keys
+const (
+	SessionIdKey    = label.Key("boundary.session_id")
+	ConnectionIdKey = label.Key("boundary.connection_id")
+	EndpointKey     = label.Key("boundary.endpoint")
+	WorkerKey       = label.Key("boundary.worker")
+)
+
+// Tracer returns the tracer used for all Boundary spans.
+func Tracer() trace.Tracer {
+	return global.Tracer(instrumentationName)
+}
+
+// Start creates a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...label.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span, marking it as failed if err is not nil.
+func End(ctx context.Context, span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(ctx, err, trace.WithErrorStatus(codes.Error))
+	}
+	span.End()
+}
+
+// RecordError marks the span in ctx, if any, as failed with err.
+func RecordError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	trace.SpanFromContext(ctx).RecordError(ctx, err, trace.WithErrorStatus(codes.Error))
+}
+
+// HttpHandler wraps h so that a span is started for each request, continuing
+// any trace propagated in the request headers. Spans are named after the
+// request method and path, with resource ids replaced to keep the number of
+// distinct names bounded.
+func HttpHandler(h http.Handler, operation string) http.Handler {
+	return otelhttp.NewHandler(h, operation,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + metrics.PathTemplate(r.URL.Path)
+		}),
+	)
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor which starts a span for
+// each unary call, continuing any trace propagated in the call metadata.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return otelgrpc.UnaryServerInterceptor()
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor which starts a span for
+// each unary call and propagates it to the server in the call metadata.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return otelgrpc.UnaryClientInterceptor()
+}
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/codes"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type testExporter struct {
+	l     sync.Mutex
+	spans []*export.SpanData
+}
+
+func (e *testExporter) ExportSpans(_ context.Context, spans []*export.SpanData) error {
+	e.l.Lock()
+	defer e.l.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *testExporter) Shutdown(context.Context) error { return nil }
+
+func TestStartEnd(t *testing.T) {
+	exp := new(testExporter)
+	global.SetTracerProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}),
+		sdktrace.WithSyncer(exp),
+	))
+
+	ctx, parent := Start(context.Background(), "parent", SessionIdKey.String("s_1234567890"))
+	cctx, child := Start(ctx, "child")
+	RecordError(cctx, errors.New("boom"))
+	child.End()
+	End(ctx, parent, nil)
+
+	require.Len(t, exp.spans, 2)
+	c, p := exp.spans[0], exp.spans[1]
+	assert.Equal(t, "child", c.Name)
+	assert.Equal(t, codes.Error, c.StatusCode)
+	assert.Equal(t, p.SpanContext.SpanID, c.ParentSpanID)
+	assert.Equal(t, p.SpanContext.TraceID, c.SpanContext.TraceID)
+
+	assert.Equal(t, "parent", p.Name)
+	assert.NotEqual(t, codes.Error, p.StatusCode)
+	assert.Contains(t, p.Attributes, SessionIdKey.String("s_1234567890"))
+}
@@ -75,6 +75,18 @@ func TestAction(t *testing.T) {
 			action: Deauthenticate,
 			want:   "deauthenticate",
 		},
+		{
+			action: Rotate,
+			want:   "rotate",
+		},
+		{
+			action: History,
+			want:   "history",
+		},
+		{
+			action: SetOperationalState,
+			want:   "set-operational-state",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
@@ -20,6 +20,9 @@ const (
 	Controller  Type = 13
 	Worker      Type = 14
 	Session     Type = 15
+	Report      Type = 16
+	Key         Type = 17
+	Job         Type = 18
 )
 
 func (r Type) String() string {
@@ -40,6 +43,9 @@ func (r Type) String() string {
 		"controller",
 		"worker",
 		"session",
+		"report",
+		"key",
+		"job",
 	}[r]
 }
 
@@ -60,4 +66,7 @@ var Map = map[string]Type{
 	Controller.String():  Controller,
 	Worker.String():      Worker,
 	Session.String():     Session,
+	Report.String():      Report,
+	Key.String():         Key,
+	Job.String():         Job,
 }
@@ -76,6 +76,14 @@ func Test_Resource(t *testing.T) {
 			typeString: "session",
 			want:       Session,
 		},
+		{
+			typeString: "key",
+			want:       Key,
+		},
+		{
+			typeString: "job",
+			want:       Job,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.typeString, func(t *testing.T) {
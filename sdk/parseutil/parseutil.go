@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +17,52 @@ import (
 	"github.com/mitchellh/mapstructure"
 )
 
+var (
+	// ErrNotAUrl is returned by ParsePath when the path is not a URL.
+	ErrNotAUrl = errors.New("not a url")
+
+	// ErrNotParsed is returned by ParsePath when the path is a URL with a
+	// scheme other than file or env.
+	ErrNotParsed = errors.New("not a parsed value")
+)
+
+// ParsePath parses a URL with schemes file:// or env://, or any other.
+// Depending on the scheme it will return specific types of data:
+//
+// * file:// will return a string with the file's contents
+//
+// * env:// will return a string with the env var's contents; it is an error
+// if the env var is not set
+//
+// * anything else will return the string as it was, along with ErrNotParsed
+//
+// Leading and trailing whitespace is trimmed from the contents. On error the
+// original string is returned along with the error, so callers can switch on
+// ErrNotAUrl and ErrNotParsed to use the input as a literal value.
+func ParsePath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	parsed, err := url.Parse(path)
+	if err != nil {
+		return path, ErrNotAUrl
+	}
+	switch parsed.Scheme {
+	case "file":
+		contents, err := ioutil.ReadFile(strings.TrimPrefix(path, "file://"))
+		if err != nil {
+			return path, fmt.Errorf("error reading file at %s: %w", path, err)
+		}
+		return strings.TrimSpace(string(contents)), nil
+	case "env":
+		name := strings.TrimPrefix(path, "env://")
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return path, fmt.Errorf("env var %s is not set", name)
+		}
+		return strings.TrimSpace(value), nil
+	}
+	return path, ErrNotParsed
+}
+
 func ParseDurationSecond(in interface{}) (time.Duration, error) {
 	var dur time.Duration
 	jsonIn, ok := in.(json.Number)
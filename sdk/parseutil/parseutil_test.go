@@ -0,0 +1,64 @@
+package parseutil
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parseutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "secret")
+	require.NoError(t, ioutil.WriteFile(file, []byte("  file secret\n"), 0600))
+
+	const envName = "BOUNDARY_PARSEUTIL_TEST"
+	require.NoError(t, os.Setenv(envName, " env secret\n"))
+	defer os.Unsetenv(envName)
+	const emptyEnvName = "BOUNDARY_PARSEUTIL_TEST_EMPTY"
+	require.NoError(t, os.Setenv(emptyEnvName, ""))
+	defer os.Unsetenv(emptyEnvName)
+	const unsetEnvName = "BOUNDARY_PARSEUTIL_TEST_UNSET"
+	require.NoError(t, os.Unsetenv(unsetEnvName))
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr error
+		// anyErr is set when an error other than a sentinel is expected
+		anyErr bool
+	}{
+		{name: "file", path: "file://" + file, want: "file secret"},
+		{name: "missing file", path: "file://" + filepath.Join(dir, "missing"), want: "file://" + filepath.Join(dir, "missing"), anyErr: true},
+		{name: "env", path: "env://" + envName, want: "env secret"},
+		{name: "empty env", path: "env://" + emptyEnvName, want: ""},
+		{name: "unset env", path: "env://" + unsetEnvName, want: "env://" + unsetEnvName, anyErr: true},
+		{name: "other scheme", path: "postgres://user@localhost/boundary", want: "postgres://user@localhost/boundary", wantErr: ErrNotParsed},
+		{name: "no scheme", path: " literal ", want: "literal", wantErr: ErrNotParsed},
+		{name: "not a url", path: "://bad", want: "://bad", wantErr: ErrNotAUrl},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := ParsePath(tt.path)
+			switch {
+			case tt.wantErr != nil:
+				require.Error(err)
+				assert.True(errors.Is(err, tt.wantErr), "got %v", err)
+			case tt.anyErr:
+				require.Error(err)
+				assert.False(errors.Is(err, ErrNotParsed) || errors.Is(err, ErrNotAUrl), "got %v", err)
+			default:
+				require.NoError(err)
+			}
+			assert.Equal(tt.want, got)
+		})
+	}
+}